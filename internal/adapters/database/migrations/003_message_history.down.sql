@@ -0,0 +1,5 @@
+-- Migration: message_history (rollback)
+-- Remove message history query indexes
+
+DROP INDEX IF EXISTS "idx_zp_message_content_search";
+DROP INDEX IF EXISTS "idx_zp_message_chat_timeline";
@@ -0,0 +1,11 @@
+-- Migration: message_history
+-- Indexes backing the message history query API
+
+-- Keyset pagination over a chat, newest first
+CREATE INDEX IF NOT EXISTS "idx_zp_message_chat_timeline" ON "zpMessage" ("sessionId", "zpChat", "zpTimestamp" DESC, "id" DESC);
+
+-- Full-text search over message content
+CREATE INDEX IF NOT EXISTS "idx_zp_message_content_search" ON "zpMessage" USING GIN (to_tsvector('simple', COALESCE("content", '')));
+
+COMMENT ON INDEX "idx_zp_message_chat_timeline" IS 'Cursor pagination of chat history by timestamp';
+COMMENT ON INDEX "idx_zp_message_content_search" IS 'Full-text search over message content';
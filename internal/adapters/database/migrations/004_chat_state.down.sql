@@ -0,0 +1,5 @@
+-- Migration: chat_state (rollback)
+-- Drop per-chat state
+
+DROP TRIGGER IF EXISTS update_zp_chat_updated_at ON "zpChat";
+DROP TABLE IF EXISTS "zpChat";
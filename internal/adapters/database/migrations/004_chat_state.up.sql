@@ -0,0 +1,31 @@
+-- Migration: chat_state
+-- Per-chat state tracked by zpwoot (unread counters)
+
+CREATE TABLE IF NOT EXISTS "zpChat" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "zpChat" VARCHAR(255) NOT NULL,
+    "unreadCount" INTEGER NOT NULL DEFAULT 0 CHECK ("unreadCount" >= 0),
+    "lastReadAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Chat indexes
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_chat_unique" ON "zpChat" ("sessionId", "zpChat");
+
+-- Chat trigger
+CREATE TRIGGER update_zp_chat_updated_at
+    BEFORE UPDATE ON "zpChat"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Chat table comments
+COMMENT ON TABLE "zpChat" IS 'Per-chat state maintained from messages, receipts and read marks';
+COMMENT ON COLUMN "zpChat"."id" IS 'Unique chat state identifier';
+COMMENT ON COLUMN "zpChat"."sessionId" IS 'WhatsApp session identifier';
+COMMENT ON COLUMN "zpChat"."zpChat" IS 'WhatsApp chat JID';
+COMMENT ON COLUMN "zpChat"."unreadCount" IS 'Incoming messages not yet read by the account';
+COMMENT ON COLUMN "zpChat"."lastReadAt" IS 'When the chat was last marked as read';
+COMMENT ON COLUMN "zpChat"."createdAt" IS 'Record creation timestamp';
+COMMENT ON COLUMN "zpChat"."updatedAt" IS 'Record last update timestamp';
@@ -0,0 +1,6 @@
+-- Migration: webhook_outbox (rollback)
+-- Drop webhook delivery queue and dead-letter store
+
+DROP TRIGGER IF EXISTS update_zp_webhook_outbox_updated_at ON "zpWebhookOutbox";
+DROP TABLE IF EXISTS "zpWebhookDeadLetter";
+DROP TABLE IF EXISTS "zpWebhookOutbox";
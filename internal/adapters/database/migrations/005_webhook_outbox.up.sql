@@ -0,0 +1,74 @@
+-- Migration: webhook_outbox
+-- Durable webhook delivery queue and dead-letter store
+
+-- =====================================================
+-- Webhook Outbox - Pending deliveries
+-- =====================================================
+CREATE TABLE IF NOT EXISTS "zpWebhookOutbox" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "endpoint" VARCHAR(2048) NOT NULL,
+    "secret" VARCHAR(255),
+    "eventId" VARCHAR(255) NOT NULL,
+    "eventType" VARCHAR(100) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "nextAttemptAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "lockedUntil" TIMESTAMP WITH TIME ZONE,
+    "lastError" TEXT,
+    "lastStatusCode" INTEGER,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Outbox indexes
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_outbox_endpoint_order" ON "zpWebhookOutbox" ("endpoint", "createdAt", "id");
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_outbox_next_attempt" ON "zpWebhookOutbox" ("nextAttemptAt");
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_outbox_session_id" ON "zpWebhookOutbox" ("sessionId");
+
+-- Outbox trigger
+CREATE TRIGGER update_zp_webhook_outbox_updated_at
+    BEFORE UPDATE ON "zpWebhookOutbox"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Outbox table comments
+COMMENT ON TABLE "zpWebhookOutbox" IS 'Webhook events waiting for delivery, sent in order per endpoint';
+COMMENT ON COLUMN "zpWebhookOutbox"."endpoint" IS 'Destination URL captured when the event was queued';
+COMMENT ON COLUMN "zpWebhookOutbox"."secret" IS 'HMAC secret used to sign the payload';
+COMMENT ON COLUMN "zpWebhookOutbox"."eventId" IS 'Webhook event identifier sent to the receiver';
+COMMENT ON COLUMN "zpWebhookOutbox"."payload" IS 'Serialized webhook event';
+COMMENT ON COLUMN "zpWebhookOutbox"."attempts" IS 'Delivery attempts made so far';
+COMMENT ON COLUMN "zpWebhookOutbox"."nextAttemptAt" IS 'Earliest time of the next attempt (exponential backoff)';
+COMMENT ON COLUMN "zpWebhookOutbox"."lockedUntil" IS 'Lease held by the worker currently delivering the event';
+COMMENT ON COLUMN "zpWebhookOutbox"."lastError" IS 'Error of the last failed attempt';
+COMMENT ON COLUMN "zpWebhookOutbox"."lastStatusCode" IS 'HTTP status of the last failed attempt';
+
+-- =====================================================
+-- Webhook Dead Letters - Undeliverable events
+-- =====================================================
+CREATE TABLE IF NOT EXISTS "zpWebhookDeadLetter" (
+    "id" UUID PRIMARY KEY,
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "endpoint" VARCHAR(2048) NOT NULL,
+    "secret" VARCHAR(255),
+    "eventId" VARCHAR(255) NOT NULL,
+    "eventType" VARCHAR(100) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "attempts" INTEGER NOT NULL,
+    "lastError" TEXT,
+    "lastStatusCode" INTEGER,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "failedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Dead letter indexes
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_dead_letter_session_id" ON "zpWebhookDeadLetter" ("sessionId");
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_dead_letter_failed_at" ON "zpWebhookDeadLetter" ("failedAt");
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_dead_letter_event_type" ON "zpWebhookDeadLetter" ("eventType");
+
+-- Dead letter table comments
+COMMENT ON TABLE "zpWebhookDeadLetter" IS 'Webhook events that could not be delivered, kept for inspection and replay';
+COMMENT ON COLUMN "zpWebhookDeadLetter"."id" IS 'Identifier of the failed outbox delivery';
+COMMENT ON COLUMN "zpWebhookDeadLetter"."createdAt" IS 'When the event was originally queued';
+COMMENT ON COLUMN "zpWebhookDeadLetter"."failedAt" IS 'When the event was given up on';
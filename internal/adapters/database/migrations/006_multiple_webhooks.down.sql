@@ -0,0 +1,13 @@
+-- Migration: multiple_webhooks (rollback)
+-- Keep the oldest webhook of every session and restore the one-per-session constraint
+
+DROP INDEX IF EXISTS "idx_zp_webhooks_unique_session_url";
+
+DELETE FROM "zpWebhooks" w
+USING "zpWebhooks" older
+WHERE w."sessionId" = older."sessionId"
+  AND (older."createdAt", older."id") < (w."createdAt", w."id");
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_webhooks_unique_session" ON "zpWebhooks" ("sessionId");
+
+COMMENT ON TABLE "zpWebhooks" IS 'Webhook configurations for sessions';
@@ -0,0 +1,9 @@
+-- Migration: multiple_webhooks
+-- Allow a session to register several webhooks
+
+DROP INDEX IF EXISTS "idx_zp_webhooks_unique_session";
+
+-- A session may list the same URL only once
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_webhooks_unique_session_url" ON "zpWebhooks" ("sessionId", "url");
+
+COMMENT ON TABLE "zpWebhooks" IS 'Webhook configurations for sessions (several per session)';
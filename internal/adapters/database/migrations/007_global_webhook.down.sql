@@ -0,0 +1,6 @@
+-- Migration: global_webhook (rollback)
+-- Drop the global webhook
+
+DROP INDEX IF EXISTS "idx_zp_webhooks_global";
+
+DELETE FROM "zpWebhooks" WHERE "sessionId" IS NULL;
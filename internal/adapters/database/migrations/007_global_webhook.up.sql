@@ -0,0 +1,5 @@
+-- Migration: global_webhook
+-- Instance-wide webhook stored with a NULL sessionId
+
+-- At most one global webhook
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_webhooks_global" ON "zpWebhooks" ((true)) WHERE "sessionId" IS NULL;
@@ -0,0 +1,6 @@
+-- Migration: webhook_payload_format (rollback)
+-- Drop the per-webhook payload format
+
+ALTER TABLE "zpWebhooks" DROP CONSTRAINT IF EXISTS "chk_zp_webhooks_payload_format";
+
+ALTER TABLE "zpWebhooks" DROP COLUMN IF EXISTS "payloadFormat";
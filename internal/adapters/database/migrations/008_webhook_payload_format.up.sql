@@ -0,0 +1,11 @@
+-- Migration: webhook_payload_format
+-- Per-webhook payload format: normalized schema or raw whatsmeow structures
+
+ALTER TABLE "zpWebhooks" ADD COLUMN IF NOT EXISTS "payloadFormat" VARCHAR(20) NOT NULL DEFAULT 'normalized';
+
+-- Existing webhooks were built against the raw payloads; keep them unchanged
+UPDATE "zpWebhooks" SET "payloadFormat" = 'raw';
+
+ALTER TABLE "zpWebhooks" ADD CONSTRAINT "chk_zp_webhooks_payload_format" CHECK ("payloadFormat" IN ('normalized', 'raw'));
+
+COMMENT ON COLUMN "zpWebhooks"."payloadFormat" IS 'Payload format sent to the webhook: normalized or raw';
@@ -0,0 +1,5 @@
+-- Migration: chatwoot_import (rollback)
+-- Drop Chatwoot import progress
+
+DROP INDEX IF EXISTS "idx_zp_message_session_sync_timestamp";
+DROP TABLE IF EXISTS "zpChatwootImport";
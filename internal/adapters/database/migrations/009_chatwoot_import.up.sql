@@ -0,0 +1,34 @@
+-- Migration: chatwoot_import
+-- Progress of the Chatwoot contact and history import of each session
+
+CREATE TABLE IF NOT EXISTS "zpChatwootImport" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'running',
+    "phase" VARCHAR(20) NOT NULL DEFAULT 'contacts',
+    "contactsTotal" INTEGER NOT NULL DEFAULT 0,
+    "contactsImported" INTEGER NOT NULL DEFAULT 0,
+    "messagesTotal" INTEGER NOT NULL DEFAULT 0,
+    "messagesImported" INTEGER NOT NULL DEFAULT 0,
+    "messagesFailed" INTEGER NOT NULL DEFAULT 0,
+    "cursorTimestamp" TIMESTAMP WITH TIME ZONE,
+    "cursorId" UUID,
+    "lastError" TEXT,
+    "startedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "finishedAt" TIMESTAMP WITH TIME ZONE,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "chk_zp_chatwoot_import_status" CHECK ("status" IN ('running', 'completed', 'failed')),
+    CONSTRAINT "chk_zp_chatwoot_import_phase" CHECK ("phase" IN ('contacts', 'messages', 'done'))
+);
+
+-- One import per session; a new run replaces the previous one
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_chatwoot_import_unique_session" ON "zpChatwootImport" ("sessionId");
+
+-- Unsynced messages are imported in timestamp order
+CREATE INDEX IF NOT EXISTS "idx_zp_message_session_sync_timestamp" ON "zpMessage" ("sessionId", "syncStatus", "zpTimestamp", "id");
+
+COMMENT ON TABLE "zpChatwootImport" IS 'Chatwoot import job of a session - one per session';
+COMMENT ON COLUMN "zpChatwootImport"."phase" IS 'Step the import resumes from: contacts, messages or done';
+COMMENT ON COLUMN "zpChatwootImport"."cursorTimestamp" IS 'Timestamp of the last message the import handled';
+COMMENT ON COLUMN "zpChatwootImport"."cursorId" IS 'ID of the last message the import handled';
+COMMENT ON COLUMN "zpChatwootImport"."lastError" IS 'Error that stopped the import';
@@ -0,0 +1,5 @@
+-- Migration: scheduled_messages (rollback)
+-- Drop scheduled messages
+
+DROP TRIGGER IF EXISTS update_zp_scheduled_message_updated_at ON "zpScheduledMessage";
+DROP TABLE IF EXISTS "zpScheduledMessage";
@@ -0,0 +1,43 @@
+-- Migration: scheduled_messages
+-- Messages queued to be sent at a later time
+
+CREATE TABLE IF NOT EXISTS "zpScheduledMessage" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "phone" VARCHAR(255) NOT NULL,
+    "type" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "sendAt" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "timezone" VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    "onDisconnected" VARCHAR(10) NOT NULL DEFAULT 'retry',
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "nextAttemptAt" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "lockedUntil" TIMESTAMP WITH TIME ZONE,
+    "messageId" VARCHAR(255),
+    "lastError" TEXT,
+    "sentAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "chk_zp_scheduled_message_status" CHECK ("status" IN ('pending', 'sent', 'failed', 'skipped', 'cancelled')),
+    CONSTRAINT "chk_zp_scheduled_message_on_disconnected" CHECK ("onDisconnected" IN ('retry', 'skip'))
+);
+
+-- The scheduler only looks at pending messages that are due
+CREATE INDEX IF NOT EXISTS "idx_zp_scheduled_message_due" ON "zpScheduledMessage" ("nextAttemptAt") WHERE "status" = 'pending';
+CREATE INDEX IF NOT EXISTS "idx_zp_scheduled_message_session_send_at" ON "zpScheduledMessage" ("sessionId", "sendAt");
+
+CREATE TRIGGER update_zp_scheduled_message_updated_at
+    BEFORE UPDATE ON "zpScheduledMessage"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE "zpScheduledMessage" IS 'Messages to be sent by the scheduler at sendAt';
+COMMENT ON COLUMN "zpScheduledMessage"."type" IS 'Message type: text, image, audio, video, document, sticker, location, contact or poll';
+COMMENT ON COLUMN "zpScheduledMessage"."payload" IS 'Body of the matching send endpoint';
+COMMENT ON COLUMN "zpScheduledMessage"."timezone" IS 'IANA time zone sendAt was given in';
+COMMENT ON COLUMN "zpScheduledMessage"."onDisconnected" IS 'Whether to retry or skip the message when the session is not connected when due';
+COMMENT ON COLUMN "zpScheduledMessage"."nextAttemptAt" IS 'Earliest time of the next send attempt';
+COMMENT ON COLUMN "zpScheduledMessage"."attempts" IS 'Send attempts made while the session was connected';
+COMMENT ON COLUMN "zpScheduledMessage"."lockedUntil" IS 'Lease held by the scheduler while sending the message';
+COMMENT ON COLUMN "zpScheduledMessage"."messageId" IS 'WhatsApp message ID once sent';
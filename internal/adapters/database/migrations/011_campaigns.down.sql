@@ -0,0 +1,7 @@
+-- Migration: campaigns (rollback)
+-- Drop campaigns and their recipients
+
+DROP TRIGGER IF EXISTS update_zp_campaign_recipient_updated_at ON "zpCampaignRecipient";
+DROP TRIGGER IF EXISTS update_zp_campaign_updated_at ON "zpCampaign";
+DROP TABLE IF EXISTS "zpCampaignRecipient";
+DROP TABLE IF EXISTS "zpCampaign";
@@ -0,0 +1,62 @@
+-- Migration: campaigns
+-- Broadcast campaigns sending one templated text to a list of recipients
+
+CREATE TABLE IF NOT EXISTS "zpCampaign" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "text" TEXT NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'running',
+    "intervalSeconds" INTEGER NOT NULL DEFAULT 5,
+    "nextSendAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "lockedUntil" TIMESTAMP WITH TIME ZONE,
+    "completedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "chk_zp_campaign_status" CHECK ("status" IN ('running', 'paused', 'completed', 'cancelled'))
+);
+
+CREATE TABLE IF NOT EXISTS "zpCampaignRecipient" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "campaignId" UUID NOT NULL REFERENCES "zpCampaign"("id") ON DELETE CASCADE,
+    "phone" VARCHAR(255) NOT NULL,
+    "jid" VARCHAR(255),
+    "variables" JSONB NOT NULL DEFAULT '{}',
+    "status" VARCHAR(20) NOT NULL DEFAULT 'queued',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "messageId" VARCHAR(255),
+    "error" TEXT,
+    "sentAt" TIMESTAMP WITH TIME ZONE,
+    "deliveredAt" TIMESTAMP WITH TIME ZONE,
+    "readAt" TIMESTAMP WITH TIME ZONE,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "uq_zp_campaign_recipient_phone" UNIQUE ("campaignId", "phone"),
+    CONSTRAINT "chk_zp_campaign_recipient_status" CHECK ("status" IN ('queued', 'sent', 'delivered', 'read', 'failed'))
+);
+
+-- The runner only looks at running campaigns that are due
+CREATE INDEX IF NOT EXISTS "idx_zp_campaign_due" ON "zpCampaign" ("nextSendAt") WHERE "status" = 'running';
+CREATE INDEX IF NOT EXISTS "idx_zp_campaign_session" ON "zpCampaign" ("sessionId", "createdAt" DESC);
+CREATE INDEX IF NOT EXISTS "idx_zp_campaign_recipient_status" ON "zpCampaignRecipient" ("campaignId", "status", "id");
+-- Receipts are matched to recipients by message ID
+CREATE INDEX IF NOT EXISTS "idx_zp_campaign_recipient_message" ON "zpCampaignRecipient" ("messageId") WHERE "messageId" IS NOT NULL;
+
+CREATE TRIGGER update_zp_campaign_updated_at
+    BEFORE UPDATE ON "zpCampaign"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_zp_campaign_recipient_updated_at
+    BEFORE UPDATE ON "zpCampaignRecipient"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE "zpCampaign" IS 'Broadcast campaigns sending one templated text to many recipients';
+COMMENT ON COLUMN "zpCampaign"."text" IS 'Message text with {{variable}} placeholders filled per recipient';
+COMMENT ON COLUMN "zpCampaign"."intervalSeconds" IS 'Minimum time between two messages of the campaign';
+COMMENT ON COLUMN "zpCampaign"."nextSendAt" IS 'Earliest time of the next send';
+COMMENT ON COLUMN "zpCampaign"."lockedUntil" IS 'Lease held by the runner while sending a message of the campaign';
+COMMENT ON TABLE "zpCampaignRecipient" IS 'Recipients of a campaign with their delivery status';
+COMMENT ON COLUMN "zpCampaignRecipient"."jid" IS 'WhatsApp JID the phone resolved to when the campaign was created';
+COMMENT ON COLUMN "zpCampaignRecipient"."variables" IS 'Values for the placeholders of the campaign text';
+COMMENT ON COLUMN "zpCampaignRecipient"."status" IS 'queued, sent, delivered, read or failed; updated from receipts once sent';
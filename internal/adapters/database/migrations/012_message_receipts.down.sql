@@ -0,0 +1,5 @@
+-- Migration: message_receipts (rollback)
+-- Drop message receipts
+
+DROP TRIGGER IF EXISTS update_zp_message_receipt_updated_at ON "zpMessageReceipt";
+DROP TABLE IF EXISTS "zpMessageReceipt";
@@ -0,0 +1,25 @@
+-- Migration: message_receipts
+-- Delivery, read and played status of sent messages per recipient
+
+CREATE TABLE IF NOT EXISTS "zpMessageReceipt" (
+    "sessionId" UUID NOT NULL,
+    "zpMessageId" VARCHAR(255) NOT NULL,
+    "zpRecipient" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(20) NOT NULL,
+    "deliveredAt" TIMESTAMP WITH TIME ZONE,
+    "readAt" TIMESTAMP WITH TIME ZONE,
+    "playedAt" TIMESTAMP WITH TIME ZONE,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("sessionId", "zpMessageId", "zpRecipient"),
+    FOREIGN KEY ("sessionId", "zpMessageId") REFERENCES "zpMessage"("sessionId", "zpMessageId") ON DELETE CASCADE,
+    CONSTRAINT "chk_zp_message_receipt_status" CHECK ("status" IN ('delivered', 'read', 'played'))
+);
+
+CREATE TRIGGER update_zp_message_receipt_updated_at
+    BEFORE UPDATE ON "zpMessageReceipt"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE "zpMessageReceipt" IS 'Receipts of sent messages; a message without receipts was only acknowledged by the server';
+COMMENT ON COLUMN "zpMessageReceipt"."zpRecipient" IS 'JID of the user the receipt came from, a group participant for group messages';
+COMMENT ON COLUMN "zpMessageReceipt"."status" IS 'Furthest status reached: delivered, read or played';
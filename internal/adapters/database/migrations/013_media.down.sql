@@ -0,0 +1,5 @@
+-- Migration: media (rollback)
+-- Drop downloaded media
+
+DROP TRIGGER IF EXISTS update_zp_media_updated_at ON "zpMedia";
+DROP TABLE IF EXISTS "zpMedia";
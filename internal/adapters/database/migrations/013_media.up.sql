@@ -0,0 +1,38 @@
+-- Migration: media
+-- Files of media messages downloaded from WhatsApp
+
+CREATE TABLE IF NOT EXISTS "zpMedia" (
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "zpMessageId" VARCHAR(255) NOT NULL,
+    "zpChat" VARCHAR(255) NOT NULL,
+    "zpSender" VARCHAR(255) NOT NULL,
+    "zpFromMe" BOOLEAN NOT NULL,
+    "zpIsGroup" BOOLEAN NOT NULL DEFAULT FALSE,
+    "type" VARCHAR(20) NOT NULL,
+    "mimeType" VARCHAR(255),
+    "fileName" VARCHAR(1024),
+    "size" BIGINT NOT NULL DEFAULT 0,
+    "sha256" VARCHAR(64),
+    "descriptor" BYTEA NOT NULL,
+    "storageKey" VARCHAR(512),
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "error" TEXT,
+    "downloadedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("sessionId", "zpMessageId"),
+    CONSTRAINT "chk_zp_media_status" CHECK ("status" IN ('pending', 'stored', 'retrying', 'failed', 'expired'))
+);
+
+-- Retention walks stored media by download time
+CREATE INDEX IF NOT EXISTS "idx_zp_media_stored" ON "zpMedia" ("downloadedAt") WHERE "status" = 'stored';
+
+CREATE TRIGGER update_zp_media_updated_at
+    BEFORE UPDATE ON "zpMedia"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE "zpMedia" IS 'Files of image, video, audio, document and sticker messages';
+COMMENT ON COLUMN "zpMedia"."descriptor" IS 'Encrypted media message needed to download the file from WhatsApp';
+COMMENT ON COLUMN "zpMedia"."storageKey" IS 'Key of the file in the blob store while stored';
+COMMENT ON COLUMN "zpMedia"."status" IS 'pending, stored, retrying (re-upload requested from the phone), failed or expired (removed by retention)';
@@ -0,0 +1,5 @@
+-- Migration: api_keys (rollback)
+-- Drop scoped API keys
+
+DROP TRIGGER IF EXISTS update_zp_api_key_updated_at ON "zpApiKey";
+DROP TABLE IF EXISTS "zpApiKey";
@@ -0,0 +1,29 @@
+-- Migration: api_keys
+-- API keys scoped to sessions and permissions, besides the global ZP_API_KEY
+
+CREATE TABLE IF NOT EXISTS "zpApiKey" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "name" VARCHAR(255) NOT NULL,
+    "prefix" VARCHAR(32) NOT NULL,
+    "keyHash" VARCHAR(64) NOT NULL,
+    "sessionIds" TEXT[] NOT NULL DEFAULT '{}',
+    "permissions" TEXT[] NOT NULL DEFAULT '{}',
+    "expiresAt" TIMESTAMP WITH TIME ZONE,
+    "lastUsedAt" TIMESTAMP WITH TIME ZONE,
+    "revokedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "uq_zp_api_key_hash" UNIQUE ("keyHash")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_zp_api_key_created" ON "zpApiKey" ("createdAt" DESC);
+
+CREATE TRIGGER update_zp_api_key_updated_at
+    BEFORE UPDATE ON "zpApiKey"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE "zpApiKey" IS 'API keys with limited access; only the SHA-256 of each key is stored';
+COMMENT ON COLUMN "zpApiKey"."prefix" IS 'First characters of the key, shown to tell keys apart';
+COMMENT ON COLUMN "zpApiKey"."sessionIds" IS 'Sessions the key may act on; empty for all sessions';
+COMMENT ON COLUMN "zpApiKey"."permissions" IS 'send, read, sessions, webhooks or admin, which grants everything';
@@ -0,0 +1,11 @@
+-- Migration: tenants (rollback)
+-- Drop tenants; session names become globally unique again
+
+DROP TABLE IF EXISTS "zpTenantUsage";
+DROP INDEX IF EXISTS "idx_zp_api_key_tenant";
+ALTER TABLE "zpApiKey" DROP COLUMN IF EXISTS "tenantId";
+DROP INDEX IF EXISTS "idx_zp_sessions_tenant_name";
+ALTER TABLE "zpSessions" DROP COLUMN IF EXISTS "tenantId";
+ALTER TABLE "zpSessions" ADD CONSTRAINT "zpSessions_name_key" UNIQUE ("name");
+DROP TRIGGER IF EXISTS update_zp_tenant_updated_at ON "zpTenant";
+DROP TABLE IF EXISTS "zpTenant";
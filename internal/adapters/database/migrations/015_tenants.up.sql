@@ -0,0 +1,48 @@
+-- Migration: tenants
+-- Tenants owning sessions, with quotas; existing sessions move to the default tenant
+
+CREATE TABLE IF NOT EXISTS "zpTenant" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "name" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'active',
+    "maxSessions" INTEGER NOT NULL DEFAULT 0,
+    "maxMessagesPerDay" INTEGER NOT NULL DEFAULT 0,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT "uq_zp_tenant_name" UNIQUE ("name"),
+    CONSTRAINT "chk_zp_tenant_status" CHECK ("status" IN ('active', 'suspended')),
+    CONSTRAINT "chk_zp_tenant_quotas" CHECK ("maxSessions" >= 0 AND "maxMessagesPerDay" >= 0)
+);
+
+CREATE TRIGGER update_zp_tenant_updated_at
+    BEFORE UPDATE ON "zpTenant"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO "zpTenant" ("id", "name")
+VALUES ('00000000-0000-0000-0000-000000000000', 'default')
+ON CONFLICT DO NOTHING;
+
+-- Session names are unique within a tenant only
+ALTER TABLE "zpSessions" DROP CONSTRAINT IF EXISTS "zpSessions_name_key";
+ALTER TABLE "zpSessions" ADD COLUMN IF NOT EXISTS "tenantId" UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000000' REFERENCES "zpTenant"("id");
+ALTER TABLE "zpSessions" ALTER COLUMN "tenantId" DROP DEFAULT;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_sessions_tenant_name" ON "zpSessions" ("tenantId", "name");
+
+ALTER TABLE "zpApiKey" ADD COLUMN IF NOT EXISTS "tenantId" UUID REFERENCES "zpTenant"("id") ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS "idx_zp_api_key_tenant" ON "zpApiKey" ("tenantId");
+
+CREATE TABLE IF NOT EXISTS "zpTenantUsage" (
+    "tenantId" UUID NOT NULL REFERENCES "zpTenant"("id") ON DELETE CASCADE,
+    "day" DATE NOT NULL,
+    "messages" INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY ("tenantId", "day")
+);
+
+COMMENT ON TABLE "zpTenant" IS 'Customers sharing the server; each owns its sessions and everything stored for them';
+COMMENT ON COLUMN "zpTenant"."maxSessions" IS 'Most sessions the tenant may have; 0 for no limit';
+COMMENT ON COLUMN "zpTenant"."maxMessagesPerDay" IS 'Most messages the tenant may send per UTC day; 0 for no limit';
+COMMENT ON COLUMN "zpSessions"."tenantId" IS 'Tenant owning the session';
+COMMENT ON COLUMN "zpApiKey"."tenantId" IS 'Tenant the key acts for; NULL for keys of the server operator';
+COMMENT ON TABLE "zpTenantUsage" IS 'Messages sent by each tenant per UTC day, counted when they are queued for sending';
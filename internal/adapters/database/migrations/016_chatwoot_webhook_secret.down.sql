@@ -0,0 +1,4 @@
+-- Migration: chatwoot_webhook_secret (rollback)
+-- Drop the Chatwoot webhook secret
+
+ALTER TABLE "zpChatwoot" DROP COLUMN IF EXISTS "webhookSecret";
@@ -0,0 +1,12 @@
+-- Migration: chatwoot_webhook_secret
+-- Secret Chatwoot must send to the webhook of a session; existing configs get a random one
+
+ALTER TABLE "zpChatwoot" ADD COLUMN IF NOT EXISTS "webhookSecret" VARCHAR(64);
+
+UPDATE "zpChatwoot"
+SET "webhookSecret" = replace(gen_random_uuid()::text, '-', '') || replace(gen_random_uuid()::text, '-', '')
+WHERE "webhookSecret" IS NULL;
+
+ALTER TABLE "zpChatwoot" ALTER COLUMN "webhookSecret" SET NOT NULL;
+
+COMMENT ON COLUMN "zpChatwoot"."webhookSecret" IS 'Secret required in the secret query parameter of the Chatwoot webhook URL';
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = `"id", "tenantId", "name", "prefix", "keyHash", "sessionIds", "permissions",
+		       "expiresAt", "lastUsedAt", "revokedAt", "createdAt", "updatedAt"`
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) error {
+	query := `
+		INSERT INTO "zpApiKey" (` + apiKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID,
+		nullString(key.TenantID),
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		pq.StringArray(key.SessionIDs),
+		pq.StringArray(permissionStrings(key.Permissions)),
+		key.ExpiresAt,
+		key.LastUsedAt,
+		key.RevokedAt,
+		key.CreatedAt,
+		key.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id string) (*apikey.APIKey, error) {
+	return r.get(ctx, `"id" = $1`, id)
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	return r.get(ctx, `"keyHash" = $1`, hash)
+}
+
+func (r *APIKeyRepository) get(ctx context.Context, condition string, arg interface{}) (*apikey.APIKey, error) {
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{arg})
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM "zpApiKey"
+		WHERE ` + condition + scope
+
+	var row apiKeyDB
+
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrAPIKeyNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM "zpApiKey"
+		WHERE TRUE`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, nil)
+	query += scope + `
+		ORDER BY "createdAt" DESC
+	`
+
+	var rows []apiKeyDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]*apikey.APIKey, len(rows))
+	for i := range rows {
+		keys[i] = rows[i].toDomain()
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Save(ctx context.Context, key *apikey.APIKey) error {
+	query := `
+		UPDATE "zpApiKey" SET
+			"prefix" = $2,
+			"keyHash" = $3,
+			"revokedAt" = $4
+		WHERE "id" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{key.ID, key.Prefix, key.Hash, key.RevokedAt})
+	query += scope
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE "zpApiKey" SET "lastUsedAt" = $2 WHERE "id" = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
+
+func permissionStrings(permissions []apikey.Permission) []string {
+	values := make([]string, len(permissions))
+	for i, permission := range permissions {
+		values[i] = string(permission)
+	}
+
+	return values
+}
+
+type apiKeyDB struct {
+	ID          string         `db:"id"`
+	TenantID    sql.NullString `db:"tenantId"`
+	Name        string         `db:"name"`
+	Prefix      string         `db:"prefix"`
+	KeyHash     string         `db:"keyHash"`
+	SessionIDs  pq.StringArray `db:"sessionIds"`
+	Permissions pq.StringArray `db:"permissions"`
+	ExpiresAt   sql.NullTime   `db:"expiresAt"`
+	LastUsedAt  sql.NullTime   `db:"lastUsedAt"`
+	RevokedAt   sql.NullTime   `db:"revokedAt"`
+	CreatedAt   time.Time      `db:"createdAt"`
+	UpdatedAt   time.Time      `db:"updatedAt"`
+}
+
+func (k *apiKeyDB) toDomain() *apikey.APIKey {
+	permissions := make([]apikey.Permission, len(k.Permissions))
+	for i, permission := range k.Permissions {
+		permissions[i] = apikey.Permission(permission)
+	}
+
+	return &apikey.APIKey{
+		ID:          k.ID,
+		TenantID:    k.TenantID.String,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Hash:        k.KeyHash,
+		SessionIDs:  []string(k.SessionIDs),
+		Permissions: permissions,
+		ExpiresAt:   nullTimePtr(k.ExpiresAt),
+		LastUsedAt:  nullTimePtr(k.LastUsedAt),
+		RevokedAt:   nullTimePtr(k.RevokedAt),
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+	}
+}
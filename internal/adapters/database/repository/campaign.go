@@ -0,0 +1,525 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const campaignColumns = `"id", "sessionId", "name", "text", "status", "intervalSeconds", "nextSendAt",
+		       "completedAt", "createdAt", "updatedAt"`
+
+const campaignRecipientColumns = `"id", "campaignId", "phone", "jid", "variables", "status", "attempts",
+		       "messageId", "error", "sentAt", "deliveredAt", "readAt", "updatedAt"`
+
+type CampaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewCampaignRepository(db *sqlx.DB) *CampaignRepository {
+	return &CampaignRepository{
+		db: db,
+	}
+}
+
+func (r *CampaignRepository) Create(ctx context.Context, c *campaign.Campaign, recipients []*campaign.Recipient) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "zpCampaign" (`+campaignColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		c.ID,
+		c.SessionID,
+		c.Name,
+		c.Text,
+		string(c.Status),
+		int(c.Interval/time.Second),
+		c.NextSendAt,
+		c.CompletedAt,
+		c.CreatedAt,
+		c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	stmt, err := tx.PreparexContext(ctx, `
+		INSERT INTO "zpCampaignRecipient" ("campaignId", "phone", "jid", "variables", "status", "error", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING "id"
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare recipient insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, recipient := range recipients {
+		variables, err := json.Marshal(recipient.Variables)
+		if err != nil {
+			return fmt.Errorf("failed to encode variables of %s: %w", recipient.Phone, err)
+		}
+
+		err = stmt.GetContext(ctx, &recipient.ID,
+			c.ID,
+			recipient.Phone,
+			nullString(recipient.JID),
+			variables,
+			string(recipient.Status),
+			recipient.Error,
+			recipient.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create recipient %s: %w", recipient.Phone, err)
+		}
+
+		recipient.CampaignID = c.ID
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit campaign: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) GetByID(ctx context.Context, sessionID, id string) (*campaign.Campaign, error) {
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM "zpCampaign"
+		WHERE "sessionId" = $1 AND "id" = $2`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID, id})
+	query += scope
+
+	var row campaignDB
+
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrCampaignNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *CampaignRepository) List(ctx context.Context, filter *campaign.ListFilter) ([]*campaign.Campaign, int, error) {
+	var (
+		conditions = []string{`"sessionId" = $1`}
+		args       = []interface{}{filter.SessionID}
+	)
+
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, `"status" = $`+strconv.Itoa(len(args)))
+	}
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, args)
+	where := ` WHERE ` + strings.Join(conditions, " AND ") + scope
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM "zpCampaign"`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM "zpCampaign"` + where + `
+		ORDER BY "createdAt" DESC
+		LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	var rows []campaignDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	campaigns := make([]*campaign.Campaign, len(rows))
+	for i := range rows {
+		campaigns[i] = rows[i].toDomain()
+	}
+
+	return campaigns, total, nil
+}
+
+func (r *CampaignRepository) UpdateStatus(ctx context.Context, c *campaign.Campaign) error {
+	query := `
+		UPDATE "zpCampaign"
+		SET "status" = $3, "nextSendAt" = $4
+		WHERE "sessionId" = $1 AND "id" = $2
+		  AND "status" NOT IN ('completed', 'cancelled')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, c.SessionID, c.ID, string(c.Status), c.NextSendAt)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return shared.ErrCampaignFinished
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) ListRecipients(ctx context.Context, filter *campaign.RecipientFilter) ([]*campaign.Recipient, int, error) {
+	var (
+		conditions = []string{`"campaignId" = $1`}
+		args       = []interface{}{filter.CampaignID}
+	)
+
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, `"status" = $`+strconv.Itoa(len(args)))
+	}
+
+	where := ` WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM "zpCampaignRecipient"`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := `
+		SELECT ` + campaignRecipientColumns + `
+		FROM "zpCampaignRecipient"` + where + `
+		ORDER BY "id"
+		LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	var rows []campaignRecipientDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list recipients: %w", err)
+	}
+
+	recipients := make([]*campaign.Recipient, len(rows))
+	for i := range rows {
+		recipients[i] = rows[i].toDomain()
+	}
+
+	return recipients, total, nil
+}
+
+func (r *CampaignRepository) Report(ctx context.Context, campaignID string) (*campaign.Report, error) {
+	var counts []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	query := `
+		SELECT "status", COUNT(*) AS "count"
+		FROM "zpCampaignRecipient"
+		WHERE "campaignId" = $1
+		GROUP BY "status"
+	`
+
+	if err := r.db.SelectContext(ctx, &counts, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	report := &campaign.Report{Errors: make(map[string]int)}
+
+	for _, count := range counts {
+		report.Total += count.Count
+
+		switch campaign.RecipientStatus(count.Status) {
+		case campaign.RecipientQueued:
+			report.Queued = count.Count
+		case campaign.RecipientSent:
+			report.Sent = count.Count
+		case campaign.RecipientDelivered:
+			report.Delivered = count.Count
+		case campaign.RecipientRead:
+			report.Read = count.Count
+		case campaign.RecipientFailed:
+			report.Failed = count.Count
+		}
+	}
+
+	var errorCounts []struct {
+		Error string `db:"error"`
+		Count int    `db:"count"`
+	}
+
+	query = `
+		SELECT COALESCE("error", '') AS "error", COUNT(*) AS "count"
+		FROM "zpCampaignRecipient"
+		WHERE "campaignId" = $1 AND "status" = 'failed'
+		GROUP BY 1
+	`
+
+	if err := r.db.SelectContext(ctx, &errorCounts, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to count recipient errors: %w", err)
+	}
+
+	for _, count := range errorCounts {
+		report.Errors[count.Error] = count.Count
+	}
+
+	return report, nil
+}
+
+// ClaimDue leases due campaigns with FOR UPDATE SKIP LOCKED, so a campaign
+// shared by several instances is only sent by one of them at a time.
+func (r *CampaignRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*campaign.Campaign, error) {
+	query := `
+		WITH due AS (
+			SELECT "id"
+			FROM "zpCampaign"
+			WHERE "status" = 'running'
+			  AND "nextSendAt" <= NOW()
+			  AND ("lockedUntil" IS NULL OR "lockedUntil" < NOW())
+			ORDER BY "nextSendAt"
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE "zpCampaign" c
+		SET "lockedUntil" = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM due
+		WHERE c."id" = due."id"
+		RETURNING c."id", c."sessionId", c."name", c."text", c."status", c."intervalSeconds",
+		          c."nextSendAt", c."completedAt", c."createdAt", c."updatedAt"
+	`
+
+	var rows []campaignDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, limit, lease.Milliseconds()); err != nil {
+		return nil, fmt.Errorf("failed to claim campaigns: %w", err)
+	}
+
+	campaigns := make([]*campaign.Campaign, len(rows))
+	for i := range rows {
+		campaigns[i] = rows[i].toDomain()
+	}
+
+	return campaigns, nil
+}
+
+func (r *CampaignRepository) NextRecipient(ctx context.Context, campaignID string) (*campaign.Recipient, error) {
+	query := `
+		SELECT ` + campaignRecipientColumns + `
+		FROM "zpCampaignRecipient"
+		WHERE "campaignId" = $1 AND "status" = 'queued'
+		ORDER BY "id"
+		LIMIT 1
+	`
+
+	var row campaignRecipientDB
+
+	if err := r.db.GetContext(ctx, &row, query, campaignID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get next recipient: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *CampaignRepository) SaveRecipient(ctx context.Context, recipient *campaign.Recipient) error {
+	query := `
+		UPDATE "zpCampaignRecipient"
+		SET "status" = $2, "attempts" = $3, "messageId" = $4, "error" = $5, "sentAt" = $6
+		WHERE "id" = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		recipient.ID,
+		string(recipient.Status),
+		recipient.Attempts,
+		recipient.MessageID,
+		recipient.Error,
+		recipient.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save recipient: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) Release(ctx context.Context, c *campaign.Campaign) error {
+	query := `
+		UPDATE "zpCampaign"
+		SET "nextSendAt" = $2, "lockedUntil" = NULL
+		WHERE "id" = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, c.ID, c.NextSendAt); err != nil {
+		return fmt.Errorf("failed to release campaign: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) Complete(ctx context.Context, c *campaign.Campaign) error {
+	query := `
+		UPDATE "zpCampaign"
+		SET "status" = CASE WHEN "status" = 'running' THEN 'completed' ELSE "status" END,
+		    "completedAt" = CASE WHEN "status" = 'running' THEN $2 ELSE "completedAt" END,
+		    "lockedUntil" = NULL
+		WHERE "id" = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, c.ID, c.CompletedAt); err != nil {
+		return fmt.Errorf("failed to complete campaign: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) ApplyReceipt(ctx context.Context, sessionID string, messageIDs []string, status campaign.RecipientStatus, at time.Time) (int, error) {
+	var (
+		column   string
+		previous []string
+	)
+
+	switch status {
+	case campaign.RecipientDelivered:
+		column = "deliveredAt"
+		previous = []string{string(campaign.RecipientSent)}
+	case campaign.RecipientRead:
+		column = "readAt"
+		previous = []string{string(campaign.RecipientSent), string(campaign.RecipientDelivered)}
+	default:
+		return 0, fmt.Errorf("receipts cannot move recipients to %s", status)
+	}
+
+	query := `
+		UPDATE "zpCampaignRecipient" r
+		SET "status" = $3, "` + column + `" = $4
+		FROM "zpCampaign" c
+		WHERE c."id" = r."campaignId"
+		  AND c."sessionId" = $1
+		  AND r."messageId" = ANY($2)
+		  AND r."status" = ANY($5)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, sessionID, pq.Array(messageIDs), string(status), at, pq.Array(previous))
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply receipt: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+type campaignDB struct {
+	ID              string       `db:"id"`
+	SessionID       string       `db:"sessionId"`
+	Name            string       `db:"name"`
+	Text            string       `db:"text"`
+	Status          string       `db:"status"`
+	IntervalSeconds int          `db:"intervalSeconds"`
+	NextSendAt      time.Time    `db:"nextSendAt"`
+	CompletedAt     sql.NullTime `db:"completedAt"`
+	CreatedAt       time.Time    `db:"createdAt"`
+	UpdatedAt       time.Time    `db:"updatedAt"`
+}
+
+func (c *campaignDB) toDomain() *campaign.Campaign {
+	result := &campaign.Campaign{
+		ID:         c.ID,
+		SessionID:  c.SessionID,
+		Name:       c.Name,
+		Text:       c.Text,
+		Status:     campaign.Status(c.Status),
+		Interval:   time.Duration(c.IntervalSeconds) * time.Second,
+		NextSendAt: c.NextSendAt,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+	}
+
+	if c.CompletedAt.Valid {
+		result.CompletedAt = &c.CompletedAt.Time
+	}
+
+	return result
+}
+
+type campaignRecipientDB struct {
+	ID          int64          `db:"id"`
+	CampaignID  string         `db:"campaignId"`
+	Phone       string         `db:"phone"`
+	JID         sql.NullString `db:"jid"`
+	Variables   []byte         `db:"variables"`
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	MessageID   sql.NullString `db:"messageId"`
+	Error       sql.NullString `db:"error"`
+	SentAt      sql.NullTime   `db:"sentAt"`
+	DeliveredAt sql.NullTime   `db:"deliveredAt"`
+	ReadAt      sql.NullTime   `db:"readAt"`
+	UpdatedAt   time.Time      `db:"updatedAt"`
+}
+
+func (r *campaignRecipientDB) toDomain() *campaign.Recipient {
+	recipient := &campaign.Recipient{
+		ID:         r.ID,
+		CampaignID: r.CampaignID,
+		Phone:      r.Phone,
+		JID:        r.JID.String,
+		Variables:  map[string]string{},
+		Status:     campaign.RecipientStatus(r.Status),
+		Attempts:   r.Attempts,
+		UpdatedAt:  r.UpdatedAt,
+	}
+
+	_ = json.Unmarshal(r.Variables, &recipient.Variables)
+
+	if r.MessageID.Valid {
+		recipient.MessageID = &r.MessageID.String
+	}
+
+	if r.Error.Valid {
+		recipient.Error = &r.Error.String
+	}
+
+	if r.SentAt.Valid {
+		recipient.SentAt = &r.SentAt.Time
+	}
+
+	if r.DeliveredAt.Valid {
+		recipient.DeliveredAt = &r.DeliveredAt.Time
+	}
+
+	if r.ReadAt.Valid {
+		recipient.ReadAt = &r.ReadAt.Time
+	}
+
+	return recipient
+}
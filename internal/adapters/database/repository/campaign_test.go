@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/shared"
+)
+
+type campaignTest struct {
+	t         *testing.T
+	repo      *CampaignRepository
+	ctx       context.Context
+	sessionID string
+}
+
+func newCampaignTest(t *testing.T) *campaignTest {
+	db := newTestDB(t)
+
+	return &campaignTest{
+		t:         t,
+		repo:      NewCampaignRepository(db),
+		ctx:       context.Background(),
+		sessionID: createTestSession(t, db).ID,
+	}
+}
+
+// create stores a running campaign with a queued recipient for each phone.
+func (c *campaignTest) create(phones ...string) (*campaign.Campaign, []*campaign.Recipient) {
+	c.t.Helper()
+
+	cmp := campaign.NewCampaign(c.sessionID, "launch", "Hello {{phone}}", time.Second)
+
+	recipients := make([]*campaign.Recipient, len(phones))
+	for i, phone := range phones {
+		recipients[i] = campaign.NewRecipient(phone, nil)
+	}
+
+	if err := c.repo.Create(c.ctx, cmp, recipients); err != nil {
+		c.t.Fatalf("Create: %v", err)
+	}
+
+	return cmp, recipients
+}
+
+// expectClaim claims every due campaign and checks they are want.
+func (c *campaignTest) expectClaim(lease time.Duration, want ...*campaign.Campaign) []*campaign.Campaign {
+	c.t.Helper()
+
+	claimed, err := c.repo.ClaimDue(c.ctx, 10, lease)
+	if err != nil {
+		c.t.Fatalf("ClaimDue: %v", err)
+	}
+
+	if len(claimed) != len(want) {
+		c.t.Fatalf("claimed %d campaigns, want %d", len(claimed), len(want))
+	}
+
+	for i := range want {
+		if claimed[i].ID != want[i].ID {
+			c.t.Fatalf("claimed campaign %d is %s, want %s", i, claimed[i].ID, want[i].ID)
+		}
+	}
+
+	return claimed
+}
+
+func (c *campaignTest) release(cmp *campaign.Campaign, nextSendAt time.Time) {
+	c.t.Helper()
+
+	cmp.NextSendAt = nextSendAt
+
+	if err := c.repo.Release(c.ctx, cmp); err != nil {
+		c.t.Fatalf("Release: %v", err)
+	}
+}
+
+func (c *campaignTest) updateStatus(cmp *campaign.Campaign, update func(*campaign.Campaign)) {
+	c.t.Helper()
+
+	update(cmp)
+
+	if err := c.repo.UpdateStatus(c.ctx, cmp); err != nil {
+		c.t.Fatalf("UpdateStatus: %v", err)
+	}
+}
+
+func (c *campaignTest) get(cmp *campaign.Campaign) *campaign.Campaign {
+	c.t.Helper()
+
+	stored, err := c.repo.GetByID(c.ctx, c.sessionID, cmp.ID)
+	if err != nil {
+		c.t.Fatalf("GetByID: %v", err)
+	}
+
+	return stored
+}
+
+func TestCampaignClaimDueLeasesUntilReleased(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, _ := c.create("5511111111111")
+
+	claimed := c.expectClaim(time.Minute, cmp)[0]
+
+	if claimed.Interval != time.Second {
+		t.Errorf("interval = %s, want 1s", claimed.Interval)
+	}
+
+	// A leased campaign is not handed out again until it is released.
+	c.expectClaim(time.Minute)
+
+	c.release(claimed, time.Now().Add(time.Hour))
+	c.expectClaim(time.Minute)
+
+	c.release(claimed, time.Now().Add(-time.Second))
+	c.expectClaim(time.Minute, cmp)
+}
+
+func TestCampaignClaimDueReclaimsExpiredLeases(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, _ := c.create("5511111111111")
+
+	c.expectClaim(10*time.Millisecond, cmp)
+
+	time.Sleep(50 * time.Millisecond)
+
+	c.expectClaim(time.Minute, cmp)
+}
+
+func TestCampaignPauseAndResume(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, _ := c.create("5511111111111")
+
+	c.updateStatus(cmp, (*campaign.Campaign).Pause)
+	c.expectClaim(time.Minute)
+
+	c.updateStatus(cmp, (*campaign.Campaign).Resume)
+	c.expectClaim(time.Minute, cmp)
+}
+
+func TestCampaignNextRecipient(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, recipients := c.create("5511111111111", "5522222222222", "5533333333333")
+
+	next := func() *campaign.Recipient {
+		t.Helper()
+
+		recipient, err := c.repo.NextRecipient(c.ctx, cmp.ID)
+		if err != nil {
+			t.Fatalf("NextRecipient: %v", err)
+		}
+
+		return recipient
+	}
+
+	save := func(recipient *campaign.Recipient) {
+		t.Helper()
+
+		if err := c.repo.SaveRecipient(c.ctx, recipient); err != nil {
+			t.Fatalf("SaveRecipient: %v", err)
+		}
+	}
+
+	first := next()
+	if first == nil || first.ID != recipients[0].ID {
+		t.Fatalf("next recipient = %+v, want %s", first, recipients[0].Phone)
+	}
+
+	// A failed attempt keeps the recipient queued and first in line.
+	first.Attempts++
+	reason := "number is unreachable"
+	first.Error = &reason
+	save(first)
+
+	retried := next()
+	if retried == nil || retried.ID != first.ID || retried.Attempts != 1 || retried.Error == nil {
+		t.Fatalf("next recipient = %+v, want %s after 1 attempt with its error", retried, first.Phone)
+	}
+
+	retried.Fail(reason)
+	save(retried)
+
+	second := next()
+	if second == nil || second.ID != recipients[1].ID {
+		t.Fatalf("next recipient = %+v, want %s", second, recipients[1].Phone)
+	}
+
+	second.Attempts++
+	second.MarkSent("MSG2", time.Now())
+	save(second)
+
+	third := next()
+	if third == nil || third.ID != recipients[2].ID {
+		t.Fatalf("next recipient = %+v, want %s", third, recipients[2].Phone)
+	}
+
+	third.Attempts++
+	third.MarkSent("MSG3", time.Now())
+	save(third)
+
+	if last := next(); last != nil {
+		t.Fatalf("next recipient = %+v, want none left", last)
+	}
+
+	report, err := c.repo.Report(c.ctx, cmp.ID)
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	if report.Total != 3 || report.Sent != 2 || report.Failed != 1 || report.Errors[reason] != 1 {
+		t.Errorf("report = %+v, want 2 sent and 1 failed with its error", report)
+	}
+}
+
+func TestCampaignComplete(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, _ := c.create()
+
+	claimed := c.expectClaim(time.Minute, cmp)[0]
+	claimed.Complete()
+
+	if err := c.repo.Complete(c.ctx, claimed); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	stored := c.get(cmp)
+	if stored.Status != campaign.StatusCompleted || stored.CompletedAt == nil {
+		t.Errorf("status = %s, want %s with its completion time", stored.Status, campaign.StatusCompleted)
+	}
+
+	c.expectClaim(time.Minute)
+
+	// A finished campaign can no longer be paused or resumed.
+	stored.Pause()
+
+	if err := c.repo.UpdateStatus(c.ctx, stored); !errors.Is(err, shared.ErrCampaignFinished) {
+		t.Errorf("UpdateStatus = %v, want %v", err, shared.ErrCampaignFinished)
+	}
+}
+
+func TestCampaignCompleteLeavesPausedCampaigns(t *testing.T) {
+	c := newCampaignTest(t)
+
+	cmp, _ := c.create()
+
+	claimed := c.expectClaim(time.Minute, cmp)[0]
+
+	// Paused after the runner claimed it with no recipient left.
+	c.updateStatus(c.get(cmp), (*campaign.Campaign).Pause)
+
+	claimed.Complete()
+
+	if err := c.repo.Complete(c.ctx, claimed); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	stored := c.get(cmp)
+	if stored.Status != campaign.StatusPaused || stored.CompletedAt != nil {
+		t.Errorf("status = %s, want it left %s", stored.Status, campaign.StatusPaused)
+	}
+
+	// Its lease is released, so it is claimed again once resumed.
+	c.updateStatus(stored, (*campaign.Campaign).Resume)
+	c.expectClaim(time.Minute, cmp)
+}
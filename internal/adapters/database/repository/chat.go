@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/chat"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ChatRepository struct {
+	db *sqlx.DB
+}
+
+func NewChatRepository(db *sqlx.DB) *ChatRepository {
+	return &ChatRepository{
+		db: db,
+	}
+}
+
+func (r *ChatRepository) IncrementUnread(ctx context.Context, sessionID, chatJID string) error {
+	query := `
+		INSERT INTO "zpChat" ("sessionId", "zpChat", "unreadCount")
+		VALUES ($1, $2, 1)
+		ON CONFLICT ("sessionId", "zpChat")
+		DO UPDATE SET "unreadCount" = "zpChat"."unreadCount" + 1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID); err != nil {
+		return fmt.Errorf("failed to increment unread count: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) MarkRead(ctx context.Context, sessionID, chatJID string, readAt time.Time) error {
+	query := `
+		INSERT INTO "zpChat" ("sessionId", "zpChat", "unreadCount", "lastReadAt")
+		VALUES ($1, $2, 0, $3)
+		ON CONFLICT ("sessionId", "zpChat")
+		DO UPDATE SET "unreadCount" = 0, "lastReadAt" = EXCLUDED."lastReadAt"
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, readAt); err != nil {
+		return fmt.Errorf("failed to mark chat as read: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) SetUnread(ctx context.Context, sessionID, chatJID string, count int) error {
+	query := `
+		INSERT INTO "zpChat" ("sessionId", "zpChat", "unreadCount")
+		VALUES ($1, $2, $3)
+		ON CONFLICT ("sessionId", "zpChat")
+		DO UPDATE SET "unreadCount" = EXCLUDED."unreadCount"
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, max(count, 0)); err != nil {
+		return fmt.Errorf("failed to set unread count: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) List(ctx context.Context, sessionID string) ([]*chat.Chat, error) {
+	query := `
+		SELECT "id", "sessionId", "zpChat", "unreadCount", "lastReadAt", "createdAt", "updatedAt"
+		FROM "zpChat"
+		WHERE "sessionId" = $1`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID})
+	query += scope
+
+	var rows []chatDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+
+	chats := make([]*chat.Chat, len(rows))
+	for i := range rows {
+		chats[i] = rows[i].toDomain()
+	}
+
+	return chats, nil
+}
+
+type chatDB struct {
+	ID          string       `db:"id"`
+	SessionID   string       `db:"sessionId"`
+	JID         string       `db:"zpChat"`
+	UnreadCount int          `db:"unreadCount"`
+	LastReadAt  sql.NullTime `db:"lastReadAt"`
+	CreatedAt   time.Time    `db:"createdAt"`
+	UpdatedAt   time.Time    `db:"updatedAt"`
+}
+
+func (c *chatDB) toDomain() *chat.Chat {
+	result := &chat.Chat{
+		ID:          c.ID,
+		SessionID:   c.SessionID,
+		JID:         c.JID,
+		UnreadCount: c.UnreadCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+
+	if c.LastReadAt.Valid {
+		lastReadAt := c.LastReadAt.Time
+		result.LastReadAt = &lastReadAt
+	}
+
+	return result
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ChatwootRepository struct {
+	db *sqlx.DB
+}
+
+func NewChatwootRepository(db *sqlx.DB) *ChatwootRepository {
+	return &ChatwootRepository{
+		db: db,
+	}
+}
+
+const chatwootColumns = `"id", "sessionId", "url", "token", "accountId", "inboxId", "enabled",
+		       "inboxName", "autoCreate", "signMsg", "signDelimiter", "reopenConv",
+		       "convPending", "importContacts", "importMessages", "importDays",
+		       "mergeBrazil", "organization", "logo", "number", "ignoreJids",
+		       "createdAt", "updatedAt", "webhookSecret"`
+
+func (r *ChatwootRepository) Create(ctx context.Context, cfg *chatwoot.Config) error {
+	query := `
+		INSERT INTO "zpChatwoot" (` + chatwootColumns + `)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+			$13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, chatwootArgs(cfg)...)
+	if err != nil {
+		return fmt.Errorf("failed to create chatwoot config: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChatwootRepository) GetBySessionID(ctx context.Context, sessionID string) (*chatwoot.Config, error) {
+	query := `
+		SELECT ` + chatwootColumns + `
+		FROM "zpChatwoot"
+		WHERE "sessionId" = $1`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID})
+	query += scope
+
+	var cfg chatwootDB
+
+	err := r.db.GetContext(ctx, &cfg, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrChatwootConfigNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	return cfg.toDomain(), nil
+}
+
+func (r *ChatwootRepository) Update(ctx context.Context, cfg *chatwoot.Config) error {
+	query := `
+		UPDATE "zpChatwoot" SET
+			"url" = $3,
+			"token" = $4,
+			"accountId" = $5,
+			"inboxId" = $6,
+			"enabled" = $7,
+			"inboxName" = $8,
+			"autoCreate" = $9,
+			"signMsg" = $10,
+			"signDelimiter" = $11,
+			"reopenConv" = $12,
+			"convPending" = $13,
+			"importContacts" = $14,
+			"importMessages" = $15,
+			"importDays" = $16,
+			"mergeBrazil" = $17,
+			"organization" = $18,
+			"logo" = $19,
+			"number" = $20,
+			"ignoreJids" = $21,
+			"updatedAt" = $22,
+			"webhookSecret" = $23
+		WHERE "id" = $1 AND "sessionId" = $2
+	`
+
+	args := chatwootArgs(cfg)
+	args = append(args[:21], cfg.UpdatedAt, cfg.WebhookSecret)
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update chatwoot config: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrChatwootConfigNotFound
+	}
+
+	return nil
+}
+
+func (r *ChatwootRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM "zpChatwoot" WHERE "sessionId" = $1`
+
+	result, err := r.db.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chatwoot config: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrChatwootConfigNotFound
+	}
+
+	return nil
+}
+
+func (r *ChatwootRepository) SaveImport(ctx context.Context, job *chatwoot.ImportJob) error {
+	query := `
+		INSERT INTO "zpChatwootImport" (
+			"id", "sessionId", "status", "phase", "contactsTotal", "contactsImported",
+			"messagesTotal", "messagesImported", "messagesFailed", "cursorTimestamp",
+			"cursorId", "lastError", "startedAt", "finishedAt", "updatedAt"
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			"id" = EXCLUDED."id",
+			"status" = EXCLUDED."status",
+			"phase" = EXCLUDED."phase",
+			"contactsTotal" = EXCLUDED."contactsTotal",
+			"contactsImported" = EXCLUDED."contactsImported",
+			"messagesTotal" = EXCLUDED."messagesTotal",
+			"messagesImported" = EXCLUDED."messagesImported",
+			"messagesFailed" = EXCLUDED."messagesFailed",
+			"cursorTimestamp" = EXCLUDED."cursorTimestamp",
+			"cursorId" = EXCLUDED."cursorId",
+			"lastError" = EXCLUDED."lastError",
+			"startedAt" = EXCLUDED."startedAt",
+			"finishedAt" = EXCLUDED."finishedAt",
+			"updatedAt" = EXCLUDED."updatedAt"
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID,
+		job.SessionID,
+		string(job.Status),
+		string(job.Phase),
+		job.ContactsTotal,
+		job.ContactsImported,
+		job.MessagesTotal,
+		job.MessagesImported,
+		job.MessagesFailed,
+		job.CursorTimestamp,
+		nullString(job.CursorID),
+		nullString(job.LastError),
+		job.StartedAt,
+		job.FinishedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save chatwoot import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ChatwootRepository) GetImport(ctx context.Context, sessionID string) (*chatwoot.ImportJob, error) {
+	query := `
+		SELECT "id", "sessionId", "status", "phase", "contactsTotal", "contactsImported",
+		       "messagesTotal", "messagesImported", "messagesFailed", "cursorTimestamp",
+		       "cursorId", "lastError", "startedAt", "finishedAt", "updatedAt"
+		FROM "zpChatwootImport"
+		WHERE "sessionId" = $1
+	`
+
+	var job chatwootImportDB
+
+	err := r.db.GetContext(ctx, &job, query, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrChatwootImportNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get chatwoot import: %w", err)
+	}
+
+	return job.toDomain(), nil
+}
+
+// chatwootArgs returns the values of chatwootColumns, in order.
+func chatwootArgs(cfg *chatwoot.Config) []interface{} {
+	return []interface{}{
+		cfg.ID,
+		cfg.SessionID,
+		cfg.URL,
+		cfg.Token,
+		cfg.AccountID,
+		nullString(cfg.InboxID),
+		cfg.Enabled,
+		nullString(cfg.InboxName),
+		cfg.AutoCreate,
+		cfg.SignMsg,
+		cfg.SignDelimiter,
+		cfg.ReopenConv,
+		cfg.ConvPending,
+		cfg.ImportContacts,
+		cfg.ImportMessages,
+		cfg.ImportDays,
+		cfg.MergeBrazil,
+		nullString(cfg.Organization),
+		nullString(cfg.Logo),
+		nullString(cfg.Number),
+		pq.StringArray(cfg.IgnoreJIDs),
+		cfg.CreatedAt,
+		cfg.UpdatedAt,
+		cfg.WebhookSecret,
+	}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+type chatwootDB struct {
+	ID             string         `db:"id"`
+	SessionID      string         `db:"sessionId"`
+	URL            string         `db:"url"`
+	Token          string         `db:"token"`
+	AccountID      string         `db:"accountId"`
+	InboxID        sql.NullString `db:"inboxId"`
+	Enabled        bool           `db:"enabled"`
+	InboxName      sql.NullString `db:"inboxName"`
+	AutoCreate     sql.NullBool   `db:"autoCreate"`
+	SignMsg        sql.NullBool   `db:"signMsg"`
+	SignDelimiter  sql.NullString `db:"signDelimiter"`
+	ReopenConv     sql.NullBool   `db:"reopenConv"`
+	ConvPending    sql.NullBool   `db:"convPending"`
+	ImportContacts sql.NullBool   `db:"importContacts"`
+	ImportMessages sql.NullBool   `db:"importMessages"`
+	ImportDays     sql.NullInt64  `db:"importDays"`
+	MergeBrazil    sql.NullBool   `db:"mergeBrazil"`
+	Organization   sql.NullString `db:"organization"`
+	Logo           sql.NullString `db:"logo"`
+	Number         sql.NullString `db:"number"`
+	IgnoreJIDs     pq.StringArray `db:"ignoreJids"`
+	CreatedAt      time.Time      `db:"createdAt"`
+	UpdatedAt      time.Time      `db:"updatedAt"`
+	WebhookSecret  string         `db:"webhookSecret"`
+}
+
+func (c *chatwootDB) toDomain() *chatwoot.Config {
+	signDelimiter := chatwoot.DefaultSignDelimiter
+	if c.SignDelimiter.Valid {
+		signDelimiter = c.SignDelimiter.String
+	}
+
+	importDays := 60
+	if c.ImportDays.Valid {
+		importDays = int(c.ImportDays.Int64)
+	}
+
+	return &chatwoot.Config{
+		ID:             c.ID,
+		SessionID:      c.SessionID,
+		URL:            c.URL,
+		Token:          c.Token,
+		AccountID:      c.AccountID,
+		InboxID:        c.InboxID.String,
+		Enabled:        c.Enabled,
+		InboxName:      c.InboxName.String,
+		AutoCreate:     c.AutoCreate.Bool,
+		SignMsg:        c.SignMsg.Bool,
+		SignDelimiter:  signDelimiter,
+		ReopenConv:     !c.ReopenConv.Valid || c.ReopenConv.Bool,
+		ConvPending:    c.ConvPending.Bool,
+		ImportContacts: c.ImportContacts.Bool,
+		ImportMessages: c.ImportMessages.Bool,
+		ImportDays:     importDays,
+		MergeBrazil:    !c.MergeBrazil.Valid || c.MergeBrazil.Bool,
+		Organization:   c.Organization.String,
+		Logo:           c.Logo.String,
+		Number:         c.Number.String,
+		IgnoreJIDs:     c.IgnoreJIDs,
+		WebhookSecret:  c.WebhookSecret,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+type chatwootImportDB struct {
+	ID               string         `db:"id"`
+	SessionID        string         `db:"sessionId"`
+	Status           string         `db:"status"`
+	Phase            string         `db:"phase"`
+	ContactsTotal    int            `db:"contactsTotal"`
+	ContactsImported int            `db:"contactsImported"`
+	MessagesTotal    int            `db:"messagesTotal"`
+	MessagesImported int            `db:"messagesImported"`
+	MessagesFailed   int            `db:"messagesFailed"`
+	CursorTimestamp  sql.NullTime   `db:"cursorTimestamp"`
+	CursorID         sql.NullString `db:"cursorId"`
+	LastError        sql.NullString `db:"lastError"`
+	StartedAt        time.Time      `db:"startedAt"`
+	FinishedAt       sql.NullTime   `db:"finishedAt"`
+	UpdatedAt        time.Time      `db:"updatedAt"`
+}
+
+func (j *chatwootImportDB) toDomain() *chatwoot.ImportJob {
+	job := &chatwoot.ImportJob{
+		ID:               j.ID,
+		SessionID:        j.SessionID,
+		Status:           chatwoot.ImportStatus(j.Status),
+		Phase:            chatwoot.ImportPhase(j.Phase),
+		ContactsTotal:    j.ContactsTotal,
+		ContactsImported: j.ContactsImported,
+		MessagesTotal:    j.MessagesTotal,
+		MessagesImported: j.MessagesImported,
+		MessagesFailed:   j.MessagesFailed,
+		CursorID:         j.CursorID.String,
+		LastError:        j.LastError.String,
+		StartedAt:        j.StartedAt,
+		UpdatedAt:        j.UpdatedAt,
+	}
+
+	if j.CursorTimestamp.Valid {
+		job.CursorTimestamp = &j.CursorTimestamp.Time
+	}
+
+	if j.FinishedAt.Valid {
+		job.FinishedAt = &j.FinishedAt.Time
+	}
+
+	return job
+}
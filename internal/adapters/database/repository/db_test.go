@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/database"
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/tenant"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// testDatabaseURLEnv names the Postgres database the repository tests run
+// against. Tests that need a database are skipped when it is not set.
+const testDatabaseURLEnv = "ZPWOOT_TEST_DATABASE_URL"
+
+// newTestDB returns a connection to a schema of its own, migrated to the
+// latest version and dropped when the test ends.
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	databaseURL := os.Getenv(testDatabaseURLEnv)
+	if databaseURL == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	admin, err := sqlx.Connect("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("zpwoot_test_%d", time.Now().UnixNano())
+
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		_ = admin.Close()
+		t.Fatalf("failed to create schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("failed to drop schema %s: %v", schema, err)
+		}
+
+		_ = admin.Close()
+	})
+
+	db, err := sqlx.Connect("postgres", withSearchPath(databaseURL, schema))
+	if err != nil {
+		t.Fatalf("failed to connect to schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := database.NewMigrator(&database.Database{DB: db}, logger.New()).RunMigrations(); err != nil {
+		t.Fatalf("failed to migrate schema %s: %v", schema, err)
+	}
+
+	return db
+}
+
+// withSearchPath makes every connection of a pool use schema, keeping public
+// for the functions of installed extensions.
+func withSearchPath(databaseURL, schema string) string {
+	searchPath := schema + ",public"
+
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		if parsed, err := url.Parse(databaseURL); err == nil {
+			query := parsed.Query()
+			query.Set("search_path", searchPath)
+			parsed.RawQuery = query.Encode()
+
+			return parsed.String()
+		}
+	}
+
+	return databaseURL + " search_path=" + searchPath
+}
+
+// createTestSession stores a session of the default tenant, which rows of
+// most tables refer to.
+func createTestSession(t *testing.T, db *sqlx.DB) *session.Session {
+	t.Helper()
+
+	sess := session.NewSession("session-" + fmt.Sprint(time.Now().UnixNano()))
+	sess.TenantID = tenant.DefaultID
+
+	if err := NewSessionRepository(db).Create(context.Background(), sess); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	return sess
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/media"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const mediaColumns = `"sessionId", "zpMessageId", "zpChat", "zpSender", "zpFromMe", "zpIsGroup",
+		       "type", "mimeType", "fileName", "size", "sha256", "descriptor", "storageKey",
+		       "status", "error", "downloadedAt", "createdAt", "updatedAt"`
+
+type MediaRepository struct {
+	db *sqlx.DB
+}
+
+func NewMediaRepository(db *sqlx.DB) *MediaRepository {
+	return &MediaRepository{
+		db: db,
+	}
+}
+
+func (r *MediaRepository) Create(ctx context.Context, m *media.Media) (bool, error) {
+	query := `
+		INSERT INTO "zpMedia" (
+			"sessionId", "zpMessageId", "zpChat", "zpSender", "zpFromMe", "zpIsGroup",
+			"type", "mimeType", "fileName", "size", "sha256", "descriptor", "status",
+			"createdAt", "updatedAt"
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)
+		ON CONFLICT ("sessionId", "zpMessageId") DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		m.SessionID,
+		m.MessageID,
+		m.Chat,
+		m.Sender,
+		m.FromMe,
+		m.IsGroup,
+		m.Type,
+		nullString(m.MimeType),
+		nullString(m.FileName),
+		m.Size,
+		nullString(m.SHA256),
+		m.Descriptor,
+		string(m.Status),
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create media: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *MediaRepository) GetByMessageID(ctx context.Context, sessionID, messageID string) (*media.Media, error) {
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM "zpMedia"
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID, messageID})
+	query += scope
+
+	var row mediaDB
+
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMediaNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *MediaRepository) Save(ctx context.Context, m *media.Media) error {
+	query := `
+		UPDATE "zpMedia" SET
+			"size" = $3,
+			"descriptor" = $4,
+			"storageKey" = $5,
+			"status" = $6,
+			"error" = $7,
+			"downloadedAt" = $8
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		m.SessionID,
+		m.MessageID,
+		m.Size,
+		m.Descriptor,
+		nullString(m.StorageKey),
+		string(m.Status),
+		m.Error,
+		m.DownloadedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save media: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+func (r *MediaRepository) ListStoredBefore(ctx context.Context, before time.Time, limit int) ([]*media.Media, error) {
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM "zpMedia"
+		WHERE "status" = 'stored' AND "downloadedAt" < $1
+		ORDER BY "downloadedAt"
+		LIMIT $2
+	`
+
+	var rows []mediaDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, before, limit); err != nil {
+		return nil, fmt.Errorf("failed to list stored media: %w", err)
+	}
+
+	items := make([]*media.Media, len(rows))
+	for i := range rows {
+		items[i] = rows[i].toDomain()
+	}
+
+	return items, nil
+}
+
+type mediaDB struct {
+	SessionID    string         `db:"sessionId"`
+	MessageID    string         `db:"zpMessageId"`
+	Chat         string         `db:"zpChat"`
+	Sender       string         `db:"zpSender"`
+	FromMe       bool           `db:"zpFromMe"`
+	IsGroup      bool           `db:"zpIsGroup"`
+	Type         string         `db:"type"`
+	MimeType     sql.NullString `db:"mimeType"`
+	FileName     sql.NullString `db:"fileName"`
+	Size         int64          `db:"size"`
+	SHA256       sql.NullString `db:"sha256"`
+	Descriptor   []byte         `db:"descriptor"`
+	StorageKey   sql.NullString `db:"storageKey"`
+	Status       string         `db:"status"`
+	Error        sql.NullString `db:"error"`
+	DownloadedAt sql.NullTime   `db:"downloadedAt"`
+	CreatedAt    time.Time      `db:"createdAt"`
+	UpdatedAt    time.Time      `db:"updatedAt"`
+}
+
+func (m *mediaDB) toDomain() *media.Media {
+	return &media.Media{
+		SessionID:    m.SessionID,
+		MessageID:    m.MessageID,
+		Chat:         m.Chat,
+		Sender:       m.Sender,
+		FromMe:       m.FromMe,
+		IsGroup:      m.IsGroup,
+		Type:         m.Type,
+		MimeType:     m.MimeType.String,
+		FileName:     m.FileName.String,
+		Size:         m.Size,
+		SHA256:       m.SHA256.String,
+		Descriptor:   m.Descriptor,
+		StorageKey:   m.StorageKey.String,
+		Status:       media.Status(m.Status),
+		Error:        nullStringPtr(m.Error),
+		DownloadedAt: nullTimePtr(m.DownloadedAt),
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
@@ -0,0 +1,530 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type MessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewMessageRepository(db *sqlx.DB) *MessageRepository {
+	return &MessageRepository{
+		db: db,
+	}
+}
+
+const insertMessageQuery = `
+	INSERT INTO "zpMessage" (
+		"id", "sessionId", "zpMessageId", "zpSender", "zpChat",
+		"zpTimestamp", "zpFromMe", "zpType", "content", "syncStatus",
+		"createdAt", "updatedAt"
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+	)
+	ON CONFLICT ("sessionId", "zpMessageId") DO NOTHING
+`
+
+func (r *MessageRepository) Create(ctx context.Context, msg *message.Message) (bool, error) {
+	result, err := r.db.ExecContext(ctx, insertMessageQuery, messageInsertArgs(msg)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *MessageRepository) CreateBatch(ctx context.Context, messages []*message.Message) (int, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.PreparexContext(ctx, insertMessageQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare message insert: %w", err)
+	}
+	defer stmt.Close()
+
+	inserted := 0
+
+	for _, msg := range messages {
+		result, err := stmt.ExecContext(ctx, messageInsertArgs(msg)...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create message %s: %w", msg.MessageID, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		inserted += int(rowsAffected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit messages: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (r *MessageRepository) GetByMessageID(ctx context.Context, sessionID, messageID string) (*message.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID, messageID})
+	query += scope
+
+	var msg messageDB
+
+	err := r.db.GetContext(ctx, &msg, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMessageNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return msg.toDomain(), nil
+}
+
+func (r *MessageRepository) GetOldestInChat(ctx context.Context, sessionID, chatJID string) (*message.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE "sessionId" = $1 AND "zpChat" = $2
+		ORDER BY "zpTimestamp" ASC
+		LIMIT 1
+	`
+
+	var msg messageDB
+
+	err := r.db.GetContext(ctx, &msg, query, sessionID, chatJID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMessageNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get oldest message: %w", err)
+	}
+
+	return msg.toDomain(), nil
+}
+
+func (r *MessageRepository) GetLatestInConversation(ctx context.Context, sessionID string, cwConversationID int) (*message.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE "sessionId" = $1 AND "cwConversationId" = $2
+		ORDER BY "zpTimestamp" DESC
+		LIMIT 1
+	`
+
+	var msg messageDB
+
+	err := r.db.GetContext(ctx, &msg, query, sessionID, cwConversationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMessageNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get latest conversation message: %w", err)
+	}
+
+	return msg.toDomain(), nil
+}
+
+func (r *MessageRepository) List(ctx context.Context, q *message.Query) ([]*message.Message, error) {
+	conditions := []string{`"sessionId" = $1`, `"zpChat" = $2`}
+	args := []interface{}{q.SessionID, q.Chat}
+
+	addCondition := func(format string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+
+		conditions = append(conditions, fmt.Sprintf(format, placeholders...))
+	}
+
+	if q.Type != "" {
+		addCondition(`"zpType" = %s`, q.Type)
+	}
+
+	if q.FromMe != nil {
+		addCondition(`"zpFromMe" = %s`, *q.FromMe)
+	}
+
+	if q.Since != nil {
+		addCondition(`"zpTimestamp" >= %s`, *q.Since)
+	}
+
+	if q.Until != nil {
+		addCondition(`"zpTimestamp" <= %s`, *q.Until)
+	}
+
+	if q.Search != "" {
+		addCondition(`to_tsvector('simple', COALESCE("content", '')) @@ plainto_tsquery('simple', %s)`, q.Search)
+	}
+
+	if q.Before != nil {
+		addCondition(`("zpTimestamp", "id") < (%s, %s::uuid)`, q.Before.Timestamp, q.Before.ID)
+	}
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, args)
+	args = append(args, q.Limit)
+
+	query := `
+		SELECT ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE ` + strings.Join(conditions, " AND ") + scope + `
+		ORDER BY "zpTimestamp" DESC, "id" DESC
+		LIMIT $` + strconv.Itoa(len(args))
+
+	var rows []messageDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	messages := make([]*message.Message, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toDomain()
+	}
+
+	return messages, nil
+}
+
+// ListLastPerChat returns the most recent message of every chat of a session.
+func (r *MessageRepository) ListLastPerChat(ctx context.Context, sessionID string) ([]*message.Message, error) {
+	query := `
+		SELECT DISTINCT ON ("zpChat") ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE "sessionId" = $1`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID})
+	query += scope + `
+		ORDER BY "zpChat", "zpTimestamp" DESC, "id" DESC
+	`
+
+	var rows []messageDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list last messages: %w", err)
+	}
+
+	messages := make([]*message.Message, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toDomain()
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepository) ListUnsynced(ctx context.Context, q *message.UnsyncedQuery) ([]*message.Message, error) {
+	conditions, args := unsyncedConditions(q)
+	args = append(args, q.Limit)
+
+	query := `
+		SELECT ` + messageColumns + `
+		FROM "zpMessage"
+		WHERE ` + conditions + `
+		ORDER BY "zpTimestamp" ASC, "id" ASC
+		LIMIT $` + strconv.Itoa(len(args))
+
+	var rows []messageDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list unsynced messages: %w", err)
+	}
+
+	messages := make([]*message.Message, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toDomain()
+	}
+
+	return messages, nil
+}
+
+func (r *MessageRepository) CountUnsynced(ctx context.Context, q *message.UnsyncedQuery) (int, error) {
+	conditions, args := unsyncedConditions(q)
+
+	query := `SELECT COUNT(*) FROM "zpMessage" WHERE ` + conditions
+
+	var count int
+
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to count unsynced messages: %w", err)
+	}
+
+	return count, nil
+}
+
+func unsyncedConditions(q *message.UnsyncedQuery) (string, []interface{}) {
+	conditions := `"sessionId" = $1 AND "syncStatus" <> $2 AND "zpTimestamp" BETWEEN $3 AND $4`
+	args := []interface{}{q.SessionID, string(message.SyncStatusSynced), q.Since, q.Until}
+
+	if q.After != nil {
+		conditions += ` AND ("zpTimestamp", "id") > ($5, $6::uuid)`
+		args = append(args, q.After.Timestamp, q.After.ID)
+	}
+
+	return conditions, args
+}
+
+func (r *MessageRepository) MarkSynced(ctx context.Context, sessionID, messageID string, cwMessageID, cwConversationID int) error {
+	query := `
+		UPDATE "zpMessage" SET
+			"cwMessageId" = $3,
+			"cwConversationId" = $4,
+			"syncStatus" = $5,
+			"syncedAt" = NOW(),
+			"updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2
+	`
+
+	return r.updateSync(ctx, query, sessionID, messageID, cwMessageID, cwConversationID, string(message.SyncStatusSynced))
+}
+
+func (r *MessageRepository) MarkSyncFailed(ctx context.Context, sessionID, messageID string) error {
+	query := `
+		UPDATE "zpMessage" SET
+			"syncStatus" = $3,
+			"updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2
+	`
+
+	return r.updateSync(ctx, query, sessionID, messageID, string(message.SyncStatusFailed))
+}
+
+func (r *MessageRepository) updateSync(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update message sync status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// ApplyReceipt only writes a receipt when it moves the recipient's status
+// forward, keeping the first time each status was reached.
+func (r *MessageRepository) ApplyReceipt(ctx context.Context, sessionID string, messageIDs []string, recipient string, status message.Status, timestamp time.Time) ([]string, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO "zpMessageReceipt" (
+			"sessionId", "zpMessageId", "zpRecipient", "status",
+			"deliveredAt", "readAt", "playedAt", "updatedAt"
+		)
+		SELECT m."sessionId", m."zpMessageId", $3::varchar, $4::varchar,
+		       $5::timestamptz, $6::timestamptz, $7::timestamptz, NOW()
+		FROM "zpMessage" m
+		WHERE m."sessionId" = $1 AND m."zpMessageId" = ANY($2) AND m."zpFromMe"
+		ON CONFLICT ("sessionId", "zpMessageId", "zpRecipient") DO UPDATE SET
+			"status" = EXCLUDED."status",
+			"deliveredAt" = COALESCE("zpMessageReceipt"."deliveredAt", EXCLUDED."deliveredAt"),
+			"readAt" = COALESCE("zpMessageReceipt"."readAt", EXCLUDED."readAt"),
+			"playedAt" = COALESCE("zpMessageReceipt"."playedAt", EXCLUDED."playedAt")
+		WHERE ` + receiptRank(`EXCLUDED."status"`) + ` > ` + receiptRank(`"zpMessageReceipt"."status"`) + `
+		RETURNING "zpMessageId"
+	`
+
+	receipt := message.NewReceipt(sessionID, "", recipient, status, timestamp)
+
+	var updated []string
+
+	err := r.db.SelectContext(ctx, &updated, query,
+		sessionID, pq.Array(messageIDs), recipient, string(status),
+		receipt.DeliveredAt, receipt.ReadAt, receipt.PlayedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply receipt: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *MessageRepository) ListReceipts(ctx context.Context, sessionID, messageID string) ([]*message.Receipt, error) {
+	query := `
+		SELECT "sessionId", "zpMessageId", "zpRecipient", "status",
+		       "deliveredAt", "readAt", "playedAt", "updatedAt"
+		FROM "zpMessageReceipt"
+		WHERE "sessionId" = $1 AND "zpMessageId" = $2`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID, messageID})
+	query += scope + `
+		ORDER BY "zpRecipient"
+	`
+
+	var rows []receiptDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	receipts := make([]*message.Receipt, len(rows))
+	for i := range rows {
+		receipts[i] = rows[i].toDomain()
+	}
+
+	return receipts, nil
+}
+
+// receiptRank orders the receipt statuses in SQL the way message.Status
+// orders them in Go.
+func receiptRank(column string) string {
+	return `array_position(ARRAY['delivered', 'read', 'played']::varchar[], ` + column + `)`
+}
+
+const messageColumns = `"id", "sessionId", "zpMessageId", "zpSender", "zpChat",
+		       "zpTimestamp", "zpFromMe", "zpType", "content", "cwMessageId",
+		       "cwConversationId", "syncStatus", "createdAt", "updatedAt", "syncedAt"`
+
+func messageInsertArgs(msg *message.Message) []interface{} {
+	syncStatus := msg.SyncStatus
+	if syncStatus == "" {
+		syncStatus = message.SyncStatusPending
+	}
+
+	createdAt := msg.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	updatedAt := msg.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+
+	return []interface{}{
+		msg.ID,
+		msg.SessionID,
+		msg.MessageID,
+		msg.Sender,
+		msg.Chat,
+		msg.Timestamp,
+		msg.FromMe,
+		msg.Type,
+		msg.Content,
+		string(syncStatus),
+		createdAt,
+		updatedAt,
+	}
+}
+
+type messageDB struct {
+	ID               string         `db:"id"`
+	SessionID        string         `db:"sessionId"`
+	MessageID        string         `db:"zpMessageId"`
+	Sender           string         `db:"zpSender"`
+	Chat             string         `db:"zpChat"`
+	Timestamp        time.Time      `db:"zpTimestamp"`
+	FromMe           bool           `db:"zpFromMe"`
+	Type             string         `db:"zpType"`
+	Content          sql.NullString `db:"content"`
+	CwMessageID      sql.NullInt64  `db:"cwMessageId"`
+	CwConversationID sql.NullInt64  `db:"cwConversationId"`
+	SyncStatus       string         `db:"syncStatus"`
+	CreatedAt        time.Time      `db:"createdAt"`
+	UpdatedAt        time.Time      `db:"updatedAt"`
+	SyncedAt         sql.NullTime   `db:"syncedAt"`
+}
+
+func (m *messageDB) toDomain() *message.Message {
+	msg := &message.Message{
+		ID:         m.ID,
+		SessionID:  m.SessionID,
+		MessageID:  m.MessageID,
+		Sender:     m.Sender,
+		Chat:       m.Chat,
+		Timestamp:  m.Timestamp,
+		FromMe:     m.FromMe,
+		Type:       m.Type,
+		Content:    m.Content.String,
+		SyncStatus: message.SyncStatus(m.SyncStatus),
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+
+	if m.CwMessageID.Valid {
+		id := int(m.CwMessageID.Int64)
+		msg.CwMessageID = &id
+	}
+
+	if m.CwConversationID.Valid {
+		id := int(m.CwConversationID.Int64)
+		msg.CwConversationID = &id
+	}
+
+	if m.SyncedAt.Valid {
+		syncedAt := m.SyncedAt.Time
+		msg.SyncedAt = &syncedAt
+	}
+
+	return msg
+}
+
+type receiptDB struct {
+	SessionID   string       `db:"sessionId"`
+	MessageID   string       `db:"zpMessageId"`
+	Recipient   string       `db:"zpRecipient"`
+	Status      string       `db:"status"`
+	DeliveredAt sql.NullTime `db:"deliveredAt"`
+	ReadAt      sql.NullTime `db:"readAt"`
+	PlayedAt    sql.NullTime `db:"playedAt"`
+	UpdatedAt   time.Time    `db:"updatedAt"`
+}
+
+func (r *receiptDB) toDomain() *message.Receipt {
+	return &message.Receipt{
+		SessionID:   r.SessionID,
+		MessageID:   r.MessageID,
+		Recipient:   r.Recipient,
+		Status:      message.Status(r.Status),
+		DeliveredAt: nullTimePtr(r.DeliveredAt),
+		ReadAt:      nullTimePtr(r.ReadAt),
+		PlayedAt:    nullTimePtr(r.PlayedAt),
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
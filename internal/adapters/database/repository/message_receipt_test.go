@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/domain/message"
+)
+
+const (
+	receiptChat        = "5511999999999@s.whatsapp.net"
+	receiptGroup       = "120363000000000000@g.us"
+	receiptParticipant = "5522888888888@s.whatsapp.net"
+	receiptOther       = "5533777777777@s.whatsapp.net"
+)
+
+type receiptTest struct {
+	t         *testing.T
+	repo      *MessageRepository
+	ctx       context.Context
+	sessionID string
+}
+
+func newReceiptTest(t *testing.T) *receiptTest {
+	db := newTestDB(t)
+
+	return &receiptTest{
+		t:         t,
+		repo:      NewMessageRepository(db),
+		ctx:       context.Background(),
+		sessionID: createTestSession(t, db).ID,
+	}
+}
+
+func (r *receiptTest) store(messageID, chat string, fromMe bool) {
+	r.t.Helper()
+
+	msg := message.NewMessage(r.sessionID, messageID, chat, receiptChat, "text", "hello", fromMe, time.Now())
+
+	if _, err := r.repo.Create(r.ctx, msg); err != nil {
+		r.t.Fatalf("Create: %v", err)
+	}
+}
+
+// apply records a receipt and checks it moved exactly want forward.
+func (r *receiptTest) apply(recipient string, status message.Status, at time.Time, messageIDs []string, want ...string) {
+	r.t.Helper()
+
+	updated, err := r.repo.ApplyReceipt(r.ctx, r.sessionID, messageIDs, recipient, status, at)
+	if err != nil {
+		r.t.Fatalf("ApplyReceipt: %v", err)
+	}
+
+	moved := make(map[string]bool, len(updated))
+	for _, id := range updated {
+		moved[id] = true
+	}
+
+	if len(updated) != len(want) {
+		r.t.Fatalf("%s receipt from %s moved %v, want %v", status, recipient, updated, want)
+	}
+
+	for _, id := range want {
+		if !moved[id] {
+			r.t.Fatalf("%s receipt from %s moved %v, want %v", status, recipient, updated, want)
+		}
+	}
+}
+
+func (r *receiptTest) receipts(messageID string) map[string]*message.Receipt {
+	r.t.Helper()
+
+	receipts, err := r.repo.ListReceipts(r.ctx, r.sessionID, messageID)
+	if err != nil {
+		r.t.Fatalf("ListReceipts: %v", err)
+	}
+
+	byRecipient := make(map[string]*message.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		byRecipient[receipt.Recipient] = receipt
+	}
+
+	return byRecipient
+}
+
+func sameTime(got *time.Time, want time.Time) bool {
+	return got != nil && got.Equal(want)
+}
+
+func TestApplyReceiptMovesForwardOnly(t *testing.T) {
+	r := newReceiptTest(t)
+	r.store("MSG1", receiptChat, true)
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	deliveredAt := base
+	readAt := base.Add(time.Minute)
+
+	r.apply(receiptChat, message.StatusDelivered, deliveredAt, []string{"MSG1"}, "MSG1")
+	r.apply(receiptChat, message.StatusRead, readAt, []string{"MSG1"}, "MSG1")
+
+	// Late or repeated receipts neither move the message back nor change
+	// when each status was first reached.
+	r.apply(receiptChat, message.StatusDelivered, base.Add(2*time.Minute), []string{"MSG1"})
+	r.apply(receiptChat, message.StatusRead, base.Add(3*time.Minute), []string{"MSG1"})
+
+	receipt := r.receipts("MSG1")[receiptChat]
+	if receipt == nil {
+		t.Fatalf("no receipt stored for %s", receiptChat)
+	}
+
+	if receipt.Status != message.StatusRead {
+		t.Errorf("status = %s, want %s", receipt.Status, message.StatusRead)
+	}
+
+	if !sameTime(receipt.DeliveredAt, deliveredAt) || !sameTime(receipt.ReadAt, readAt) || receipt.PlayedAt != nil {
+		t.Errorf("delivered at %v and read at %v, want %s and %s", receipt.DeliveredAt, receipt.ReadAt, deliveredAt, readAt)
+	}
+
+	playedAt := base.Add(4 * time.Minute)
+	r.apply(receiptChat, message.StatusPlayed, playedAt, []string{"MSG1"}, "MSG1")
+
+	receipt = r.receipts("MSG1")[receiptChat]
+	if receipt.Status != message.StatusPlayed || !sameTime(receipt.PlayedAt, playedAt) || !sameTime(receipt.ReadAt, readAt) {
+		t.Errorf("receipt = %+v, want played at %s, still read at %s", receipt, playedAt, readAt)
+	}
+}
+
+func TestApplyReceiptSkippingDelivered(t *testing.T) {
+	r := newReceiptTest(t)
+	r.store("MSG1", receiptChat, true)
+
+	readAt := time.Now().Truncate(time.Millisecond)
+	r.apply(receiptChat, message.StatusRead, readAt, []string{"MSG1"}, "MSG1")
+
+	// A read receipt counts as delivery.
+	receipt := r.receipts("MSG1")[receiptChat]
+	if receipt == nil || !sameTime(receipt.DeliveredAt, readAt) || !sameTime(receipt.ReadAt, readAt) {
+		t.Errorf("receipt = %+v, want delivered and read at %s", receipt, readAt)
+	}
+
+	r.apply(receiptChat, message.StatusDelivered, readAt.Add(time.Minute), []string{"MSG1"})
+}
+
+func TestApplyReceiptPerGroupParticipant(t *testing.T) {
+	r := newReceiptTest(t)
+	r.store("MSG1", receiptGroup, true)
+
+	now := time.Now()
+
+	r.apply(receiptParticipant, message.StatusRead, now, []string{"MSG1"}, "MSG1")
+	r.apply(receiptOther, message.StatusDelivered, now, []string{"MSG1"}, "MSG1")
+
+	receipts := r.receipts("MSG1")
+	if len(receipts) != 2 {
+		t.Fatalf("stored %d receipts, want one per participant", len(receipts))
+	}
+
+	if receipts[receiptParticipant].Status != message.StatusRead || receipts[receiptOther].Status != message.StatusDelivered {
+		t.Errorf("participants are %s and %s, want read and delivered",
+			receipts[receiptParticipant].Status, receipts[receiptOther].Status)
+	}
+
+	list := make([]*message.Receipt, 0, len(receipts))
+	for _, receipt := range receipts {
+		list = append(list, receipt)
+	}
+
+	if status := message.OverallStatus(list); status != message.StatusRead {
+		t.Errorf("overall status = %s, want %s", status, message.StatusRead)
+	}
+}
+
+func TestApplyReceiptIgnoresOtherMessages(t *testing.T) {
+	r := newReceiptTest(t)
+	r.store("SENT", receiptChat, true)
+	r.store("RECEIVED", receiptChat, false)
+
+	r.apply(receiptChat, message.StatusRead, time.Now(), []string{"SENT", "RECEIVED", "UNKNOWN"}, "SENT")
+
+	if receipts := r.receipts("RECEIVED"); len(receipts) != 0 {
+		t.Errorf("stored %d receipts for a received message, want none", len(receipts))
+	}
+
+	r.apply(receiptChat, message.StatusRead, time.Now(), nil)
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/domain/shared"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const scheduledMessageColumns = `"id", "sessionId", "phone", "type", "payload", "sendAt", "timezone",
+		       "onDisconnected", "status", "attempts", "nextAttemptAt", "messageId", "lastError",
+		       "sentAt", "createdAt", "updatedAt"`
+
+type ScheduledMessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduledMessageRepository(db *sqlx.DB) *ScheduledMessageRepository {
+	return &ScheduledMessageRepository{
+		db: db,
+	}
+}
+
+func (r *ScheduledMessageRepository) Create(ctx context.Context, msg *schedule.ScheduledMessage) error {
+	query := `
+		INSERT INTO "zpScheduledMessage" (
+			"id", "sessionId", "phone", "type", "payload", "sendAt", "timezone",
+			"onDisconnected", "status", "attempts", "nextAttemptAt", "createdAt", "updatedAt"
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		msg.ID,
+		msg.SessionID,
+		msg.Phone,
+		msg.Type,
+		msg.Payload,
+		msg.SendAt,
+		msg.Timezone,
+		string(msg.OnDisconnected),
+		string(msg.Status),
+		msg.Attempts,
+		msg.NextAttemptAt,
+		msg.CreatedAt,
+		msg.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduledMessageRepository) GetByID(ctx context.Context, sessionID, id string) (*schedule.ScheduledMessage, error) {
+	query := `
+		SELECT ` + scheduledMessageColumns + `
+		FROM "zpScheduledMessage"
+		WHERE "sessionId" = $1 AND "id" = $2`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID, id})
+	query += scope
+
+	var row scheduledMessageDB
+
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrScheduledMessageNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *ScheduledMessageRepository) List(ctx context.Context, filter *schedule.ListFilter) ([]*schedule.ScheduledMessage, int, error) {
+	where, args := scheduledMessageConditions(ctx, filter)
+
+	countQuery := `SELECT COUNT(*) FROM "zpScheduledMessage"` + where
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count scheduled messages: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := `
+		SELECT ` + scheduledMessageColumns + `
+		FROM "zpScheduledMessage"` + where + `
+		ORDER BY "sendAt", "id"
+		LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	var rows []scheduledMessageDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	messages := make([]*schedule.ScheduledMessage, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toDomain()
+	}
+
+	return messages, total, nil
+}
+
+func (r *ScheduledMessageRepository) UpdatePending(ctx context.Context, msg *schedule.ScheduledMessage) error {
+	query := `
+		UPDATE "zpScheduledMessage"
+		SET "sendAt" = $3, "timezone" = $4, "status" = $5, "attempts" = $6,
+		    "nextAttemptAt" = $7, "lastError" = $8
+		WHERE "sessionId" = $1 AND "id" = $2
+		  AND "status" = 'pending'
+		  AND ("lockedUntil" IS NULL OR "lockedUntil" < NOW())
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		msg.SessionID,
+		msg.ID,
+		msg.SendAt,
+		msg.Timezone,
+		string(msg.Status),
+		msg.Attempts,
+		msg.NextAttemptAt,
+		msg.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled message: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return shared.ErrScheduledMessageNotPending
+	}
+
+	return nil
+}
+
+// ClaimDue leases due messages with FOR UPDATE SKIP LOCKED, so several
+// instances sharing the database never send the same message twice.
+func (r *ScheduledMessageRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*schedule.ScheduledMessage, error) {
+	query := `
+		WITH due AS (
+			SELECT "id"
+			FROM "zpScheduledMessage"
+			WHERE "status" = 'pending'
+			  AND "nextAttemptAt" <= NOW()
+			  AND ("lockedUntil" IS NULL OR "lockedUntil" < NOW())
+			ORDER BY "nextAttemptAt"
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE "zpScheduledMessage" m
+		SET "lockedUntil" = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM due
+		WHERE m."id" = due."id"
+		RETURNING m."id", m."sessionId", m."phone", m."type", m."payload", m."sendAt", m."timezone",
+		          m."onDisconnected", m."status", m."attempts", m."nextAttemptAt", m."messageId",
+		          m."lastError", m."sentAt", m."createdAt", m."updatedAt"
+	`
+
+	var rows []scheduledMessageDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, limit, lease.Milliseconds()); err != nil {
+		return nil, fmt.Errorf("failed to claim scheduled messages: %w", err)
+	}
+
+	messages := make([]*schedule.ScheduledMessage, len(rows))
+	for i := range rows {
+		messages[i] = rows[i].toDomain()
+	}
+
+	return messages, nil
+}
+
+func (r *ScheduledMessageRepository) Save(ctx context.Context, msg *schedule.ScheduledMessage) error {
+	query := `
+		UPDATE "zpScheduledMessage"
+		SET "status" = $2, "attempts" = $3, "nextAttemptAt" = $4, "lockedUntil" = NULL,
+		    "messageId" = $5, "lastError" = $6, "sentAt" = $7
+		WHERE "id" = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		msg.ID,
+		string(msg.Status),
+		msg.Attempts,
+		msg.NextAttemptAt,
+		msg.MessageID,
+		msg.LastError,
+		msg.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+func scheduledMessageConditions(ctx context.Context, filter *schedule.ListFilter) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.SessionID != "" {
+		add(`"sessionId" = $%d`, filter.SessionID)
+	}
+
+	if filter.Status != "" {
+		add(`"status" = $%d`, string(filter.Status))
+	}
+
+	if tenantID := shared.TenantFromContext(ctx); tenantID != "" {
+		add(`"sessionId" IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $%d)`, tenantID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+type scheduledMessageDB struct {
+	ID             string         `db:"id"`
+	SessionID      string         `db:"sessionId"`
+	Phone          string         `db:"phone"`
+	Type           string         `db:"type"`
+	Payload        []byte         `db:"payload"`
+	SendAt         time.Time      `db:"sendAt"`
+	Timezone       string         `db:"timezone"`
+	OnDisconnected string         `db:"onDisconnected"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"nextAttemptAt"`
+	MessageID      sql.NullString `db:"messageId"`
+	LastError      sql.NullString `db:"lastError"`
+	SentAt         sql.NullTime   `db:"sentAt"`
+	CreatedAt      time.Time      `db:"createdAt"`
+	UpdatedAt      time.Time      `db:"updatedAt"`
+}
+
+func (m *scheduledMessageDB) toDomain() *schedule.ScheduledMessage {
+	msg := &schedule.ScheduledMessage{
+		ID:             m.ID,
+		SessionID:      m.SessionID,
+		Phone:          m.Phone,
+		Type:           m.Type,
+		Payload:        m.Payload,
+		SendAt:         m.SendAt,
+		Timezone:       m.Timezone,
+		OnDisconnected: schedule.DisconnectedPolicy(m.OnDisconnected),
+		Status:         schedule.Status(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+
+	if m.MessageID.Valid {
+		msg.MessageID = &m.MessageID.String
+	}
+
+	if m.LastError.Valid {
+		msg.LastError = &m.LastError.String
+	}
+
+	if m.SentAt.Valid {
+		msg.SentAt = &m.SentAt.Time
+	}
+
+	return msg
+}
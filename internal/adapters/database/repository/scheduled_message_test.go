@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/domain/schedule"
+)
+
+type scheduleTest struct {
+	t         *testing.T
+	repo      *ScheduledMessageRepository
+	ctx       context.Context
+	sessionID string
+}
+
+func newScheduleTest(t *testing.T) *scheduleTest {
+	db := newTestDB(t)
+
+	return &scheduleTest{
+		t:         t,
+		repo:      NewScheduledMessageRepository(db),
+		ctx:       context.Background(),
+		sessionID: createTestSession(t, db).ID,
+	}
+}
+
+// create stores a text message due at sendAt.
+func (s *scheduleTest) create(sendAt time.Time, timezone string) *schedule.ScheduledMessage {
+	s.t.Helper()
+
+	msg := schedule.NewScheduledMessage(s.sessionID, "5511999999999", schedule.TypeText,
+		[]byte(`{"phone":"5511999999999","text":"hello"}`), sendAt, timezone, schedule.DisconnectedRetry)
+
+	if err := s.repo.Create(s.ctx, msg); err != nil {
+		s.t.Fatalf("Create: %v", err)
+	}
+
+	return msg
+}
+
+// expectClaim claims every due message and checks they are want, in order.
+func (s *scheduleTest) expectClaim(lease time.Duration, want ...*schedule.ScheduledMessage) []*schedule.ScheduledMessage {
+	s.t.Helper()
+
+	claimed, err := s.repo.ClaimDue(s.ctx, 10, lease)
+	if err != nil {
+		s.t.Fatalf("ClaimDue: %v", err)
+	}
+
+	if len(claimed) != len(want) {
+		s.t.Fatalf("claimed %d messages, want %d", len(claimed), len(want))
+	}
+
+	for i := range want {
+		if claimed[i].ID != want[i].ID {
+			s.t.Fatalf("claimed message %d is %s, want %s", i, claimed[i].ID, want[i].ID)
+		}
+	}
+
+	return claimed
+}
+
+func TestScheduledClaimDueReturnsDueMessagesInOrder(t *testing.T) {
+	s := newScheduleTest(t)
+
+	now := time.Now()
+	later := s.create(now.Add(-time.Minute), "UTC")
+	earlier := s.create(now.Add(-time.Hour), "UTC")
+	s.create(now.Add(time.Hour), "UTC")
+
+	cancelled := s.create(now.Add(-time.Hour), "UTC")
+	cancelled.Cancel()
+
+	if err := s.repo.UpdatePending(s.ctx, cancelled); err != nil {
+		t.Fatalf("UpdatePending: %v", err)
+	}
+
+	s.expectClaim(time.Minute, earlier, later)
+
+	// Leased messages are not handed out again.
+	s.expectClaim(time.Minute)
+}
+
+func TestScheduledClaimDueReclaimsExpiredLeases(t *testing.T) {
+	s := newScheduleTest(t)
+
+	msg := s.create(time.Now().Add(-time.Minute), "UTC")
+
+	s.expectClaim(10*time.Millisecond, msg)
+
+	time.Sleep(50 * time.Millisecond)
+
+	s.expectClaim(time.Minute, msg)
+}
+
+func TestScheduledSaveReleasesTheLeaseUntilTheNextAttempt(t *testing.T) {
+	s := newScheduleTest(t)
+
+	msg := s.create(time.Now().Add(-time.Minute), "UTC")
+
+	claimed := s.expectClaim(time.Hour, msg)[0]
+
+	// A failed attempt is retried once its next attempt is due, even though
+	// the lease it was claimed with has not expired.
+	claimed.Attempts++
+	claimed.Retry("send failed", time.Now().Add(time.Hour))
+
+	if err := s.repo.Save(s.ctx, claimed); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	s.expectClaim(time.Minute)
+
+	claimed.Retry("send failed", time.Now().Add(-time.Second))
+
+	if err := s.repo.Save(s.ctx, claimed); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	retried := s.expectClaim(time.Minute, msg)[0]
+	if retried.Attempts != 1 || retried.LastError == nil || *retried.LastError != "send failed" {
+		t.Errorf("retried after %d attempts with error %v, want 1 attempt and the last error", retried.Attempts, retried.LastError)
+	}
+
+	// Messages that are no longer pending are never claimed.
+	retried.MarkSent("MSG1", time.Now())
+
+	if err := s.repo.Save(s.ctx, retried); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	s.expectClaim(time.Minute)
+}
+
+func TestScheduledSendAtKeepsItsInstant(t *testing.T) {
+	s := newScheduleTest(t)
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+
+	sendAt := time.Date(time.Now().Year()+1, time.December, 24, 9, 0, 0, 0, saoPaulo)
+	msg := s.create(sendAt, "America/Sao_Paulo")
+
+	stored, err := s.repo.GetByID(s.ctx, s.sessionID, msg.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if !stored.SendAt.Equal(sendAt) || !stored.NextAttemptAt.Equal(sendAt) {
+		t.Errorf("stored sendAt %s and next attempt %s, want %s", stored.SendAt, stored.NextAttemptAt, sendAt)
+	}
+
+	if stored.Timezone != "America/Sao_Paulo" {
+		t.Errorf("timezone = %q, want America/Sao_Paulo", stored.Timezone)
+	}
+
+	// Not due until then, whatever the time zone of the database.
+	s.expectClaim(time.Minute)
+}
@@ -23,21 +23,60 @@ func NewSessionRepository(db *sqlx.DB) *SessionRepository {
 	}
 }
 
+// Create inserts the session while holding a lock on its tenant, so
+// concurrent creates cannot take the tenant past its session quota.
 func (r *SessionRepository) Create(ctx context.Context, sess *session.Session) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var owner tenantDB
+
+	err = tx.GetContext(ctx, &owner, `
+		SELECT `+tenantColumns+`
+		FROM "zpTenant"
+		WHERE "id" = $1
+		FOR UPDATE
+	`, sess.TenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return shared.ErrTenantNotFound
+		}
+
+		return fmt.Errorf("failed to lock tenant: %w", err)
+	}
+
+	var count int
+
+	err = tx.GetContext(ctx, &count, `SELECT COUNT(*) FROM "zpSessions" WHERE "tenantId" = $1`, sess.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to count tenant sessions: %w", err)
+	}
+
+	if !owner.toDomain().CanAddSession(count) {
+		return shared.ErrSessionQuotaExceeded
+	}
+
 	query := `
 		INSERT INTO "zpSessions" (
-			"id", "name", "deviceJid", "isConnected", "connectionError",
+			"id", "tenantId", "name", "deviceJid", "isConnected", "connectionError",
 			"qrCode", "qrCodeExpiresAt", "proxyConfig", "createdAt",
 			"updatedAt", "connectedAt", "lastSeen"
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 		)
 	`
 
 	var proxyConfig interface{} = sess.ProxyConfig
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		sess.ID,
+		sess.TenantID,
 		sess.Name,
 		sess.DeviceJID,
 		sess.IsConnected,
@@ -55,21 +94,27 @@ func (r *SessionRepository) Create(ctx context.Context, sess *session.Session) e
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session: %w", err)
+	}
+
 	return nil
 }
 
 func (r *SessionRepository) GetByID(ctx context.Context, id string) (*session.Session, error) {
 	query := `
-		SELECT "id", "name", "deviceJid", "isConnected", "connectionError", 
+		SELECT "id", "tenantId", "name", "deviceJid", "isConnected", "connectionError", 
 			   "qrCode", "qrCodeExpiresAt", "proxyConfig", "createdAt", 
 			   "updatedAt", "connectedAt", "lastSeen"
 		FROM "zpSessions" 
-		WHERE "id" = $1
-	`
+		WHERE "id" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{id})
+	query += scope
 
 	var sess session.Session
 
-	err := r.db.GetContext(ctx, &sess, query, id)
+	err := r.db.GetContext(ctx, &sess, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, shared.ErrSessionNotFound
@@ -83,16 +128,18 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*session.Se
 
 func (r *SessionRepository) GetByJID(ctx context.Context, jid string) (*session.Session, error) {
 	query := `
-		SELECT "id", "name", "deviceJid", "isConnected", "connectionError",
+		SELECT "id", "tenantId", "name", "deviceJid", "isConnected", "connectionError",
 			   "qrCode", "qrCodeExpiresAt", "proxyConfig", "createdAt",
 			   "updatedAt", "connectedAt", "lastSeen"
 		FROM "zpSessions"
-		WHERE "deviceJid" = $1
-	`
+		WHERE "deviceJid" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{jid})
+	query += scope
 
 	var sess session.Session
 
-	err := r.db.GetContext(ctx, &sess, query, jid)
+	err := r.db.GetContext(ctx, &sess, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, shared.ErrSessionNotFound
@@ -106,16 +153,18 @@ func (r *SessionRepository) GetByJID(ctx context.Context, jid string) (*session.
 
 func (r *SessionRepository) GetByName(ctx context.Context, name string) (*session.Session, error) {
 	query := `
-		SELECT "id", "name", "deviceJid", "isConnected", "connectionError", 
+		SELECT "id", "tenantId", "name", "deviceJid", "isConnected", "connectionError", 
 			   "qrCode", "qrCodeExpiresAt", "proxyConfig", "createdAt", 
 			   "updatedAt", "connectedAt", "lastSeen"
 		FROM "zpSessions" 
-		WHERE "name" = $1
-	`
+		WHERE "name" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{name})
+	query += scope
 
 	var sess session.Session
 
-	err := r.db.GetContext(ctx, &sess, query, name)
+	err := r.db.GetContext(ctx, &sess, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, shared.ErrSessionNotFound
@@ -129,17 +178,21 @@ func (r *SessionRepository) GetByName(ctx context.Context, name string) (*sessio
 
 func (r *SessionRepository) List(ctx context.Context, limit, offset int) ([]*session.Session, error) {
 	query := `
-		SELECT "id", "name", "deviceJid", "isConnected", "connectionError",
+		SELECT "id", "tenantId", "name", "deviceJid", "isConnected", "connectionError",
 			   "qrCode", "qrCodeExpiresAt", "proxyConfig", "createdAt",
 			   "updatedAt", "connectedAt", "lastSeen"
 		FROM "zpSessions"
+		WHERE TRUE`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{limit, offset})
+	query += scope + `
 		ORDER BY "createdAt" DESC
 		LIMIT $1 OFFSET $2
 	`
 
 	var sessions []*session.Session
 
-	err := r.db.SelectContext(ctx, &sessions, query, limit, offset)
+	err := r.db.SelectContext(ctx, &sessions, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
@@ -160,12 +213,11 @@ func (r *SessionRepository) Update(ctx context.Context, sess *session.Session) e
 			"updatedAt" = $9,
 			"connectedAt" = $10,
 			"lastSeen" = $11
-		WHERE "id" = $1
-	`
+		WHERE "id" = $1`
 
 	var proxyConfig interface{} = sess.ProxyConfig
 
-	result, err := r.db.ExecContext(ctx, query,
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{
 		sess.ID,
 		sess.Name,
 		sess.DeviceJID,
@@ -177,8 +229,10 @@ func (r *SessionRepository) Update(ctx context.Context, sess *session.Session) e
 		time.Now(),
 		sess.ConnectedAt,
 		sess.LastSeen,
-	)
+	})
+	query += scope
 
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update session: %w", err)
 	}
@@ -200,10 +254,12 @@ func (r *SessionRepository) UpdateStatus(ctx context.Context, id string, status
 		UPDATE "zpSessions" SET
 			"isConnected" = $2,
 			"updatedAt" = NOW()
-		WHERE "id" = $1
-	`
+		WHERE "id" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{id, status == session.StatusConnected})
+	query += scope
 
-	result, err := r.db.ExecContext(ctx, query, id, status == session.StatusConnected)
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update session status: %w", err)
 	}
@@ -223,7 +279,10 @@ func (r *SessionRepository) UpdateStatus(ctx context.Context, id string, status
 func (r *SessionRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM "zpSessions" WHERE "id" = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{id})
+	query += scope
+
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
@@ -244,10 +303,12 @@ func (r *SessionRepository) UpdateQRCode(ctx context.Context, id string, qrCode
 	query := `
 		UPDATE "zpSessions"
 		SET "qrCode" = $2, "updatedAt" = NOW()
-		WHERE "id" = $1
-	`
+		WHERE "id" = $1`
+
+	scope, args := tenantCondition(ctx, `"tenantId"`, []interface{}{id, qrCode})
+	query += scope
 
-	result, err := r.db.ExecContext(ctx, query, id, qrCode)
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update session QR code: %w", err)
 	}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const tenantColumns = `"id", "name", "status", "maxSessions", "maxMessagesPerDay", "createdAt", "updatedAt"`
+
+type TenantRepository struct {
+	db *sqlx.DB
+}
+
+func NewTenantRepository(db *sqlx.DB) *TenantRepository {
+	return &TenantRepository{
+		db: db,
+	}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
+	query := `
+		INSERT INTO "zpTenant" (` + tenantColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		t.ID,
+		t.Name,
+		string(t.Status),
+		t.MaxSessions,
+		t.MaxMessagesPerDay,
+		t.CreatedAt,
+		t.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return shared.ErrTenantAlreadyExists
+		}
+
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	query := `
+		SELECT ` + tenantColumns + `
+		FROM "zpTenant"
+		WHERE "id" = $1
+	`
+
+	return r.get(ctx, query, id)
+}
+
+func (r *TenantRepository) GetBySessionID(ctx context.Context, sessionID string) (*tenant.Tenant, error) {
+	query := `
+		SELECT ` + tenantColumns + `
+		FROM "zpTenant"
+		WHERE "id" = (SELECT "tenantId" FROM "zpSessions" WHERE "id" = $1)
+	`
+
+	return r.get(ctx, query, sessionID)
+}
+
+func (r *TenantRepository) get(ctx context.Context, query string, arg interface{}) (*tenant.Tenant, error) {
+	var row tenantDB
+
+	if err := r.db.GetContext(ctx, &row, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrTenantNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	query := `
+		SELECT ` + tenantColumns + `
+		FROM "zpTenant"
+		ORDER BY "createdAt" ASC, "id" ASC
+	`
+
+	var rows []tenantDB
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	tenants := make([]*tenant.Tenant, len(rows))
+	for i := range rows {
+		tenants[i] = rows[i].toDomain()
+	}
+
+	return tenants, nil
+}
+
+func (r *TenantRepository) Update(ctx context.Context, t *tenant.Tenant) error {
+	query := `
+		UPDATE "zpTenant" SET
+			"name" = $2,
+			"status" = $3,
+			"maxSessions" = $4,
+			"maxMessagesPerDay" = $5
+		WHERE "id" = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		t.ID,
+		t.Name,
+		string(t.Status),
+		t.MaxSessions,
+		t.MaxMessagesPerDay,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return shared.ErrTenantAlreadyExists
+		}
+
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrTenantNotFound
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM "zpTenant" WHERE "id" = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return shared.ErrTenantHasSessions
+		}
+
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return shared.ErrTenantNotFound
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) CountSessions(ctx context.Context, tenantID string) (int, error) {
+	query := `SELECT COUNT(*) FROM "zpSessions" WHERE "tenantId" = $1`
+
+	var count int
+
+	if err := r.db.GetContext(ctx, &count, query, tenantID); err != nil {
+		return 0, fmt.Errorf("failed to count tenant sessions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ConsumeMessage counts the message with a single upsert, so concurrent
+// sends of a tenant cannot go over its limit together.
+func (r *TenantRepository) ConsumeMessage(ctx context.Context, tenantID string, day time.Time, limit int) (bool, error) {
+	query := `
+		INSERT INTO "zpTenantUsage" ("tenantId", "day", "messages")
+		VALUES ($1, $2, 1)
+		ON CONFLICT ("tenantId", "day") DO UPDATE SET
+			"messages" = "zpTenantUsage"."messages" + 1
+		WHERE $3::INTEGER <= 0 OR "zpTenantUsage"."messages" < $3::INTEGER
+	`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID, day, limit)
+	if err != nil {
+		return false, fmt.Errorf("failed to count tenant message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *TenantRepository) GetUsage(ctx context.Context, tenantID string, day time.Time) (*tenant.Usage, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT "messages" FROM "zpTenantUsage" WHERE "tenantId" = $1 AND "day" = $2),
+			0
+		)
+	`
+
+	usage := &tenant.Usage{
+		TenantID: tenantID,
+		Day:      day,
+	}
+
+	if err := r.db.GetContext(ctx, &usage.Messages, query, tenantID, day); err != nil {
+		return nil, fmt.Errorf("failed to get tenant usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+type tenantDB struct {
+	ID                string    `db:"id"`
+	Name              string    `db:"name"`
+	Status            string    `db:"status"`
+	MaxSessions       int       `db:"maxSessions"`
+	MaxMessagesPerDay int       `db:"maxMessagesPerDay"`
+	CreatedAt         time.Time `db:"createdAt"`
+	UpdatedAt         time.Time `db:"updatedAt"`
+}
+
+func (t *tenantDB) toDomain() *tenant.Tenant {
+	return &tenant.Tenant{
+		ID:                t.ID,
+		Name:              t.Name,
+		Status:            tenant.Status(t.Status),
+		MaxSessions:       t.MaxSessions,
+		MaxMessagesPerDay: t.MaxMessagesPerDay,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+	}
+}
+
+// tenantCondition limits a query to the tenant ctx is limited to, if any,
+// by comparing column, a tenant ID, with it. It appends the tenant to args
+// and returns the condition to add to the WHERE clause.
+func tenantCondition(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	tenantID := shared.TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", args
+	}
+
+	args = append(args, tenantID)
+
+	return ` AND ` + column + ` = $` + strconv.Itoa(len(args)), args
+}
+
+// sessionTenantCondition is tenantCondition for tables that reference a
+// session rather than a tenant: column, a session ID, must be one of the
+// tenant's sessions.
+func sessionTenantCondition(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	tenantID := shared.TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", args
+	}
+
+	args = append(args, tenantID)
+
+	return ` AND ` + column + ` IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $` + strconv.Itoa(len(args)) + `)`, args
+}
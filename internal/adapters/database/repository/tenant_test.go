@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/domain/shared"
+)
+
+func TestTenantCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCond string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "no tenant",
+			ctx:      context.Background(),
+			wantCond: "",
+			wantArgs: []interface{}{"session-1"},
+		},
+		{
+			name:     "tenant",
+			ctx:      shared.WithTenant(context.Background(), "tenant-a"),
+			wantCond: ` AND "tenantId" = $2`,
+			wantArgs: []interface{}{"session-1", "tenant-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, args := tenantCondition(tt.ctx, `"tenantId"`, []interface{}{"session-1"})
+
+			if cond != tt.wantCond {
+				t.Errorf("condition = %q, want %q", cond, tt.wantCond)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSessionTenantCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		args     []interface{}
+		wantCond string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "no tenant",
+			ctx:      context.Background(),
+			args:     []interface{}{"session-1"},
+			wantCond: "",
+			wantArgs: []interface{}{"session-1"},
+		},
+		{
+			name:     "tenant",
+			ctx:      shared.WithTenant(context.Background(), "tenant-a"),
+			args:     []interface{}{"session-1"},
+			wantCond: ` AND "sessionId" IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $2)`,
+			wantArgs: []interface{}{"session-1", "tenant-a"},
+		},
+		{
+			name:     "tenant after other arguments",
+			ctx:      shared.WithTenant(context.Background(), "tenant-a"),
+			args:     []interface{}{"session-1", "message-1", 10},
+			wantCond: ` AND "sessionId" IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $4)`,
+			wantArgs: []interface{}{"session-1", "message-1", 10, "tenant-a"},
+		},
+		{
+			name:     "tenant without other arguments",
+			ctx:      shared.WithTenant(context.Background(), "tenant-a"),
+			args:     nil,
+			wantCond: ` AND "sessionId" IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $1)`,
+			wantArgs: []interface{}{"tenant-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, args := sessionTenantCondition(tt.ctx, `"sessionId"`, tt.args)
+
+			if cond != tt.wantCond {
+				t.Errorf("condition = %q, want %q", cond, tt.wantCond)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestScheduledMessageConditionsScopeTenant(t *testing.T) {
+	ctx := shared.WithTenant(context.Background(), "tenant-a")
+
+	where, args := scheduledMessageConditions(ctx, &schedule.ListFilter{SessionID: "session-1"})
+
+	wantWhere := ` WHERE "sessionId" = $1 AND "sessionId" IN (SELECT "id" FROM "zpSessions" WHERE "tenantId" = $2)`
+	if where != wantWhere {
+		t.Errorf("where = %q, want %q", where, wantWhere)
+	}
+
+	if want := []interface{}{"session-1", "tenant-a"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"zpwoot/internal/core/domain/shared"
 	"zpwoot/internal/core/domain/webhook"
 
 	"github.com/jmoiron/sqlx"
@@ -31,19 +32,20 @@ func (r *WebhookRepository) Create(ctx context.Context, wh *webhook.Webhook) err
 	query := `
 		INSERT INTO "zpWebhooks" (
 			"id", "sessionId", "url", "secret", "events", 
-			"enabled", "createdAt", "updatedAt"
+			"enabled", "payloadFormat", "createdAt", "updatedAt"
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
 		)
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
 		wh.ID,
-		wh.SessionID,
+		sql.NullString{String: wh.SessionID, Valid: !wh.IsGlobal()},
 		wh.URL,
 		wh.Secret,
 		eventsJSON,
 		wh.Enabled,
+		payloadFormat(wh),
 		wh.CreatedAt,
 		wh.UpdatedAt,
 	)
@@ -52,7 +54,7 @@ func (r *WebhookRepository) Create(ctx context.Context, wh *webhook.Webhook) err
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) {
 			if pqErr.Code == "23505" {
-				return fmt.Errorf("webhook already exists for session")
+				return shared.ErrWebhookAlreadyExists
 			}
 		}
 
@@ -64,17 +66,19 @@ func (r *WebhookRepository) Create(ctx context.Context, wh *webhook.Webhook) err
 func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*webhook.Webhook, error) {
 	query := `
 		SELECT "id", "sessionId", "url", "secret", "events", 
-		       "enabled", "createdAt", "updatedAt"
+		       "enabled", "payloadFormat", "createdAt", "updatedAt"
 		FROM "zpWebhooks"
-		WHERE "id" = $1
-	`
+		WHERE "id" = $1`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{id})
+	query += scope
 
 	var wh webhookDB
 
-	err := r.db.GetContext(ctx, &wh, query, id)
+	err := r.db.GetContext(ctx, &wh, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("webhook not found")
+			return nil, shared.ErrWebhookNotFound
 		}
 
 		return nil, fmt.Errorf("failed to get webhook: %w", err)
@@ -82,27 +86,71 @@ func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*webhook.We
 
 	return wh.toDomain()
 }
-func (r *WebhookRepository) GetBySessionID(ctx context.Context, sessionID string) (*webhook.Webhook, error) {
+func (r *WebhookRepository) ListBySessionID(ctx context.Context, sessionID string) ([]*webhook.Webhook, error) {
+	query := `
+		SELECT "id", "sessionId", "url", "secret", "events", 
+		       "enabled", "payloadFormat", "createdAt", "updatedAt"
+		FROM "zpWebhooks"
+		WHERE "sessionId" = $1`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{sessionID})
+	query += scope + `
+		ORDER BY "createdAt" ASC, "id" ASC
+	`
+
+	var webhooksDB []webhookDB
+
+	err := r.db.SelectContext(ctx, &webhooksDB, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session webhooks: %w", err)
+	}
+
+	return toDomainWebhooks(webhooksDB)
+}
+
+// GetGlobal returns the instance-wide webhook, stored with a NULL session.
+func (r *WebhookRepository) GetGlobal(ctx context.Context) (*webhook.Webhook, error) {
 	query := `
 		SELECT "id", "sessionId", "url", "secret", "events", 
-		       "enabled", "createdAt", "updatedAt"
+		       "enabled", "payloadFormat", "createdAt", "updatedAt"
 		FROM "zpWebhooks"
-		WHERE "sessionId" = $1
+		WHERE "sessionId" IS NULL
 	`
 
 	var wh webhookDB
 
-	err := r.db.GetContext(ctx, &wh, query, sessionID)
+	err := r.db.GetContext(ctx, &wh, query)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("webhook not found")
+			return nil, shared.ErrWebhookNotFound
 		}
 
-		return nil, fmt.Errorf("failed to get webhook: %w", err)
+		return nil, fmt.Errorf("failed to get global webhook: %w", err)
 	}
 
 	return wh.toDomain()
 }
+
+// ListForSession returns the webhooks of a session followed by the global
+// webhook, i.e. every webhook an event of that session may be delivered to.
+func (r *WebhookRepository) ListForSession(ctx context.Context, sessionID string) ([]*webhook.Webhook, error) {
+	query := `
+		SELECT "id", "sessionId", "url", "secret", "events", 
+		       "enabled", "payloadFormat", "createdAt", "updatedAt"
+		FROM "zpWebhooks"
+		WHERE "sessionId" = $1 OR "sessionId" IS NULL
+		ORDER BY "sessionId" NULLS LAST, "createdAt" ASC, "id" ASC
+	`
+
+	var webhooksDB []webhookDB
+
+	err := r.db.SelectContext(ctx, &webhooksDB, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks for session: %w", err)
+	}
+
+	return toDomainWebhooks(webhooksDB)
+}
 func (r *WebhookRepository) Update(ctx context.Context, wh *webhook.Webhook) error {
 	eventsJSON, err := json.Marshal(wh.Events)
 	if err != nil {
@@ -115,20 +163,28 @@ func (r *WebhookRepository) Update(ctx context.Context, wh *webhook.Webhook) err
 			"secret" = $3,
 			"events" = $4,
 			"enabled" = $5,
-			"updatedAt" = $6
-		WHERE "id" = $1
-	`
+			"payloadFormat" = $6,
+			"updatedAt" = $7
+		WHERE "id" = $1`
 
-	result, err := r.db.ExecContext(ctx, query,
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{
 		wh.ID,
 		wh.URL,
 		wh.Secret,
 		eventsJSON,
 		wh.Enabled,
+		payloadFormat(wh),
 		wh.UpdatedAt,
-	)
+	})
+	query += scope
 
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return shared.ErrWebhookAlreadyExists
+		}
+
 		return fmt.Errorf("failed to update webhook: %w", err)
 	}
 
@@ -138,7 +194,7 @@ func (r *WebhookRepository) Update(ctx context.Context, wh *webhook.Webhook) err
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("webhook not found")
+		return shared.ErrWebhookNotFound
 	}
 
 	return nil
@@ -146,7 +202,10 @@ func (r *WebhookRepository) Update(ctx context.Context, wh *webhook.Webhook) err
 func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM "zpWebhooks" WHERE "id" = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{id})
+	query += scope
+
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
@@ -157,7 +216,7 @@ func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("webhook not found")
+		return shared.ErrWebhookNotFound
 	}
 
 	return nil
@@ -176,7 +235,7 @@ func (r *WebhookRepository) DeleteBySessionID(ctx context.Context, sessionID str
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("webhook not found")
+		return shared.ErrWebhookNotFound
 	}
 
 	return nil
@@ -184,19 +243,33 @@ func (r *WebhookRepository) DeleteBySessionID(ctx context.Context, sessionID str
 func (r *WebhookRepository) List(ctx context.Context, limit, offset int) ([]*webhook.Webhook, error) {
 	query := `
 		SELECT "id", "sessionId", "url", "secret", "events", 
-		       "enabled", "createdAt", "updatedAt"
+		       "enabled", "payloadFormat", "createdAt", "updatedAt"
 		FROM "zpWebhooks"
+		WHERE TRUE`
+
+	scope, args := sessionTenantCondition(ctx, `"sessionId"`, []interface{}{limit, offset})
+	query += scope + `
 		ORDER BY "createdAt" DESC
 		LIMIT $1 OFFSET $2
 	`
 
 	var webhooksDB []webhookDB
 
-	err := r.db.SelectContext(ctx, &webhooksDB, query, limit, offset)
+	err := r.db.SelectContext(ctx, &webhooksDB, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list webhooks: %w", err)
 	}
 
+	return toDomainWebhooks(webhooksDB)
+}
+func payloadFormat(wh *webhook.Webhook) string {
+	if wh.Format == "" {
+		return webhook.FormatNormalized
+	}
+
+	return wh.Format
+}
+func toDomainWebhooks(webhooksDB []webhookDB) ([]*webhook.Webhook, error) {
 	webhooks := make([]*webhook.Webhook, 0, len(webhooksDB))
 
 	for _, whDB := range webhooksDB {
@@ -212,14 +285,15 @@ func (r *WebhookRepository) List(ctx context.Context, limit, offset int) ([]*web
 }
 
 type webhookDB struct {
-	ID        string       `db:"id"`
-	SessionID string       `db:"sessionId"`
-	URL       string       `db:"url"`
-	Secret    *string      `db:"secret"`
-	Events    []byte       `db:"events"`
-	Enabled   bool         `db:"enabled"`
-	CreatedAt sql.NullTime `db:"createdAt"`
-	UpdatedAt sql.NullTime `db:"updatedAt"`
+	ID        string         `db:"id"`
+	SessionID sql.NullString `db:"sessionId"`
+	URL       string         `db:"url"`
+	Secret    *string        `db:"secret"`
+	Events    []byte         `db:"events"`
+	Enabled   bool           `db:"enabled"`
+	Format    string         `db:"payloadFormat"`
+	CreatedAt sql.NullTime   `db:"createdAt"`
+	UpdatedAt sql.NullTime   `db:"updatedAt"`
 }
 
 func (wh *webhookDB) toDomain() (*webhook.Webhook, error) {
@@ -232,11 +306,12 @@ func (wh *webhookDB) toDomain() (*webhook.Webhook, error) {
 
 	return &webhook.Webhook{
 		ID:        wh.ID,
-		SessionID: wh.SessionID,
+		SessionID: wh.SessionID.String,
 		URL:       wh.URL,
 		Secret:    wh.Secret,
 		Events:    events,
 		Enabled:   wh.Enabled,
+		Format:    wh.Format,
 		CreatedAt: wh.CreatedAt.Time,
 		UpdatedAt: wh.UpdatedAt.Time,
 	}, nil
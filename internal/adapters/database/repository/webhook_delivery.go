@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/webhook"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhookDeliveryRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookDeliveryRepository(db *sqlx.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, d *webhook.Delivery) error {
+	query := `
+		INSERT INTO "zpWebhookOutbox" (
+			"id", "sessionId", "endpoint", "secret", "eventId", "eventType",
+			"payload", "attempts", "nextAttemptAt", "createdAt", "updatedAt"
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID,
+		d.SessionID,
+		d.Endpoint,
+		d.Secret,
+		d.EventID,
+		d.EventType,
+		d.Payload,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.CreatedAt,
+		d.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue leases up to limit deliveries that are due. Only the oldest
+// delivery of each endpoint is eligible, which keeps events of an endpoint in
+// order: a failing head blocks the ones queued after it until it is
+// delivered or dead-lettered.
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	query := `
+		WITH heads AS (
+			SELECT DISTINCT ON ("endpoint") "id", "nextAttemptAt", "lockedUntil"
+			FROM "zpWebhookOutbox"
+			ORDER BY "endpoint", "createdAt", "id"
+		), due AS (
+			SELECT "id"
+			FROM heads
+			WHERE "nextAttemptAt" <= NOW()
+			  AND ("lockedUntil" IS NULL OR "lockedUntil" < NOW())
+			ORDER BY "nextAttemptAt"
+			LIMIT $1
+		)
+		UPDATE "zpWebhookOutbox" o
+		SET "lockedUntil" = NOW() + $2 * INTERVAL '1 millisecond',
+		    "attempts" = o."attempts" + 1
+		FROM due
+		WHERE o."id" = due."id"
+		  AND (o."lockedUntil" IS NULL OR o."lockedUntil" < NOW())
+		RETURNING ` + deliveryReturningColumns
+
+	var rows []deliveryDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, limit, lease.Milliseconds()); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*webhook.Delivery, len(rows))
+	for i := range rows {
+		deliveries[i] = rows[i].toDomain()
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) Complete(ctx context.Context, id string) error {
+	query := `DELETE FROM "zpWebhookOutbox" WHERE "id" = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to complete webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Reschedule(ctx context.Context, id string, nextAttemptAt time.Time, lastError string, statusCode *int) error {
+	query := `
+		UPDATE "zpWebhookOutbox"
+		SET "nextAttemptAt" = $2, "lockedUntil" = NULL, "lastError" = $3, "lastStatusCode" = $4
+		WHERE "id" = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, nextAttemptAt, lastError, statusCode); err != nil {
+		return fmt.Errorf("failed to reschedule webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) MoveToDeadLetter(ctx context.Context, id string, lastError string, statusCode *int) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM "zpWebhookOutbox"
+			WHERE "id" = $1
+			RETURNING "id", "sessionId", "endpoint", "secret", "eventId", "eventType",
+			          "payload", "attempts", "createdAt"
+		)
+		INSERT INTO "zpWebhookDeadLetter" (
+			"id", "sessionId", "endpoint", "secret", "eventId", "eventType",
+			"payload", "attempts", "lastError", "lastStatusCode", "createdAt", "failedAt"
+		)
+		SELECT "id", "sessionId", "endpoint", "secret", "eventId", "eventType",
+		       "payload", "attempts", $2, $3, "createdAt", NOW()
+		FROM moved
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, lastError, statusCode); err != nil {
+		return fmt.Errorf("failed to dead-letter webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) ListDeadLetters(ctx context.Context, filter *webhook.DeadLetterFilter) ([]*webhook.DeadLetter, int, error) {
+	where, args := deadLetterConditions(filter)
+
+	countQuery := `SELECT COUNT(*) FROM "zpWebhookDeadLetter"` + where
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := `
+		SELECT ` + deadLetterColumns + `
+		FROM "zpWebhookDeadLetter"` + where + `
+		ORDER BY "failedAt" DESC, "id"
+		LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	var rows []deadLetterDB
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	deadLetters := make([]*webhook.DeadLetter, len(rows))
+	for i := range rows {
+		deadLetters[i] = rows[i].toDomain()
+	}
+
+	return deadLetters, total, nil
+}
+
+func (r *WebhookDeliveryRepository) GetDeadLetter(ctx context.Context, id string) (*webhook.DeadLetter, error) {
+	query := `
+		SELECT ` + deadLetterColumns + `
+		FROM "zpWebhookDeadLetter"
+		WHERE "id" = $1
+	`
+
+	var row deadLetterDB
+
+	err := r.db.GetContext(ctx, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrDeadLetterNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (r *WebhookDeliveryRepository) ReplayDeadLetters(ctx context.Context, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	return r.replay(ctx, ` WHERE "id" = ANY($1::uuid[])`, []interface{}{pq.Array(ids)})
+}
+
+func (r *WebhookDeliveryRepository) ReplayDeadLettersInRange(ctx context.Context, filter *webhook.DeadLetterFilter) (int, error) {
+	where, args := deadLetterConditions(filter)
+	return r.replay(ctx, where, args)
+}
+
+// replay moves the selected dead letters back to the tail of the outbox with
+// a fresh attempt budget. The event ID and payload are kept, so receivers can
+// deduplicate.
+func (r *WebhookDeliveryRepository) replay(ctx context.Context, where string, args []interface{}) (int, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM "zpWebhookDeadLetter"` + where + `
+			RETURNING "id", "sessionId", "endpoint", "secret", "eventId", "eventType", "payload"
+		)
+		INSERT INTO "zpWebhookOutbox" (
+			"id", "sessionId", "endpoint", "secret", "eventId", "eventType", "payload"
+		)
+		SELECT "id", "sessionId", "endpoint", "secret", "eventId", "eventType", "payload"
+		FROM moved
+	`
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replay dead letters: %w", err)
+	}
+
+	replayed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(replayed), nil
+}
+
+func deadLetterConditions(filter *webhook.DeadLetterFilter) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.SessionID != "" {
+		add(`"sessionId" = $%d`, filter.SessionID)
+	}
+
+	if filter.EventType != "" {
+		add(`"eventType" = $%d`, filter.EventType)
+	}
+
+	if filter.Since != nil {
+		add(`"failedAt" >= $%d`, *filter.Since)
+	}
+
+	if filter.Until != nil {
+		add(`"failedAt" <= $%d`, *filter.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+const deliveryReturningColumns = `o."id", o."sessionId", o."endpoint", o."secret", o."eventId", o."eventType",
+		          o."payload", o."attempts", o."nextAttemptAt", o."lastError", o."lastStatusCode",
+		          o."createdAt", o."updatedAt"`
+
+const deadLetterColumns = `"id", "sessionId", "endpoint", "secret", "eventId", "eventType",
+		       "payload", "attempts", "lastError", "lastStatusCode", "createdAt", "failedAt"`
+
+type deliveryDB struct {
+	ID             string         `db:"id"`
+	SessionID      string         `db:"sessionId"`
+	Endpoint       string         `db:"endpoint"`
+	Secret         sql.NullString `db:"secret"`
+	EventID        string         `db:"eventId"`
+	EventType      string         `db:"eventType"`
+	Payload        []byte         `db:"payload"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"nextAttemptAt"`
+	LastError      sql.NullString `db:"lastError"`
+	LastStatusCode sql.NullInt64  `db:"lastStatusCode"`
+	CreatedAt      time.Time      `db:"createdAt"`
+	UpdatedAt      time.Time      `db:"updatedAt"`
+}
+
+func (d *deliveryDB) toDomain() *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             d.ID,
+		SessionID:      d.SessionID,
+		Endpoint:       d.Endpoint,
+		Secret:         nullStringPtr(d.Secret),
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastError:      nullStringPtr(d.LastError),
+		LastStatusCode: nullIntPtr(d.LastStatusCode),
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+type deadLetterDB struct {
+	ID             string         `db:"id"`
+	SessionID      string         `db:"sessionId"`
+	Endpoint       string         `db:"endpoint"`
+	Secret         sql.NullString `db:"secret"`
+	EventID        string         `db:"eventId"`
+	EventType      string         `db:"eventType"`
+	Payload        []byte         `db:"payload"`
+	Attempts       int            `db:"attempts"`
+	LastError      sql.NullString `db:"lastError"`
+	LastStatusCode sql.NullInt64  `db:"lastStatusCode"`
+	CreatedAt      time.Time      `db:"createdAt"`
+	FailedAt       time.Time      `db:"failedAt"`
+}
+
+func (d *deadLetterDB) toDomain() *webhook.DeadLetter {
+	return &webhook.DeadLetter{
+		ID:             d.ID,
+		SessionID:      d.SessionID,
+		Endpoint:       d.Endpoint,
+		Secret:         nullStringPtr(d.Secret),
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Attempts:       d.Attempts,
+		LastError:      nullStringPtr(d.LastError),
+		LastStatusCode: nullIntPtr(d.LastStatusCode),
+		CreatedAt:      d.CreatedAt,
+		FailedAt:       d.FailedAt,
+	}
+}
+
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.String
+}
+
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Time
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	result := int(value.Int64)
+
+	return &result
+}
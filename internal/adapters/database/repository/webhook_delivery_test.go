@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/domain/webhook"
+)
+
+type outboxTest struct {
+	t    *testing.T
+	repo *WebhookDeliveryRepository
+	ctx  context.Context
+	// sessionID owns every queued delivery.
+	sessionID string
+	queued    time.Time
+}
+
+func newOutboxTest(t *testing.T) *outboxTest {
+	db := newTestDB(t)
+
+	return &outboxTest{
+		t:         t,
+		repo:      NewWebhookDeliveryRepository(db),
+		ctx:       context.Background(),
+		sessionID: createTestSession(t, db).ID,
+		queued:    time.Now().Add(-time.Hour),
+	}
+}
+
+// enqueue queues an event for endpoint, after every event queued before.
+func (o *outboxTest) enqueue(endpoint, eventID string) *webhook.Delivery {
+	o.t.Helper()
+
+	delivery := webhook.NewDelivery(o.sessionID, endpoint, nil, eventID, "Message", []byte(`{"type":"Message"}`))
+
+	o.queued = o.queued.Add(time.Second)
+	delivery.CreatedAt = o.queued
+	delivery.NextAttemptAt = o.queued
+
+	if err := o.repo.Enqueue(o.ctx, delivery); err != nil {
+		o.t.Fatalf("Enqueue: %v", err)
+	}
+
+	return delivery
+}
+
+// claim claims every due delivery and returns them by event ID.
+func (o *outboxTest) claim(lease time.Duration) map[string]*webhook.Delivery {
+	o.t.Helper()
+
+	deliveries, err := o.repo.ClaimDue(o.ctx, 10, lease)
+	if err != nil {
+		o.t.Fatalf("ClaimDue: %v", err)
+	}
+
+	claimed := make(map[string]*webhook.Delivery, len(deliveries))
+	for _, delivery := range deliveries {
+		claimed[delivery.EventID] = delivery
+	}
+
+	return claimed
+}
+
+func eventIDs(claimed map[string]*webhook.Delivery) []string {
+	ids := make([]string, 0, len(claimed))
+	for id := range claimed {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (o *outboxTest) expectClaim(lease time.Duration, want ...string) map[string]*webhook.Delivery {
+	o.t.Helper()
+
+	claimed := o.claim(lease)
+
+	got := eventIDs(claimed)
+	if len(got) != len(want) {
+		o.t.Fatalf("claimed %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			o.t.Fatalf("claimed %v, want %v", got, want)
+		}
+	}
+
+	return claimed
+}
+
+func TestClaimDueHandsOutTheHeadOfEachEndpoint(t *testing.T) {
+	o := newOutboxTest(t)
+
+	o.enqueue("https://a.example.com/hook", "a1")
+	o.enqueue("https://a.example.com/hook", "a2")
+	o.enqueue("https://b.example.com/hook", "b1")
+
+	claimed := o.expectClaim(time.Minute, "a1", "b1")
+
+	for _, delivery := range claimed {
+		if delivery.Attempts != 1 {
+			t.Errorf("%s claimed for attempt %d, want 1", delivery.EventID, delivery.Attempts)
+		}
+	}
+
+	// Leased deliveries are not handed out again, and a2 waits for a1.
+	o.expectClaim(time.Minute)
+
+	// A failed head is retried before the events queued after it.
+	status := http.StatusServiceUnavailable
+	if err := o.repo.Reschedule(o.ctx, claimed["a1"].ID, time.Now().Add(-time.Minute), "webhook returned status 503", &status); err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+
+	retried := o.expectClaim(time.Minute, "a1")
+	if retried["a1"].Attempts != 2 {
+		t.Errorf("a1 claimed for attempt %d, want 2", retried["a1"].Attempts)
+	}
+
+	if err := o.repo.Complete(o.ctx, claimed["a1"].ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	o.expectClaim(time.Minute, "a2")
+}
+
+func TestClaimDueWaitsForTheBackoffOfTheHead(t *testing.T) {
+	o := newOutboxTest(t)
+
+	o.enqueue("https://a.example.com/hook", "a1")
+	o.enqueue("https://a.example.com/hook", "a2")
+
+	claimed := o.expectClaim(time.Minute, "a1")
+
+	if err := o.repo.Reschedule(o.ctx, claimed["a1"].ID, time.Now().Add(time.Hour), "webhook returned status 500", nil); err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+
+	// a2 is due, but may not overtake a1.
+	o.expectClaim(time.Minute)
+}
+
+func TestClaimDueReclaimsExpiredLeases(t *testing.T) {
+	o := newOutboxTest(t)
+
+	o.enqueue("https://a.example.com/hook", "a1")
+
+	o.expectClaim(10*time.Millisecond, "a1")
+
+	time.Sleep(50 * time.Millisecond)
+
+	reclaimed := o.expectClaim(time.Minute, "a1")
+	if reclaimed["a1"].Attempts != 2 {
+		t.Errorf("a1 reclaimed for attempt %d, want 2", reclaimed["a1"].Attempts)
+	}
+}
+
+func TestClaimDueLimit(t *testing.T) {
+	o := newOutboxTest(t)
+
+	o.enqueue("https://a.example.com/hook", "a1")
+	o.enqueue("https://b.example.com/hook", "b1")
+	o.enqueue("https://c.example.com/hook", "c1")
+
+	deliveries, err := o.repo.ClaimDue(o.ctx, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+
+	if len(deliveries) != 2 {
+		t.Fatalf("claimed %d deliveries, want 2", len(deliveries))
+	}
+
+	o.expectClaim(time.Minute, "c1")
+}
+
+func TestMoveToDeadLetterUnblocksTheEndpoint(t *testing.T) {
+	o := newOutboxTest(t)
+
+	o.enqueue("https://a.example.com/hook", "a1")
+	o.enqueue("https://a.example.com/hook", "a2")
+
+	claimed := o.expectClaim(time.Minute, "a1")
+
+	status := http.StatusBadRequest
+	if err := o.repo.MoveToDeadLetter(o.ctx, claimed["a1"].ID, "webhook returned status 400", &status); err != nil {
+		t.Fatalf("MoveToDeadLetter: %v", err)
+	}
+
+	deadLetter, err := o.repo.GetDeadLetter(o.ctx, claimed["a1"].ID)
+	if err != nil {
+		t.Fatalf("GetDeadLetter: %v", err)
+	}
+
+	if deadLetter.EventID != "a1" || deadLetter.Attempts != 1 || deadLetter.LastStatusCode == nil || *deadLetter.LastStatusCode != status {
+		t.Errorf("dead letter = %+v, want a1 after 1 attempt with status %d", deadLetter, status)
+	}
+
+	o.expectClaim(time.Minute, "a2")
+}
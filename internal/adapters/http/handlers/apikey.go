@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCases input.APIKeyUseCases
+	logger         *logger.Logger
+}
+
+func NewAPIKeyHandler(apiKeyUseCases input.APIKeyUseCases, logger *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCases: apiKeyUseCases,
+		logger:         logger,
+	}
+}
+
+// @Summary		Create API Key
+// @Description	Create an API key limited to the sessions in sessionIds (every session when empty) and to permissions: send, read, sessions, webhooks or admin, which grants everything and cannot be limited to some sessions. Keys created with a tenant's key belong to that tenant; the server operator may set tenantId to create a key for a tenant. The key is only returned in this response; store it safely
+// @Tags			API Keys
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.CreateAPIKeyRequest		true	"API key"
+// @Success		201		{object}	dto.APIKeySecretResponse	"API key created"
+// @Failure		400		{object}	dto.ErrorResponse			"Invalid request"
+// @Failure		403		{object}	dto.ErrorResponse			"Admin permission required"
+// @Failure		500		{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/admin/api-keys [post]
+// @Security		ApiKeyAuth
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.apiKeyUseCases.Create(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("api_key_id", response.ID).
+		Str("tenant_id", response.TenantID).
+		Str("prefix", response.Prefix).
+		Strs("permissions", response.Permissions).
+		Strs("session_ids", response.SessionIDs).
+		Msg("API key created")
+
+	h.writeJSON(w, http.StatusCreated, response)
+}
+
+// @Summary		List API Keys
+// @Description	List every API key, revoked and expired ones included, newest first. Tenants only see their own keys. Secrets are never returned
+// @Tags			API Keys
+// @Produce		json
+// @Success		200	{object}	dto.APIKeyListResponse	"API keys"
+// @Failure		403	{object}	dto.ErrorResponse		"Admin permission required"
+// @Failure		500	{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/admin/api-keys [get]
+// @Security		ApiKeyAuth
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	response, err := h.apiKeyUseCases.List(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get API Key
+// @Description	Get an API key with its scope, expiry and when it was last used
+// @Tags			API Keys
+// @Produce		json
+// @Param			keyId	path		string				true	"API key ID"
+// @Success		200		{object}	dto.APIKeyResponse	"API key"
+// @Failure		403		{object}	dto.ErrorResponse	"Admin permission required"
+// @Failure		404		{object}	dto.ErrorResponse	"API key not found"
+// @Failure		500		{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/api-keys/{keyId} [get]
+// @Security		ApiKeyAuth
+func (h *APIKeyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.apiKeyUseCases.Get(r.Context(), chi.URLParam(r, "keyId"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Rotate API Key
+// @Description	Replace the secret of an API key, keeping its name, scope and expiry. The old secret stops working right away and the new one is only returned in this response
+// @Tags			API Keys
+// @Produce		json
+// @Param			keyId	path		string						true	"API key ID"
+// @Success		200		{object}	dto.APIKeySecretResponse	"API key rotated"
+// @Failure		403		{object}	dto.ErrorResponse			"Admin permission required"
+// @Failure		404		{object}	dto.ErrorResponse			"API key not found"
+// @Failure		409		{object}	dto.ErrorResponse			"API key is revoked"
+// @Failure		500		{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/admin/api-keys/{keyId}/rotate [post]
+// @Security		ApiKeyAuth
+func (h *APIKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, "API key rotated", func(ctx context.Context, keyID string) (interface{}, error) {
+		return h.apiKeyUseCases.Rotate(ctx, keyID)
+	})
+}
+
+// @Summary		Revoke API Key
+// @Description	Disable an API key for good. The key stays listed as revoked
+// @Tags			API Keys
+// @Produce		json
+// @Param			keyId	path		string				true	"API key ID"
+// @Success		200		{object}	dto.APIKeyResponse	"API key revoked"
+// @Failure		403		{object}	dto.ErrorResponse	"Admin permission required"
+// @Failure		404		{object}	dto.ErrorResponse	"API key not found"
+// @Failure		500		{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/api-keys/{keyId} [delete]
+// @Security		ApiKeyAuth
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, "API key revoked", func(ctx context.Context, keyID string) (interface{}, error) {
+		return h.apiKeyUseCases.Revoke(ctx, keyID)
+	})
+}
+
+func (h *APIKeyHandler) update(
+	w http.ResponseWriter,
+	r *http.Request,
+	message string,
+	update func(ctx context.Context, keyID string) (interface{}, error),
+) {
+	keyID := chi.URLParam(r, "keyId")
+
+	response, err := update(r.Context(), keyID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("api_key_id", keyID).
+		Msg(message)
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *APIKeyHandler) handleError(w http.ResponseWriter, err error) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, shared.ErrAPIKeyNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "api key not found")
+	case errors.Is(err, shared.ErrAPIKeyRevoked):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, "api key is revoked")
+	default:
+		h.logger.Error().Err(err).Msg("API key operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+
+func (h *APIKeyHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *APIKeyHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CampaignHandler struct {
+	campaignUseCases input.CampaignUseCases
+	logger           *logger.Logger
+}
+
+func NewCampaignHandler(campaignUseCases input.CampaignUseCases, logger *logger.Logger) *CampaignHandler {
+	return &CampaignHandler{
+		campaignUseCases: campaignUseCases,
+		logger:           logger,
+	}
+}
+
+// @Summary		Create Campaign
+// @Description	Create a campaign sending text to many recipients, one message every intervalSeconds (default 5). Recipients come either as a JSON list or as CSV with a header row holding a phone column; the other columns become variables. Placeholders such as {{name}} are filled from each recipient's variables and {{phone}} is always available. Recipients are checked against WhatsApp first and those without an account are marked as failed. The session must be connected
+// @Tags			Campaigns
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			request		body		dto.CreateCampaignRequest	true	"Campaign"
+// @Success		201			{object}	dto.CampaignResponse		"Campaign created"
+// @Failure		400			{object}	dto.ErrorResponse			"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse			"Session not found"
+// @Failure		412			{object}	dto.ErrorResponse			"Session not connected"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns [post]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	var req dto.CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.campaignUseCases.Create(r.Context(), sessionID, &req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("campaign_id", response.ID).
+		Int("recipients", response.Report.Total).
+		Msg("Campaign created")
+
+	h.writeJSON(w, http.StatusCreated, response)
+}
+
+// @Summary		List Campaigns
+// @Description	List the campaigns of a session, newest first
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			status		query		string					false	"running, paused, completed or cancelled"
+// @Param			limit		query		int						false	"Page size (default 20, max 100)"
+// @Param			offset		query		int						false	"Number of campaigns to skip"
+// @Success		200			{object}	dto.PaginationResponse	"Page with dto.CampaignResponse items"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse		"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns [get]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	query := r.URL.Query()
+
+	req := &dto.ListCampaignsRequest{
+		Status: query.Get("status"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "limit must be a number")
+		return
+	}
+
+	if req.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "offset must be a number")
+		return
+	}
+
+	response, err := h.campaignUseCases.List(r.Context(), sessionID, req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Campaign
+// @Description	Get a campaign with the number of recipients in each status
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			campaignId	path		string					true	"Campaign ID"
+// @Success		200			{object}	dto.CampaignResponse	"Campaign"
+// @Failure		404			{object}	dto.ErrorResponse		"Campaign not found"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId} [get]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	campaignID := chi.URLParam(r, "campaignId")
+
+	response, err := h.campaignUseCases.Get(r.Context(), sessionID, campaignID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Campaign Report
+// @Description	Summarize a campaign: recipients per status, progress, delivery and read rates and the most common errors
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			campaignId	path		string						true	"Campaign ID"
+// @Success		200			{object}	dto.CampaignReportResponse	"Campaign report"
+// @Failure		404			{object}	dto.ErrorResponse			"Campaign not found"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId}/report [get]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Report(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	campaignID := chi.URLParam(r, "campaignId")
+
+	response, err := h.campaignUseCases.Report(r.Context(), sessionID, campaignID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		List Campaign Recipients
+// @Description	List the recipients of a campaign with their status, the ID of the sent message and the error of failed ones
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			campaignId	path		string					true	"Campaign ID"
+// @Param			status		query		string					false	"queued, sent, delivered, read or failed"
+// @Param			limit		query		int						false	"Page size (default 20, max 100)"
+// @Param			offset		query		int						false	"Number of recipients to skip"
+// @Success		200			{object}	dto.PaginationResponse	"Page with dto.CampaignRecipientResponse items"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse		"Campaign not found"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId}/recipients [get]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) ListRecipients(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	campaignID := chi.URLParam(r, "campaignId")
+	query := r.URL.Query()
+
+	req := &dto.ListCampaignRecipientsRequest{
+		Status: query.Get("status"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "limit must be a number")
+		return
+	}
+
+	if req.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "offset must be a number")
+		return
+	}
+
+	response, err := h.campaignUseCases.ListRecipients(r.Context(), sessionID, campaignID, req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Pause Campaign
+// @Description	Stop sending a running campaign until it is resumed
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			campaignId	path		string					true	"Campaign ID"
+// @Success		200			{object}	dto.CampaignResponse	"Campaign paused"
+// @Failure		404			{object}	dto.ErrorResponse		"Campaign not found"
+// @Failure		409			{object}	dto.ErrorResponse		"Campaign already finished"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId}/pause [post]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.updateStatus(w, r, h.campaignUseCases.Pause)
+}
+
+// @Summary		Resume Campaign
+// @Description	Resume sending a paused campaign
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			campaignId	path		string					true	"Campaign ID"
+// @Success		200			{object}	dto.CampaignResponse	"Campaign resumed"
+// @Failure		404			{object}	dto.ErrorResponse		"Campaign not found"
+// @Failure		409			{object}	dto.ErrorResponse		"Campaign already finished"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId}/resume [post]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.updateStatus(w, r, h.campaignUseCases.Resume)
+}
+
+// @Summary		Cancel Campaign
+// @Description	Stop a campaign for good. Recipients not sent to yet stay queued
+// @Tags			Campaigns
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			campaignId	path		string					true	"Campaign ID"
+// @Success		200			{object}	dto.CampaignResponse	"Campaign cancelled"
+// @Failure		404			{object}	dto.ErrorResponse		"Campaign not found"
+// @Failure		409			{object}	dto.ErrorResponse		"Campaign already finished"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/campaigns/{campaignId}/cancel [post]
+// @Security		ApiKeyAuth
+func (h *CampaignHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.updateStatus(w, r, h.campaignUseCases.Cancel)
+}
+
+func (h *CampaignHandler) updateStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	update func(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error),
+) {
+	sessionID := chi.URLParam(r, "sessionId")
+	campaignID := chi.URLParam(r, "campaignId")
+
+	response, err := update(r.Context(), sessionID, campaignID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("campaign_id", campaignID).
+		Str("status", response.Status).
+		Msg("Campaign status updated")
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *CampaignHandler) handleError(w http.ResponseWriter, sessionID string, err error) {
+	var (
+		validationErr *dto.ValidationError
+		waErr         *output.WhatsAppError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, shared.ErrCampaignNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "campaign not found")
+	case errors.Is(err, shared.ErrCampaignFinished):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, "campaign is already finished")
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotFound.Code:
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotConnected.Code:
+		h.writeError(w, http.StatusPreconditionFailed, "not_connected", "session not connected")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Campaign operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+
+func (h *CampaignHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *CampaignHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
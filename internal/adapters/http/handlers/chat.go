@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ChatHandler struct {
+	messageUseCases input.MessageUseCases
+	logger          *logger.Logger
+}
+
+func NewChatHandler(messageUseCases input.MessageUseCases, logger *logger.Logger) *ChatHandler {
+	return &ChatHandler{
+		messageUseCases: messageUseCases,
+		logger:          logger,
+	}
+}
+
+// @Summary		List Chats
+// @Description	Lists the chats of a session with unread counters, archive/pin/mute flags and the last stored message
+// @Tags			Chats
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			limit		query		int						false	"Page size (default 20, max 100)"
+// @Param			offset		query		int						false	"Number of chats to skip"
+// @Param			sort		query		string					false	"lastMessage (default), unread or name"
+// @Param			order		query		string					false	"asc or desc"
+// @Success		200			{object}	dto.PaginationResponse	"Chat page with dto.ChatResponse items"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse		"Session not found"
+// @Failure		412			{object}	dto.ErrorResponse		"Session not connected"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/chats [get]
+// @Security		ApiKeyAuth
+func (h *ChatHandler) ListChats(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	query := r.URL.Query()
+
+	req := &dto.ListChatsRequest{
+		Sort:  query.Get("sort"),
+		Order: query.Get("order"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "limit must be a number")
+		return
+	}
+
+	if req.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "offset must be a number")
+		return
+	}
+
+	response, err := h.messageUseCases.GetChats(r.Context(), sessionID, req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Chat Messages
+// @Description	Lists the persisted messages of a chat, newest first. Pass nextCursor back as cursor to fetch older messages.
+// @Tags			Chats
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			chatJid		path		string							true	"Chat JID or phone number"
+// @Param			limit		query		int								false	"Page size (default 50, max 200)"
+// @Param			cursor		query		string							false	"Cursor returned as nextCursor by the previous page"
+// @Param			type		query		string							false	"Message type (text, image, audio, ...)"
+// @Param			fromMe		query		bool							false	"Only sent (true) or received (false) messages"
+// @Param			since		query		string							false	"Oldest timestamp (RFC3339)"
+// @Param			until		query		string							false	"Newest timestamp (RFC3339)"
+// @Param			q			query		string							false	"Full-text search over message content"
+// @Success		200			{object}	dto.MessageHistoryResponse		"Message page"
+// @Failure		400			{object}	dto.ErrorResponse				"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse				"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/sessions/{sessionId}/chats/{chatJid}/messages [get]
+// @Security		ApiKeyAuth
+func (h *ChatHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	req, err := parseMessageHistoryRequest(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, err.Error())
+		return
+	}
+
+	response, err := h.messageUseCases.GetMessageHistory(r.Context(), sessionID, req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Message Status
+// @Description	Get the delivery status of a sent message. status is sent once the server acknowledged the message, then delivered, read and played as receipts arrive; in groups it is the furthest status any participant reached and recipients lists each participant's receipt. Changes are also reported through the MessageStatus webhook
+// @Tags			Messages
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			messageId	path		string						true	"WhatsApp message ID"
+// @Success		200			{object}	dto.MessageStatusResponse	"Message status"
+// @Failure		404			{object}	dto.ErrorResponse			"Session or message not found"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/messages/{messageId}/status [get]
+// @Security		ApiKeyAuth
+func (h *ChatHandler) GetMessageStatus(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	messageID := chi.URLParam(r, "messageId")
+
+	response, err := h.messageUseCases.GetMessageStatus(r.Context(), sessionID, messageID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func parseMessageHistoryRequest(r *http.Request) (*dto.MessageHistoryRequest, error) {
+	query := r.URL.Query()
+
+	req := &dto.MessageHistoryRequest{
+		ChatJID: chi.URLParam(r, "chatJid"),
+		Cursor:  query.Get("cursor"),
+		Type:    query.Get("type"),
+		Search:  query.Get("q"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		return nil, dto.NewValidationError("limit", "limit must be a number")
+	}
+
+	if fromMe := query.Get("fromMe"); fromMe != "" {
+		value, err := strconv.ParseBool(fromMe)
+		if err != nil {
+			return nil, dto.NewValidationError("fromMe", "fromMe must be true or false")
+		}
+
+		req.FromMe = &value
+	}
+
+	if req.Since, err = parseTimeParam(query.Get("since")); err != nil {
+		return nil, dto.NewValidationError("since", "since must be an RFC3339 timestamp")
+	}
+
+	if req.Until, err = parseTimeParam(query.Get("until")); err != nil {
+		return nil, dto.NewValidationError("until", "until must be an RFC3339 timestamp")
+	}
+
+	return req, nil
+}
+
+func parseIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (h *ChatHandler) handleError(w http.ResponseWriter, sessionID string, err error) {
+	var (
+		validationErr *dto.ValidationError
+		waErr         *output.WhatsAppError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, shared.ErrMessageNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "message not found")
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotFound.Code:
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotConnected.Code:
+		h.writeError(w, http.StatusPreconditionFailed, "not_connected", "session not connected")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to query chats")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "failed to query chats")
+	}
+}
+
+func (h *ChatHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *ChatHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
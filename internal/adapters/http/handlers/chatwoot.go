@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxChatwootWebhookBody bounds the events read from the public webhook
+// route. Attachments are referenced by URL, so real events are far smaller.
+const maxChatwootWebhookBody = 1 << 20
+
+type ChatwootHandler struct {
+	chatwootUseCases input.ChatwootUseCases
+	logger           *logger.Logger
+}
+
+func NewChatwootHandler(chatwootUseCases input.ChatwootUseCases, logger *logger.Logger) *ChatwootHandler {
+	return &ChatwootHandler{
+		chatwootUseCases: chatwootUseCases,
+		logger:           logger,
+	}
+}
+
+// @Summary		Configure Chatwoot
+// @Description	Create the Chatwoot configuration of a session or update the given fields. Incoming messages are then posted to the Chatwoot inbox. The response carries the webhookSecret to put in the secret query parameter of the Chatwoot webhook URL; set rotateWebhookSecret to replace it
+// @Tags			Chatwoot
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			request		body		dto.ChatwootConfigRequest	true	"Chatwoot configuration"
+// @Success		200			{object}	dto.ChatwootConfigResponse	"Chatwoot configuration"
+// @Failure		400			{object}	dto.ErrorResponse			"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse			"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/chatwoot [put]
+// @Security		ApiKeyAuth
+func (h *ChatwootHandler) SetConfig(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	var req dto.ChatwootConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode chatwoot request")
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+
+		return
+	}
+
+	response, err := h.chatwootUseCases.SetConfig(r.Context(), sessionID, &req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().Str("session_id", sessionID).Bool("enabled", response.Enabled).Msg("Chatwoot configured")
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Chatwoot Configuration
+// @Description	Get the Chatwoot configuration of a session. The API token is never returned
+// @Tags			Chatwoot
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Success		200			{object}	dto.ChatwootConfigResponse	"Chatwoot configuration"
+// @Failure		404			{object}	dto.ErrorResponse			"Chatwoot not configured"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/chatwoot [get]
+// @Security		ApiKeyAuth
+func (h *ChatwootHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+
+	response, err := h.chatwootUseCases.GetConfig(r.Context(), sessionID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Delete Chatwoot Configuration
+// @Description	Remove the Chatwoot configuration of a session and stop posting its messages
+// @Tags			Chatwoot
+// @Produce		json
+// @Param			sessionId	path		string				true	"Session ID"
+// @Success		200			{object}	map[string]interface{}	"Chatwoot configuration deleted"
+// @Failure		404			{object}	dto.ErrorResponse	"Chatwoot not configured"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/sessions/{sessionId}/chatwoot [delete]
+// @Security		ApiKeyAuth
+func (h *ChatwootHandler) DeleteConfig(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+
+	if err := h.chatwootUseCases.DeleteConfig(r.Context(), sessionID); err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().Str("session_id", sessionID).Msg("Chatwoot configuration deleted")
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Chatwoot configuration deleted successfully",
+	})
+}
+
+// @Summary		Start Chatwoot Import
+// @Description	Import the WhatsApp contacts (importContacts) and the stored messages of the last importDays days (importMessages) into Chatwoot, skipping ignored chats. A failed or interrupted import resumes where it stopped; a completed one starts over. Imports also start automatically two minutes after a session is paired
+// @Tags			Chatwoot
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Success		202			{object}	dto.ChatwootImportResponse	"Import progress"
+// @Failure		400			{object}	dto.ErrorResponse			"Import not enabled"
+// @Failure		404			{object}	dto.ErrorResponse			"Chatwoot not configured"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/chatwoot/import [post]
+// @Security		ApiKeyAuth
+func (h *ChatwootHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+
+	response, err := h.chatwootUseCases.StartImport(r.Context(), sessionID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, response)
+}
+
+// @Summary		Get Chatwoot Import
+// @Description	Get the progress of the latest Chatwoot import of a session
+// @Tags			Chatwoot
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Success		200			{object}	dto.ChatwootImportResponse	"Import progress"
+// @Failure		404			{object}	dto.ErrorResponse			"No import found"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/chatwoot/import [get]
+// @Security		ApiKeyAuth
+func (h *ChatwootHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+
+	response, err := h.chatwootUseCases.GetImport(r.Context(), sessionID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Chatwoot Webhook
+// @Description	Receive Chatwoot webhook events. Agent replies (message_created, outgoing, not private) in conversations zpwoot synced a message to are sent to their WhatsApp chat; other events are acknowledged and ignored. Set this URL, with the webhookSecret of the Chatwoot config in the secret query parameter, as the webhook of the Chatwoot inbox. It is not protected by the API key
+// @Tags			Chatwoot
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			secret		query		string							true	"Webhook secret of the Chatwoot config"
+// @Param			request		body		dto.ChatwootWebhookRequest		true	"Chatwoot event"
+// @Success		200			{object}	dto.ChatwootWebhookResponse	"Event handled"
+// @Failure		400			{object}	dto.ErrorResponse				"Invalid request"
+// @Failure		401			{object}	dto.ErrorResponse				"Invalid webhook secret"
+// @Failure		404			{object}	dto.ErrorResponse				"Chatwoot not configured"
+// @Failure		413			{object}	dto.ErrorResponse				"Event too large"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/chatwoot/webhook/{sessionId} [post]
+func (h *ChatwootHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+
+	var req dto.ChatwootWebhookRequest
+
+	body := http.MaxBytesReader(w, r.Body, maxChatwootWebhookBody)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, dto.ErrorCodeBadRequest, "Event body too large")
+			return
+		}
+
+		h.logger.Error().Err(err).Msg("Failed to decode chatwoot webhook")
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+
+		return
+	}
+
+	response, err := h.chatwootUseCases.HandleWebhook(r.Context(), sessionID, r.URL.Query().Get("secret"), &req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	if response.Status == dto.ChatwootWebhookStatusIgnored {
+		h.logger.Debug().Str("session_id", sessionID).Str("event", req.Event).Str("reason", response.Reason).Msg("Chatwoot webhook ignored")
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *ChatwootHandler) handleError(w http.ResponseWriter, sessionID string, err error) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, shared.ErrChatwootConfigNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "chatwoot not configured for session")
+	case errors.Is(err, shared.ErrChatwootImportNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "no chatwoot import for session")
+	case errors.Is(err, shared.ErrChatwootWebhookSecret):
+		h.logger.Warn().Str("session_id", sessionID).Msg("Chatwoot webhook refused: invalid secret")
+		h.writeError(w, http.StatusUnauthorized, dto.ErrorCodeUnauthorized, "invalid webhook secret")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Chatwoot operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "internal error")
+	}
+}
+
+func (h *ChatwootHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *ChatwootHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
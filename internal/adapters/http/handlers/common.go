@@ -5,6 +5,7 @@ import (
 	"zpwoot/internal/adapters/logger"
 	"zpwoot/internal/adapters/waclient"
 	"zpwoot/internal/config"
+	"zpwoot/internal/core/application/utils"
 	"zpwoot/internal/core/ports/input"
 	"zpwoot/internal/core/ports/output"
 )
@@ -12,12 +13,19 @@ import (
 type Handlers struct {
 	Session    *SessionHandler
 	Message    *MessageHandler
+	Chat       *ChatHandler
 	Group      *GroupHandler
 	Contact    *ContactHandler
 	Community  *CommunityHandler
 	Newsletter *NewsletterHandler
 	Health     *HealthHandler
 	Webhook    *WebhookHandler
+	Chatwoot   *ChatwootHandler
+	Schedule   *ScheduleHandler
+	Campaign   *CampaignHandler
+	Media      *MediaHandler
+	APIKey     *APIKeyHandler
+	Tenant     *TenantHandler
 }
 
 func NewHandlers(
@@ -27,17 +35,31 @@ func NewHandlers(
 	sessionUseCases input.SessionUseCases,
 	messageUseCases input.MessageUseCases,
 	webhookUseCases input.WebhookUseCases,
+	chatwootUseCases input.ChatwootUseCases,
+	scheduleUseCases input.ScheduleUseCases,
+	campaignUseCases input.CampaignUseCases,
+	mediaUseCases input.MediaUseCases,
+	mediaProcessor *utils.MediaProcessor,
+	apiKeyUseCases input.APIKeyUseCases,
+	tenantUseCases input.TenantUseCases,
 	waClient output.WhatsAppClient,
 ) *Handlers {
 	return &Handlers{
 		Session:    createSessionHandler(logger, sessionUseCases, waClient),
-		Message:    createMessageHandler(logger, waClient),
+		Message:    createMessageHandler(logger, mediaProcessor, waClient),
+		Chat:       NewChatHandler(messageUseCases, logger),
 		Group:      createGroupHandler(logger, waClient),
 		Contact:    createContactHandler(logger, waClient),
 		Community:  createCommunityHandler(logger, waClient),
 		Newsletter: createNewsletterHandler(logger, waClient),
 		Health:     NewHealthHandler(db, logger),
 		Webhook:    NewWebhookHandler(webhookUseCases, logger),
+		Chatwoot:   NewChatwootHandler(chatwootUseCases, logger),
+		Schedule:   NewScheduleHandler(scheduleUseCases, logger),
+		Campaign:   NewCampaignHandler(campaignUseCases, logger),
+		Media:      NewMediaHandler(mediaUseCases, logger),
+		APIKey:     NewAPIKeyHandler(apiKeyUseCases, logger),
+		Tenant:     NewTenantHandler(tenantUseCases, logger),
 	}
 }
 
@@ -55,6 +77,7 @@ func createSessionHandler(
 
 func createMessageHandler(
 	logger *logger.Logger,
+	mediaProcessor *utils.MediaProcessor,
 	waClient output.WhatsAppClient,
 ) *MessageHandler {
 	waClientAdapter, ok := waClient.(*waclient.WAClientAdapter)
@@ -67,6 +90,7 @@ func createMessageHandler(
 
 	return NewMessageHandler(
 		messageService,
+		mediaProcessor,
 		logger,
 	)
 }
@@ -23,7 +23,7 @@ func NewContactHandler(contactService input.ContactService, logger output.Logger
 	}
 }
 
-// @Summary      Check if users are on WhatsApp
+// @Description  Verify if phone numbers are registered on WhatsApp. Brazilian mobile numbers are looked up with and without the ninth digit and the registered JID is returned
 // @Description  Verify if phone numbers are registered on WhatsApp
 // @Tags         Contacts
 // @Accept       json
@@ -73,6 +73,7 @@ func (h *ContactHandler) CheckUser(w http.ResponseWriter, r *http.Request) {
 			IsInWhatsApp: result.IsInWhatsApp,
 			JID:          result.JID,
 			VerifiedName: result.VerifiedName,
+			Candidates:   result.Candidates,
 		})
 	}
 
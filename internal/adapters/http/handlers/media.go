@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type MediaHandler struct {
+	mediaUseCases input.MediaUseCases
+	logger        *logger.Logger
+}
+
+func NewMediaHandler(mediaUseCases input.MediaUseCases, logger *logger.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaUseCases: mediaUseCases,
+		logger:        logger,
+	}
+}
+
+// @Summary		Get Media
+// @Description	Download the file of an image, video, audio, document or sticker message. Files not stored yet are downloaded from WhatsApp first; when WhatsApp no longer has the file it is requested again from the phone and the request answers 409 until it arrives. Webhook payloads link here through media.mediaUrl
+// @Tags			Messages
+// @Produce		application/octet-stream
+// @Param			sessionId	path		string				true	"Session ID"
+// @Param			messageId	path		string				true	"Message ID"
+// @Success		200			{file}		file				"Media file"
+// @Failure		404			{object}	dto.ErrorResponse	"Session or media not found"
+// @Failure		409			{object}	dto.ErrorResponse	"Media requested again from the phone, try later"
+// @Failure		412			{object}	dto.ErrorResponse	"Session not connected"
+// @Failure		413			{object}	dto.ErrorResponse	"Media exceeds the maximum download size"
+// @Failure		502			{object}	dto.ErrorResponse	"Media could not be downloaded from WhatsApp"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/sessions/{sessionId}/media/{messageId} [get]
+// @Security		ApiKeyAuth
+func (h *MediaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	messageID := chi.URLParam(r, "messageId")
+
+	if sessionID == "" || messageID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId and messageId are required")
+		return
+	}
+
+	file, err := h.mediaUseCases.Get(r.Context(), sessionID, messageID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+	defer file.Content.Close()
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+
+	if file.FileName != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": file.FileName}))
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file.Content); err != nil {
+		h.logger.Warn().Err(err).Str("session_id", sessionID).Str("message_id", messageID).Msg("Failed to stream media")
+	}
+}
+
+func (h *MediaHandler) handleError(w http.ResponseWriter, sessionID string, err error) {
+	var waErr *output.WhatsAppError
+
+	switch {
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, shared.ErrMediaNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "media not found")
+	case errors.Is(err, shared.ErrMediaRetrying):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, err.Error())
+	case errors.Is(err, shared.ErrMediaTooLarge):
+		h.writeError(w, http.StatusRequestEntityTooLarge, dto.ErrorCodeValidation, err.Error())
+	case errors.Is(err, shared.ErrMediaFailed):
+		h.writeError(w, http.StatusBadGateway, dto.ErrorCodeServiceError, err.Error())
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotFound.Code:
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.As(err, &waErr) && waErr.Code == output.ErrSessionNotConnected.Code:
+		h.writeError(w, http.StatusPreconditionFailed, "not_connected", "session not connected")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to get media")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "failed to get media")
+	}
+}
+
+func (h *MediaHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
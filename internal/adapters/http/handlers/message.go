@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,14 +24,29 @@ const (
 	whatsappNetSuffix = "@s.whatsapp.net"
 )
 
+const (
+	// maxFormFields and maxFormFieldSize bound the fields of a multipart
+	// media request other than its file.
+	maxFormFields    = 32
+	maxFormFieldSize = 64 << 10
+
+	// uploadTimeout is how long a multipart media request may take to
+	// arrive; sending it may take as long again.
+	uploadTimeout = 10 * time.Minute
+)
+
+var errInvalidForm = errors.New("invalid multipart form")
+
 type MessageHandler struct {
 	messageService input.MessageService
+	mediaProcessor *utils.MediaProcessor
 	logger         output.Logger
 }
 
-func NewMessageHandler(messageService input.MessageService, logger output.Logger) *MessageHandler {
+func NewMessageHandler(messageService input.MessageService, mediaProcessor *utils.MediaProcessor, logger output.Logger) *MessageHandler {
 	return &MessageHandler{
 		messageService: messageService,
+		mediaProcessor: mediaProcessor,
 		logger:         logger,
 	}
 }
@@ -61,11 +80,14 @@ func (h *MessageHandler) buildMessageResponse(result *output.MessageResult, to,
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                        true  "Session ID"
 // @Param        message     body      dto.SendTextMessageRequest    true  "Text message data"
+// @Param        async       query     bool                          false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse       "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse       "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse             "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse             "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse             "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse             "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse             "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse             "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/text [post]
 func (h *MessageHandler) SendText(w http.ResponseWriter, r *http.Request) {
@@ -99,7 +121,7 @@ func (h *MessageHandler) SendText(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result, err := h.messageService.SendTextMessage(r.Context(), sessionID, req.Phone, req.Text, contextInfo)
+	result, err := h.messageService.SendTextMessage(h.sendContext(r), sessionID, req.Phone, req.Text, contextInfo)
 	if err != nil {
 		h.logger.Error().
 			Err(err).
@@ -117,22 +139,27 @@ func (h *MessageHandler) SendText(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "text", req.Text)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send image message
-// @Description  Send an image message to a WhatsApp contact with optional caption. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed.
+// @Description  Send an image message to a WhatsApp contact with optional caption. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed. Also accepts multipart/form-data with the file streamed in a "file" part and the other fields as form values.
 // @Tags         Messages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                        true  "Session ID"
 // @Param        message     body      dto.SendImageMessageRequest   true  "Image message data"
+// @Param        async       query     bool                          false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse       "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse       "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse             "Invalid request or media processing error"
 // @Failure      401         {object}  dto.ErrorResponse             "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse             "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse             "Session not connected"
+// @Failure      413         {object}  dto.ErrorResponse             "Media exceeds the size limit of its type"
+// @Failure      415         {object}  dto.ErrorResponse             "Media content does not match its type"
+// @Failure      429         {object}  dto.ErrorResponse             "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse             "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/image [post]
 func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
@@ -143,17 +170,19 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SendImageMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+
+	upload, ok := h.decodeMediaRequest(w, r, utils.MediaKindImage, &req)
+	if !ok {
 		return
 	}
+	defer h.mediaProcessor.Release(upload)
 
 	if req.Phone == "" {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "phone is required")
 		return
 	}
 
-	if req.File == "" {
+	if req.File == "" && upload == nil {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "file is required")
 		return
 	}
@@ -166,24 +195,15 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaProcessor := utils.NewMediaProcessor()
-
-	media, err := mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
-	if err != nil {
-		h.logger.Error().
-			Err(err).
-			Str("session_id", sessionID).
-			Str("file", req.File).
-			Msg("Failed to process media")
-		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
-
+	media, ok := h.loadMedia(w, sessionID, utils.MediaKindImage, upload, req.File, req.MimeType, req.FileName)
+	if !ok {
 		return
 	}
 
 	media.Caption = req.Caption
 	media.ViewOnce = req.ViewOnce
 
-	result, err := h.messageService.SendMediaMessage(r.Context(), sessionID, req.Phone, media, contextInfo)
+	result, err := h.messageService.SendMediaMessage(h.sendContext(r), sessionID, req.Phone, media, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -195,22 +215,27 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "image", req.Caption)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send audio message
-// @Description  Send an audio message to a WhatsApp contact. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed.
+// @Description  Send an audio message to a WhatsApp contact. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed. Also accepts multipart/form-data with the file streamed in a "file" part and the other fields as form values.
 // @Tags         Messages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                        true  "Session ID"
 // @Param        message     body      dto.SendAudioMessageRequest   true  "Audio message data"
+// @Param        async       query     bool                          false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse       "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse       "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse             "Invalid request or media processing error"
 // @Failure      401         {object}  dto.ErrorResponse             "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse             "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse             "Session not connected"
+// @Failure      413         {object}  dto.ErrorResponse             "Media exceeds the size limit of its type"
+// @Failure      415         {object}  dto.ErrorResponse             "Media content does not match its type"
+// @Failure      429         {object}  dto.ErrorResponse             "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse             "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/audio [post]
 func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
@@ -221,17 +246,19 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SendAudioMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+
+	upload, ok := h.decodeMediaRequest(w, r, utils.MediaKindAudio, &req)
+	if !ok {
 		return
 	}
+	defer h.mediaProcessor.Release(upload)
 
 	if req.Phone == "" {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "phone is required")
 		return
 	}
 
-	if req.File == "" {
+	if req.File == "" && upload == nil {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "file is required")
 		return
 	}
@@ -244,23 +271,14 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaProcessor := utils.NewMediaProcessor()
-
-	media, err := mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
-	if err != nil {
-		h.logger.Error().
-			Err(err).
-			Str("session_id", sessionID).
-			Str("file", req.File).
-			Msg("Failed to process media")
-		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
-
+	media, ok := h.loadMedia(w, sessionID, utils.MediaKindAudio, upload, req.File, req.MimeType, req.FileName)
+	if !ok {
 		return
 	}
 
 	media.ViewOnce = req.ViewOnce
 
-	result, err := h.messageService.SendMediaMessage(r.Context(), sessionID, req.Phone, media, contextInfo)
+	result, err := h.messageService.SendMediaMessage(h.sendContext(r), sessionID, req.Phone, media, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -272,7 +290,211 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "audio", "")
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
+}
+
+// sendContext returns the context for a send request. With async=true the
+// message is queued and the request returns without waiting for the send.
+func (h *MessageHandler) sendContext(r *http.Request) context.Context {
+	if async, _ := strconv.ParseBool(r.URL.Query().Get("async")); async {
+		return output.WithQueuedSend(r.Context())
+	}
+
+	return r.Context()
+}
+
+// decodeMediaRequest decodes a media send request into req. JSON bodies name
+// the file in the file field as a URL, path or base64; multipart/form-data
+// bodies stream it in a "file" part, with the other fields as form values
+// and contextInfo as JSON. The streamed file is returned already checked,
+// and must be released with h.mediaProcessor.Release.
+func (h *MessageHandler) decodeMediaRequest(w http.ResponseWriter, r *http.Request, kind utils.MediaKind, req interface{}) (*output.MediaData, bool) {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "multipart/form-data" {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+			return nil, false
+		}
+
+		return nil, true
+	}
+
+	// Large files take longer than the server timeouts, which are sized for
+	// JSON requests.
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Now().Add(uploadTimeout))
+	_ = rc.SetWriteDeadline(time.Now().Add(2 * uploadTimeout))
+
+	upload, err := h.readMultipartMedia(r, kind, req)
+	if err != nil {
+		h.logger.Warn().Err(err).Str("kind", string(kind)).Msg("Failed to read media upload")
+		h.writeMediaError(w, err)
+
+		return nil, false
+	}
+
+	return upload, true
+}
+
+// readMultipartMedia reads the fields of a multipart media request into req
+// and streams its file to a temporary file. The fileName and mimeType
+// fields apply to the file when they come before it. A second file part is
+// refused.
+func (h *MessageHandler) readMultipartMedia(r *http.Request, kind utils.MediaKind, req interface{}) (*output.MediaData, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidForm, err)
+	}
+
+	fields := make(map[string]interface{})
+
+	var upload *output.MediaData
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			h.mediaProcessor.Release(upload)
+			return nil, fmt.Errorf("%w: %w", errInvalidForm, err)
+		}
+
+		switch name := part.FormName(); {
+		case name == "file" && upload != nil:
+			err = fmt.Errorf("%w: only one file may be uploaded", errInvalidForm)
+		case name == "file" && part.FileName() != "":
+			upload, err = h.mediaProcessor.ProcessUpload(kind, part, formString(fields, "mimeType", part.Header.Get("Content-Type")), formString(fields, "fileName", part.FileName()))
+		case name != "":
+			err = readFormField(fields, name, part)
+		}
+
+		_ = part.Close()
+
+		if err != nil {
+			h.mediaProcessor.Release(upload)
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(fields)
+	if err == nil {
+		err = json.Unmarshal(body, req)
+	}
+
+	if err != nil {
+		h.mediaProcessor.Release(upload)
+		return nil, fmt.Errorf("%w: %w", errInvalidForm, err)
+	}
+
+	return upload, nil
+}
+
+// readFormField reads a form value the way it would appear in the JSON
+// body: viewOnce as a boolean, contextInfo as an object and the rest as
+// strings.
+func readFormField(fields map[string]interface{}, name string, part io.Reader) error {
+	if _, ok := fields[name]; !ok && len(fields) >= maxFormFields {
+		return fmt.Errorf("%w: too many fields", errInvalidForm)
+	}
+
+	value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidForm, err)
+	}
+
+	if len(value) > maxFormFieldSize {
+		return fmt.Errorf("%w: field %s is too long", errInvalidForm, name)
+	}
+
+	switch name {
+	case "viewOnce":
+		viewOnce, err := strconv.ParseBool(string(value))
+		if err != nil {
+			return fmt.Errorf("%w: viewOnce must be true or false", errInvalidForm)
+		}
+
+		fields[name] = viewOnce
+	case "contextInfo":
+		if !json.Valid(value) {
+			return fmt.Errorf("%w: contextInfo must be a JSON object", errInvalidForm)
+		}
+
+		fields[name] = json.RawMessage(value)
+	default:
+		fields[name] = string(value)
+	}
+
+	return nil
+}
+
+func formString(fields map[string]interface{}, name, fallback string) string {
+	if value, ok := fields[name].(string); ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// loadMedia returns the media of a send request: the uploaded file, or the
+// one named in the file field of a JSON body, checked against the size
+// limit and type of kind.
+func (h *MessageHandler) loadMedia(w http.ResponseWriter, sessionID string, kind utils.MediaKind, upload *output.MediaData, file, mimeType, fileName string) (*output.MediaData, bool) {
+	if upload != nil {
+		if fileName != "" {
+			upload.FileName = fileName
+		}
+
+		return upload, true
+	}
+
+	media, err := h.mediaProcessor.ProcessMedia(file, mimeType, fileName)
+	if err == nil {
+		err = h.mediaProcessor.Verify(kind, media)
+	}
+
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("file", file).
+			Msg("Failed to process media")
+		h.writeMediaError(w, err)
+
+		return nil, false
+	}
+
+	return media, true
+}
+
+func (h *MessageHandler) writeMediaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrMediaTooLarge):
+		h.writeError(w, http.StatusRequestEntityTooLarge, "media_too_large", err.Error())
+	case errors.Is(err, utils.ErrMediaTypeInvalid):
+		h.writeError(w, http.StatusUnsupportedMediaType, "unsupported_media_type", err.Error())
+	case errors.Is(err, errInvalidForm):
+		h.writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
+	}
+}
+
+// writeMessageResponse answers a send with 200, or 202 when the message was
+// only queued.
+func (h *MessageHandler) writeMessageResponse(w http.ResponseWriter, response *dto.SendMessageResponse) {
+	if response.Status != output.MessageStatusQueued {
+		h.writeJSON(w, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
 }
 
 func (h *MessageHandler) writeJSON(w http.ResponseWriter, data interface{}) {
@@ -316,6 +538,14 @@ func (h *MessageHandler) handleMessageError(w http.ResponseWriter, err error) {
 			h.writeError(w, http.StatusPreconditionFailed, "not_connected", "Session not connected")
 		case "INVALID_JID":
 			h.writeError(w, http.StatusBadRequest, "invalid_jid", "Invalid recipient JID")
+		case "NO_HISTORY_ANCHOR":
+			h.writeError(w, http.StatusConflict, "no_history_anchor", waErr.Message)
+		case "SEND_QUEUE_FULL":
+			h.writeError(w, http.StatusTooManyRequests, "send_queue_full", waErr.Message)
+		case "QUOTA_EXCEEDED":
+			h.writeError(w, http.StatusTooManyRequests, "quota_exceeded", waErr.Message)
+		case "TENANT_SUSPENDED":
+			h.writeError(w, http.StatusForbidden, "tenant_suspended", waErr.Message)
 		default:
 			h.writeError(w, http.StatusInternalServerError, "whatsapp_error", waErr.Message)
 		}
@@ -327,18 +557,23 @@ func (h *MessageHandler) handleMessageError(w http.ResponseWriter, err error) {
 }
 
 // @Summary      Send video message
-// @Description  Send a video message to a WhatsApp contact with optional caption. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed.
+// @Description  Send a video message to a WhatsApp contact with optional caption. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Set viewOnce to true to send as a view-once message that disappears after being viewed. Also accepts multipart/form-data with the file streamed in a "file" part and the other fields as form values.
 // @Tags         Messages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                        true  "Session ID"
 // @Param        message     body      dto.SendVideoMessageRequest   true  "Video message data"
+// @Param        async       query     bool                          false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse       "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse       "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse             "Invalid request or media processing error"
 // @Failure      401         {object}  dto.ErrorResponse             "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse             "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse             "Session not connected"
+// @Failure      413         {object}  dto.ErrorResponse             "Media exceeds the size limit of its type"
+// @Failure      415         {object}  dto.ErrorResponse             "Media content does not match its type"
+// @Failure      429         {object}  dto.ErrorResponse             "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse             "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/video [post]
 func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
@@ -349,17 +584,19 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SendVideoMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+
+	upload, ok := h.decodeMediaRequest(w, r, utils.MediaKindVideo, &req)
+	if !ok {
 		return
 	}
+	defer h.mediaProcessor.Release(upload)
 
 	if req.Phone == "" {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "phone is required")
 		return
 	}
 
-	if req.File == "" {
+	if req.File == "" && upload == nil {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "file is required")
 		return
 	}
@@ -372,24 +609,15 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaProcessor := utils.NewMediaProcessor()
-
-	media, err := mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
-	if err != nil {
-		h.logger.Error().
-			Err(err).
-			Str("session_id", sessionID).
-			Str("file", req.File).
-			Msg("Failed to process media")
-		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
-
+	media, ok := h.loadMedia(w, sessionID, utils.MediaKindVideo, upload, req.File, req.MimeType, req.FileName)
+	if !ok {
 		return
 	}
 
 	media.Caption = req.Caption
 	media.ViewOnce = req.ViewOnce
 
-	result, err := h.messageService.SendMediaMessage(r.Context(), sessionID, req.Phone, media, contextInfo)
+	result, err := h.messageService.SendMediaMessage(h.sendContext(r), sessionID, req.Phone, media, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -401,22 +629,27 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "video", req.Caption)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send document message
-// @Description  Send a document message to a WhatsApp contact. Supports Base64, URL, or file path. Supports reply/quote using contextInfo.
+// @Description  Send a document message to a WhatsApp contact. Supports Base64, URL, or file path. Supports reply/quote using contextInfo. Also accepts multipart/form-data with the file streamed in a "file" part and the other fields as form values.
 // @Tags         Messages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                           true  "Session ID"
 // @Param        message     body      dto.SendDocumentMessageRequest   true  "Document message data"
+// @Param        async       query     bool                             false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse          "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse          "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse                "Invalid request or media processing error"
 // @Failure      401         {object}  dto.ErrorResponse                "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse                "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse                "Session not connected"
+// @Failure      413         {object}  dto.ErrorResponse                "Media exceeds the size limit of its type"
+// @Failure      415         {object}  dto.ErrorResponse                "Media content does not match its type"
+// @Failure      429         {object}  dto.ErrorResponse                "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse                "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/document [post]
 func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
@@ -427,17 +660,19 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SendDocumentMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+
+	upload, ok := h.decodeMediaRequest(w, r, utils.MediaKindDocument, &req)
+	if !ok {
 		return
 	}
+	defer h.mediaProcessor.Release(upload)
 
 	if req.Phone == "" {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "phone is required")
 		return
 	}
 
-	if req.File == "" {
+	if req.File == "" && upload == nil {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "file is required")
 		return
 	}
@@ -450,23 +685,14 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaProcessor := utils.NewMediaProcessor()
-
-	media, err := mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
-	if err != nil {
-		h.logger.Error().
-			Err(err).
-			Str("session_id", sessionID).
-			Str("file", req.File).
-			Msg("Failed to process media")
-		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
-
+	media, ok := h.loadMedia(w, sessionID, utils.MediaKindDocument, upload, req.File, req.MimeType, req.FileName)
+	if !ok {
 		return
 	}
 
 	media.Caption = req.Caption
 
-	result, err := h.messageService.SendMediaMessage(r.Context(), sessionID, req.Phone, media, contextInfo)
+	result, err := h.messageService.SendMediaMessage(h.sendContext(r), sessionID, req.Phone, media, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -478,7 +704,7 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "document", req.Caption)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send location message
@@ -489,11 +715,14 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                           true  "Session ID"
 // @Param        message     body      dto.SendLocationMessageRequest   true  "Location message data"
+// @Param        async       query     bool                             false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse          "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse          "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse                "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse                "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse                "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse                "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse                "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse                "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/location [post]
 func (h *MessageHandler) SendLocation(w http.ResponseWriter, r *http.Request) {
@@ -522,7 +751,7 @@ func (h *MessageHandler) SendLocation(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result, err := h.messageService.SendLocationMessage(r.Context(), sessionID, req.Phone, req.Latitude, req.Longitude, req.Name, contextInfo)
+	result, err := h.messageService.SendLocationMessage(h.sendContext(r), sessionID, req.Phone, req.Latitude, req.Longitude, req.Name, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -534,7 +763,7 @@ func (h *MessageHandler) SendLocation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "location", req.Name)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send contact message
@@ -545,11 +774,14 @@ func (h *MessageHandler) SendLocation(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                          true  "Session ID"
 // @Param        message     body      dto.SendContactMessageRequest   true  "Contact message data"
+// @Param        async       query     bool                            false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse         "Message sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse         "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse               "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse               "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse               "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse               "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse               "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse               "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/contact [post]
 func (h *MessageHandler) SendContact(w http.ResponseWriter, r *http.Request) {
@@ -589,7 +821,7 @@ func (h *MessageHandler) SendContact(w http.ResponseWriter, r *http.Request) {
 		VCard: req.Contact.VCard,
 	}
 
-	result, err := h.messageService.SendContactMessage(r.Context(), sessionID, req.Phone, contactInfo, contextInfo)
+	result, err := h.messageService.SendContactMessage(h.sendContext(r), sessionID, req.Phone, contactInfo, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -601,7 +833,7 @@ func (h *MessageHandler) SendContact(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "contact", contactInfo.Name)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send reaction message
@@ -612,11 +844,14 @@ func (h *MessageHandler) SendContact(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                           true  "Session ID"
 // @Param        message     body      dto.SendReactionMessageRequest   true  "Reaction message data"
+// @Param        async       query     bool                             false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse          "Reaction sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse          "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse                "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse                "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse                "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse                "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse                "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse                "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/reaction [post]
 func (h *MessageHandler) SendReaction(w http.ResponseWriter, r *http.Request) {
@@ -654,7 +889,7 @@ func (h *MessageHandler) SendReaction(w http.ResponseWriter, r *http.Request) {
 		fromMe = *req.FromMe
 	}
 
-	result, err := h.messageService.SendReactionMessage(r.Context(), sessionID, req.Phone, messageID, req.Reaction, fromMe)
+	result, err := h.messageService.SendReactionMessage(h.sendContext(r), sessionID, req.Phone, messageID, req.Reaction, fromMe)
 	if err != nil {
 		h.logger.Error().
 			Err(err).
@@ -673,7 +908,7 @@ func (h *MessageHandler) SendReaction(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "reaction", req.Reaction)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send poll message
@@ -684,11 +919,14 @@ func (h *MessageHandler) SendReaction(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                       true  "Session ID"
 // @Param        message     body      dto.SendPollMessageRequest   true  "Poll message data"
+// @Param        async       query     bool                         false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse      "Poll sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse      "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse            "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse            "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse            "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse            "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse            "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse            "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/poll [post]
 func (h *MessageHandler) SendPoll(w http.ResponseWriter, r *http.Request) {
@@ -719,7 +957,7 @@ func (h *MessageHandler) SendPoll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.messageService.SendPollMessage(r.Context(), sessionID, req.Phone, req.Name, req.Options, req.SelectableOptionsCount)
+	result, err := h.messageService.SendPollMessage(h.sendContext(r), sessionID, req.Phone, req.Name, req.Options, req.SelectableOptionsCount)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -731,22 +969,27 @@ func (h *MessageHandler) SendPoll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "poll", req.Name)
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send sticker message
-// @Description  Send a sticker message to a WhatsApp contact. Supports Base64, URL, or file path. Image will be converted to WebP format.
+// @Description  Send a sticker message to a WhatsApp contact. Supports Base64, URL, or file path. Image will be converted to WebP format. Also accepts multipart/form-data with the file streamed in a "file" part and the other fields as form values.
 // @Tags         Messages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                          true  "Session ID"
 // @Param        message     body      dto.SendStickerMessageRequest   true  "Sticker message data"
+// @Param        async       query     bool                            false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse         "Sticker sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse         "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse               "Invalid request or media processing error"
 // @Failure      401         {object}  dto.ErrorResponse               "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse               "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse               "Session not connected"
+// @Failure      413         {object}  dto.ErrorResponse               "Media exceeds the size limit of its type"
+// @Failure      415         {object}  dto.ErrorResponse               "Media content does not match its type"
+// @Failure      429         {object}  dto.ErrorResponse               "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse               "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/sticker [post]
 func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
@@ -757,17 +1000,19 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SendStickerMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+
+	upload, ok := h.decodeMediaRequest(w, r, utils.MediaKindSticker, &req)
+	if !ok {
 		return
 	}
+	defer h.mediaProcessor.Release(upload)
 
 	if req.Phone == "" {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "phone is required")
 		return
 	}
 
-	if req.File == "" {
+	if req.File == "" && upload == nil {
 		h.writeError(w, http.StatusBadRequest, "validation_error", "file is required")
 		return
 	}
@@ -780,21 +1025,14 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaProcessor := utils.NewMediaProcessor()
-
-	media, err := mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
-	if err != nil {
-		h.logger.Error().
-			Err(err).
-			Str("session_id", sessionID).
-			Str("file", req.File).
-			Msg("Failed to process media")
-		h.writeError(w, http.StatusBadRequest, "media_processing_error", fmt.Sprintf("Failed to process media: %v", err))
-
+	media, ok := h.loadMedia(w, sessionID, utils.MediaKindSticker, upload, req.File, req.MimeType, req.FileName)
+	if !ok {
 		return
 	}
 
-	result, err := h.messageService.SendMediaMessage(r.Context(), sessionID, req.Phone, media, contextInfo)
+	media.Sticker = true
+
+	result, err := h.messageService.SendMediaMessage(h.sendContext(r), sessionID, req.Phone, media, contextInfo)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -806,7 +1044,7 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "sticker", "")
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send multiple contacts
@@ -817,11 +1055,14 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                                true  "Session ID"
 // @Param        message     body      dto.SendMultipleContactsRequest   true  "Contacts array message data"
+// @Param        async       query     bool                              false "Queue the message and return 202 with its ID; the outcome is reported by the MessageSent or MessageSendFailed webhook"
 // @Success      200         {object}  dto.SendMessageResponse               "Contacts sent successfully"
+// @Success      202         {object}  dto.SendMessageResponse               "Message queued (async=true)"
 // @Failure      400         {object}  dto.ErrorResponse                     "Invalid request"
 // @Failure      401         {object}  dto.ErrorResponse                     "Unauthorized"
 // @Failure      404         {object}  dto.ErrorResponse                     "Session not found"
 // @Failure      412         {object}  dto.ErrorResponse                     "Session not connected"
+// @Failure      429         {object}  dto.ErrorResponse                     "Send queue full"
 // @Failure      500         {object}  dto.ErrorResponse                     "Internal server error"
 // @Router       /sessions/{sessionId}/messages/send/contacts [post]
 func (h *MessageHandler) SendContactsArray(w http.ResponseWriter, r *http.Request) {
@@ -856,7 +1097,7 @@ func (h *MessageHandler) SendContactsArray(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	result, err := h.messageService.SendContactsArrayMessage(r.Context(), sessionID, req.Phone, contacts)
+	result, err := h.messageService.SendContactsArrayMessage(h.sendContext(r), sessionID, req.Phone, contacts)
 	if err != nil {
 		h.handleMessageError(w, err)
 		return
@@ -868,7 +1109,7 @@ func (h *MessageHandler) SendContactsArray(w http.ResponseWriter, r *http.Reques
 	}
 
 	response := h.buildMessageResponse(result, normalizedTo, "contacts", fmt.Sprintf("%d contacts", len(contacts)))
-	h.writeJSON(w, response)
+	h.writeMessageResponse(w, response)
 }
 
 // @Summary      Send template message
@@ -1090,16 +1331,17 @@ func (h *MessageHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary      Request history sync
-// @Description  Request synchronization of message history
+// @Description  Request older messages of a chat from the primary device. The request is anchored at the oldest stored message of the chat; the messages arrive asynchronously, are stored and delivered as paginated HistorySync webhooks.
 // @Tags         Messages
 // @Accept       json
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Param        sessionId   path      string                           true  "Session ID"
-// @Param        request     body      dto.HistorySyncRequest    false "History sync request"
+// @Param        request     body      dto.HistorySyncRequest    true "History sync request"
 // @Success      200  {object}  dto.HistorySyncResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse "No stored message to anchor the request"
 // @Failure      500  {object}  dto.ErrorResponse
 // @Router       /sessions/{sessionId}/messages/historysync [post]
 func (h *MessageHandler) RequestHistorySync(w http.ResponseWriter, r *http.Request) {
@@ -1110,11 +1352,14 @@ func (h *MessageHandler) RequestHistorySync(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req dto.HistorySyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
 
-	if r.Body != nil {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			h.logger.Error().Err(err).Msg("Failed to decode request body")
-		}
+	if req.ChatJID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "chatJid is required")
+		return
 	}
 
 	count := req.Count
@@ -1122,15 +1367,16 @@ func (h *MessageHandler) RequestHistorySync(w http.ResponseWriter, r *http.Reque
 		count = 50
 	}
 
-	err := h.messageService.RequestHistorySync(r.Context(), sessionID, count)
+	err := h.messageService.RequestHistorySync(r.Context(), sessionID, req.ChatJID, count)
 	if err != nil {
 		h.logger.Error().
 			Err(err).
 			Str("session_id", sessionID).
+			Str("chat", req.ChatJID).
 			Int("count", count).
 			Msg("Failed to request history sync")
 
-		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "Failed to request history sync")
+		h.handleMessageError(w, err)
 
 		return
 	}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/utils"
+)
+
+type formPart struct {
+	name     string
+	fileName string
+	content  string
+}
+
+func newMultipartRequest(t *testing.T, parts []formPart) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range parts {
+		var (
+			w   io.Writer
+			err error
+		)
+
+		if part.fileName != "" {
+			w, err = writer.CreateFormFile(part.name, part.fileName)
+		} else {
+			w, err = writer.CreateFormField(part.name)
+		}
+
+		if err != nil {
+			t.Fatalf("failed to build form: %v", err)
+		}
+
+		_, _ = w.Write([]byte(part.content))
+	}
+
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/session-1/send/message/document", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func TestDecodeMediaRequestFileParts(t *testing.T) {
+	tests := []struct {
+		name       string
+		parts      []formPart
+		wantOK     bool
+		wantStatus int
+	}{
+		{
+			name:   "one file",
+			parts:  []formPart{{"to", "", "5511999999999"}, {"file", "report.txt", "first"}},
+			wantOK: true,
+		},
+		{
+			name:       "two files",
+			parts:      []formPart{{"file", "report.txt", "first"}, {"file", "other.txt", "second"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "file and a file field after it",
+			parts:      []formPart{{"file", "report.txt", "first"}, {"file", "", "https://example.com/other.txt"}},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	handler := NewMessageHandler(nil, utils.NewMediaProcessor(), logger.New())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			var req map[string]interface{}
+
+			upload, ok := handler.decodeMediaRequest(rec, newMultipartRequest(t, tt.parts), utils.MediaKindDocument, &req)
+			defer handler.mediaProcessor.Release(upload)
+
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v (status %d, body %s)", ok, tt.wantOK, rec.Code, rec.Body.String())
+			}
+
+			if !tt.wantOK {
+				if rec.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+				}
+
+				return
+			}
+
+			if upload == nil || upload.FileName != "report.txt" {
+				t.Fatalf("upload = %+v, want report.txt", upload)
+			}
+
+			if req["to"] != "5511999999999" {
+				t.Errorf("to = %v, want 5511999999999", req["to"])
+			}
+		})
+	}
+}
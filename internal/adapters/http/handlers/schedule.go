@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ScheduleHandler struct {
+	scheduleUseCases input.ScheduleUseCases
+	logger           *logger.Logger
+}
+
+func NewScheduleHandler(scheduleUseCases input.ScheduleUseCases, logger *logger.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleUseCases: scheduleUseCases,
+		logger:           logger,
+	}
+}
+
+// @Summary		Schedule Message
+// @Description	Schedule a message to be sent at sendAt. message is the body of the send endpoint matching type (text, image, audio, video, document, sticker, location, contact or poll). sendAt is RFC3339 or a local time in timezone (IANA name, UTC by default). onDisconnected decides what happens when the session is not connected at sendAt: retry (default) keeps trying for up to 24 hours, skip gives up at once. The outcome is reported through the ScheduledMessageSent and ScheduledMessageFailed webhooks
+// @Tags			Messages
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			request		body		dto.ScheduleMessageRequest		true	"Scheduled message"
+// @Success		201			{object}	dto.ScheduledMessageResponse	"Message scheduled"
+// @Failure		400			{object}	dto.ErrorResponse				"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse				"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/sessions/{sessionId}/messages/schedule [post]
+// @Security		ApiKeyAuth
+func (h *ScheduleHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	var req dto.ScheduleMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.scheduleUseCases.Schedule(r.Context(), sessionID, &req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("schedule_id", response.ID).
+		Str("type", response.Type).
+		Time("send_at", response.SendAt).
+		Msg("Message scheduled")
+
+	h.writeJSON(w, http.StatusCreated, response)
+}
+
+// @Summary		List Scheduled Messages
+// @Description	List the scheduled messages of a session by send time
+// @Tags			Messages
+// @Produce		json
+// @Param			sessionId	path		string					true	"Session ID"
+// @Param			status		query		string					false	"pending, sent, failed, skipped or cancelled"
+// @Param			limit		query		int						false	"Page size (default 20, max 100)"
+// @Param			offset		query		int						false	"Number of messages to skip"
+// @Success		200			{object}	dto.PaginationResponse	"Page with dto.ScheduledMessageResponse items"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse		"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/sessions/{sessionId}/messages/schedule [get]
+// @Security		ApiKeyAuth
+func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	query := r.URL.Query()
+
+	req := &dto.ListScheduledMessagesRequest{
+		Status: query.Get("status"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "limit must be a number")
+		return
+	}
+
+	if req.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "offset must be a number")
+		return
+	}
+
+	response, err := h.scheduleUseCases.List(r.Context(), sessionID, req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Scheduled Message
+// @Description	Get a scheduled message with its body, status and the ID of the sent message
+// @Tags			Messages
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			scheduleId	path		string							true	"Scheduled message ID"
+// @Success		200			{object}	dto.ScheduledMessageResponse	"Scheduled message"
+// @Failure		404			{object}	dto.ErrorResponse				"Scheduled message not found"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/sessions/{sessionId}/messages/schedule/{scheduleId} [get]
+// @Security		ApiKeyAuth
+func (h *ScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	scheduleID := chi.URLParam(r, "scheduleId")
+
+	response, err := h.scheduleUseCases.Get(r.Context(), sessionID, scheduleID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Reschedule Message
+// @Description	Move a pending scheduled message to a new send time. Its attempts start over
+// @Tags			Messages
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			scheduleId	path		string							true	"Scheduled message ID"
+// @Param			request		body		dto.RescheduleMessageRequest	true	"New send time"
+// @Success		200			{object}	dto.ScheduledMessageResponse	"Message rescheduled"
+// @Failure		400			{object}	dto.ErrorResponse				"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse				"Scheduled message not found"
+// @Failure		409			{object}	dto.ErrorResponse				"Message is no longer pending"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/sessions/{sessionId}/messages/schedule/{scheduleId} [put]
+// @Security		ApiKeyAuth
+func (h *ScheduleHandler) Reschedule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	scheduleID := chi.URLParam(r, "scheduleId")
+
+	var req dto.RescheduleMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.scheduleUseCases.Reschedule(r.Context(), sessionID, scheduleID, &req)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("schedule_id", scheduleID).
+		Time("send_at", response.SendAt).
+		Msg("Scheduled message rescheduled")
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Cancel Scheduled Message
+// @Description	Cancel a pending scheduled message
+// @Tags			Messages
+// @Produce		json
+// @Param			sessionId	path		string							true	"Session ID"
+// @Param			scheduleId	path		string							true	"Scheduled message ID"
+// @Success		200			{object}	dto.ScheduledMessageResponse	"Message cancelled"
+// @Failure		404			{object}	dto.ErrorResponse				"Scheduled message not found"
+// @Failure		409			{object}	dto.ErrorResponse				"Message is no longer pending"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/sessions/{sessionId}/messages/schedule/{scheduleId} [delete]
+// @Security		ApiKeyAuth
+func (h *ScheduleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	scheduleID := chi.URLParam(r, "scheduleId")
+
+	response, err := h.scheduleUseCases.Cancel(r.Context(), sessionID, scheduleID)
+	if err != nil {
+		h.handleError(w, sessionID, err)
+		return
+	}
+
+	h.logger.Info().Str("session_id", sessionID).Str("schedule_id", scheduleID).Msg("Scheduled message cancelled")
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *ScheduleHandler) handleError(w http.ResponseWriter, sessionID string, err error) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, shared.ErrScheduledMessageNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "scheduled message not found")
+	case errors.Is(err, shared.ErrScheduledMessageNotPending):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, "scheduled message is no longer pending")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Scheduled message operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+
+func (h *ScheduleHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *ScheduleHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
 	"zpwoot/internal/core/ports/input"
 	"zpwoot/internal/core/ports/output"
 
@@ -27,13 +29,14 @@ func NewSessionHandler(useCases input.SessionUseCases, waClient output.WhatsAppC
 }
 
 // @Summary		Create WhatsApp Session
-// @Description	Creates a new WhatsApp session with the specified configuration
+// @Description	Creates a new WhatsApp session with the specified configuration. The session belongs to the tenant of the API key, or to tenantId or the default tenant for keys of the server operator
 // @Tags			Sessions
 // @Accept			json
 // @Produce		json
 // @Param			request	body		CreateSessionRequest	true	"Session configuration"
 // @Success		201		{object}	SessionResponse			"Session created successfully"
 // @Failure		400		{object}	ErrorResponse			"Invalid request body or validation error"
+// @Failure		403		{object}	ErrorResponse			"Tenant suspended or at its session quota"
 // @Failure		409		{object}	ErrorResponse			"Session already exists"
 // @Failure		500		{object}	ErrorResponse			"Internal server error"
 // @Security		ApiKeyAuth
@@ -72,6 +75,17 @@ func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, shared.ErrSessionQuotaExceeded) || errors.Is(err, shared.ErrTenantSuspended) {
+			h.writeErrorResponse(w, http.StatusForbidden, dto.ErrorCodeForbidden, err.Error())
+			return
+		}
+
+		var validationErr *dto.ValidationError
+		if errors.As(err, &validationErr) {
+			h.writeErrorResponse(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+			return
+		}
+
 		h.writeErrorResponse(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "Failed to create session")
 
 		return
@@ -114,7 +128,7 @@ func (h *SessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary		List WhatsApp Sessions
-// @Description	Retrieves a list of all WhatsApp sessions with their current status (without QR codes)
+// @Description	Retrieves a list of all WhatsApp sessions with their current status (without QR codes). API keys limited to some sessions only see those
 // @Tags			Sessions
 // @Accept			json
 // @Produce		json
@@ -138,9 +152,31 @@ func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filterSessionsByKey(apikey.FromContext(r.Context()), response)
+
 	h.writeSuccessResponse(w, http.StatusOK, response)
 }
 
+// filterSessionsByKey drops the sessions a key limited to some sessions does
+// not cover.
+func filterSessionsByKey(key *apikey.APIKey, response *dto.PaginationResponse) {
+	sessions, ok := response.Items.([]dto.SessionListInfo)
+	if key == nil || key.CoversAllSessions() || !ok {
+		return
+	}
+
+	covered := make([]dto.SessionListInfo, 0, len(sessions))
+
+	for _, s := range sessions {
+		if key.CoversSession(s.SessionID) {
+			covered = append(covered, s)
+		}
+	}
+
+	response.Items = covered
+	response.Total = len(covered)
+}
+
 // @Summary		Connect WhatsApp Session
 // @Description	Connects a WhatsApp session. If already connected, returns current status with appropriate message.
 // @Tags			Sessions
@@ -352,6 +388,84 @@ func (h *SessionHandler) QRCode(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccessResponse(w, http.StatusOK, response)
 }
 
+// @Summary		Set Session Proxy
+// @Description	Stores the proxy (http, https or socks5, optionally authenticated) used by a session for the WhatsApp websocket and media transfers. A connected session is reconnected to apply the change. Sending enabled=false removes the proxy.
+// @Tags			Sessions
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string				true	"Session ID"	Format(uuid)
+// @Param			request		body		ProxySettings		true	"Proxy configuration"
+// @Success		200			{object}	ProxyResponse		"Proxy updated"
+// @Failure		400			{object}	dto.ErrorResponse	"Invalid request body or validation error"
+// @Failure		404			{object}	dto.ErrorResponse	"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Security		ApiKeyAuth
+// @Router			/sessions/{sessionId}/proxy [put]
+func (h *SessionHandler) SetProxy(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	var req dto.ProxySettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.useCases.SetProxy(r.Context(), sessionID, &req)
+	if err != nil {
+		h.handleProxyError(w, sessionID, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, response)
+}
+
+// @Summary		Remove Session Proxy
+// @Description	Removes the proxy of a session. A connected session is reconnected without proxy.
+// @Tags			Sessions
+// @Produce		json
+// @Param			sessionId	path		string				true	"Session ID"	Format(uuid)
+// @Success		200			{object}	ProxyResponse		"Proxy removed"
+// @Failure		404			{object}	dto.ErrorResponse	"Session not found"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Security		ApiKeyAuth
+// @Router			/sessions/{sessionId}/proxy [delete]
+func (h *SessionHandler) RemoveProxy(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	response, err := h.useCases.RemoveProxy(r.Context(), sessionID)
+	if err != nil {
+		h.handleProxyError(w, sessionID, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, response)
+}
+
+func (h *SessionHandler) handleProxyError(w http.ResponseWriter, sessionID string, err error) {
+	h.logger.Error().
+		Err(err).
+		Str("session_id", sessionID).
+		Msg("Failed to update session proxy")
+
+	var validationErr *dto.ValidationError
+	switch {
+	case errors.Is(err, dto.ErrSessionNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, dto.ErrorCodeNotFound, "session not found")
+	case errors.As(err, &validationErr):
+		h.writeErrorResponse(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, "failed to update session proxy")
+	}
+}
+
 func (h *SessionHandler) writeSuccessResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
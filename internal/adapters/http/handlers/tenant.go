@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TenantHandler struct {
+	tenantUseCases input.TenantUseCases
+	logger         *logger.Logger
+}
+
+func NewTenantHandler(tenantUseCases input.TenantUseCases, logger *logger.Logger) *TenantHandler {
+	return &TenantHandler{
+		tenantUseCases: tenantUseCases,
+		logger:         logger,
+	}
+}
+
+// @Summary		Create Tenant
+// @Description	Create a tenant with its quotas: the most sessions it may have and messages it may send per UTC day, 0 meaning no limit. Give it API keys with tenantId to let it use the API
+// @Tags			Tenants
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.CreateTenantRequest	true	"Tenant"
+// @Success		201		{object}	dto.TenantResponse		"Tenant created"
+// @Failure		400		{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		403		{object}	dto.ErrorResponse		"Server operator admin key required"
+// @Failure		409		{object}	dto.ErrorResponse		"Tenant name already in use"
+// @Failure		500		{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/admin/tenants [post]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	response, err := h.tenantUseCases.Create(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("tenant_id", response.ID).
+		Str("name", response.Name).
+		Msg("Tenant created")
+
+	h.writeJSON(w, http.StatusCreated, response)
+}
+
+// @Summary		List Tenants
+// @Description	List every tenant, the default one included, oldest first
+// @Tags			Tenants
+// @Produce		json
+// @Success		200	{object}	dto.TenantListResponse	"Tenants"
+// @Failure		403	{object}	dto.ErrorResponse		"Server operator admin key required"
+// @Failure		500	{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/admin/tenants [get]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
+	response, err := h.tenantUseCases.List(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Tenant
+// @Description	Get a tenant with its quotas and usage: its sessions and the messages it sent today (UTC)
+// @Tags			Tenants
+// @Produce		json
+// @Param			tenantId	path		string				true	"Tenant ID"
+// @Success		200			{object}	dto.TenantResponse	"Tenant"
+// @Failure		403			{object}	dto.ErrorResponse	"Server operator admin key required"
+// @Failure		404			{object}	dto.ErrorResponse	"Tenant not found"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/tenants/{tenantId} [get]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.tenantUseCases.Get(r.Context(), chi.URLParam(r, "tenantId"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Update Tenant
+// @Description	Change the name or quotas of a tenant. Lowering a quota below the current usage keeps existing sessions but stops new sessions and messages
+// @Tags			Tenants
+// @Accept			json
+// @Produce		json
+// @Param			tenantId	path		string					true	"Tenant ID"
+// @Param			request		body		dto.UpdateTenantRequest	true	"Fields to change"
+// @Success		200			{object}	dto.TenantResponse		"Tenant updated"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		403			{object}	dto.ErrorResponse		"Server operator admin key required"
+// @Failure		404			{object}	dto.ErrorResponse		"Tenant not found"
+// @Failure		409			{object}	dto.ErrorResponse		"Tenant name already in use"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/admin/tenants/{tenantId} [put]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	h.update(w, r, "Tenant updated", func(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+		return h.tenantUseCases.Update(ctx, tenantID, &req)
+	})
+}
+
+// @Summary		Suspend Tenant
+// @Description	Refuse the API keys of a tenant and stop its sessions from sending, keeping its sessions and data. The default tenant cannot be suspended
+// @Tags			Tenants
+// @Produce		json
+// @Param			tenantId	path		string				true	"Tenant ID"
+// @Success		200			{object}	dto.TenantResponse	"Tenant suspended"
+// @Failure		403			{object}	dto.ErrorResponse	"Server operator admin key required"
+// @Failure		404			{object}	dto.ErrorResponse	"Tenant not found"
+// @Failure		409			{object}	dto.ErrorResponse	"Default tenant"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/tenants/{tenantId}/suspend [post]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, "Tenant suspended", h.tenantUseCases.Suspend)
+}
+
+// @Summary		Activate Tenant
+// @Description	Lift the suspension of a tenant
+// @Tags			Tenants
+// @Produce		json
+// @Param			tenantId	path		string				true	"Tenant ID"
+// @Success		200			{object}	dto.TenantResponse	"Tenant activated"
+// @Failure		403			{object}	dto.ErrorResponse	"Server operator admin key required"
+// @Failure		404			{object}	dto.ErrorResponse	"Tenant not found"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/tenants/{tenantId}/activate [post]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, "Tenant activated", h.tenantUseCases.Activate)
+}
+
+// @Summary		Delete Tenant
+// @Description	Delete a tenant together with its API keys. Its sessions must be deleted first. The default tenant cannot be deleted
+// @Tags			Tenants
+// @Produce		json
+// @Param			tenantId	path	string	true	"Tenant ID"
+// @Success		204			"Tenant deleted"
+// @Failure		403			{object}	dto.ErrorResponse	"Server operator admin key required"
+// @Failure		404			{object}	dto.ErrorResponse	"Tenant not found"
+// @Failure		409			{object}	dto.ErrorResponse	"Tenant still has sessions, or is the default tenant"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/admin/tenants/{tenantId} [delete]
+// @Security		ApiKeyAuth
+func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenantID := chi.URLParam(r, "tenantId")
+
+	if err := h.tenantUseCases.Delete(r.Context(), tenantID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("tenant_id", tenantID).
+		Msg("Tenant deleted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TenantHandler) update(
+	w http.ResponseWriter,
+	r *http.Request,
+	message string,
+	update func(ctx context.Context, tenantID string) (*dto.TenantResponse, error),
+) {
+	tenantID := chi.URLParam(r, "tenantId")
+
+	response, err := update(r.Context(), tenantID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("tenant_id", tenantID).
+		Str("status", response.Status).
+		Msg(message)
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *TenantHandler) handleError(w http.ResponseWriter, err error) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, shared.ErrTenantNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "tenant not found")
+	case errors.Is(err, shared.ErrTenantAlreadyExists):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, "a tenant with this name already exists")
+	case errors.Is(err, shared.ErrTenantHasSessions), errors.Is(err, shared.ErrDefaultTenant):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, err.Error())
+	default:
+		h.logger.Error().Err(err).Msg("Tenant operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+
+func (h *TenantHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+func (h *TenantHandler) writeError(w http.ResponseWriter, statusCode int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := dto.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"zpwoot/internal/adapters/logger"
 	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
 	"zpwoot/internal/core/ports/input"
 
 	"github.com/go-chi/chi/v5"
@@ -24,7 +26,7 @@ func NewWebhookHandler(webhookUseCases input.WebhookUseCases, logger *logger.Log
 }
 
 // @Summary		Configure Webhook
-// @Description	Configure or update webhook for a session
+// @Description	Register a webhook for a session. A session can have several webhooks; posting a URL that is already registered updates that webhook instead of adding a new one
 // @Tags			Webhooks
 // @Accept			json
 // @Produce		json
@@ -52,90 +54,239 @@ func (h *WebhookHandler) SetWebhook(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.webhookUseCases.Upsert(r.Context(), sessionID, &req)
 	if err != nil {
-		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to set webhook")
-		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
-
+		h.handleWebhookError(w, err, sessionID, "Failed to set webhook")
 		return
 	}
 
 	h.logger.Info().
 		Str("session_id", sessionID).
+		Str("webhook_id", response.ID).
 		Str("webhook_url", req.URL).
 		Msg("Webhook configured successfully")
 
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// @Summary		Get Webhook Configuration
-// @Description	Get webhook configuration for a session
+// @Summary		List Webhooks
+// @Description	List all webhooks registered for a session
 // @Tags			Webhooks
 // @Produce		json
-// @Param			sessionId	path		string				true	"Session ID"
-// @Success		200			{object}	dto.WebhookResponse	"Webhook configuration"
+// @Param			sessionId	path		string						true	"Session ID"
+// @Success		200			{object}	dto.ListWebhooksResponse	"Session webhooks"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/webhooks [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+		return
+	}
+
+	response, err := h.webhookUseCases.List(r.Context(), sessionID)
+	if err != nil {
+		h.handleWebhookError(w, err, sessionID, "Failed to list webhooks")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Delete Session Webhooks
+// @Description	Delete every webhook registered for a session
+// @Tags			Webhooks
+// @Produce		json
+// @Param			sessionId	path		string			true	"Session ID"
+// @Success		200			{object}	dto.APIResponse	"Webhooks deleted successfully"
 // @Failure		404			{object}	dto.ErrorResponse	"Webhook not found"
 // @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
-// @Router			/sessions/{sessionId}/webhooks [get]
+// @Router			/sessions/{sessionId}/webhooks [delete]
 // @Security		ApiKeyAuth
-func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+func (h *WebhookHandler) DeleteWebhooks(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "sessionId")
 	if sessionID == "" {
 		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
 		return
 	}
 
-	response, err := h.webhookUseCases.Get(r.Context(), sessionID)
+	if err := h.webhookUseCases.DeleteAll(r.Context(), sessionID); err != nil {
+		h.handleWebhookError(w, err, sessionID, "Failed to delete webhooks")
+		return
+	}
+
+	h.logger.Info().Str("session_id", sessionID).Msg("Session webhooks deleted successfully")
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Webhooks deleted successfully",
+	})
+}
+
+// @Summary		Get Webhook
+// @Description	Get a single webhook of a session
+// @Tags			Webhooks
+// @Produce		json
+// @Param			sessionId	path		string				true	"Session ID"
+// @Param			webhookId	path		string				true	"Webhook ID"
+// @Success		200			{object}	dto.WebhookResponse	"Webhook configuration"
+// @Failure		404			{object}	dto.ErrorResponse	"Webhook not found"
+// @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/sessions/{sessionId}/webhooks/{webhookId} [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	webhookID := chi.URLParam(r, "webhookId")
+
+	response, err := h.webhookUseCases.Get(r.Context(), sessionID, webhookID)
 	if err != nil {
-		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to get webhook")
+		h.handleWebhookError(w, err, sessionID, "Failed to get webhook")
+		return
+	}
 
-		if err.Error() == "webhook not found" {
-			h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "Webhook not found for this session")
-			return
-		}
+	h.writeJSON(w, http.StatusOK, response)
+}
 
-		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+// @Summary		Update Webhook
+// @Description	Replace the URL, events, secret and enabled flag of a session webhook
+// @Tags			Webhooks
+// @Accept			json
+// @Produce		json
+// @Param			sessionId	path		string						true	"Session ID"
+// @Param			webhookId	path		string						true	"Webhook ID"
+// @Param			request		body		dto.CreateWebhookRequest	true	"Webhook configuration"
+// @Success		200			{object}	dto.WebhookResponse			"Webhook updated successfully"
+// @Failure		400			{object}	dto.ErrorResponse			"Invalid request"
+// @Failure		404			{object}	dto.ErrorResponse			"Webhook not found"
+// @Failure		409			{object}	dto.ErrorResponse			"URL already registered for the session"
+// @Failure		500			{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/sessions/{sessionId}/webhooks/{webhookId} [put]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "sessionId")
+	webhookID := chi.URLParam(r, "webhookId")
+
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
 
+	response, err := h.webhookUseCases.Update(r.Context(), sessionID, webhookID, &req)
+	if err != nil {
+		h.handleWebhookError(w, err, sessionID, "Failed to update webhook")
 		return
 	}
 
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("webhook_id", webhookID).
+		Msg("Webhook updated successfully")
+
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// @Summary		Delete Webhook Configuration
-// @Description	Delete webhook configuration for a session
+// @Summary		Delete Webhook
+// @Description	Delete a single webhook of a session
 // @Tags			Webhooks
 // @Produce		json
 // @Param			sessionId	path		string			true	"Session ID"
+// @Param			webhookId	path		string			true	"Webhook ID"
 // @Success		200			{object}	dto.APIResponse	"Webhook deleted successfully"
 // @Failure		404			{object}	dto.ErrorResponse	"Webhook not found"
 // @Failure		500			{object}	dto.ErrorResponse	"Internal server error"
-// @Router			/sessions/{sessionId}/webhooks [delete]
+// @Router			/sessions/{sessionId}/webhooks/{webhookId} [delete]
 // @Security		ApiKeyAuth
 func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "sessionId")
-	if sessionID == "" {
-		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "sessionId is required")
+	webhookID := chi.URLParam(r, "webhookId")
+
+	if err := h.webhookUseCases.Delete(r.Context(), sessionID, webhookID); err != nil {
+		h.handleWebhookError(w, err, sessionID, "Failed to delete webhook")
+		return
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("webhook_id", webhookID).
+		Msg("Webhook deleted successfully")
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// @Summary		Get Global Webhook
+// @Description	Get the instance-wide webhook that receives the events of every session
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	dto.WebhookResponse	"Global webhook configuration"
+// @Failure		404	{object}	dto.ErrorResponse	"Global webhook not configured"
+// @Failure		500	{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/webhooks/global [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) GetGlobalWebhook(w http.ResponseWriter, r *http.Request) {
+	response, err := h.webhookUseCases.GetGlobal(r.Context())
+	if err != nil {
+		h.handleGlobalWebhookError(w, err, "Failed to get global webhook")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Configure Global Webhook
+// @Description	Create or replace the instance-wide webhook. Its URL, secret and event filter are independent of session webhooks
+// @Tags			Webhooks
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.CreateWebhookRequest	true	"Webhook configuration"
+// @Success		200		{object}	dto.WebhookResponse			"Global webhook configured successfully"
+// @Failure		400		{object}	dto.ErrorResponse			"Invalid request"
+// @Failure		500		{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/webhooks/global [put]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) SetGlobalWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
 		return
 	}
 
-	err := h.webhookUseCases.Delete(r.Context(), sessionID)
+	response, err := h.webhookUseCases.SetGlobal(r.Context(), &req)
 	if err != nil {
-		h.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to delete webhook")
+		h.handleGlobalWebhookError(w, err, "Failed to set global webhook")
+		return
+	}
 
-		if err.Error() == "webhook not found" {
-			h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "Webhook not found for this session")
-			return
-		}
+	h.logger.Info().
+		Str("webhook_id", response.ID).
+		Str("webhook_url", response.URL).
+		Msg("Global webhook configured successfully")
 
-		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	h.writeJSON(w, http.StatusOK, response)
+}
 
+// @Summary		Delete Global Webhook
+// @Description	Stop delivering the events of every session to the global webhook
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	dto.APIResponse		"Global webhook deleted successfully"
+// @Failure		404	{object}	dto.ErrorResponse	"Global webhook not configured"
+// @Failure		500	{object}	dto.ErrorResponse	"Internal server error"
+// @Router			/webhooks/global [delete]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) DeleteGlobalWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhookUseCases.DeleteGlobal(r.Context()); err != nil {
+		h.handleGlobalWebhookError(w, err, "Failed to delete global webhook")
 		return
 	}
 
-	h.logger.Info().Str("session_id", sessionID).Msg("Webhook deleted successfully")
+	h.logger.Info().Msg("Global webhook deleted successfully")
 
 	h.writeJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"message": "Webhook deleted successfully",
+		"message": "Global webhook deleted successfully",
 	})
 }
 
@@ -158,6 +309,231 @@ func (h *WebhookHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 
 	h.writeJSON(w, http.StatusOK, response)
 }
+
+// @Summary		List Event Schemas
+// @Description	JSON Schema (draft 2020-12) of every normalized webhook event, envelope included
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	dto.EventSchemasResponse	"Schemas by event type"
+// @Failure		500	{object}	dto.ErrorResponse			"Internal server error"
+// @Router			/webhooks/schemas [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) ListEventSchemas(w http.ResponseWriter, r *http.Request) {
+	response, err := h.webhookUseCases.GetEventSchemas(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list webhook event schemas")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Event Schema
+// @Description	JSON Schema (draft 2020-12) of one normalized webhook event, envelope included
+// @Tags			Webhooks
+// @Produce		json
+// @Param			eventType	path		string				true	"Event type, e.g. Message"
+// @Success		200			{object}	map[string]interface{}	"JSON Schema"
+// @Failure		404			{object}	dto.ErrorResponse	"Unknown event type"
+// @Router			/webhooks/schemas/{eventType} [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) GetEventSchema(w http.ResponseWriter, r *http.Request) {
+	eventType := chi.URLParam(r, "eventType")
+
+	schema, err := h.webhookUseCases.GetEventSchema(r.Context(), eventType)
+	if err != nil {
+		if errors.Is(err, shared.ErrUnknownEventType) {
+			h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, err.Error())
+			return
+		}
+
+		h.logger.Error().Err(err).Str("event_type", eventType).Msg("Failed to get webhook event schema")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(schema); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+// @Summary		List Dead Letters
+// @Description	List webhook events that could not be delivered, newest failure first
+// @Tags			Webhooks
+// @Produce		json
+// @Param			sessionId	query		string					false	"Filter by session ID"
+// @Param			eventType	query		string					false	"Filter by event type"
+// @Param			since		query		string					false	"Failed at or after (RFC3339)"
+// @Param			until		query		string					false	"Failed at or before (RFC3339)"
+// @Param			limit		query		int						false	"Page size (default 20, max 100)"
+// @Param			offset		query		int						false	"Number of items to skip"
+// @Success		200			{object}	dto.PaginationResponse	"Dead letters with dto.DeadLetterResponse items"
+// @Failure		400			{object}	dto.ErrorResponse		"Invalid request"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/webhooks/deadletters [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	request := &dto.ListDeadLettersRequest{
+		SessionID: query.Get("sessionId"),
+		EventType: query.Get("eventType"),
+	}
+
+	var err error
+
+	if request.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "limit must be a number")
+		return
+	}
+
+	if request.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "offset must be a number")
+		return
+	}
+
+	if request.Since, err = parseTimeParam(query.Get("since")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "since must be an RFC3339 timestamp")
+		return
+	}
+
+	if request.Until, err = parseTimeParam(query.Get("until")); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, "until must be an RFC3339 timestamp")
+		return
+	}
+
+	response, err := h.webhookUseCases.ListDeadLetters(r.Context(), request)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list dead letters")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Get Dead Letter
+// @Description	Get a failed webhook delivery including its payload
+// @Tags			Webhooks
+// @Produce		json
+// @Param			deliveryId	path		string					true	"Delivery ID"
+// @Success		200			{object}	dto.DeadLetterResponse	"Dead letter"
+// @Failure		404			{object}	dto.ErrorResponse		"Dead letter not found"
+// @Failure		500			{object}	dto.ErrorResponse		"Internal server error"
+// @Router			/webhooks/deadletters/{deliveryId} [get]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	deliveryID := chi.URLParam(r, "deliveryId")
+
+	response, err := h.webhookUseCases.GetDeadLetter(r.Context(), deliveryID)
+	if err != nil {
+		h.handleDeadLetterError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// @Summary		Replay Dead Letters
+// @Description	Queue failed webhook events for delivery again, selected by IDs or by a failure time range
+// @Tags			Webhooks
+// @Accept			json
+// @Produce		json
+// @Param			request	body		dto.ReplayDeadLettersRequest	true	"IDs or time range"
+// @Success		200		{object}	dto.ReplayDeadLettersResponse	"Number of replayed events"
+// @Failure		400		{object}	dto.ErrorResponse				"Invalid request"
+// @Failure		500		{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/webhooks/deadletters/replay [post]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) ReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var request dto.ReplayDeadLettersRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeBadRequest, "Invalid JSON body")
+		return
+	}
+
+	h.replayDeadLetters(w, r, &request)
+}
+
+// @Summary		Replay Dead Letter
+// @Description	Queue a single failed webhook event for delivery again
+// @Tags			Webhooks
+// @Produce		json
+// @Param			deliveryId	path		string							true	"Delivery ID"
+// @Success		200			{object}	dto.ReplayDeadLettersResponse	"Number of replayed events"
+// @Failure		404			{object}	dto.ErrorResponse				"Dead letter not found"
+// @Failure		500			{object}	dto.ErrorResponse				"Internal server error"
+// @Router			/webhooks/deadletters/{deliveryId}/replay [post]
+// @Security		ApiKeyAuth
+func (h *WebhookHandler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	deliveryID := chi.URLParam(r, "deliveryId")
+
+	if _, err := h.webhookUseCases.GetDeadLetter(r.Context(), deliveryID); err != nil {
+		h.handleDeadLetterError(w, err)
+		return
+	}
+
+	h.replayDeadLetters(w, r, &dto.ReplayDeadLettersRequest{IDs: []string{deliveryID}})
+}
+func (h *WebhookHandler) replayDeadLetters(w http.ResponseWriter, r *http.Request, request *dto.ReplayDeadLettersRequest) {
+	response, err := h.webhookUseCases.ReplayDeadLetters(r.Context(), request)
+	if err != nil {
+		h.handleDeadLetterError(w, err)
+		return
+	}
+
+	h.logger.Info().Int("replayed", response.Replayed).Msg("Dead letters replayed")
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+func (h *WebhookHandler) handleWebhookError(w http.ResponseWriter, err error, sessionID, logMessage string) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, shared.ErrWebhookNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "Webhook not found for this session")
+	case errors.Is(err, shared.ErrWebhookAlreadyExists):
+		h.writeError(w, http.StatusConflict, dto.ErrorCodeConflict, "A webhook with this URL already exists for this session")
+	default:
+		h.logger.Error().Err(err).Str("session_id", sessionID).Msg(logMessage)
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+func (h *WebhookHandler) handleGlobalWebhookError(w http.ResponseWriter, err error, logMessage string) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, shared.ErrWebhookNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "Global webhook not configured")
+	default:
+		h.logger.Error().Err(err).Msg(logMessage)
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
+func (h *WebhookHandler) handleDeadLetterError(w http.ResponseWriter, err error) {
+	var validationErr *dto.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeError(w, http.StatusBadRequest, dto.ErrorCodeValidation, validationErr.Error())
+	case errors.Is(err, shared.ErrDeadLetterNotFound):
+		h.writeError(w, http.StatusNotFound, dto.ErrorCodeNotFound, "Dead letter not found")
+	default:
+		h.logger.Error().Err(err).Msg("Dead letter operation failed")
+		h.writeError(w, http.StatusInternalServerError, dto.ErrorCodeInternalError, err.Error())
+	}
+}
 func (h *WebhookHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -1,14 +1,27 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"zpwoot/internal/adapters/logger"
 	"zpwoot/internal/config"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
 )
 
-func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+// AuthMiddleware accepts the global ZP_API_KEY, which acts as a superuser,
+// and the active API keys stored in the database. The key a request was
+// made with is put in its context for RequirePermission. Requests made
+// with a key of a tenant are limited to that tenant, and on routes with a
+// {sessionId} the session must be one of its own.
+func AuthMiddleware(cfg *config.Config, apiKeys input.APIKeyUseCases, tenants input.TenantUseCases) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.URL.Path == "/health" || r.URL.Path == "/" {
@@ -27,23 +40,139 @@ func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 				}
 			}
 
-			if apiKey == "" || apiKey != cfg.APIKey {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
+			if apiKey == "" {
+				writeUnauthorized(w)
+				return
+			}
 
-				errorMsg := `{"error":"unauthorized","message":"invalid or missing API key. Use 'Authorization: YOUR_API_KEY' or 'X-API-Key: YOUR_API_KEY' header"}`
-				if _, err := w.Write([]byte(errorMsg)); err != nil {
-					logger.Error().Err(err).Msg("Failed to write unauthorized response")
+			if subtle.ConstantTimeCompare([]byte(apiKey), []byte(cfg.APIKey)) == 1 {
+				next.ServeHTTP(w, r.WithContext(apikey.NewContext(r.Context(), apikey.Superuser())))
+				return
+			}
+
+			key, err := apiKeys.Authenticate(r.Context(), apiKey)
+			if err != nil {
+				switch {
+				case errors.Is(err, shared.ErrTenantSuspended):
+					writeForbidden(w, "the tenant of this API key is suspended")
+					return
+				case !errors.Is(err, shared.ErrUnauthorized):
+					logger.Error().Err(err).Msg("Failed to authenticate API key")
 				}
 
+				writeUnauthorized(w)
+
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			ctx := apikey.NewContext(r.Context(), key)
+
+			if key.TenantID != "" {
+				ctx = shared.WithTenant(ctx, key.TenantID)
+
+				if sessionID := chi.URLParam(r, "sessionId"); sessionID != "" {
+					if err := tenants.CheckSession(ctx, sessionID); err != nil {
+						if !errors.Is(err, shared.ErrSessionNotFound) {
+							logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to check session tenant")
+						}
+
+						writeError(w, http.StatusNotFound, "not_found", "session not found")
+
+						return
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// RequirePermission lets through requests made with a key holding
+// permission. On routes with a {sessionId}, the key must also cover that
+// session.
+func RequirePermission(permission apikey.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apikey.FromContext(r.Context())
+			sessionID := chi.URLParam(r, "sessionId")
+
+			switch {
+			case key == nil || !key.HasPermission(permission):
+				writeForbidden(w, "API key lacks the "+string(permission)+" permission")
+			case sessionID != "" && !key.Allows(sessionID, permission):
+				writeForbidden(w, "API key does not cover this session")
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RequireGlobalPermission is RequirePermission for routes that span every
+// session, such as creating sessions or replaying dead letters, which keys
+// limited to some sessions may not use.
+func RequireGlobalPermission(permission apikey.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apikey.FromContext(r.Context())
+
+			switch {
+			case key == nil || !key.HasPermission(permission):
+				writeForbidden(w, "API key lacks the "+string(permission)+" permission")
+			case !key.CoversAllSessions():
+				writeForbidden(w, "API key is limited to some sessions")
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RequirePlatformPermission is RequireGlobalPermission for routes that
+// span every tenant, such as the global webhook and managing tenants,
+// which only keys of the server operator may use.
+func RequirePlatformPermission(permission apikey.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return RequireGlobalPermission(permission)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := apikey.FromContext(r.Context()); key.TenantID != "" {
+				writeForbidden(w, "API key belongs to a tenant")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+
+	errorMsg := `{"error":"unauthorized","message":"invalid or missing API key. Use 'Authorization: YOUR_API_KEY' or 'X-API-Key: YOUR_API_KEY' header"}`
+	if _, err := w.Write([]byte(errorMsg)); err != nil {
+		logger.Error().Err(err).Msg("Failed to write unauthorized response")
+	}
+}
+
+func writeForbidden(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusForbidden, "forbidden", message)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	errorResponse := map[string]string{
+		"error":   code,
+		"message": message,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		logger.Error().Err(err).Msg("Failed to write error response")
+	}
+}
+
 func CORSMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
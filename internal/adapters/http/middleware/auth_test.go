@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"zpwoot/internal/config"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const testSuperuserKey = "superuser-key"
+
+// fakeAPIKeys authenticates the tokens of keys, or returns the error of
+// suspended tokens.
+type fakeAPIKeys struct {
+	input.APIKeyUseCases
+
+	keys      map[string]*apikey.APIKey
+	suspended map[string]bool
+}
+
+func (f *fakeAPIKeys) Authenticate(_ context.Context, token string) (*apikey.APIKey, error) {
+	if f.suspended[token] {
+		return nil, shared.ErrTenantSuspended
+	}
+
+	key, ok := f.keys[token]
+	if !ok {
+		return nil, shared.ErrUnauthorized
+	}
+
+	return key, nil
+}
+
+// fakeTenants knows which tenant owns each session.
+type fakeTenants struct {
+	input.TenantUseCases
+
+	owners map[string]string
+}
+
+func (f *fakeTenants) CheckSession(ctx context.Context, sessionID string) error {
+	tenantID := shared.TenantFromContext(ctx)
+	if tenantID == "" {
+		return nil
+	}
+
+	if owner, ok := f.owners[sessionID]; !ok || owner != tenantID {
+		return shared.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func newTestRouter() http.Handler {
+	apiKeys := &fakeAPIKeys{
+		keys: map[string]*apikey.APIKey{
+			"tenant-a-key": {ID: "key-a", TenantID: "tenant-a", Permissions: []apikey.Permission{apikey.PermissionAdmin}},
+			"tenant-b-key": {ID: "key-b", TenantID: "tenant-b", Permissions: []apikey.Permission{apikey.PermissionAdmin}},
+			"operator-key": {ID: "key-op", Permissions: []apikey.Permission{apikey.PermissionAdmin}},
+		},
+		suspended: map[string]bool{"suspended-key": true},
+	}
+
+	tenants := &fakeTenants{
+		owners: map[string]string{
+			"session-a": "tenant-a",
+			"session-b": "tenant-b",
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		SetupAuthMiddleware(r, &config.Config{APIKey: testSuperuserKey}, apiKeys, tenants)
+
+		r.Get("/sessions/{sessionId}/info", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Tenant", shared.TenantFromContext(r.Context()))
+			w.WriteHeader(http.StatusOK)
+		})
+
+		r.With(RequirePlatformPermission(apikey.PermissionAdmin)).Get("/admin/tenants", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	return r
+}
+
+func TestAuthMiddlewareTenantSessions(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		path       string
+		wantStatus int
+		wantTenant string
+	}{
+		{"own session", "tenant-a-key", "/sessions/session-a/info", http.StatusOK, "tenant-a"},
+		{"session of another tenant", "tenant-a-key", "/sessions/session-b/info", http.StatusNotFound, ""},
+		{"other tenant on its own session", "tenant-b-key", "/sessions/session-b/info", http.StatusOK, "tenant-b"},
+		{"other tenant on the first tenant's session", "tenant-b-key", "/sessions/session-a/info", http.StatusNotFound, ""},
+		{"unknown session", "tenant-a-key", "/sessions/missing/info", http.StatusNotFound, ""},
+		{"server operator key", "operator-key", "/sessions/session-b/info", http.StatusOK, ""},
+		{"superuser key", testSuperuserKey, "/sessions/session-a/info", http.StatusOK, ""},
+		{"suspended tenant", "suspended-key", "/sessions/session-a/info", http.StatusForbidden, ""},
+		{"unknown key", "wrong-key", "/sessions/session-a/info", http.StatusUnauthorized, ""},
+		{"missing key", "", "/sessions/session-a/info", http.StatusUnauthorized, ""},
+	}
+
+	router := newTestRouter()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("Authorization", tt.key)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if got := rec.Header().Get("X-Tenant"); got != tt.wantTenant {
+				t.Errorf("tenant in context = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestRequirePlatformPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus int
+	}{
+		{"tenant admin key", "tenant-a-key", http.StatusForbidden},
+		{"server operator key", "operator-key", http.StatusOK},
+		{"superuser key", testSuperuserKey, http.StatusOK},
+	}
+
+	router := newTestRouter()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/tenants", nil)
+			req.Header.Set("X-API-Key", tt.key)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"zpwoot/internal/config"
+	"zpwoot/internal/core/ports/input"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -28,6 +29,6 @@ func SetupMiddleware(r *chi.Mux) {
 	r.Use(JSONMiddleware())
 }
 
-func SetupAuthMiddleware(r chi.Router, cfg *config.Config) {
-	r.Use(AuthMiddleware(cfg))
+func SetupAuthMiddleware(r chi.Router, cfg *config.Config, apiKeys input.APIKeyUseCases, tenants input.TenantUseCases) {
+	r.Use(AuthMiddleware(cfg, apiKeys, tenants))
 }
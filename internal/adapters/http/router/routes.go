@@ -10,6 +10,8 @@ import (
 	"zpwoot/internal/adapters/http/middleware"
 	"zpwoot/internal/config"
 	"zpwoot/internal/container"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/ports/input"
 )
 
 func NewRouter(c *container.Container) http.Handler {
@@ -23,11 +25,18 @@ func NewRouter(c *container.Container) http.Handler {
 		c.GetSessionUseCases(),
 		c.GetMessageUseCases(),
 		c.GetWebhookUseCases(),
+		c.GetChatwootUseCases(),
+		c.GetScheduleUseCases(),
+		c.GetCampaignUseCases(),
+		c.GetMediaUseCases(),
+		c.GetMediaProcessor(),
+		c.GetAPIKeyUseCases(),
+		c.GetTenantUseCases(),
 		c.GetWhatsAppClient(),
 	)
 
 	setupPublicRoutes(r, h)
-	setupProtectedRoutes(r, h, c.GetConfig())
+	setupProtectedRoutes(r, h, c.GetConfig(), c.GetAPIKeyUseCases(), c.GetTenantUseCases())
 
 	return r
 }
@@ -36,109 +45,233 @@ func setupPublicRoutes(r *chi.Mux, h *handlers.Handlers) {
 	r.Get("/", h.Health.Info)
 	r.Get("/health", h.Health.Health)
 	r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("/swagger/doc.json")))
+	r.Post("/chatwoot/webhook/{sessionId}", h.Chatwoot.Webhook)
 }
 
-func setupProtectedRoutes(r *chi.Mux, h *handlers.Handlers, cfg *config.Config) {
+// setupProtectedRoutes requires an API key on every route and, through
+// the permission middleware of each route, that the key may use it.
+func setupProtectedRoutes(r *chi.Mux, h *handlers.Handlers, cfg *config.Config, apiKeys input.APIKeyUseCases, tenants input.TenantUseCases) {
 	r.Group(func(r chi.Router) {
-		middleware.SetupAuthMiddleware(r, cfg)
+		middleware.SetupAuthMiddleware(r, cfg, apiKeys, tenants)
 
 		setupSessionRoutes(r, h)
 		setupMessageRoutes(r, h)
+		setupChatRoutes(r, h)
+		setupCampaignRoutes(r, h)
+		setupMediaRoutes(r, h)
 		setupContactRoutes(r, h)
 		setupGroupRoutes(r, h)
 		setupCommunityRoutes(r, h)
 		setupNewsletterRoutes(r, h)
 		setupWebhookRoutes(r, h)
+		setupChatwootRoutes(r, h)
+		setupAPIKeyRoutes(r, h)
+		setupTenantRoutes(r, h)
 	})
 }
 
+func withPermission(r chi.Router, permission apikey.Permission) chi.Router {
+	return r.With(middleware.RequirePermission(permission))
+}
+
+func withGlobalPermission(r chi.Router, permission apikey.Permission) chi.Router {
+	return r.With(middleware.RequireGlobalPermission(permission))
+}
+
+func withPlatformPermission(r chi.Router, permission apikey.Permission) chi.Router {
+	return r.With(middleware.RequirePlatformPermission(permission))
+}
+
 func setupSessionRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Post("/sessions", h.Session.Create)
+	read := withPermission(r, apikey.PermissionRead)
+	manage := withPermission(r, apikey.PermissionSessions)
+
+	// Every key may list sessions; the list only holds those the key covers.
 	r.Get("/sessions", h.Session.List)
-	r.Get("/sessions/{sessionId}", h.Session.Get)
-	r.Delete("/sessions/{sessionId}", h.Session.Delete)
-	r.Post("/sessions/{sessionId}/connect", h.Session.Connect)
-	r.Post("/sessions/{sessionId}/disconnect", h.Session.Disconnect)
-	r.Post("/sessions/{sessionId}/logout", h.Session.Logout)
-	r.Get("/sessions/{sessionId}/qr", h.Session.QRCode)
-	r.Post("/sessions/{sessionId}/pair", h.Session.PairPhone)
+	withGlobalPermission(r, apikey.PermissionSessions).Post("/sessions", h.Session.Create)
+	read.Get("/sessions/{sessionId}", h.Session.Get)
+	manage.Delete("/sessions/{sessionId}", h.Session.Delete)
+	manage.Post("/sessions/{sessionId}/connect", h.Session.Connect)
+	manage.Post("/sessions/{sessionId}/disconnect", h.Session.Disconnect)
+	manage.Post("/sessions/{sessionId}/logout", h.Session.Logout)
+	manage.Get("/sessions/{sessionId}/qr", h.Session.QRCode)
+	manage.Post("/sessions/{sessionId}/pair", h.Session.PairPhone)
+	manage.Put("/sessions/{sessionId}/proxy", h.Session.SetProxy)
+	manage.Delete("/sessions/{sessionId}/proxy", h.Session.RemoveProxy)
 }
 
 func setupMessageRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Post("/sessions/{sessionId}/messages/send/text", h.Message.SendText)
-	r.Post("/sessions/{sessionId}/messages/send/image", h.Message.SendImage)
-	r.Post("/sessions/{sessionId}/messages/send/audio", h.Message.SendAudio)
-	r.Post("/sessions/{sessionId}/messages/send/video", h.Message.SendVideo)
-	r.Post("/sessions/{sessionId}/messages/send/document", h.Message.SendDocument)
-	r.Post("/sessions/{sessionId}/messages/send/sticker", h.Message.SendSticker)
-	r.Post("/sessions/{sessionId}/messages/send/location", h.Message.SendLocation)
-	r.Post("/sessions/{sessionId}/messages/send/contact", h.Message.SendContact)
-	r.Post("/sessions/{sessionId}/messages/send/contacts", h.Message.SendContactsArray)
-	r.Post("/sessions/{sessionId}/messages/send/reaction", h.Message.SendReaction)
-	r.Post("/sessions/{sessionId}/messages/send/poll", h.Message.SendPoll)
-	r.Post("/sessions/{sessionId}/messages/send/buttons", h.Message.SendButtons)
-	r.Post("/sessions/{sessionId}/messages/send/list", h.Message.SendList)
-	r.Post("/sessions/{sessionId}/messages/send/template", h.Message.SendTemplate)
-	r.Post("/sessions/{sessionId}/messages/delete", h.Message.DeleteMessage)
-	r.Post("/sessions/{sessionId}/messages/edit", h.Message.EditMessage)
-	r.Post("/sessions/{sessionId}/messages/markread", h.Message.MarkRead)
-	r.Post("/sessions/{sessionId}/messages/historysync", h.Message.RequestHistorySync)
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	send.Post("/sessions/{sessionId}/messages/send/text", h.Message.SendText)
+	send.Post("/sessions/{sessionId}/messages/send/image", h.Message.SendImage)
+	send.Post("/sessions/{sessionId}/messages/send/audio", h.Message.SendAudio)
+	send.Post("/sessions/{sessionId}/messages/send/video", h.Message.SendVideo)
+	send.Post("/sessions/{sessionId}/messages/send/document", h.Message.SendDocument)
+	send.Post("/sessions/{sessionId}/messages/send/sticker", h.Message.SendSticker)
+	send.Post("/sessions/{sessionId}/messages/send/location", h.Message.SendLocation)
+	send.Post("/sessions/{sessionId}/messages/send/contact", h.Message.SendContact)
+	send.Post("/sessions/{sessionId}/messages/send/contacts", h.Message.SendContactsArray)
+	send.Post("/sessions/{sessionId}/messages/send/reaction", h.Message.SendReaction)
+	send.Post("/sessions/{sessionId}/messages/send/poll", h.Message.SendPoll)
+	send.Post("/sessions/{sessionId}/messages/send/buttons", h.Message.SendButtons)
+	send.Post("/sessions/{sessionId}/messages/send/list", h.Message.SendList)
+	send.Post("/sessions/{sessionId}/messages/send/template", h.Message.SendTemplate)
+	send.Post("/sessions/{sessionId}/messages/delete", h.Message.DeleteMessage)
+	send.Post("/sessions/{sessionId}/messages/edit", h.Message.EditMessage)
+	send.Post("/sessions/{sessionId}/messages/markread", h.Message.MarkRead)
+	send.Post("/sessions/{sessionId}/messages/historysync", h.Message.RequestHistorySync)
+	send.Post("/sessions/{sessionId}/messages/schedule", h.Schedule.Schedule)
+	read.Get("/sessions/{sessionId}/messages/schedule", h.Schedule.List)
+	read.Get("/sessions/{sessionId}/messages/schedule/{scheduleId}", h.Schedule.Get)
+	send.Put("/sessions/{sessionId}/messages/schedule/{scheduleId}", h.Schedule.Reschedule)
+	send.Delete("/sessions/{sessionId}/messages/schedule/{scheduleId}", h.Schedule.Cancel)
+}
+
+func setupChatRoutes(r chi.Router, h *handlers.Handlers) {
+	read := withPermission(r, apikey.PermissionRead)
+
+	read.Get("/sessions/{sessionId}/chats", h.Chat.ListChats)
+	read.Get("/sessions/{sessionId}/chats/{chatJid}/messages", h.Chat.GetMessages)
+	read.Get("/sessions/{sessionId}/messages/{messageId}/status", h.Chat.GetMessageStatus)
+}
+
+func setupCampaignRoutes(r chi.Router, h *handlers.Handlers) {
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	send.Post("/sessions/{sessionId}/campaigns", h.Campaign.Create)
+	read.Get("/sessions/{sessionId}/campaigns", h.Campaign.List)
+	read.Get("/sessions/{sessionId}/campaigns/{campaignId}", h.Campaign.Get)
+	read.Get("/sessions/{sessionId}/campaigns/{campaignId}/report", h.Campaign.Report)
+	read.Get("/sessions/{sessionId}/campaigns/{campaignId}/recipients", h.Campaign.ListRecipients)
+	send.Post("/sessions/{sessionId}/campaigns/{campaignId}/pause", h.Campaign.Pause)
+	send.Post("/sessions/{sessionId}/campaigns/{campaignId}/resume", h.Campaign.Resume)
+	send.Post("/sessions/{sessionId}/campaigns/{campaignId}/cancel", h.Campaign.Cancel)
+}
+
+func setupMediaRoutes(r chi.Router, h *handlers.Handlers) {
+	read := withPermission(r, apikey.PermissionRead)
+
+	read.Get("/sessions/{sessionId}/media/{messageId}", h.Media.Get)
 }
 
 func setupContactRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Post("/sessions/{sessionId}/presence/send", h.Contact.SendPresence)
-	r.Post("/sessions/{sessionId}/presence/chat", h.Contact.ChatPresence)
-	r.Get("/sessions/{sessionId}/contacts", h.Contact.GetContacts)
-	r.Post("/sessions/{sessionId}/contacts/check", h.Contact.CheckUser)
-	r.Post("/sessions/{sessionId}/contacts/user", h.Contact.GetUser)
-	r.Post("/sessions/{sessionId}/contacts/avatar", h.Contact.GetAvatar)
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	send.Post("/sessions/{sessionId}/presence/send", h.Contact.SendPresence)
+	send.Post("/sessions/{sessionId}/presence/chat", h.Contact.ChatPresence)
+	read.Get("/sessions/{sessionId}/contacts", h.Contact.GetContacts)
+	read.Post("/sessions/{sessionId}/contacts/check", h.Contact.CheckUser)
+	read.Post("/sessions/{sessionId}/contacts/user", h.Contact.GetUser)
+	read.Post("/sessions/{sessionId}/contacts/avatar", h.Contact.GetAvatar)
 }
 
 func setupGroupRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Get("/sessions/{sessionId}/groups", h.Group.ListGroups)
-	r.Get("/sessions/{sessionId}/groups/info", h.Group.GetGroupInfo)
-	r.Post("/sessions/{sessionId}/groups/invite-info", h.Group.GetGroupInviteInfo)
-	r.Get("/sessions/{sessionId}/groups/invite-link", h.Group.GetGroupInviteLink)
-	r.Post("/sessions/{sessionId}/groups/join", h.Group.JoinGroup)
-	r.Post("/sessions/{sessionId}/groups/create", h.Group.CreateGroup)
-	r.Post("/sessions/{sessionId}/groups/leave", h.Group.LeaveGroup)
-	r.Post("/sessions/{sessionId}/groups/participants", h.Group.UpdateGroupParticipants)
-	r.Post("/sessions/{sessionId}/groups/name", h.Group.SetGroupName)
-	r.Post("/sessions/{sessionId}/groups/topic", h.Group.SetGroupTopic)
-	r.Post("/sessions/{sessionId}/groups/settings/locked", h.Group.SetGroupLocked)
-	r.Post("/sessions/{sessionId}/groups/settings/announce", h.Group.SetGroupAnnounce)
-	r.Post("/sessions/{sessionId}/groups/settings/disappearing", h.Group.SetDisappearingTimer)
-	r.Post("/sessions/{sessionId}/groups/photo", h.Group.SetGroupPhoto)
-	r.Delete("/sessions/{sessionId}/groups/photo", h.Group.RemoveGroupPhoto)
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	read.Get("/sessions/{sessionId}/groups", h.Group.ListGroups)
+	read.Get("/sessions/{sessionId}/groups/info", h.Group.GetGroupInfo)
+	read.Post("/sessions/{sessionId}/groups/invite-info", h.Group.GetGroupInviteInfo)
+	read.Get("/sessions/{sessionId}/groups/invite-link", h.Group.GetGroupInviteLink)
+	send.Post("/sessions/{sessionId}/groups/join", h.Group.JoinGroup)
+	send.Post("/sessions/{sessionId}/groups/create", h.Group.CreateGroup)
+	send.Post("/sessions/{sessionId}/groups/leave", h.Group.LeaveGroup)
+	send.Post("/sessions/{sessionId}/groups/participants", h.Group.UpdateGroupParticipants)
+	send.Post("/sessions/{sessionId}/groups/name", h.Group.SetGroupName)
+	send.Post("/sessions/{sessionId}/groups/topic", h.Group.SetGroupTopic)
+	send.Post("/sessions/{sessionId}/groups/settings/locked", h.Group.SetGroupLocked)
+	send.Post("/sessions/{sessionId}/groups/settings/announce", h.Group.SetGroupAnnounce)
+	send.Post("/sessions/{sessionId}/groups/settings/disappearing", h.Group.SetDisappearingTimer)
+	send.Post("/sessions/{sessionId}/groups/photo", h.Group.SetGroupPhoto)
+	send.Delete("/sessions/{sessionId}/groups/photo", h.Group.RemoveGroupPhoto)
 }
 
 func setupCommunityRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Get("/sessions/{sessionId}/communities", h.Community.ListCommunities)
-	r.Get("/sessions/{sessionId}/communities/info", h.Community.GetCommunityInfo)
-	r.Post("/sessions/{sessionId}/communities", h.Community.CreateCommunity)
-	r.Get("/sessions/{sessionId}/communities/{communityJid}/groups", h.Community.GetSubGroups)
-	r.Get("/sessions/{sessionId}/communities/{communityJid}/participants", h.Community.GetParticipants)
-	r.Post("/sessions/{sessionId}/communities/{communityJid}/link", h.Community.LinkGroup)
-	r.Post("/sessions/{sessionId}/communities/{communityJid}/unlink", h.Community.UnlinkGroup)
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	read.Get("/sessions/{sessionId}/communities", h.Community.ListCommunities)
+	read.Get("/sessions/{sessionId}/communities/info", h.Community.GetCommunityInfo)
+	send.Post("/sessions/{sessionId}/communities", h.Community.CreateCommunity)
+	read.Get("/sessions/{sessionId}/communities/{communityJid}/groups", h.Community.GetSubGroups)
+	read.Get("/sessions/{sessionId}/communities/{communityJid}/participants", h.Community.GetParticipants)
+	send.Post("/sessions/{sessionId}/communities/{communityJid}/link", h.Community.LinkGroup)
+	send.Post("/sessions/{sessionId}/communities/{communityJid}/unlink", h.Community.UnlinkGroup)
 }
 
 func setupNewsletterRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Get("/sessions/{sessionId}/newsletters", h.Newsletter.ListNewsletters)
-	r.Get("/sessions/{sessionId}/newsletters/info", h.Newsletter.GetNewsletterInfo)
-	r.Post("/sessions/{sessionId}/newsletters/info-invite", h.Newsletter.GetNewsletterInfoWithInvite)
-	r.Post("/sessions/{sessionId}/newsletters", h.Newsletter.CreateNewsletter)
-	r.Post("/sessions/{sessionId}/newsletters/follow", h.Newsletter.FollowNewsletter)
-	r.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/unfollow", h.Newsletter.UnfollowNewsletter)
-	r.Get("/sessions/{sessionId}/newsletters/{newsletterJid}/messages", h.Newsletter.GetMessages)
-	r.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/mark-viewed", h.Newsletter.MarkViewed)
-	r.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/react", h.Newsletter.SendReaction)
-	r.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/mute", h.Newsletter.ToggleMute)
-	r.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/send", h.Newsletter.SendMessage)
+	read := withPermission(r, apikey.PermissionRead)
+	send := withPermission(r, apikey.PermissionSend)
+
+	read.Get("/sessions/{sessionId}/newsletters", h.Newsletter.ListNewsletters)
+	read.Get("/sessions/{sessionId}/newsletters/info", h.Newsletter.GetNewsletterInfo)
+	read.Post("/sessions/{sessionId}/newsletters/info-invite", h.Newsletter.GetNewsletterInfoWithInvite)
+	send.Post("/sessions/{sessionId}/newsletters", h.Newsletter.CreateNewsletter)
+	send.Post("/sessions/{sessionId}/newsletters/follow", h.Newsletter.FollowNewsletter)
+	send.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/unfollow", h.Newsletter.UnfollowNewsletter)
+	read.Get("/sessions/{sessionId}/newsletters/{newsletterJid}/messages", h.Newsletter.GetMessages)
+	send.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/mark-viewed", h.Newsletter.MarkViewed)
+	send.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/react", h.Newsletter.SendReaction)
+	send.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/mute", h.Newsletter.ToggleMute)
+	send.Post("/sessions/{sessionId}/newsletters/{newsletterJid}/send", h.Newsletter.SendMessage)
 }
 
 func setupWebhookRoutes(r chi.Router, h *handlers.Handlers) {
-	r.Post("/sessions/{sessionId}/webhooks", h.Webhook.SetWebhook)
-	r.Get("/sessions/{sessionId}/webhooks", h.Webhook.GetWebhook)
-	r.Delete("/sessions/{sessionId}/webhooks", h.Webhook.DeleteWebhook)
-	r.Get("/webhooks/events", h.Webhook.ListEvents)
+	webhooks := withPermission(r, apikey.PermissionWebhooks)
+	// The global webhook and dead letters carry events of every session of
+	// every tenant.
+	global := withPlatformPermission(r, apikey.PermissionWebhooks)
+
+	webhooks.Post("/sessions/{sessionId}/webhooks", h.Webhook.SetWebhook)
+	webhooks.Get("/sessions/{sessionId}/webhooks", h.Webhook.ListWebhooks)
+	webhooks.Delete("/sessions/{sessionId}/webhooks", h.Webhook.DeleteWebhooks)
+	webhooks.Get("/sessions/{sessionId}/webhooks/{webhookId}", h.Webhook.GetWebhook)
+	webhooks.Put("/sessions/{sessionId}/webhooks/{webhookId}", h.Webhook.UpdateWebhook)
+	webhooks.Delete("/sessions/{sessionId}/webhooks/{webhookId}", h.Webhook.DeleteWebhook)
+	webhooks.Get("/webhooks/events", h.Webhook.ListEvents)
+	webhooks.Get("/webhooks/schemas", h.Webhook.ListEventSchemas)
+	webhooks.Get("/webhooks/schemas/{eventType}", h.Webhook.GetEventSchema)
+	global.Get("/webhooks/global", h.Webhook.GetGlobalWebhook)
+	global.Put("/webhooks/global", h.Webhook.SetGlobalWebhook)
+	global.Delete("/webhooks/global", h.Webhook.DeleteGlobalWebhook)
+	global.Get("/webhooks/deadletters", h.Webhook.ListDeadLetters)
+	global.Post("/webhooks/deadletters/replay", h.Webhook.ReplayDeadLetters)
+	global.Get("/webhooks/deadletters/{deliveryId}", h.Webhook.GetDeadLetter)
+	global.Post("/webhooks/deadletters/{deliveryId}/replay", h.Webhook.ReplayDeadLetter)
+}
+
+func setupChatwootRoutes(r chi.Router, h *handlers.Handlers) {
+	webhooks := withPermission(r, apikey.PermissionWebhooks)
+
+	webhooks.Get("/sessions/{sessionId}/chatwoot", h.Chatwoot.GetConfig)
+	webhooks.Put("/sessions/{sessionId}/chatwoot", h.Chatwoot.SetConfig)
+	webhooks.Delete("/sessions/{sessionId}/chatwoot", h.Chatwoot.DeleteConfig)
+	webhooks.Post("/sessions/{sessionId}/chatwoot/import", h.Chatwoot.StartImport)
+	webhooks.Get("/sessions/{sessionId}/chatwoot/import", h.Chatwoot.GetImport)
+}
+
+func setupAPIKeyRoutes(r chi.Router, h *handlers.Handlers) {
+	admin := withPermission(r, apikey.PermissionAdmin)
+
+	admin.Post("/admin/api-keys", h.APIKey.Create)
+	admin.Get("/admin/api-keys", h.APIKey.List)
+	admin.Get("/admin/api-keys/{keyId}", h.APIKey.Get)
+	admin.Post("/admin/api-keys/{keyId}/rotate", h.APIKey.Rotate)
+	admin.Delete("/admin/api-keys/{keyId}", h.APIKey.Revoke)
+}
+
+func setupTenantRoutes(r chi.Router, h *handlers.Handlers) {
+	admin := withPlatformPermission(r, apikey.PermissionAdmin)
+
+	admin.Post("/admin/tenants", h.Tenant.Create)
+	admin.Get("/admin/tenants", h.Tenant.List)
+	admin.Get("/admin/tenants/{tenantId}", h.Tenant.Get)
+	admin.Put("/admin/tenants/{tenantId}", h.Tenant.Update)
+	admin.Delete("/admin/tenants/{tenantId}", h.Tenant.Delete)
+	admin.Post("/admin/tenants/{tenantId}/suspend", h.Tenant.Suspend)
+	admin.Post("/admin/tenants/{tenantId}/activate", h.Tenant.Activate)
 }
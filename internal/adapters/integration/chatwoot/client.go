@@ -0,0 +1,349 @@
+package chatwoot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	domainChatwoot "zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/ports/output"
+)
+
+// HTTPClient implements output.ChatwootClient on top of the Chatwoot
+// application API (/api/v1/accounts/{accountId}).
+type HTTPClient struct {
+	httpClient *http.Client
+}
+
+func NewHTTPClient(httpClient *http.Client) output.ChatwootClient {
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	return &HTTPClient{
+		httpClient: httpClient,
+	}
+}
+
+// maxAttachmentSize matches the largest document WhatsApp accepts.
+const maxAttachmentSize = 100 << 20
+
+// maxRedirects is the limit net/http applies when a client has no
+// CheckRedirect.
+const maxRedirects = 10
+
+type apiInbox struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type apiContact struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Identifier  string `json:"identifier"`
+}
+
+type apiConversation struct {
+	ID      int    `json:"id"`
+	InboxID int    `json:"inbox_id"`
+	Status  string `json:"status"`
+}
+
+func (c *HTTPClient) ListInboxes(ctx context.Context, cfg *domainChatwoot.Config) ([]*domainChatwoot.Inbox, error) {
+	var response struct {
+		Payload []apiInbox `json:"payload"`
+	}
+
+	if err := c.do(ctx, cfg, http.MethodGet, "/inboxes", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list inboxes: %w", err)
+	}
+
+	inboxes := make([]*domainChatwoot.Inbox, len(response.Payload))
+	for i, inbox := range response.Payload {
+		inboxes[i] = &domainChatwoot.Inbox{ID: inbox.ID, Name: inbox.Name}
+	}
+
+	return inboxes, nil
+}
+
+func (c *HTTPClient) CreateInbox(ctx context.Context, cfg *domainChatwoot.Config, name string) (*domainChatwoot.Inbox, error) {
+	body := map[string]interface{}{
+		"name": name,
+		"channel": map[string]interface{}{
+			"type": "api",
+		},
+	}
+
+	var response apiInbox
+	if err := c.do(ctx, cfg, http.MethodPost, "/inboxes", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to create inbox: %w", err)
+	}
+
+	return &domainChatwoot.Inbox{ID: response.ID, Name: response.Name}, nil
+}
+
+func (c *HTTPClient) SearchContacts(ctx context.Context, cfg *domainChatwoot.Config, query string) ([]*domainChatwoot.Contact, error) {
+	var response struct {
+		Payload []apiContact `json:"payload"`
+	}
+
+	path := "/contacts/search?q=" + url.QueryEscape(query)
+	if err := c.do(ctx, cfg, http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+
+	contacts := make([]*domainChatwoot.Contact, len(response.Payload))
+	for i := range response.Payload {
+		contacts[i] = response.Payload[i].toDomain()
+	}
+
+	return contacts, nil
+}
+
+func (c *HTTPClient) CreateContact(ctx context.Context, cfg *domainChatwoot.Config, inboxID int, contact *domainChatwoot.Contact) (*domainChatwoot.Contact, error) {
+	body := map[string]interface{}{
+		"inbox_id":   inboxID,
+		"name":       contact.Name,
+		"identifier": contact.Identifier,
+	}
+
+	if contact.PhoneNumber != "" {
+		body["phone_number"] = contact.PhoneNumber
+	}
+
+	var response struct {
+		Payload struct {
+			Contact apiContact `json:"contact"`
+		} `json:"payload"`
+	}
+
+	if err := c.do(ctx, cfg, http.MethodPost, "/contacts", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	return response.Payload.Contact.toDomain(), nil
+}
+
+func (c *HTTPClient) ListContactConversations(ctx context.Context, cfg *domainChatwoot.Config, contactID int) ([]*domainChatwoot.Conversation, error) {
+	var response struct {
+		Payload []apiConversation `json:"payload"`
+	}
+
+	path := fmt.Sprintf("/contacts/%d/conversations", contactID)
+	if err := c.do(ctx, cfg, http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list contact conversations: %w", err)
+	}
+
+	conversations := make([]*domainChatwoot.Conversation, len(response.Payload))
+	for i := range response.Payload {
+		conversations[i] = response.Payload[i].toDomain()
+	}
+
+	return conversations, nil
+}
+
+func (c *HTTPClient) CreateConversation(ctx context.Context, cfg *domainChatwoot.Config, inboxID, contactID int, status string) (*domainChatwoot.Conversation, error) {
+	body := map[string]interface{}{
+		"inbox_id":   inboxID,
+		"contact_id": contactID,
+		"status":     status,
+	}
+
+	var response apiConversation
+	if err := c.do(ctx, cfg, http.MethodPost, "/conversations", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	return response.toDomain(), nil
+}
+
+func (c *HTTPClient) SetConversationStatus(ctx context.Context, cfg *domainChatwoot.Config, conversationID int, status string) error {
+	path := fmt.Sprintf("/conversations/%d/toggle_status", conversationID)
+	if err := c.do(ctx, cfg, http.MethodPost, path, map[string]string{"status": status}, nil); err != nil {
+		return fmt.Errorf("failed to set conversation status: %w", err)
+	}
+
+	return nil
+}
+
+func (c *HTTPClient) CreateMessage(ctx context.Context, cfg *domainChatwoot.Config, conversationID int, message *domainChatwoot.Message) (int, error) {
+	body := map[string]interface{}{
+		"content":      message.Content,
+		"message_type": message.MessageType,
+		"private":      message.Private,
+	}
+
+	if message.SourceID != "" {
+		body["source_id"] = message.SourceID
+	}
+
+	var response struct {
+		ID int `json:"id"`
+	}
+
+	path := fmt.Sprintf("/conversations/%d/messages", conversationID)
+	if err := c.do(ctx, cfg, http.MethodPost, path, body, &response); err != nil {
+		return 0, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	return response.ID, nil
+}
+
+// DownloadAttachment fetches the file behind an attachment's data_url. The
+// access token is only sent when the file is served by Chatwoot itself.
+func (c *HTTPClient) DownloadAttachment(ctx context.Context, cfg *domainChatwoot.Config, dataURL string) (*domainChatwoot.Attachment, error) {
+	parsed, err := url.Parse(dataURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid attachment URL %q", dataURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dataURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpClient := c.httpClient
+
+	if base, err := url.Parse(cfg.URL); err == nil && base.Host == parsed.Host {
+		req.Header.Set("api_access_token", cfg.Token)
+		httpClient = keepTokenOnHost(c.httpClient, parsed.Host)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("attachment download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAttachmentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	if len(data) > maxAttachmentSize {
+		return nil, fmt.Errorf("attachment exceeds %d bytes", maxAttachmentSize)
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+
+	return &domainChatwoot.Attachment{
+		Data:     data,
+		MimeType: mimeType,
+		FileName: path.Base(parsed.Path),
+	}, nil
+}
+
+// keepTokenOnHost returns a copy of httpClient that drops the access token
+// when a redirect leaves host. net/http copies custom headers to every
+// redirect, whatever its host.
+func keepTokenOnHost(httpClient *http.Client, host string) *http.Client {
+	client := *httpClient
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != host {
+			req.Header.Del("api_access_token")
+		}
+
+		if httpClient.CheckRedirect != nil {
+			return httpClient.CheckRedirect(req, via)
+		}
+
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		return nil
+	}
+
+	return &client
+}
+
+// do sends an authenticated request to the account API and decodes the JSON
+// response into out, when given.
+func (c *HTTPClient) do(ctx context.Context, cfg *domainChatwoot.Config, method, path string, body, out interface{}) error {
+	var reader io.Reader
+
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		reader = bytes.NewReader(payload)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%s%s", cfg.URL, url.PathEscape(cfg.AccountID), path)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("api_access_token", cfg.Token)
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("chatwoot returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+func (c *apiContact) toDomain() *domainChatwoot.Contact {
+	return &domainChatwoot.Contact{
+		ID:          c.ID,
+		Name:        c.Name,
+		PhoneNumber: c.PhoneNumber,
+		Identifier:  c.Identifier,
+	}
+}
+
+func (c *apiConversation) toDomain() *domainChatwoot.Conversation {
+	return &domainChatwoot.Conversation{
+		ID:      c.ID,
+		InboxID: c.InboxID,
+		Status:  c.Status,
+	}
+}
@@ -0,0 +1,384 @@
+package chatwoot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	domainChatwoot "zpwoot/internal/core/domain/chatwoot"
+)
+
+const testToken = "test-token"
+
+// fakeChatwoot serves the account API of account 7 from a map of
+// "METHOD path" to handlers, recording the body of each request.
+type fakeChatwoot struct {
+	t        *testing.T
+	routes   map[string]http.HandlerFunc
+	requests map[string]map[string]interface{}
+}
+
+func newFakeChatwoot(t *testing.T, routes map[string]http.HandlerFunc) (*fakeChatwoot, *domainChatwoot.Config) {
+	fake := &fakeChatwoot{t: t, routes: routes, requests: make(map[string]map[string]interface{})}
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, &domainChatwoot.Config{URL: server.URL, AccountID: "7", Token: testToken}
+}
+
+func (f *fakeChatwoot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if got := r.Header.Get("api_access_token"); got != testToken {
+		f.t.Errorf("%s %s: api_access_token = %q, want %q", r.Method, r.URL.Path, got, testToken)
+		http.Error(w, `{"error":"Invalid Access Token"}`, http.StatusUnauthorized)
+
+		return
+	}
+
+	key := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/api/v1/accounts/7")
+
+	handler, ok := f.routes[key]
+	if !ok {
+		f.t.Errorf("unexpected request %s", key)
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if r.Body != nil && r.ContentLength != 0 {
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			f.t.Errorf("%s: Content-Type = %q, want application/json", key, got)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			f.t.Errorf("%s: invalid JSON body: %v", key, err)
+		}
+
+		f.requests[key] = body
+	}
+
+	handler(w, r)
+}
+
+func reply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestContacts(t *testing.T) {
+	fake, cfg := newFakeChatwoot(t, map[string]http.HandlerFunc{
+		"GET /contacts/search": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("q"); got != "+5511999999999" {
+				t.Errorf("search query = %q, want +5511999999999", got)
+			}
+
+			reply(`{"payload":[{"id":3,"name":"Ana","phone_number":"+5511999999999","identifier":"5511999999999@s.whatsapp.net"}]}`)(w, r)
+		},
+		"POST /contacts": reply(`{"payload":{"contact":{"id":4,"name":"Bia","phone_number":"+5511888888888","identifier":"5511888888888@s.whatsapp.net"}}}`),
+	})
+
+	client := NewHTTPClient(nil)
+	ctx := context.Background()
+
+	contacts, err := client.SearchContacts(ctx, cfg, "+5511999999999")
+	if err != nil {
+		t.Fatalf("SearchContacts: %v", err)
+	}
+
+	want := []*domainChatwoot.Contact{{ID: 3, Name: "Ana", PhoneNumber: "+5511999999999", Identifier: "5511999999999@s.whatsapp.net"}}
+	if !reflect.DeepEqual(contacts, want) {
+		t.Errorf("contacts = %+v, want %+v", contacts, want)
+	}
+
+	created, err := client.CreateContact(ctx, cfg, 2, &domainChatwoot.Contact{
+		Name:        "Bia",
+		PhoneNumber: "+5511888888888",
+		Identifier:  "5511888888888@s.whatsapp.net",
+	})
+	if err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	if created.ID != 4 || created.Name != "Bia" {
+		t.Errorf("created = %+v, want contact 4 Bia", created)
+	}
+
+	wantBody := map[string]interface{}{
+		"inbox_id":     float64(2),
+		"name":         "Bia",
+		"identifier":   "5511888888888@s.whatsapp.net",
+		"phone_number": "+5511888888888",
+	}
+	if got := fake.requests["POST /contacts"]; !reflect.DeepEqual(got, wantBody) {
+		t.Errorf("create contact body = %v, want %v", got, wantBody)
+	}
+}
+
+func TestCreateContactWithoutPhoneNumber(t *testing.T) {
+	fake, cfg := newFakeChatwoot(t, map[string]http.HandlerFunc{
+		"POST /contacts": reply(`{"payload":{"contact":{"id":5,"name":"Group","identifier":"120363000000000000@g.us"}}}`),
+	})
+
+	if _, err := NewHTTPClient(nil).CreateContact(context.Background(), cfg, 2, &domainChatwoot.Contact{
+		Name:       "Group",
+		Identifier: "120363000000000000@g.us",
+	}); err != nil {
+		t.Fatalf("CreateContact: %v", err)
+	}
+
+	if _, ok := fake.requests["POST /contacts"]["phone_number"]; ok {
+		t.Error("phone_number sent for a contact without one")
+	}
+}
+
+func TestConversations(t *testing.T) {
+	fake, cfg := newFakeChatwoot(t, map[string]http.HandlerFunc{
+		"GET /contacts/3/conversations":        reply(`{"payload":[{"id":10,"inbox_id":2,"status":"resolved"}]}`),
+		"POST /conversations":                  reply(`{"id":11,"inbox_id":2,"status":"pending"}`),
+		"POST /conversations/10/toggle_status": reply(`{"payload":{"success":true}}`),
+	})
+
+	client := NewHTTPClient(nil)
+	ctx := context.Background()
+
+	conversations, err := client.ListContactConversations(ctx, cfg, 3)
+	if err != nil {
+		t.Fatalf("ListContactConversations: %v", err)
+	}
+
+	want := []*domainChatwoot.Conversation{{ID: 10, InboxID: 2, Status: "resolved"}}
+	if !reflect.DeepEqual(conversations, want) {
+		t.Errorf("conversations = %+v, want %+v", conversations, want)
+	}
+
+	created, err := client.CreateConversation(ctx, cfg, 2, 3, "pending")
+	if err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+
+	if created.ID != 11 || created.Status != "pending" {
+		t.Errorf("created = %+v, want pending conversation 11", created)
+	}
+
+	wantBody := map[string]interface{}{"inbox_id": float64(2), "contact_id": float64(3), "status": "pending"}
+	if got := fake.requests["POST /conversations"]; !reflect.DeepEqual(got, wantBody) {
+		t.Errorf("create conversation body = %v, want %v", got, wantBody)
+	}
+
+	if err := client.SetConversationStatus(ctx, cfg, 10, "open"); err != nil {
+		t.Fatalf("SetConversationStatus: %v", err)
+	}
+
+	if got := fake.requests["POST /conversations/10/toggle_status"]["status"]; got != "open" {
+		t.Errorf("toggled status = %v, want open", got)
+	}
+}
+
+func TestCreateMessage(t *testing.T) {
+	fake, cfg := newFakeChatwoot(t, map[string]http.HandlerFunc{
+		"POST /conversations/10/messages": reply(`{"id":99,"content":"hello"}`),
+	})
+
+	id, err := NewHTTPClient(nil).CreateMessage(context.Background(), cfg, 10, &domainChatwoot.Message{
+		Content:     "hello",
+		MessageType: "incoming",
+		SourceID:    "WAID:3EB0000000000000",
+	})
+	if err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+
+	if id != 99 {
+		t.Errorf("message id = %d, want 99", id)
+	}
+
+	wantBody := map[string]interface{}{
+		"content":      "hello",
+		"message_type": "incoming",
+		"private":      false,
+		"source_id":    "WAID:3EB0000000000000",
+	}
+	if got := fake.requests["POST /conversations/10/messages"]; !reflect.DeepEqual(got, wantBody) {
+		t.Errorf("message body = %v, want %v", got, wantBody)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	var chatwootToken, externalToken string
+
+	chatwootFiles := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatwootToken = r.Header.Get("api_access_token")
+		w.Header().Set("Content-Type", "image/png; charset=binary")
+		_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer chatwootFiles.Close()
+
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		externalToken = r.Header.Get("api_access_token")
+		w.Header()["Content-Type"] = nil
+		_, _ = w.Write([]byte("%PDF-1.7\n"))
+	}))
+	defer external.Close()
+
+	client := NewHTTPClient(nil)
+	cfg := &domainChatwoot.Config{URL: chatwootFiles.URL, AccountID: "7", Token: testToken}
+
+	attachment, err := client.DownloadAttachment(context.Background(), cfg, chatwootFiles.URL+"/rails/active_storage/photo.png")
+	if err != nil {
+		t.Fatalf("DownloadAttachment: %v", err)
+	}
+
+	if attachment.MimeType != "image/png" || attachment.FileName != "photo.png" || string(attachment.Data) != "\x89PNG\r\n\x1a\n" {
+		t.Errorf("attachment = %q %q %q, want image/png photo.png", attachment.MimeType, attachment.FileName, attachment.Data)
+	}
+
+	if chatwootToken != testToken {
+		t.Errorf("token sent to Chatwoot = %q, want %q", chatwootToken, testToken)
+	}
+
+	attachment, err = client.DownloadAttachment(context.Background(), cfg, external.URL+"/files/report.pdf")
+	if err != nil {
+		t.Fatalf("DownloadAttachment: %v", err)
+	}
+
+	if attachment.MimeType != "application/pdf" || attachment.FileName != "report.pdf" {
+		t.Errorf("attachment = %q %q, want the sniffed application/pdf report.pdf", attachment.MimeType, attachment.FileName)
+	}
+
+	if externalToken != "" {
+		t.Errorf("token sent to another host: %q", externalToken)
+	}
+}
+
+func TestDownloadAttachmentRedirects(t *testing.T) {
+	var externalToken string
+
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		externalToken = r.Header.Get("api_access_token")
+		_, _ = w.Write([]byte("%PDF-1.7\n"))
+	}))
+	defer external.Close()
+
+	var chatwootToken string
+
+	chatwootFiles := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rails/active_storage/report.pdf":
+			http.Redirect(w, r, external.URL+"/bucket/report.pdf", http.StatusFound)
+		case "/rails/active_storage/photo.png":
+			http.Redirect(w, r, "/files/photo.png", http.StatusFound)
+		default:
+			chatwootToken = r.Header.Get("api_access_token")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		}
+	}))
+	defer chatwootFiles.Close()
+
+	client := NewHTTPClient(nil)
+	cfg := &domainChatwoot.Config{URL: chatwootFiles.URL, AccountID: "7", Token: testToken}
+
+	if _, err := client.DownloadAttachment(context.Background(), cfg, chatwootFiles.URL+"/rails/active_storage/report.pdf"); err != nil {
+		t.Fatalf("DownloadAttachment: %v", err)
+	}
+
+	if externalToken != "" {
+		t.Errorf("token sent to the redirect target on another host: %q", externalToken)
+	}
+
+	if _, err := client.DownloadAttachment(context.Background(), cfg, chatwootFiles.URL+"/rails/active_storage/photo.png"); err != nil {
+		t.Fatalf("DownloadAttachment: %v", err)
+	}
+
+	if chatwootToken != testToken {
+		t.Errorf("token after a redirect within Chatwoot = %q, want %q", chatwootToken, testToken)
+	}
+}
+
+func TestDownloadAttachmentErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	cfg := &domainChatwoot.Config{URL: server.URL, AccountID: "7", Token: testToken}
+
+	tests := []struct {
+		name    string
+		dataURL string
+		wantErr string
+	}{
+		{"file scheme", "file:///etc/passwd", "invalid attachment URL"},
+		{"no scheme", "photo.png", "invalid attachment URL"},
+		{"missing file", server.URL + "/photo.png", "attachment download returned status 404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.DownloadAttachment(context.Background(), cfg, tt.dataURL)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	_, cfg := newFakeChatwoot(t, map[string]http.HandlerFunc{
+		"GET /inboxes": func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, `{"error":"You are not authorized to do this action"}`, http.StatusForbidden)
+		},
+		"POST /inboxes": func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, `{"message":"Name can't be blank"}`, http.StatusUnprocessableEntity)
+		},
+		"POST /conversations": reply(`not json`),
+		"POST /conversations/10/messages": func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, strings.Repeat("x", 4096), http.StatusInternalServerError)
+		},
+	})
+
+	client := NewHTTPClient(nil)
+	ctx := context.Background()
+
+	_, err := client.ListInboxes(ctx, cfg)
+	assertError(t, err, "failed to list inboxes: chatwoot returned status 403: "+`{"error":"You are not authorized to do this action"}`)
+
+	_, err = client.CreateInbox(ctx, cfg, "")
+	assertError(t, err, "failed to create inbox: chatwoot returned status 422: "+`{"message":"Name can't be blank"}`)
+
+	_, err = client.CreateConversation(ctx, cfg, 2, 3, "open")
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to create conversation: failed to decode response") {
+		t.Errorf("error = %v, want a decode error", err)
+	}
+
+	// Only the start of a long error body is kept.
+	_, err = client.CreateMessage(ctx, cfg, 10, &domainChatwoot.Message{Content: "hello"})
+	assertError(t, err, "failed to create message: chatwoot returned status 500: "+strings.Repeat("x", 512))
+}
+
+func TestUnreachableChatwoot(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	cfg := &domainChatwoot.Config{URL: server.URL, AccountID: "7", Token: testToken}
+
+	_, err := NewHTTPClient(nil).ListInboxes(context.Background(), cfg)
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to list inboxes: HTTP request failed") {
+		t.Fatalf("error = %v, want a failed request", err)
+	}
+}
+
+func assertError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
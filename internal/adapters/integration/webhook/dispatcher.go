@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	domainWebhook "zpwoot/internal/core/domain/webhook"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultMaxAttempts  = 10
+	defaultPollInterval = time.Second
+	deliveryLease       = 2 * time.Minute
+	baseBackoff         = 5 * time.Second
+	maxBackoff          = 10 * time.Minute
+)
+
+type DispatcherConfig struct {
+	Workers      int
+	MaxAttempts  int
+	PollInterval time.Duration
+}
+
+// Dispatcher delivers webhook events from the Postgres outbox. A poller
+// leases due deliveries and hands them to a fixed pool of workers; failed
+// attempts are retried with exponential backoff and moved to the dead-letter
+// store once MaxAttempts is reached.
+type Dispatcher struct {
+	repo   domainWebhook.DeliveryRepository
+	sender *HTTPWebhookSender
+	logger *logger.Logger
+	config DispatcherConfig
+
+	jobs     chan *domainWebhook.Delivery
+	wake     chan struct{}
+	inFlight atomic.Int32
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewDispatcher(repo domainWebhook.DeliveryRepository, httpClient *http.Client, logger *logger.Logger, config DispatcherConfig) *Dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	return &Dispatcher{
+		repo: repo,
+		sender: &HTTPWebhookSender{
+			httpClient: httpClient,
+			logger:     logger,
+		},
+		logger: logger,
+		config: config,
+		jobs:   make(chan *domainWebhook.Delivery),
+		wake:   make(chan struct{}, 1),
+	}
+}
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+
+		go d.work(ctx)
+	}
+
+	d.wg.Add(1)
+
+	go d.poll(ctx)
+
+	d.logger.Info().
+		Int("workers", d.config.Workers).
+		Int("max_attempts", d.config.MaxAttempts).
+		Msg("Webhook dispatcher started")
+}
+
+// Stop waits for in-flight deliveries. Leased deliveries that were not
+// started become due again once their lease expires.
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+
+	d.cancel()
+	d.wg.Wait()
+
+	d.logger.Info().Msg("Webhook dispatcher stopped")
+}
+
+// Notify wakes the poller so a freshly queued event does not wait for the
+// next poll interval.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+func (d *Dispatcher) poll(ctx context.Context) {
+	defer d.wg.Done()
+	defer close(d.jobs)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		d.dispatchDue(ctx)
+	}
+}
+func (d *Dispatcher) dispatchDue(ctx context.Context) {
+	free := d.config.Workers - int(d.inFlight.Load())
+	if free <= 0 {
+		return
+	}
+
+	deliveries, err := d.repo.ClaimDue(ctx, free, deliveryLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error().Err(err).Msg("Failed to claim webhook deliveries")
+		}
+
+		return
+	}
+
+	for _, delivery := range deliveries {
+		d.inFlight.Add(1)
+
+		select {
+		case d.jobs <- delivery:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+func (d *Dispatcher) work(ctx context.Context) {
+	defer d.wg.Done()
+
+	for delivery := range d.jobs {
+		d.deliver(ctx, delivery)
+		d.inFlight.Add(-1)
+
+		// The next event of the endpoint may be due right away.
+		d.Notify()
+	}
+}
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domainWebhook.Delivery) {
+	// An attempt that already started is finished even during shutdown, so
+	// its outcome is recorded instead of being sent twice.
+	attemptCtx := context.WithoutCancel(ctx)
+
+	statusCode, err := d.sender.Deliver(attemptCtx, delivery)
+	if err == nil {
+		if err := d.repo.Complete(attemptCtx, delivery.ID); err != nil {
+			d.logger.Error().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to complete webhook delivery")
+		}
+
+		d.logger.Debug().
+			Str("url", delivery.Endpoint).
+			Str("event_type", delivery.EventType).
+			Str("event_id", delivery.EventID).
+			Int("attempt", delivery.Attempts).
+			Msg("Webhook delivered")
+
+		return
+	}
+
+	var status *int
+	if statusCode != 0 {
+		status = &statusCode
+	}
+
+	if delivery.Attempts >= d.config.MaxAttempts || isPermanentFailure(statusCode) {
+		d.logger.Error().
+			Err(err).
+			Str("url", delivery.Endpoint).
+			Str("event_type", delivery.EventType).
+			Str("event_id", delivery.EventID).
+			Int("attempts", delivery.Attempts).
+			Msg("Webhook delivery failed, moving to dead letters")
+
+		if err := d.repo.MoveToDeadLetter(attemptCtx, delivery.ID, err.Error(), status); err != nil {
+			d.logger.Error().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to dead-letter webhook delivery")
+		}
+
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(backoff(delivery.Attempts))
+
+	d.logger.Warn().
+		Err(err).
+		Str("url", delivery.Endpoint).
+		Str("event_type", delivery.EventType).
+		Str("event_id", delivery.EventID).
+		Int("attempt", delivery.Attempts).
+		Time("next_attempt_at", nextAttemptAt).
+		Msg("Webhook delivery failed, retrying")
+
+	if err := d.repo.Reschedule(attemptCtx, delivery.ID, nextAttemptAt, err.Error(), status); err != nil {
+		d.logger.Error().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to reschedule webhook delivery")
+	}
+}
+
+// backoff returns the delay after the given failed attempt: 5s, 10s, 20s, ...
+// capped at 10 minutes.
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// isPermanentFailure reports whether the receiver rejected the event itself.
+// Timeouts and rate limiting are worth retrying like server errors.
+func isPermanentFailure(statusCode int) bool {
+	if statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests {
+		return false
+	}
+
+	return statusCode >= 400 && statusCode < 500
+}
@@ -0,0 +1,402 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	domainWebhook "zpwoot/internal/core/domain/webhook"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{4, 40 * time.Second},
+		{7, 320 * time.Second},
+		{8, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestIsPermanentFailure(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{0, false},
+		{http.StatusOK, false},
+		{http.StatusFound, false},
+		{http.StatusBadRequest, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusNotFound, true},
+		{http.StatusGone, true},
+		{http.StatusRequestTimeout, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+		{http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		if got := isPermanentFailure(tt.statusCode); got != tt.want {
+			t.Errorf("isPermanentFailure(%d) = %v, want %v", tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+type reschedule struct {
+	id            string
+	nextAttemptAt time.Time
+	statusCode    *int
+}
+
+type deadLetter struct {
+	id         string
+	attempts   int
+	statusCode *int
+}
+
+// memoryOutbox is an outbox kept in memory. Like the Postgres outbox it
+// only hands out the oldest delivery of each endpoint, but it ignores the
+// backoff so retries do not wait for it.
+type memoryOutbox struct {
+	domainWebhook.DeliveryRepository
+
+	mutex       sync.Mutex
+	pending     []*domainWebhook.Delivery
+	leased      map[string]bool
+	completed   []string
+	reschedules []reschedule
+	deadLetters []deadLetter
+}
+
+func newMemoryOutbox(deliveries ...*domainWebhook.Delivery) *memoryOutbox {
+	return &memoryOutbox{pending: deliveries, leased: make(map[string]bool)}
+}
+
+func (o *memoryOutbox) ClaimDue(_ context.Context, limit int, _ time.Duration) ([]*domainWebhook.Delivery, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var claimed []*domainWebhook.Delivery
+
+	heads := make(map[string]bool)
+
+	for _, delivery := range o.pending {
+		if heads[delivery.Endpoint] {
+			continue
+		}
+
+		heads[delivery.Endpoint] = true
+
+		if o.leased[delivery.ID] || len(claimed) == limit {
+			continue
+		}
+
+		o.leased[delivery.ID] = true
+		delivery.Attempts++
+
+		leased := *delivery
+		claimed = append(claimed, &leased)
+	}
+
+	return claimed, nil
+}
+
+func (o *memoryOutbox) Complete(_ context.Context, id string) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.remove(id)
+	o.completed = append(o.completed, id)
+
+	return nil
+}
+
+func (o *memoryOutbox) Reschedule(_ context.Context, id string, nextAttemptAt time.Time, _ string, statusCode *int) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	delete(o.leased, id)
+	o.reschedules = append(o.reschedules, reschedule{id: id, nextAttemptAt: nextAttemptAt, statusCode: statusCode})
+
+	return nil
+}
+
+func (o *memoryOutbox) MoveToDeadLetter(_ context.Context, id string, _ string, statusCode *int) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	attempts := 0
+
+	for _, delivery := range o.pending {
+		if delivery.ID == id {
+			attempts = delivery.Attempts
+		}
+	}
+
+	o.remove(id)
+	o.deadLetters = append(o.deadLetters, deadLetter{id: id, attempts: attempts, statusCode: statusCode})
+
+	return nil
+}
+
+func (o *memoryOutbox) remove(id string) {
+	delete(o.leased, id)
+
+	for i, delivery := range o.pending {
+		if delivery.ID == id {
+			o.pending = append(o.pending[:i], o.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (o *memoryOutbox) empty() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return len(o.pending) == 0
+}
+
+// receiver is a webhook endpoint answering with the next status of its
+// script, then 200.
+type receiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests []*http.Request
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.requests = append(r.requests, req)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() []*http.Request {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*http.Request(nil), r.requests...)
+}
+
+func newTestDelivery(endpoint, eventID string) *domainWebhook.Delivery {
+	return domainWebhook.NewDelivery("session-1", endpoint, nil, eventID, "Message", []byte(`{"type":"Message"}`))
+}
+
+func newTestDispatcher(repo domainWebhook.DeliveryRepository, maxAttempts int) *Dispatcher {
+	return NewDispatcher(repo, nil, logger.New(), DispatcherConfig{
+		Workers:      2,
+		MaxAttempts:  maxAttempts,
+		PollInterval: 5 * time.Millisecond,
+	})
+}
+
+// runUntilEmpty runs the dispatcher until every delivery left the outbox.
+func runUntilEmpty(t *testing.T, dispatcher *Dispatcher, outbox *memoryOutbox) {
+	t.Helper()
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !outbox.empty() {
+		if time.Now().After(deadline) {
+			t.Fatal("deliveries still pending")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		attempts       int
+		wantComplete   bool
+		wantReschedule bool
+		wantDeadLetter bool
+	}{
+		{"delivered", http.StatusOK, 1, true, false, false},
+		{"accepted", http.StatusAccepted, 1, true, false, false},
+		{"server error", http.StatusInternalServerError, 1, false, true, false},
+		{"unavailable", http.StatusServiceUnavailable, 4, false, true, false},
+		{"request timeout", http.StatusRequestTimeout, 1, false, true, false},
+		{"rate limited", http.StatusTooManyRequests, 1, false, true, false},
+		{"bad request", http.StatusBadRequest, 1, false, false, true},
+		{"gone", http.StatusGone, 1, false, false, true},
+		{"last attempt", http.StatusInternalServerError, 5, false, false, true},
+		{"rate limited on the last attempt", http.StatusTooManyRequests, 5, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(&receiver{statuses: []int{tt.status}})
+			defer server.Close()
+
+			delivery := newTestDelivery(server.URL, "event-1")
+			delivery.Attempts = tt.attempts
+
+			outbox := newMemoryOutbox(delivery)
+			start := time.Now()
+
+			newTestDispatcher(outbox, 5).deliver(context.Background(), delivery)
+
+			if completed := len(outbox.completed) == 1; completed != tt.wantComplete {
+				t.Errorf("completed = %v, want %v", outbox.completed, tt.wantComplete)
+			}
+
+			if rescheduled := len(outbox.reschedules) == 1; rescheduled != tt.wantReschedule {
+				t.Fatalf("reschedules = %v, want %v", outbox.reschedules, tt.wantReschedule)
+			}
+
+			if tt.wantReschedule {
+				r := outbox.reschedules[0]
+
+				if r.statusCode == nil || *r.statusCode != tt.status {
+					t.Errorf("rescheduled with status %v, want %d", r.statusCode, tt.status)
+				}
+
+				if wait := r.nextAttemptAt.Sub(start); wait < backoff(tt.attempts) || wait > backoff(tt.attempts)+time.Second {
+					t.Errorf("next attempt in %v, want %v", wait, backoff(tt.attempts))
+				}
+			}
+
+			if deadLettered := len(outbox.deadLetters) == 1; deadLettered != tt.wantDeadLetter {
+				t.Fatalf("dead letters = %v, want %v", outbox.deadLetters, tt.wantDeadLetter)
+			}
+
+			if tt.wantDeadLetter {
+				if status := outbox.deadLetters[0].statusCode; status == nil || *status != tt.status {
+					t.Errorf("dead-lettered with status %v, want %d", status, tt.status)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherDeliverUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	delivery := newTestDelivery(server.URL, "event-1")
+	delivery.Attempts = 1
+
+	outbox := newMemoryOutbox(delivery)
+
+	newTestDispatcher(outbox, 5).deliver(context.Background(), delivery)
+
+	if len(outbox.reschedules) != 1 || outbox.reschedules[0].statusCode != nil {
+		t.Fatalf("reschedules = %+v, want one without a status code", outbox.reschedules)
+	}
+}
+
+func TestDispatcherRetriesUntilDelivered(t *testing.T) {
+	endpoint := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	outbox := newMemoryOutbox(newTestDelivery(server.URL, "event-1"), newTestDelivery(server.URL, "event-2"))
+
+	runUntilEmpty(t, newTestDispatcher(outbox, 5), outbox)
+
+	// The second event waits for the first one, which is retried until it
+	// is delivered.
+	requests := endpoint.received()
+
+	want := []struct {
+		eventID string
+		attempt int
+	}{
+		{"event-1", 1},
+		{"event-1", 2},
+		{"event-1", 3},
+		{"event-2", 1},
+	}
+
+	if len(requests) != len(want) {
+		t.Fatalf("receiver got %d requests, want %d", len(requests), len(want))
+	}
+
+	for i, w := range want {
+		if got := requests[i].Header.Get("X-Webhook-ID"); got != w.eventID {
+			t.Errorf("request %d is for %s, want %s", i, got, w.eventID)
+		}
+
+		if got := requests[i].Header.Get("X-Webhook-Attempt"); got != strconv.Itoa(w.attempt) {
+			t.Errorf("request %d is attempt %s, want %d", i, got, w.attempt)
+		}
+	}
+
+	if len(outbox.completed) != 2 || len(outbox.reschedules) != 2 || len(outbox.deadLetters) != 0 {
+		t.Errorf("completed %d, rescheduled %d, dead-lettered %d; want 2, 2, 0",
+			len(outbox.completed), len(outbox.reschedules), len(outbox.deadLetters))
+	}
+}
+
+func TestDispatcherDeadLettersAfterMaxAttempts(t *testing.T) {
+	endpoint := &receiver{statuses: []int{500, 500, 500, 500}}
+
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	outbox := newMemoryOutbox(newTestDelivery(server.URL, "event-1"))
+
+	runUntilEmpty(t, newTestDispatcher(outbox, 3), outbox)
+
+	if got := len(endpoint.received()); got != 3 {
+		t.Errorf("receiver got %d attempts, want 3", got)
+	}
+
+	if len(outbox.deadLetters) != 1 || outbox.deadLetters[0].attempts != 3 {
+		t.Fatalf("dead letters = %+v, want the event after 3 attempts", outbox.deadLetters)
+	}
+}
+
+func TestDispatcherDeadLettersRejectedEvents(t *testing.T) {
+	endpoint := &receiver{statuses: []int{http.StatusUnprocessableEntity}}
+
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	outbox := newMemoryOutbox(newTestDelivery(server.URL, "event-1"), newTestDelivery(server.URL, "event-2"))
+
+	runUntilEmpty(t, newTestDispatcher(outbox, 5), outbox)
+
+	// A rejected event is not retried and no longer blocks the endpoint.
+	if got := len(endpoint.received()); got != 2 {
+		t.Errorf("receiver got %d requests, want 2", got)
+	}
+
+	if len(outbox.deadLetters) != 1 || len(outbox.completed) != 1 || len(outbox.reschedules) != 0 {
+		t.Errorf("dead-lettered %d, completed %d, rescheduled %d; want 1, 1, 0",
+			len(outbox.deadLetters), len(outbox.completed), len(outbox.reschedules))
+	}
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	domainWebhook "zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
+)
+
+// OutboxWebhookSender queues webhook events in the outbox instead of posting
+// them directly. Delivery is done by the Dispatcher, which survives receiver
+// outages and restarts.
+type OutboxWebhookSender struct {
+	repo       domainWebhook.DeliveryRepository
+	dispatcher *Dispatcher
+}
+
+func NewOutboxWebhookSender(repo domainWebhook.DeliveryRepository, dispatcher *Dispatcher) output.WebhookSender {
+	return &OutboxWebhookSender{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+func (s *OutboxWebhookSender) SendWebhook(ctx context.Context, url string, secret *string, event *output.WebhookEvent) error {
+	if url == "" {
+		return errors.New("webhook URL cannot be empty")
+	}
+
+	if event == nil {
+		return errors.New("webhook event cannot be nil")
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	delivery := domainWebhook.NewDelivery(event.SessionID, url, secret, event.ID, event.Type, payload)
+	if err := s.repo.Enqueue(ctx, delivery); err != nil {
+		return err
+	}
+
+	if s.dispatcher != nil {
+		s.dispatcher.Notify()
+	}
+
+	return nil
+}
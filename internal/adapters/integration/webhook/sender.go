@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"zpwoot/internal/adapters/logger"
+	domainWebhook "zpwoot/internal/core/domain/webhook"
 	"zpwoot/internal/core/ports/output"
 )
 
@@ -45,23 +46,57 @@ func (s *HTTPWebhookSender) SendWebhook(ctx context.Context, url string, secret
 		return fmt.Errorf("failed to marshal webhook event: %w", err)
 	}
 
+	req, err := newWebhookRequest(ctx, url, secret, event.ID, event.Type, event.SessionID, event.Timestamp, payload)
+	if err != nil {
+		return err
+	}
+
+	return s.sendWithRetry(ctx, req, url, event.Type)
+}
+
+// Deliver makes a single attempt to post a queued delivery. The returned
+// status code is zero when no HTTP response was received.
+func (s *HTTPWebhookSender) Deliver(ctx context.Context, delivery *domainWebhook.Delivery) (int, error) {
+	req, err := newWebhookRequest(ctx, delivery.Endpoint, delivery.Secret, delivery.EventID, delivery.EventType, delivery.SessionID, delivery.CreatedAt, delivery.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(delivery.Attempts))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if !s.isSuccessStatusCode(resp.StatusCode) {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func newWebhookRequest(ctx context.Context, url string, secret *string, eventID, eventType, sessionID string, timestamp time.Time, payload []byte) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "zpwoot-webhook/1.0")
-	req.Header.Set("X-Webhook-Event", event.Type)
-	req.Header.Set("X-Session-ID", event.SessionID)
-	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(event.Timestamp.Unix(), 10))
+	req.Header.Set("X-Webhook-ID", eventID)
+	req.Header.Set("X-Webhook-Event", eventType)
+	req.Header.Set("X-Session-ID", sessionID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp.Unix(), 10))
 
 	if secret != nil && *secret != "" {
 		signature := GenerateSignature(payload, *secret)
 		req.Header.Set("X-Webhook-Signature", signature)
 	}
 
-	return s.sendWithRetry(ctx, req, url, event.Type)
+	return req, nil
 }
 func (s *HTTPWebhookSender) sendWithRetry(ctx context.Context, req *http.Request, url, eventType string) error {
 	config := s.getRetryConfig()
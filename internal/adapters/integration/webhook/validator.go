@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"strings"
 )
 
 func ValidateURL(webhookURL string) error {
@@ -29,36 +28,12 @@ func ValidateURL(webhookURL string) error {
 		return fmt.Errorf("URL must have a host")
 	}
 
-	if isLocalhost(parsedURL.Host) {
-		return fmt.Errorf("localhost URLs are not allowed for webhooks")
-	}
-
 	if len(webhookURL) > 2048 {
 		return fmt.Errorf("URL too long, maximum length is 2048 characters")
 	}
 
 	return nil
 }
-func isLocalhost(host string) bool {
-	if colonIndex := strings.LastIndex(host, ":"); colonIndex != -1 {
-		host = host[:colonIndex]
-	}
-
-	localhosts := []string{
-		"localhost",
-		"127.0.0.1",
-		"::1",
-		"0.0.0.0",
-	}
-
-	for _, localhost := range localhosts {
-		if host == localhost {
-			return true
-		}
-	}
-
-	return false
-}
 func ValidateSecret(secret string) error {
 	if secret == "" {
 		return fmt.Errorf("secret cannot be empty")
@@ -0,0 +1,215 @@
+package outbound
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+// privatePrefixes are the ranges that never lead to a public service:
+// loopback, private networks, link-local (with cloud metadata endpoints),
+// carrier-grade NAT, and reserved, multicast and translation ranges.
+var privatePrefixes = mustParsePrefixes(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// Config lists the addresses outbound requests may reach. Deny wins over
+// Allow, and Allow exempts ranges from BlockPrivate, so an internal webhook
+// receiver can be allowed while the rest of the private network is not.
+type Config struct {
+	AllowCIDRs   []string
+	DenyCIDRs    []string
+	BlockPrivate bool
+	MaxRedirects int
+}
+
+// Policy enforces a Config. Its HTTP clients check every address they
+// connect to, after DNS resolution, so a host that resolves to a public
+// address when it is checked and to a private one when it is used (DNS
+// rebinding) is still refused.
+type Policy struct {
+	allow        []netip.Prefix
+	deny         []netip.Prefix
+	blockPrivate bool
+	maxRedirects int
+	resolver     *net.Resolver
+}
+
+func NewPolicy(config Config) (*Policy, error) {
+	allow, err := parsePrefixes(config.AllowCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed CIDR: %w", err)
+	}
+
+	deny, err := parsePrefixes(config.DenyCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid denied CIDR: %w", err)
+	}
+
+	return &Policy{
+		allow:        allow,
+		deny:         deny,
+		blockPrivate: config.BlockPrivate,
+		maxRedirects: max(config.MaxRedirects, 0),
+		resolver:     net.DefaultResolver,
+	}, nil
+}
+
+// CheckAddr returns an error wrapping output.ErrOutboundDenied when addr
+// may not be reached.
+func (p *Policy) CheckAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	if containsAddr(p.deny, addr) {
+		return fmt.Errorf("%w: %s is in a denied range", output.ErrOutboundDenied, addr)
+	}
+
+	if containsAddr(p.allow, addr) {
+		return nil
+	}
+
+	if p.blockPrivate && containsAddr(privatePrefixes, addr) {
+		return fmt.Errorf("%w: %s is a private address", output.ErrOutboundDenied, addr)
+	}
+
+	return nil
+}
+
+// CheckURL checks the scheme of rawURL and every address its host resolves
+// to. Hosts that do not resolve pass, since the connection is checked
+// again when it is made.
+func (p *Policy) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: invalid URL: %w", output.ErrOutboundDenied, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q is not allowed", output.ErrOutboundDenied, u.Scheme)
+	}
+
+	host := u.Hostname()
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddr(addr)
+	}
+
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if err := p.CheckAddr(addr); err != nil {
+			return fmt.Errorf("%s resolves to a denied address: %w", host, err)
+		}
+	}
+
+	return nil
+}
+
+// HTTPClient returns a client that only connects to allowed addresses and
+// follows a limited number of redirects. It ignores proxy settings, since a
+// proxy would make the connection on its behalf.
+func (p *Policy) HTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   p.control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: p.checkRedirect,
+	}
+}
+
+// control runs after DNS resolution, right before each connection is made.
+func (p *Policy) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", output.ErrOutboundDenied, err)
+	}
+
+	return p.CheckAddr(addrPort.Addr())
+}
+
+func (p *Policy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > p.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", output.ErrOutboundDenied, p.maxRedirects)
+	}
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirect to scheme %q", output.ErrOutboundDenied, req.URL.Scheme)
+	}
+
+	return nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePrefixes parses CIDRs, taking a bare address as a single host.
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(cidr)
+			if addrErr != nil {
+				return nil, fmt.Errorf("%q: %w", cidr, err)
+			}
+
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+func mustParsePrefixes(cidrs ...string) []netip.Prefix {
+	prefixes, err := parsePrefixes(cidrs)
+	if err != nil {
+		panic(err)
+	}
+
+	return prefixes
+}
@@ -0,0 +1,230 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+func newTestPolicy(t *testing.T, config Config) *Policy {
+	t.Helper()
+
+	policy, err := NewPolicy(config)
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	return policy
+}
+
+func TestCheckAddr(t *testing.T) {
+	blockPrivate := Config{BlockPrivate: true}
+
+	tests := []struct {
+		name    string
+		config  Config
+		addr    string
+		allowed bool
+	}{
+		{"public IPv4", blockPrivate, "93.184.216.34", true},
+		{"public IPv6", blockPrivate, "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"loopback", blockPrivate, "127.0.0.1", false},
+		{"loopback range", blockPrivate, "127.10.0.1", false},
+		{"IPv6 loopback", blockPrivate, "::1", false},
+		{"unspecified", blockPrivate, "0.0.0.0", false},
+		{"IPv6 unspecified", blockPrivate, "::", false},
+		{"private 10/8", blockPrivate, "10.1.2.3", false},
+		{"private 172.16/12", blockPrivate, "172.20.0.5", false},
+		{"private 192.168/16", blockPrivate, "192.168.1.1", false},
+		{"carrier-grade NAT", blockPrivate, "100.64.0.1", false},
+		{"link-local", blockPrivate, "169.254.10.10", false},
+		{"cloud metadata", blockPrivate, "169.254.169.254", false},
+		{"IPv6 link-local", blockPrivate, "fe80::1", false},
+		{"IPv6 unique local", blockPrivate, "fd00::1", false},
+		{"multicast", blockPrivate, "224.0.0.1", false},
+		{"IPv4-mapped loopback", blockPrivate, "::ffff:127.0.0.1", false},
+		{"IPv4-mapped metadata", blockPrivate, "::ffff:169.254.169.254", false},
+		{"IPv4-mapped public", blockPrivate, "::ffff:93.184.216.34", true},
+		{"NAT64 translation", blockPrivate, "64:ff9b::7f00:1", false},
+		{"private allowed when not blocked", Config{}, "10.1.2.3", true},
+		{"loopback allowed when not blocked", Config{}, "127.0.0.1", true},
+		{"allowed range", Config{BlockPrivate: true, AllowCIDRs: []string{"172.18.0.0/16"}}, "172.18.0.7", true},
+		{"outside the allowed range", Config{BlockPrivate: true, AllowCIDRs: []string{"172.18.0.0/16"}}, "172.19.0.7", false},
+		{"allowed single address", Config{BlockPrivate: true, AllowCIDRs: []string{"127.0.0.1"}}, "127.0.0.1", true},
+		{"allowed address, mapped", Config{BlockPrivate: true, AllowCIDRs: []string{"127.0.0.1"}}, "::ffff:127.0.0.1", true},
+		{"denied public range", Config{BlockPrivate: true, DenyCIDRs: []string{"93.184.216.0/24"}}, "93.184.216.34", false},
+		{"denied without blocking private", Config{DenyCIDRs: []string{"10.0.0.0/8"}}, "10.1.2.3", false},
+		{"deny wins over allow", Config{AllowCIDRs: []string{"10.0.0.0/8"}, DenyCIDRs: []string{"10.1.0.0/16"}}, "10.1.2.3", false},
+		{"denied address, mapped", Config{DenyCIDRs: []string{"93.184.216.34/32"}}, "::ffff:93.184.216.34", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestPolicy(t, tt.config).CheckAddr(netip.MustParseAddr(tt.addr))
+
+			if tt.allowed && err != nil {
+				t.Fatalf("CheckAddr(%s) = %v, want allowed", tt.addr, err)
+			}
+
+			if !tt.allowed && !errors.Is(err, output.ErrOutboundDenied) {
+				t.Fatalf("CheckAddr(%s) = %v, want %v", tt.addr, err, output.ErrOutboundDenied)
+			}
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	policy := newTestPolicy(t, Config{BlockPrivate: true})
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"http://[2606:2800:220:1:248:1893:25c8:1946]:8080/hook", true},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://[::ffff:a9fe:a9fe]/latest/meta-data", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://localhost:8080/hook", false},
+		{"ftp://93.184.216.34/file", false},
+		{"file:///etc/passwd", false},
+		{"gopher://93.184.216.34/", false},
+		{"://missing-scheme", false},
+		// Hosts that do not resolve are checked again when dialed.
+		{"https://zpwoot-test.invalid/hook", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := policy.CheckURL(context.Background(), tt.url)
+
+			if tt.allowed && err != nil {
+				t.Fatalf("CheckURL = %v, want allowed", err)
+			}
+
+			if !tt.allowed && !errors.Is(err, output.ErrOutboundDenied) {
+				t.Fatalf("CheckURL = %v, want %v", err, output.ErrOutboundDenied)
+			}
+		})
+	}
+}
+
+func TestNewPolicyRejectsInvalidCIDRs(t *testing.T) {
+	for _, config := range []Config{
+		{AllowCIDRs: []string{"10.0.0.0/33"}},
+		{DenyCIDRs: []string{"not-an-address"}},
+	} {
+		if _, err := NewPolicy(config); err == nil {
+			t.Errorf("NewPolicy(%+v) accepted an invalid CIDR", config)
+		}
+	}
+}
+
+func TestHTTPClientRefusesHostsResolvingToLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	// CheckURL passes for a name it cannot resolve; the dial is what stops
+	// a name that resolves to a private address by the time it is used.
+	policy := newTestPolicy(t, Config{BlockPrivate: true})
+	client := policy.HTTPClient(5 * time.Second)
+
+	localURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	resp, err := client.Get(localURL)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	if !errors.Is(err, output.ErrOutboundDenied) {
+		t.Fatalf("GET %s = %v, want %v", localURL, err, output.ErrOutboundDenied)
+	}
+}
+
+func TestHTTPClientAllowsAllowedRanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	policy := newTestPolicy(t, Config{BlockPrivate: true, AllowCIDRs: []string{"127.0.0.1/32"}})
+
+	resp, err := policy.HTTPClient(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestHTTPClientRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// /hop/n redirects n more times before answering.
+	mux.HandleFunc("/hop/", func(w http.ResponseWriter, r *http.Request) {
+		hops, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if err != nil || hops == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		http.Redirect(w, r, server.URL+"/hop/"+strconv.Itoa(hops-1), http.StatusFound)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+	})
+
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+
+	tests := []struct {
+		name    string
+		target  string
+		allowed bool
+	}{
+		{"within the limit", server.URL + "/hop/3", true},
+		{"over the limit", server.URL + "/hop/4", false},
+		// 127.0.0.2 is loopback as well, but not allowed.
+		{"to a blocked address", server.URL + "/redirect?to=http://127.0.0.2" + port + "/hop/0", false},
+		{"to another scheme", server.URL + "/redirect?to=ftp://127.0.0.1/file", false},
+	}
+
+	policy := newTestPolicy(t, Config{BlockPrivate: true, AllowCIDRs: []string{"127.0.0.1/32"}, MaxRedirects: 3})
+	client := policy.HTTPClient(5 * time.Second)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Get(tt.target)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if tt.allowed && err != nil {
+				t.Fatalf("GET = %v, want allowed", err)
+			}
+
+			if !tt.allowed && !errors.Is(err, output.ErrOutboundDenied) {
+				t.Fatalf("GET = %v, want %v", err, output.ErrOutboundDenied)
+			}
+		})
+	}
+}
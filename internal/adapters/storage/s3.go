@@ -0,0 +1,438 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+// maxPresignExpiry is the longest validity SigV4 allows for a presigned URL.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// lifecycleRuleID names the lifecycle rule that expires media, so other
+// rules of the bucket are left alone.
+const lifecycleRuleID = "zpwoot-media-expiry"
+
+type S3Config struct {
+	// Endpoint is the base URL of the store, e.g. https://s3.amazonaws.com
+	// or http://localhost:9000 for MinIO.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Prefix is prepended to every key, so one bucket can be shared.
+	Prefix string
+	// PathStyle addresses the bucket as endpoint/bucket instead of
+	// bucket.endpoint. MinIO needs it.
+	PathStyle bool
+}
+
+// S3BlobStore keeps blobs in an S3-compatible bucket. Uploads carry a
+// SHA-256 checksum the store verifies before accepting the object.
+type S3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	signer   *sigV4Signer
+	client   *http.Client
+}
+
+func NewS3BlobStore(config S3Config, client *http.Client) (*S3BlobStore, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+
+	config.Prefix = strings.Trim(config.Prefix, "/")
+
+	return &S3BlobStore{
+		config:   config,
+		endpoint: endpoint,
+		signer: &sigV4Signer{
+			accessKey: config.AccessKey,
+			secretKey: config.SecretKey,
+			region:    config.Region,
+		},
+		client: client,
+	}, nil
+}
+
+// Put uploads a blob in a single request. Readers that cannot seek are
+// spooled to a temporary file first, as the checksum must be known before
+// the upload starts.
+func (s *S3BlobStore) Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	body, ok := data.(io.ReadSeeker)
+	if !ok {
+		spooled, err := spool(data)
+		if err != nil {
+			return err
+		}
+		defer spooled.Close()
+
+		body = spooled
+	}
+
+	hash := sha256.New()
+
+	length, err := io.Copy(hash, body)
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind blob: %w", err)
+	}
+
+	sum := hash.Sum(nil)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), io.NopCloser(body))
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	req.ContentLength = length
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(sum))
+
+	resp, err := s.do(req, hex.EncodeToString(sum))
+	if err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload blob: %w", s3Error(resp))
+	}
+
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, output.ErrBlobNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to get blob: %w", s3Error(resp))
+	}
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete blob: %w", s3Error(resp))
+	}
+
+	return nil
+}
+
+// PresignGet returns a URL anyone can download the blob from until it
+// expires. Signing happens locally; the blob is not checked.
+func (s *S3BlobStore) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	expires = min(max(expires, time.Second), maxPresignExpiry)
+
+	return s.signer.presign(u, expires, time.Now()), nil
+}
+
+// ExpireAfter sets a lifecycle rule deleting the objects below the prefix
+// days after they were written, or removes the rule when days is 0. Rules
+// the bucket has for anything else are kept.
+func (s *S3BlobStore) ExpireAfter(ctx context.Context, days int) error {
+	rules, err := s.lifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]lifecycleRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.ID != lifecycleRuleID {
+			kept = append(kept, rule)
+		}
+	}
+
+	if days <= 0 && len(kept) == len(rules) {
+		return nil
+	}
+
+	if days <= 0 && len(kept) == 0 {
+		return s.deleteLifecycle(ctx)
+	}
+
+	var body bytes.Buffer
+
+	body.WriteString(`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+
+	for _, rule := range kept {
+		body.WriteString("<Rule>" + rule.Inner + "</Rule>")
+	}
+
+	if days > 0 {
+		prefix := ""
+		if s.config.Prefix != "" {
+			prefix = s.config.Prefix + "/"
+		}
+
+		fmt.Fprintf(&body, "<Rule><ID>%s</ID><Filter><Prefix>%s</Prefix></Filter><Status>Enabled</Status><Expiration><Days>%d</Days></Expiration></Rule>",
+			lifecycleRuleID, xmlEscape(prefix), days)
+	}
+
+	body.WriteString(`</LifecycleConfiguration>`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.bucketURL("lifecycle").String(), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	// S3 insists on Content-MD5 for lifecycle configurations.
+	md5Sum := md5.Sum(body.Bytes())
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+	req.Header.Set("Content-Type", "application/xml")
+
+	resp, err := s.do(req, hashHex(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("failed to set bucket lifecycle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to set bucket lifecycle: %w", s3Error(resp))
+	}
+
+	return nil
+}
+
+func (s *S3BlobStore) deleteLifecycle(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.bucketURL("lifecycle").String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete bucket lifecycle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete bucket lifecycle: %w", s3Error(resp))
+	}
+
+	return nil
+}
+
+type lifecycleRule struct {
+	ID    string `xml:"ID"`
+	Inner string `xml:",innerxml"`
+}
+
+func (s *S3BlobStore) lifecycleRules(ctx context.Context) ([]lifecycleRule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.bucketURL("lifecycle").String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket lifecycle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Buckets without lifecycle rules answer 404 NoSuchLifecycleConfiguration.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get bucket lifecycle: %w", s3Error(resp))
+	}
+
+	var config struct {
+		Rules []lifecycleRule `xml:"Rule"`
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode bucket lifecycle: %w", err)
+	}
+
+	return config.Rules, nil
+}
+
+func (s *S3BlobStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.signer.sign(req, payloadHash, time.Now())
+	return s.client.Do(req)
+}
+
+// objectURL is the address of the object holding key, below the prefix.
+func (s *S3BlobStore) objectURL(key string) (*url.URL, error) {
+	segments := strings.Split(key, "/")
+
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+
+	if s.config.Prefix != "" {
+		segments = append(strings.Split(s.config.Prefix, "/"), segments...)
+	}
+
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = uriEncode(segment)
+	}
+
+	u := s.bucketURL("")
+	u.RawPath = u.EscapedPath() + strings.Join(escaped, "/")
+	u.Path += strings.Join(segments, "/")
+
+	return u, nil
+}
+
+// bucketURL is the address of the bucket, with an optional subresource
+// such as "lifecycle" as its query.
+func (s *S3BlobStore) bucketURL(subresource string) *url.URL {
+	u := *s.endpoint
+
+	if s.config.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket + "/"
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = "/"
+	}
+
+	u.RawPath = ""
+	u.RawQuery = ""
+
+	if subresource != "" {
+		u.RawQuery = subresource + "="
+	}
+
+	return &u
+}
+
+// spool copies a reader to a temporary file that is removed once closed.
+func spool(data io.Reader) (*spooledFile, error) {
+	file, err := os.CreateTemp("", "zpwoot-blob-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	spooled := &spooledFile{File: file}
+
+	if _, err := io.Copy(file, data); err != nil {
+		spooled.Close()
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		spooled.Close()
+		return nil, fmt.Errorf("failed to rewind blob: %w", err)
+	}
+
+	return spooled, nil
+}
+
+type spooledFile struct {
+	*os.File
+}
+
+func (f *spooledFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+
+	return err
+}
+
+// s3Error turns an S3 error response into an error carrying its code and
+// message, e.g. BadDigest when the checksum did not match.
+func s3Error(resp *http.Response) error {
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if err := xml.Unmarshal(data, &body); err != nil || body.Code == "" {
+		return fmt.Errorf("S3 returned status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("S3 returned status %d: %s: %s", resp.StatusCode, body.Code, body.Message)
+}
+
+func xmlEscape(value string) string {
+	var builder strings.Builder
+
+	_ = xml.EscapeText(&builder, []byte(value))
+
+	return builder.String()
+}
@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	testAccessKey = "test-access-key"
+	testSecretKey = "test-secret-key"
+	testRegion    = "sa-east-1"
+	testBucket    = "media"
+)
+
+type s3Object struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 is an S3 endpoint holding objects by bucket and key. It checks
+// the signature of every request against what it received, so the host and
+// path on the wire must be the ones that were signed.
+type fakeS3 struct {
+	t       *testing.T
+	signer  *sigV4Signer
+	mutex   sync.Mutex
+	objects map[string]s3Object
+	// hosts records the Host of every request.
+	hosts []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.hosts = append(f.hosts, r.Host)
+
+	rawPath, _, _ := strings.Cut(r.RequestURI, "?")
+
+	if err := f.verify(r, rawPath); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.RequestURI, err)
+		writeS3Error(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+
+		return
+	}
+
+	key := r.Host + " " + r.URL.Path
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(data)
+
+		if r.Header.Get("X-Amz-Checksum-Sha256") != base64.StdEncoding.EncodeToString(sum[:]) {
+			writeS3Error(w, http.StatusBadRequest, "BadDigest", "The SHA256 you specified did not match the calculated checksum.")
+			return
+		}
+
+		f.objects[key] = s3Object{data: data, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+
+		w.Header().Set("Content-Type", object.contentType)
+		_, _ = w.Write(object.data)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "")
+	}
+}
+
+// verify recomputes the signature of a signed or presigned request.
+func (f *fakeS3) verify(r *http.Request, rawPath string) error {
+	query := r.URL.Query()
+
+	if signature := query.Get("X-Amz-Signature"); signature != "" {
+		now, err := time.Parse(sigV4TimeFormat, query.Get("X-Amz-Date"))
+		if err != nil {
+			return err
+		}
+
+		expires, _ := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if time.Since(now) > time.Duration(expires)*time.Second {
+			return errors.New("presigned URL expired")
+		}
+
+		if query.Get("X-Amz-Credential") != testAccessKey+"/"+f.signer.scope(now) {
+			return errors.New("wrong credential " + query.Get("X-Amz-Credential"))
+		}
+
+		query.Del("X-Amz-Signature")
+
+		canonicalRequest := strings.Join([]string{
+			r.Method, rawPath, canonicalQuery(query), "host:" + r.Host + "\n", "host", unsignedPayload,
+		}, "\n")
+
+		if want := f.signer.signature(now, f.signer.scope(now), canonicalRequest); signature != want {
+			return errors.New("presigned signature does not match")
+		}
+
+		return nil
+	}
+
+	now, err := time.Parse(sigV4TimeFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err
+	}
+
+	_, params, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok {
+		return errors.New("missing Authorization")
+	}
+
+	fields := make(map[string]string)
+
+	for _, param := range strings.Split(params, ", ") {
+		name, value, _ := strings.Cut(param, "=")
+		fields[name] = value
+	}
+
+	if fields["Credential"] != testAccessKey+"/"+f.signer.scope(now) {
+		return errors.New("wrong credential " + fields["Credential"])
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(signed) {
+		return errors.New("signed headers are not sorted")
+	}
+
+	var headers strings.Builder
+
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+
+		headers.WriteString(name + ":" + value + "\n")
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+
+	canonicalRequest := strings.Join([]string{
+		r.Method, rawPath, canonicalQuery(query), headers.String(), fields["SignedHeaders"], payloadHash,
+	}, "\n")
+
+	if want := f.signer.signature(now, f.signer.scope(now), canonicalRequest); fields["Signature"] != want {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+xmlEscape(message)+"</Message></Error>")
+}
+
+// newFakeS3 starts a fake S3 answering for s3.test and every host below
+// it, and returns a store on it.
+func newFakeS3(t *testing.T, pathStyle bool, prefix string) (*S3BlobStore, *fakeS3) {
+	t.Helper()
+
+	fake := &fakeS3{
+		t:       t,
+		signer:  &sigV4Signer{accessKey: testAccessKey, secretKey: testSecretKey, region: testRegion},
+		objects: make(map[string]s3Object),
+	}
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	// Virtual-host addressing puts the bucket in the host name, so every
+	// name is dialed to the test server.
+	var dialer net.Dialer
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+
+	store, err := NewS3BlobStore(S3Config{
+		Endpoint:  "http://s3.test",
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		Prefix:    prefix,
+		PathStyle: pathStyle,
+	}, client)
+	if err != nil {
+		t.Fatalf("NewS3BlobStore: %v", err)
+	}
+
+	return store, fake
+}
+
+func TestS3BlobStore(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathStyle bool
+		prefix    string
+		wantHost  string
+		wantPath  string
+	}{
+		{"path style", true, "", "s3.test", "/media/session-1/photo 1+2.jpg"},
+		{"path style with prefix", true, "/zpwoot/", "s3.test", "/media/zpwoot/session-1/photo 1+2.jpg"},
+		{"virtual host", false, "", "media.s3.test", "/session-1/photo 1+2.jpg"},
+		{"virtual host with prefix", false, "zpwoot", "media.s3.test", "/zpwoot/session-1/photo 1+2.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newFakeS3(t, tt.pathStyle, tt.prefix)
+			ctx := context.Background()
+			key := "session-1/photo 1+2.jpg"
+
+			// A reader that cannot seek is spooled before the upload.
+			data := io.MultiReader(strings.NewReader("\xff\xd8\xff"), strings.NewReader("jpeg"))
+			if err := store.Put(ctx, key, data, 7, "image/jpeg"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			object, ok := fake.objects[tt.wantHost+" "+tt.wantPath]
+			if !ok {
+				t.Fatalf("object not stored at %s %s, have %v", tt.wantHost, tt.wantPath, fake.objects)
+			}
+
+			if string(object.data) != "\xff\xd8\xffjpeg" || object.contentType != "image/jpeg" {
+				t.Errorf("stored %q %q, want the JPEG", object.data, object.contentType)
+			}
+
+			body, err := store.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+
+			got, _ := io.ReadAll(body)
+			_ = body.Close()
+
+			if string(got) != "\xff\xd8\xffjpeg" {
+				t.Errorf("Get = %q, want the JPEG", got)
+			}
+
+			if _, err := store.Get(ctx, "session-1/missing.jpg"); !errors.Is(err, output.ErrBlobNotFound) {
+				t.Errorf("Get of a missing blob: error = %v, want %v", err, output.ErrBlobNotFound)
+			}
+
+			presigned, err := store.PresignGet(ctx, key, time.Hour)
+			if err != nil {
+				t.Fatalf("PresignGet: %v", err)
+			}
+
+			u, err := url.Parse(presigned)
+			if err != nil || u.Host != tt.wantHost || u.Path != tt.wantPath {
+				t.Fatalf("presigned URL = %s, want one for %s%s", presigned, tt.wantHost, tt.wantPath)
+			}
+
+			resp, err := store.client.Get(presigned)
+			if err != nil {
+				t.Fatalf("presigned GET: %v", err)
+			}
+
+			got, _ = io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || string(got) != "\xff\xd8\xffjpeg" {
+				t.Errorf("presigned GET = %d %q, want 200 and the JPEG", resp.StatusCode, got)
+			}
+
+			for _, host := range fake.hosts {
+				if host != tt.wantHost {
+					t.Errorf("request sent to host %s, want %s", host, tt.wantHost)
+				}
+			}
+		})
+	}
+}
+
+func TestS3PresignExpiry(t *testing.T) {
+	store, _ := newFakeS3(t, true, "")
+
+	tests := []struct {
+		expires time.Duration
+		want    string
+	}{
+		{0, "1"},
+		{15 * time.Minute, "900"},
+		{30 * 24 * time.Hour, "604800"},
+	}
+
+	for _, tt := range tests {
+		presigned, err := store.PresignGet(context.Background(), "blob", tt.expires)
+		if err != nil {
+			t.Fatalf("PresignGet: %v", err)
+		}
+
+		u, _ := url.Parse(presigned)
+		if got := u.Query().Get("X-Amz-Expires"); got != tt.want {
+			t.Errorf("PresignGet(%v) expires in %s, want %s", tt.expires, got, tt.want)
+		}
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	store, _ := newFakeS3(t, true, "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeS3Error(w, http.StatusBadRequest, "BadDigest", "The SHA256 you specified did not match the calculated checksum.")
+	}))
+	defer server.Close()
+
+	failing, err := NewS3BlobStore(S3Config{Endpoint: server.URL, Bucket: testBucket, PathStyle: true}, server.Client())
+	if err != nil {
+		t.Fatalf("NewS3BlobStore: %v", err)
+	}
+
+	err = failing.Put(context.Background(), "blob", strings.NewReader("data"), 4, "")
+	if err == nil || err.Error() != "failed to upload blob: S3 returned status 400: BadDigest: The SHA256 you specified did not match the calculated checksum." {
+		t.Errorf("Put error = %v, want the S3 error code and message", err)
+	}
+
+	for _, key := range []string{"", "a//b", "../secret", "session-1/./blob"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("data"), 4, ""); err == nil {
+			t.Errorf("Put accepted the key %q", key)
+		}
+	}
+
+	if _, err := NewS3BlobStore(S3Config{Endpoint: "http://s3.test"}, nil); err == nil {
+		t.Error("NewS3BlobStore accepted a config without a bucket")
+	}
+
+	if _, err := NewS3BlobStore(S3Config{Endpoint: "s3.test", Bucket: testBucket}, nil); err == nil {
+		t.Error("NewS3BlobStore accepted an endpoint without a scheme")
+	}
+}
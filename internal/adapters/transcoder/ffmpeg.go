@@ -0,0 +1,404 @@
+package transcoder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	stickerSize    = 512
+	thumbnailSize  = 100
+	opusMimeType   = "audio/ogg; codecs=opus"
+	stickerMime    = "image/webp"
+	waveformLength = 64
+
+	// waveformRate is the sample rate audio is decoded at to draw its
+	// waveform; 64 bars need very little of it.
+	waveformRate = 4000
+
+	// sniffLength is how much of the input is looked at to tell a
+	// playlist from media.
+	sniffLength = 512
+)
+
+// ErrPlaylist is returned for input that ffmpeg would read as a playlist
+// (HLS, DASH or a concat script) and follow to other files.
+var ErrPlaylist = errors.New("media is a playlist")
+
+// Config locates the ffmpeg and ffprobe programs, by path or by name in
+// PATH.
+type Config struct {
+	FFmpegPath  string
+	FFprobePath string
+}
+
+// CommandRunner runs a program and writes its standard output to stdout.
+// FFmpeg runs every conversion through one, so it can be exercised with
+// canned output and no ffmpeg installed.
+type CommandRunner func(ctx context.Context, name string, args []string, stdout io.Writer) error
+
+// FFmpeg is an output.MediaTranscoder built on the ffmpeg and ffprobe
+// command line tools.
+type FFmpeg struct {
+	config Config
+	run    CommandRunner
+}
+
+func NewFFmpeg(config Config) *FFmpeg {
+	return NewFFmpegWithRunner(config, runCommand)
+}
+
+func NewFFmpegWithRunner(config Config, run CommandRunner) *FFmpeg {
+	return &FFmpeg{
+		config: config,
+		run:    run,
+	}
+}
+
+// Check reports whether ffmpeg and ffprobe can be found.
+func (f *FFmpeg) Check() error {
+	for _, name := range []string{f.config.FFmpegPath, f.config.FFprobePath} {
+		if _, err := exec.LookPath(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *FFmpeg) VoiceNote(ctx context.Context, media *output.MediaData) (*output.MediaData, error) {
+	in, err := spoolInput(media)
+	if err != nil {
+		return nil, err
+	}
+	defer removeFile(in)
+
+	out, err := f.convert(ctx, in, ".ogg",
+		"-vn", "-map_metadata", "-1",
+		"-ac", "1", "-ar", "48000",
+		"-c:a", "libopus", "-b:a", "32k", "-application", "voip",
+		"-f", "ogg")
+	if err != nil {
+		return nil, err
+	}
+
+	details := &output.MediaDetails{}
+
+	if probe, err := f.probe(ctx, out.Name()); err == nil {
+		details.Duration = probe.duration()
+	}
+
+	var pcm bytes.Buffer
+
+	args := append([]string{"-hide_banner", "-loglevel", "error"}, inputArgs(out.Name())...)
+
+	if err := f.run(ctx, f.config.FFmpegPath, append(args,
+		"-ac", "1", "-ar", strconv.Itoa(waveformRate), "-f", "s16le", "pipe:1",
+	), &pcm); err == nil {
+		details.Waveform = waveform(pcm.Bytes())
+	}
+
+	return convertedMedia(media, out, opusMimeType, ".ogg", details), nil
+}
+
+func (f *FFmpeg) Sticker(ctx context.Context, media *output.MediaData) (*output.MediaData, error) {
+	in, err := spoolInput(media)
+	if err != nil {
+		return nil, err
+	}
+	defer removeFile(in)
+
+	size := strconv.Itoa(stickerSize)
+
+	// The image is fit into the square and the rest left transparent.
+	out, err := f.convert(ctx, in, ".webp",
+		"-vf", "scale="+size+":"+size+":force_original_aspect_ratio=decrease,format=rgba,"+
+			"pad="+size+":"+size+":(ow-iw)/2:(oh-ih)/2:color=0x00000000",
+		"-frames:v", "1",
+		"-c:v", "libwebp", "-quality", "80",
+		"-f", "webp")
+	if err != nil {
+		return nil, err
+	}
+
+	return convertedMedia(media, out, stickerMime, ".webp", &output.MediaDetails{
+		Width:  stickerSize,
+		Height: stickerSize,
+	}), nil
+}
+
+func (f *FFmpeg) Preview(ctx context.Context, media *output.MediaData) (*output.MediaDetails, error) {
+	in, err := spoolInput(media)
+	if err != nil {
+		return nil, err
+	}
+	defer removeFile(in)
+
+	probe, err := f.probe(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &output.MediaDetails{}
+
+	if len(probe.Streams) > 0 {
+		details.Width = probe.Streams[0].Width
+		details.Height = probe.Streams[0].Height
+	}
+
+	args := []string{"-hide_banner", "-loglevel", "error"}
+
+	if strings.HasPrefix(media.MimeType, "video/") {
+		details.Duration = probe.duration()
+
+		// The first frame of a video is often black.
+		if details.Duration > 2*time.Second {
+			args = append(args, "-ss", "1")
+		}
+	}
+
+	size := strconv.Itoa(thumbnailSize)
+
+	var thumbnail bytes.Buffer
+
+	args = append(args, inputArgs(in)...)
+
+	if err := f.run(ctx, f.config.FFmpegPath, append(args,
+		"-frames:v", "1",
+		"-vf", "scale="+size+":"+size+":force_original_aspect_ratio=decrease",
+		"-c:v", "mjpeg", "-q:v", "5",
+		"-f", "image2", "pipe:1",
+	), &thumbnail); err != nil {
+		return nil, err
+	}
+
+	details.Thumbnail = thumbnail.Bytes()
+
+	return details, nil
+}
+
+// convert runs ffmpeg on the file at in with the output options args and
+// returns the converted file, removed when closed.
+func (f *FFmpeg) convert(ctx context.Context, in, ext string, args ...string) (*tempFile, error) {
+	out, err := os.CreateTemp("", "zpwoot-transcode-*"+ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	file := &tempFile{File: out}
+
+	args = append(append([]string{"-hide_banner", "-loglevel", "error", "-y"}, inputArgs(in)...), args...)
+
+	if err := f.run(ctx, f.config.FFmpegPath, append(args, out.Name()), io.Discard); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// ffmpeg wrote the file through its own descriptor.
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+type probeResult struct {
+	Streams []struct {
+		Width  uint32 `json:"width"`
+		Height uint32 `json:"height"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+func (p *probeResult) duration() time.Duration {
+	seconds, err := strconv.ParseFloat(p.Format.Duration, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (f *FFmpeg) probe(ctx context.Context, path string) (*probeResult, error) {
+	var out bytes.Buffer
+
+	if err := f.run(ctx, f.config.FFprobePath, []string{
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		"-protocol_whitelist", "file",
+		path,
+	}, &out); err != nil {
+		return nil, err
+	}
+
+	var result probeResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	return &result, nil
+}
+
+// inputArgs are the options reading the file at path. Only local files may
+// be opened, so input cannot make ffmpeg fetch URLs.
+func inputArgs(path string) []string {
+	return []string{"-protocol_whitelist", "file", "-i", path}
+}
+
+// isPlaylist reports whether head, the start of the input, is a playlist
+// or concat script ffmpeg would follow to the files it names.
+func isPlaylist(head []byte) bool {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimLeft(head, " \t\r\n")
+
+	for _, prefix := range []string{"#EXTM3U", "ffconcat"} {
+		if len(head) >= len(prefix) && strings.EqualFold(string(head[:len(prefix)]), prefix) {
+			return true
+		}
+	}
+
+	return bytes.Contains(head, []byte("<MPD"))
+}
+
+// waveform reduces 16-bit little-endian mono PCM to the peaks of
+// waveformLength equal slices, scaled so the loudest is 100.
+func waveform(pcm []byte) []byte {
+	samples := len(pcm) / 2
+	peaks := make([]int, waveformLength)
+	loudest := 0
+
+	for i := range peaks {
+		for j := i * samples / waveformLength; j < (i+1)*samples/waveformLength; j++ {
+			sample := int(int16(binary.LittleEndian.Uint16(pcm[2*j:])))
+			if sample < 0 {
+				sample = -sample
+			}
+
+			peaks[i] = max(peaks[i], sample)
+		}
+
+		loudest = max(loudest, peaks[i])
+	}
+
+	result := make([]byte, waveformLength)
+
+	if loudest == 0 {
+		return result
+	}
+
+	for i, peak := range peaks {
+		result[i] = byte(peak * 100 / loudest)
+	}
+
+	return result
+}
+
+// convertedMedia is media with the content of out, keeping its caption and
+// the name of the original file.
+func convertedMedia(media *output.MediaData, out *tempFile, mimeType, ext string, details *output.MediaDetails) *output.MediaData {
+	fileName := media.FileName
+	if fileName != "" {
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ext
+	}
+
+	return &output.MediaData{
+		MimeType: mimeType,
+		File:     out,
+		FileName: fileName,
+		Caption:  media.Caption,
+		ViewOnce: media.ViewOnce,
+		Sticker:  media.Sticker,
+		Details:  details,
+	}
+}
+
+// spoolInput writes media to a temporary file for ffmpeg, which cannot seek
+// in piped input, and returns its path. Playlists are refused with
+// ErrPlaylist. A File is rewound for its next reader.
+func spoolInput(media *output.MediaData) (string, error) {
+	var src io.Reader = bytes.NewReader(media.Data)
+
+	if media.File != nil {
+		if _, err := media.File.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+
+		defer func() { _, _ = media.File.Seek(0, io.SeekStart) }()
+
+		src = media.File
+	}
+
+	buffered := bufio.NewReaderSize(src, sniffLength)
+
+	// Peek fails on input shorter than sniffLength, which it still returns.
+	head, _ := buffered.Peek(sniffLength)
+	if isPlaylist(head) {
+		return "", ErrPlaylist
+	}
+
+	in, err := os.CreateTemp("", "zpwoot-transcode-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	_, err = io.Copy(in, buffered)
+	if closeErr := in.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		removeFile(in.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	return in.Name(), nil
+}
+
+func removeFile(path string) {
+	_ = os.Remove(path)
+}
+
+func runCommand(ctx context.Context, name string, args []string, stdout io.Writer) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// tempFile is a temporary file removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	removeFile(f.Name())
+
+	return err
+}
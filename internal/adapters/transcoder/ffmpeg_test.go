@@ -0,0 +1,346 @@
+package transcoder
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	testFFmpeg  = "test-ffmpeg"
+	testFFprobe = "test-ffprobe"
+)
+
+type command struct {
+	name string
+	args []string
+}
+
+// fakeRunner records the commands it is given and answers them with canned
+// output: probeOutput for ffprobe and ffmpegOutput for ffmpeg.
+type fakeRunner struct {
+	commands     []command
+	probeOutput  string
+	ffmpegOutput []byte
+	err          error
+}
+
+func (r *fakeRunner) run(_ context.Context, name string, args []string, stdout io.Writer) error {
+	r.commands = append(r.commands, command{name: name, args: args})
+
+	if r.err != nil {
+		return r.err
+	}
+
+	if name == testFFprobe {
+		_, err := io.WriteString(stdout, r.probeOutput)
+		return err
+	}
+
+	_, err := stdout.Write(r.ffmpegOutput)
+
+	return err
+}
+
+func newTestFFmpeg(runner *fakeRunner) *FFmpeg {
+	return NewFFmpegWithRunner(Config{FFmpegPath: testFFmpeg, FFprobePath: testFFprobe}, runner.run)
+}
+
+// inputOf returns the file a command reads, checking it may only open
+// local files.
+func inputOf(t *testing.T, cmd command) string {
+	t.Helper()
+
+	for i, arg := range cmd.args {
+		if arg == "-i" {
+			if i < 2 || cmd.args[i-2] != "-protocol_whitelist" || cmd.args[i-1] != "file" {
+				t.Fatalf("%s reads %s without a protocol whitelist: %v", cmd.name, cmd.args[i+1], cmd.args)
+			}
+
+			return cmd.args[i+1]
+		}
+	}
+
+	t.Fatalf("%s has no input: %v", cmd.name, cmd.args)
+
+	return ""
+}
+
+// pcmRamp is 16-bit mono PCM rising from 0 to peak.
+func pcmRamp(samples int, peak int16) []byte {
+	pcm := make([]byte, 2*samples)
+
+	for i := range samples {
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(int(peak)*i/(samples-1)))
+	}
+
+	return pcm
+}
+
+func TestVoiceNote(t *testing.T) {
+	runner := &fakeRunner{
+		probeOutput:  `{"streams":[],"format":{"duration":"3.500000"}}`,
+		ffmpegOutput: pcmRamp(6400, 1000),
+	}
+
+	result, err := newTestFFmpeg(runner).VoiceNote(context.Background(), &output.MediaData{
+		MimeType: "audio/mpeg",
+		Data:     []byte("ID3 audio"),
+		FileName: "note.mp3",
+		Caption:  "caption",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer func() { _ = result.File.Close() }()
+
+	if len(runner.commands) != 3 {
+		t.Fatalf("ran %d commands, want 3", len(runner.commands))
+	}
+
+	convert := runner.commands[0]
+	in := inputOf(t, convert)
+	out := convert.args[len(convert.args)-1]
+
+	wantConvert := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-protocol_whitelist", "file", "-i", in,
+		"-vn", "-map_metadata", "-1",
+		"-ac", "1", "-ar", "48000",
+		"-c:a", "libopus", "-b:a", "32k", "-application", "voip",
+		"-f", "ogg",
+		out,
+	}
+	if convert.name != testFFmpeg || !reflect.DeepEqual(convert.args, wantConvert) {
+		t.Errorf("convert = %s %v, want %s %v", convert.name, convert.args, testFFmpeg, wantConvert)
+	}
+
+	probe := runner.commands[1]
+	wantProbe := []string{
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		"-protocol_whitelist", "file",
+		out,
+	}
+	if probe.name != testFFprobe || !reflect.DeepEqual(probe.args, wantProbe) {
+		t.Errorf("probe = %s %v, want %s %v", probe.name, probe.args, testFFprobe, wantProbe)
+	}
+
+	if got := inputOf(t, runner.commands[2]); got != out {
+		t.Errorf("waveform read %s, want the converted file %s", got, out)
+	}
+
+	if result.MimeType != opusMimeType || result.FileName != "note.ogg" || result.Caption != "caption" {
+		t.Errorf("result = %q %q %q, want %q note.ogg caption", result.MimeType, result.FileName, result.Caption, opusMimeType)
+	}
+
+	if result.Details.Duration != 3500*time.Millisecond {
+		t.Errorf("duration = %v, want 3.5s", result.Details.Duration)
+	}
+
+	waveform := result.Details.Waveform
+	if len(waveform) != waveformLength || waveform[0] != 1 || waveform[waveformLength-1] != 100 {
+		t.Errorf("waveform = %v, want %d bars rising to 100", waveform, waveformLength)
+	}
+}
+
+func TestSticker(t *testing.T) {
+	runner := &fakeRunner{}
+
+	result, err := newTestFFmpeg(runner).Sticker(context.Background(), &output.MediaData{
+		MimeType: "image/png",
+		Data:     []byte("\x89PNG"),
+		Sticker:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer func() { _ = result.File.Close() }()
+
+	if len(runner.commands) != 1 {
+		t.Fatalf("ran %d commands, want 1", len(runner.commands))
+	}
+
+	convert := runner.commands[0]
+	in := inputOf(t, convert)
+
+	wantConvert := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-protocol_whitelist", "file", "-i", in,
+		"-vf", "scale=512:512:force_original_aspect_ratio=decrease,format=rgba," +
+			"pad=512:512:(ow-iw)/2:(oh-ih)/2:color=0x00000000",
+		"-frames:v", "1",
+		"-c:v", "libwebp", "-quality", "80",
+		"-f", "webp",
+		convert.args[len(convert.args)-1],
+	}
+	if !reflect.DeepEqual(convert.args, wantConvert) {
+		t.Errorf("convert = %v, want %v", convert.args, wantConvert)
+	}
+
+	if result.MimeType != stickerMime || result.Details.Width != stickerSize || result.Details.Height != stickerSize {
+		t.Errorf("result = %q %dx%d, want %q 512x512", result.MimeType, result.Details.Width, result.Details.Height, stickerMime)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		probe    string
+		wantSeek bool
+		wantDur  time.Duration
+	}{
+		{"image", "image/jpeg", `{"streams":[{"width":800,"height":600}],"format":{"duration":"0.040000"}}`, false, 0},
+		{"short video", "video/mp4", `{"streams":[{"width":800,"height":600}],"format":{"duration":"1.5"}}`, false, 1500 * time.Millisecond},
+		{"long video", "video/mp4", `{"streams":[{"width":800,"height":600}],"format":{"duration":"12"}}`, true, 12 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{probeOutput: tt.probe, ffmpegOutput: []byte("jpeg")}
+
+			details, err := newTestFFmpeg(runner).Preview(context.Background(), &output.MediaData{
+				MimeType: tt.mimeType,
+				Data:     []byte("media"),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if len(runner.commands) != 2 {
+				t.Fatalf("ran %d commands, want 2", len(runner.commands))
+			}
+
+			thumbnail := runner.commands[1]
+			in := inputOf(t, thumbnail)
+
+			wantArgs := []string{"-hide_banner", "-loglevel", "error"}
+			if tt.wantSeek {
+				wantArgs = append(wantArgs, "-ss", "1")
+			}
+
+			wantArgs = append(wantArgs,
+				"-protocol_whitelist", "file", "-i", in,
+				"-frames:v", "1",
+				"-vf", "scale=100:100:force_original_aspect_ratio=decrease",
+				"-c:v", "mjpeg", "-q:v", "5",
+				"-f", "image2", "pipe:1")
+			if !reflect.DeepEqual(thumbnail.args, wantArgs) {
+				t.Errorf("thumbnail = %v, want %v", thumbnail.args, wantArgs)
+			}
+
+			if details.Width != 800 || details.Height != 600 || details.Duration != tt.wantDur {
+				t.Errorf("details = %dx%d %v, want 800x600 %v", details.Width, details.Height, details.Duration, tt.wantDur)
+			}
+
+			if string(details.Thumbnail) != "jpeg" {
+				t.Errorf("thumbnail = %q, want jpeg", details.Thumbnail)
+			}
+		})
+	}
+}
+
+func TestPreviewBadProbeOutput(t *testing.T) {
+	runner := &fakeRunner{probeOutput: "not json"}
+
+	if _, err := newTestFFmpeg(runner).Preview(context.Background(), &output.MediaData{
+		MimeType: "image/jpeg",
+		Data:     []byte("media"),
+	}); err == nil {
+		t.Fatal("expected an error for unparseable ffprobe output")
+	}
+}
+
+func TestCommandFailure(t *testing.T) {
+	failure := errors.New("ffmpeg failed: exit status 1")
+	media := &output.MediaData{MimeType: "audio/mpeg", Data: []byte("audio")}
+	ffmpeg := newTestFFmpeg(&fakeRunner{err: failure})
+
+	if _, err := ffmpeg.VoiceNote(context.Background(), media); !errors.Is(err, failure) {
+		t.Errorf("VoiceNote error = %v, want %v", err, failure)
+	}
+
+	if _, err := ffmpeg.Sticker(context.Background(), media); !errors.Is(err, failure) {
+		t.Errorf("Sticker error = %v, want %v", err, failure)
+	}
+
+	if _, err := ffmpeg.Preview(context.Background(), media); !errors.Is(err, failure) {
+		t.Errorf("Preview error = %v, want %v", err, failure)
+	}
+}
+
+func TestMissingFFmpeg(t *testing.T) {
+	ffmpeg := NewFFmpeg(Config{FFmpegPath: "zpwoot-missing-ffmpeg", FFprobePath: "zpwoot-missing-ffprobe"})
+
+	if err := ffmpeg.Check(); err == nil {
+		t.Fatal("Check found a missing ffmpeg")
+	}
+
+	if _, err := ffmpeg.VoiceNote(context.Background(), &output.MediaData{
+		MimeType: "audio/mpeg",
+		Data:     []byte("audio"),
+	}); err == nil {
+		t.Fatal("VoiceNote succeeded without ffmpeg")
+	}
+}
+
+func TestPlaylistRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"HLS", "#EXTM3U\n#EXTINF:10,\nfile:///etc/passwd\n"},
+		{"HLS with a byte order mark", "\xef\xbb\xbf\n#extm3u\nhttp://169.254.169.254/\n"},
+		{"concat script", "ffconcat version 1.0\nfile /etc/passwd\n"},
+		{"DASH manifest", `<?xml version="1.0"?><MPD xmlns="urn:mpeg:dash:schema:mpd:2011">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{}
+			ffmpeg := newTestFFmpeg(runner)
+			media := &output.MediaData{MimeType: "audio/mpeg", Data: []byte(tt.data)}
+
+			if _, err := ffmpeg.VoiceNote(context.Background(), media); !errors.Is(err, ErrPlaylist) {
+				t.Errorf("VoiceNote error = %v, want %v", err, ErrPlaylist)
+			}
+
+			if _, err := ffmpeg.Preview(context.Background(), media); !errors.Is(err, ErrPlaylist) {
+				t.Errorf("Preview error = %v, want %v", err, ErrPlaylist)
+			}
+
+			if len(runner.commands) != 0 {
+				t.Errorf("ran %v on a playlist", runner.commands)
+			}
+		})
+	}
+}
+
+func TestWaveform(t *testing.T) {
+	if got := waveform(make([]byte, 1000)); !reflect.DeepEqual(got, make([]byte, waveformLength)) {
+		t.Errorf("silence = %v, want all zero", got)
+	}
+
+	if got := waveform(nil); len(got) != waveformLength {
+		t.Errorf("empty input gave %d bars, want %d", len(got), waveformLength)
+	}
+
+	// Negative samples count by their magnitude.
+	pcm := make([]byte, 2*waveformLength)
+	binary.LittleEndian.PutUint16(pcm[2*(waveformLength-1):], uint16(0x10000-2000))
+	binary.LittleEndian.PutUint16(pcm[0:], 1000)
+
+	got := waveform(pcm)
+	if got[0] != 50 || got[waveformLength-1] != 100 || got[1] != 0 {
+		t.Errorf("waveform = %v, want 50 first, 100 last and 0 between", got)
+	}
+}
@@ -0,0 +1,112 @@
+package waclient
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/chat"
+
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// countsAsUnread reports whether an incoming message shows up in the unread
+// badge of its chat. Reactions, protocol messages and status updates do not.
+func countsAsUnread(evt *events.Message) bool {
+	if evt.Info.IsFromMe || evt.Info.Chat.Server == types.BroadcastServer {
+		return false
+	}
+
+	switch getMessageType(evt.Message) {
+	case unknownMessageType, "reaction":
+		return false
+	default:
+		return true
+	}
+}
+
+// isOwnReadReceipt reports whether the receipt was sent by another device of
+// the account after reading the chat there.
+func isOwnReadReceipt(evt *events.Receipt) bool {
+	if !evt.IsFromMe {
+		return false
+	}
+
+	return evt.Type == types.ReceiptTypeRead || evt.Type == types.ReceiptTypeReadSelf
+}
+
+func updateChatState(repo chat.Repository, log *logger.Logger, sessionID string, chatJID types.JID, update func(ctx context.Context, chatJID string) error) {
+	if repo == nil || chatJID.IsEmpty() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := update(ctx, chatJID.ToNonAD().String()); err != nil {
+		log.Error().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("chat", chatJID.String()).
+			Msg("Failed to update chat state")
+	}
+}
+
+func (wac *WAClient) markChatRead(sessionID string, chatJID types.JID, readAt time.Time) {
+	updateChatState(wac.chatRepo, wac.logger, sessionID, chatJID, func(ctx context.Context, jid string) error {
+		return wac.chatRepo.MarkRead(ctx, sessionID, jid, readAt)
+	})
+}
+
+func (eh *DefaultEventHandler) incrementUnread(sessionID string, chatJID types.JID) {
+	updateChatState(eh.chatRepo, eh.logger, sessionID, chatJID, func(ctx context.Context, jid string) error {
+		return eh.chatRepo.IncrementUnread(ctx, sessionID, jid)
+	})
+}
+
+func (eh *DefaultEventHandler) markChatRead(sessionID string, chatJID types.JID, readAt time.Time) {
+	updateChatState(eh.chatRepo, eh.logger, sessionID, chatJID, func(ctx context.Context, jid string) error {
+		return eh.chatRepo.MarkRead(ctx, sessionID, jid, readAt)
+	})
+}
+
+func (eh *DefaultEventHandler) setUnread(sessionID string, chatJID types.JID, count int) {
+	updateChatState(eh.chatRepo, eh.logger, sessionID, chatJID, func(ctx context.Context, jid string) error {
+		return eh.chatRepo.SetUnread(ctx, sessionID, jid, count)
+	})
+}
+
+func (eh *DefaultEventHandler) handleMarkChatAsRead(client *Client, evt *events.MarkChatAsRead) error {
+	if evt.Action.GetRead() {
+		eh.markChatRead(client.SessionID, evt.JID, evt.Timestamp)
+		return nil
+	}
+
+	// A chat marked as unread on the phone shows a badge without a count.
+	eh.setUnread(client.SessionID, evt.JID, 1)
+
+	return nil
+}
+
+// syncUnreadCounts takes over the unread counters reported by the phone.
+// On-demand syncs only carry older messages, so their counters are stale.
+func (eh *DefaultEventHandler) syncUnreadCounts(client *Client, data *waHistorySync.HistorySync) {
+	if data.GetSyncType() == waHistorySync.HistorySync_ON_DEMAND {
+		return
+	}
+
+	for _, conv := range data.GetConversations() {
+		if conv.UnreadCount == nil {
+			continue
+		}
+
+		chatJID, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			continue
+		}
+
+		eh.setUnread(client.SessionID, chatJID, int(conv.GetUnreadCount()))
+	}
+}
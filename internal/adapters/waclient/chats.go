@@ -0,0 +1,160 @@
+package waclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// GetChats builds the chat inventory of a session from the last persisted
+// message of every chat, the tracked unread counters and the joined groups,
+// which are cached per session rather than fetched for every page. Names and
+// archive/pin/mute flags come from the whatsmeow store, which is kept current
+// by app state sync.
+func (ms *Sender) GetChats(ctx context.Context, sessionID string) ([]*ChatInfo, error) {
+	client, err := ms.getConnectedClient(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	chats := make([]*ChatInfo, 0)
+	byJID := make(map[string]*ChatInfo)
+
+	get := func(jid string) *ChatInfo {
+		chat, ok := byJID[jid]
+		if !ok {
+			chat = &ChatInfo{JID: jid}
+			byJID[jid] = chat
+			chats = append(chats, chat)
+		}
+
+		return chat
+	}
+
+	if repo := ms.waClient.messageRepo; repo != nil {
+		lastMessages, err := repo.ListLastPerChat(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load last messages: %w", err)
+		}
+
+		for _, msg := range lastMessages {
+			get(msg.Chat).LastMessage = msg
+		}
+	}
+
+	if repo := ms.waClient.chatRepo; repo != nil {
+		states, err := repo.List(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chat state: %w", err)
+		}
+
+		for _, state := range states {
+			get(state.JID).UnreadCount = state.UnreadCount
+		}
+	}
+
+	if client.WAClient.IsLoggedIn() {
+		groups, err := ms.waClient.groups.Joined(ctx, sessionID, client.WAClient.GetJoinedGroups)
+		if err != nil {
+			ms.waClient.logger.Warn().Err(err).Str("session_id", sessionID).Msg("Failed to list joined groups for chat list")
+		}
+
+		for _, group := range groups {
+			chat := get(group.JID.String())
+			chat.Name = group.Name
+			chat.Topic = group.Topic
+			chat.ParticipantCount = len(group.Participants)
+		}
+	}
+
+	result := make([]*ChatInfo, 0, len(chats))
+
+	for _, chat := range chats {
+		jid, err := types.ParseJID(chat.JID)
+		if err != nil || jid.Server == types.BroadcastServer {
+			continue
+		}
+
+		ms.applyStoreInfo(ctx, client, jid, chat)
+		result = append(result, chat)
+	}
+
+	return result, nil
+}
+
+func (ms *Sender) applyStoreInfo(ctx context.Context, client *Client, jid types.JID, chat *ChatInfo) {
+	chat.IsGroup = jid.Server == types.GroupServer
+
+	deviceStore := client.WAClient.Store
+	if deviceStore == nil || deviceStore.ID == nil {
+		return
+	}
+
+	if chat.Name == "" && !chat.IsGroup {
+		if contact, err := deviceStore.Contacts.GetContact(ctx, jid); err == nil && contact.Found {
+			chat.Name = contactDisplayName(contact)
+		}
+	}
+
+	settings, err := deviceStore.ChatSettings.GetChatSettings(ctx, jid)
+	if err != nil || !settings.Found {
+		return
+	}
+
+	chat.Archived = settings.Archived
+	chat.Pinned = settings.Pinned
+
+	if settings.MutedUntil.After(time.Now()) {
+		mutedUntil := settings.MutedUntil
+		chat.MutedUntil = &mutedUntil
+	}
+}
+
+func contactDisplayName(contact types.ContactInfo) string {
+	switch {
+	case contact.FullName != "":
+		return contact.FullName
+	case contact.FirstName != "":
+		return contact.FirstName
+	case contact.BusinessName != "":
+		return contact.BusinessName
+	default:
+		return contact.PushName
+	}
+}
+
+func toOutputChatInfo(chat *ChatInfo) *output.ChatInfo {
+	result := &output.ChatInfo{
+		JID:              chat.JID,
+		Name:             chat.Name,
+		Topic:            chat.Topic,
+		IsGroup:          chat.IsGroup,
+		ParticipantCount: chat.ParticipantCount,
+		UnreadCount:      chat.UnreadCount,
+		Archived:         chat.Archived,
+		Pinned:           chat.Pinned,
+		MutedUntil:       chat.MutedUntil,
+	}
+
+	if chat.LastMessage != nil {
+		result.LastMessage = toChatLastMessage(chat.LastMessage)
+	}
+
+	return result
+}
+
+func toChatLastMessage(msg *message.Message) *output.ChatLastMessage {
+	return &output.ChatLastMessage{
+		ID:        msg.MessageID,
+		Sender:    msg.Sender,
+		FromMe:    msg.FromMe,
+		Type:      msg.Type,
+		Content:   msg.Content,
+		Timestamp: msg.Timestamp,
+	}
+}
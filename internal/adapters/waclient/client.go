@@ -2,9 +2,7 @@ package waclient
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
-	"time"
 
 	"zpwoot/internal/core/ports/output"
 
@@ -115,6 +113,10 @@ func (w *WAClientAdapter) PairPhone(ctx context.Context, sessionID, phone string
 	return linkingCode, nil
 }
 
+func (w *WAClientAdapter) ReloadProxy(ctx context.Context, sessionID string) error {
+	return w.convertError(w.client.ReloadProxy(ctx, sessionID))
+}
+
 func (w *WAClientAdapter) SendTextMessage(ctx context.Context, sessionID, to, text string) (*output.MessageResult, error) {
 	sender := NewSender(w.client)
 	resp, err := sender.SendTextMessage(ctx, sessionID, to, text, nil)
@@ -122,11 +124,7 @@ func (w *WAClientAdapter) SendTextMessage(ctx context.Context, sessionID, to, te
 		return nil, w.convertError(err)
 	}
 
-	return &output.MessageResult{
-		MessageID: resp.ID,
-		Status:    "sent",
-		SentAt:    resp.Timestamp,
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *WAClientAdapter) SendMediaMessage(ctx context.Context, sessionID, to string, media *output.MediaData) (*output.MessageResult, error) {
@@ -136,25 +134,17 @@ func (w *WAClientAdapter) SendMediaMessage(ctx context.Context, sessionID, to st
 		return nil, w.convertError(err)
 	}
 
-	return &output.MessageResult{
-		MessageID: resp.ID,
-		Status:    "sent",
-		SentAt:    resp.Timestamp,
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *WAClientAdapter) SendLocationMessage(ctx context.Context, sessionID, to string, location *output.Location) (*output.MessageResult, error) {
 	sender := NewSender(w.client)
-	err := sender.SendLocationMessage(ctx, sessionID, to, location.Latitude, location.Longitude, location.Name)
+	resp, err := sender.SendLocationMessage(ctx, sessionID, to, location.Latitude, location.Longitude, location.Name)
 	if err != nil {
 		return nil, w.convertError(err)
 	}
 
-	return &output.MessageResult{
-		MessageID: generateMessageID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *WAClientAdapter) SendContactMessage(ctx context.Context, sessionID, to string, contact *output.ContactInfo) (*output.MessageResult, error) {
@@ -164,16 +154,27 @@ func (w *WAClientAdapter) SendContactMessage(ctx context.Context, sessionID, to
 		Phone: contact.PhoneNumber,
 	}
 
-	err := sender.SendContactMessage(ctx, sessionID, to, contactInfo)
+	resp, err := sender.SendContactMessage(ctx, sessionID, to, contactInfo)
 	if err != nil {
 		return nil, w.convertError(err)
 	}
 
-	return &output.MessageResult{
-		MessageID: generateMessageID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
+}
+
+func (w *WAClientAdapter) GetChats(ctx context.Context, sessionID string) ([]*output.ChatInfo, error) {
+	sender := NewSender(w.client)
+	chats, err := sender.GetChats(ctx, sessionID)
+	if err != nil {
+		return nil, w.convertError(err)
+	}
+
+	result := make([]*output.ChatInfo, 0, len(chats))
+	for _, chat := range chats {
+		result = append(result, toOutputChatInfo(chat))
+	}
+
+	return result, nil
 }
 
 func (w *WAClientAdapter) convertError(err error) error {
@@ -202,25 +203,3 @@ func (w *WAClientAdapter) convertError(err error) error {
 		}
 	}
 }
-
-func generateMessageID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
-}
-
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	randomBytes := make([]byte, length)
-
-	if _, err := rand.Read(randomBytes); err != nil {
-		for i := range b {
-			b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-		}
-		return string(b)
-	}
-
-	for i := range b {
-		b[i] = charset[randomBytes[i]%byte(len(charset))]
-	}
-	return string(b)
-}
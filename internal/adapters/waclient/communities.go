@@ -151,6 +151,8 @@ func (cs *CommunityService) CreateCommunity(ctx context.Context, sessionID strin
 		return nil, fmt.Errorf("failed to create community: %w", err)
 	}
 
+	cs.waClient.groups.Forget(sessionID)
+
 	return &dto.CommunityInfo{
 		JID:               group.JID.String(),
 		Name:              group.Name,
@@ -32,24 +32,20 @@ func (cs *ContactService) CheckUser(ctx context.Context, sessionID string, phone
 		return nil, ErrNotConnected
 	}
 
-	resp, err := client.WAClient.IsOnWhatsApp(phones)
+	resolutions, err := cs.waClient.numbers.Resolve(client, phones)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check users: %w", err)
 	}
 
-	results := make([]input.UserCheckResult, 0, len(resp))
-	for _, item := range resp {
-		result := input.UserCheckResult{
-			Query:        item.Query,
-			IsInWhatsApp: item.IsIn,
-			JID:          item.JID.String(),
-		}
-
-		if item.VerifiedName != nil {
-			result.VerifiedName = item.VerifiedName.Details.GetVerifiedName()
-		}
-
-		results = append(results, result)
+	results := make([]input.UserCheckResult, 0, len(resolutions))
+	for _, resolution := range resolutions {
+		results = append(results, input.UserCheckResult{
+			Query:        resolution.Query,
+			IsInWhatsApp: resolution.IsInWhatsApp,
+			JID:          resolution.JID.String(),
+			VerifiedName: resolution.VerifiedName,
+			Candidates:   resolution.Candidates,
+		})
 	}
 
 	return results, nil
@@ -64,7 +60,7 @@ func (cs *ContactService) GetUser(ctx context.Context, sessionID string, phone s
 		return nil, ErrNotConnected
 	}
 
-	jid, err := parseJID(phone)
+	jid, err := cs.waClient.resolveJID(client, phone)
 	if err != nil {
 		return nil, ErrInvalidJID
 	}
@@ -104,7 +100,7 @@ func (cs *ContactService) GetAvatar(ctx context.Context, sessionID string, phone
 		return nil, ErrNotConnected
 	}
 
-	jid, err := parseJID(phone)
+	jid, err := cs.waClient.resolveJID(client, phone)
 	if err != nil {
 		return nil, ErrInvalidJID
 	}
@@ -200,7 +196,7 @@ func (cs *ContactService) ChatPresence(ctx context.Context, sessionID string, ph
 		return ErrNotConnected
 	}
 
-	jid, err := parseJID(phone)
+	jid, err := cs.waClient.resolveJID(client, phone)
 	if err != nil {
 		return ErrInvalidJID
 	}
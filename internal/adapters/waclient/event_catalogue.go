@@ -0,0 +1,111 @@
+package waclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"zpwoot/internal/core/domain/webhook"
+)
+
+// webhookEventTypes lists every event type DefaultEventHandler delivers to
+// webhooks. It must match the catalogue advertised to subscribers.
+var webhookEventTypes = []EventType{
+	EventMessage,
+	EventMessageRevoked,
+	EventMessageReaction,
+	EventReceipt,
+	EventConnected,
+	EventDisconnected,
+	EventQRCode,
+	EventPairSuccess,
+	EventLoggedOut,
+	EventKeepAliveTimeout,
+	EventKeepAliveRestored,
+	EventGroupInfo,
+	EventJoinedGroup,
+	EventPicture,
+	EventIdentityChange,
+	EventPrivacySettings,
+	EventBlocklist,
+	EventPresence,
+	EventChatPresence,
+	EventHistorySync,
+	EventOfflineSyncPreview,
+	EventOfflineSyncCompleted,
+	EventAppState,
+	EventCallOffer,
+	EventCallAccept,
+	EventCallPreAccept,
+	EventCallTransport,
+	EventCallOfferNotice,
+	EventCallRelayLatency,
+	EventCallTerminate,
+	EventUnknownCallEvent,
+	EventNewsletterJoin,
+	EventNewsletterLeave,
+	EventNewsletterMuteChange,
+	EventNewsletterLiveUpdate,
+	EventNewsletterMessageMeta,
+	EventMediaRetry,
+	EventMediaStored,
+	EventMessageSent,
+	EventMessageSendFailed,
+	EventScheduledMessageSent,
+	EventScheduledMessageFailed,
+	EventMessageStatus,
+}
+
+// CheckEventCatalogue compares the advertised webhook events with the events
+// the handler delivers, so a subscriber can never pick an event that is never
+// sent and no delivered event is missing from the catalogue. Every advertised
+// event must also have a published payload schema.
+func CheckEventCatalogue(advertised []string) error {
+	delivered := make(map[string]bool, len(webhookEventTypes))
+	for _, eventType := range webhookEventTypes {
+		delivered[string(eventType)] = true
+	}
+
+	payloads := webhook.EventPayloads()
+
+	var neverSent, withoutSchema []string
+
+	for _, eventType := range advertised {
+		if !delivered[eventType] {
+			neverSent = append(neverSent, eventType)
+		}
+
+		if _, ok := payloads[eventType]; !ok {
+			withoutSchema = append(withoutSchema, eventType)
+		}
+
+		delete(delivered, eventType)
+	}
+
+	notAdvertised := make([]string, 0, len(delivered))
+	for eventType := range delivered {
+		notAdvertised = append(notAdvertised, eventType)
+	}
+
+	sort.Strings(notAdvertised)
+
+	var problems []string
+
+	if len(neverSent) > 0 {
+		problems = append(problems, "advertised but never sent: "+strings.Join(neverSent, ", "))
+	}
+
+	if len(notAdvertised) > 0 {
+		problems = append(problems, "sent but not advertised: "+strings.Join(notAdvertised, ", "))
+	}
+
+	if len(withoutSchema) > 0 {
+		problems = append(problems, "no payload schema: "+strings.Join(withoutSchema, ", "))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("webhook event catalogue out of sync (%s)", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package waclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingSender records the type of every webhook event it is given.
+type recordingSender struct {
+	mutex sync.Mutex
+	types []string
+}
+
+func (s *recordingSender) SendWebhook(_ context.Context, _ string, _ *string, event *output.WebhookEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.types = append(s.types, event.Type)
+
+	return nil
+}
+
+func (s *recordingSender) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.types = nil
+}
+
+// waitFor reports whether an event of eventType was sent before the
+// timeout. History syncs are sent from a goroutine, hence the wait.
+func (s *recordingSender) waitFor(eventType string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		s.mutex.Lock()
+		for _, sent := range s.types {
+			if sent == eventType {
+				s.mutex.Unlock()
+				return true
+			}
+		}
+		s.mutex.Unlock()
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// subscribedToAll is a webhook repository whose only webhook takes every
+// event.
+type subscribedToAll struct {
+	webhook.Repository
+}
+
+func (subscribedToAll) ListForSession(_ context.Context, sessionID string) ([]*webhook.Webhook, error) {
+	return []*webhook.Webhook{webhook.NewWebhook(sessionID, "https://example.com/hook", nil)}, nil
+}
+
+// sampleEvents returns, for every webhook event type, an event the handler
+// turns into it.
+func sampleEvents() map[EventType]interface{} {
+	chat := types.NewJID("5511999999999", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	newsletter := types.NewJID("120363000000000001", types.NewsletterServer)
+	now := time.Now()
+
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+		ID:            "3EB0000000000000",
+		Timestamp:     now,
+	}
+
+	newsletterInfo := info
+	newsletterInfo.Chat = newsletter
+
+	key := &waCommon.MessageKey{
+		RemoteJID: proto.String(chat.String()),
+		FromMe:    proto.Bool(false),
+		ID:        proto.String(info.ID),
+	}
+
+	call := types.BasicCallMeta{From: chat, Timestamp: now, CallID: "call-1"}
+
+	return map[EventType]interface{}{
+		EventMessage: &events.Message{
+			Info:    info,
+			Message: &waE2E.Message{Conversation: proto.String("hello")},
+		},
+		EventMessageRevoked: &events.Message{
+			Info: info,
+			Message: &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{
+				Type: waE2E.ProtocolMessage_REVOKE.Enum(),
+				Key:  key,
+			}},
+		},
+		EventMessageReaction: &events.Message{
+			Info: info,
+			Message: &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{
+				Key:  key,
+				Text: proto.String("👍"),
+			}},
+		},
+		EventNewsletterMessageMeta: &events.Message{
+			Info:           newsletterInfo,
+			Message:        &waE2E.Message{Conversation: proto.String("update")},
+			NewsletterMeta: &events.NewsletterMessageMeta{EditTS: now, OriginalTS: now},
+		},
+		EventReceipt: &events.Receipt{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			MessageIDs:    []types.MessageID{info.ID},
+			Timestamp:     now,
+			Type:          types.ReceiptTypeDelivered,
+		},
+		EventConnected:         &events.Connected{},
+		EventDisconnected:      &events.Disconnected{},
+		EventQRCode:            &QREvent{Event: "code", Code: "2@abc", ExpiresAt: now},
+		EventPairSuccess:       &events.PairSuccess{ID: chat},
+		EventLoggedOut:         &events.LoggedOut{},
+		EventKeepAliveTimeout:  &events.KeepAliveTimeout{ErrorCount: 1, LastSuccess: now},
+		EventKeepAliveRestored: &events.KeepAliveRestored{},
+		EventGroupInfo:         &events.GroupInfo{JID: group, Timestamp: now},
+		EventJoinedGroup:       &events.JoinedGroup{GroupInfo: types.GroupInfo{JID: group}},
+		EventPicture:           &events.Picture{JID: chat, Timestamp: now},
+		EventIdentityChange:    &events.IdentityChange{JID: chat, Timestamp: now},
+		EventPrivacySettings:   &events.PrivacySettings{LastSeenChanged: true},
+		EventBlocklist: &events.Blocklist{
+			Action:  events.BlocklistActionDefault,
+			Changes: []events.BlocklistChange{{JID: chat, Action: events.BlocklistChangeActionBlock}},
+		},
+		EventPresence:             &events.Presence{From: chat, LastSeen: now},
+		EventChatPresence:         &events.ChatPresence{MessageSource: types.MessageSource{Chat: chat, Sender: chat}, State: types.ChatPresenceComposing},
+		EventHistorySync:          &events.HistorySync{Data: &waHistorySync.HistorySync{SyncType: waHistorySync.HistorySync_RECENT.Enum()}},
+		EventOfflineSyncPreview:   &events.OfflineSyncPreview{Total: 1, Messages: 1},
+		EventOfflineSyncCompleted: &events.OfflineSyncCompleted{Count: 1},
+		EventAppState:             &events.AppState{Index: []string{"mute", chat.String()}},
+		EventCallOffer:            &events.CallOffer{BasicCallMeta: call},
+		EventCallAccept:           &events.CallAccept{BasicCallMeta: call},
+		EventCallPreAccept:        &events.CallPreAccept{BasicCallMeta: call},
+		EventCallTransport:        &events.CallTransport{BasicCallMeta: call},
+		EventCallOfferNotice:      &events.CallOfferNotice{BasicCallMeta: call, Media: "audio", Type: "1:1"},
+		EventCallRelayLatency:     &events.CallRelayLatency{BasicCallMeta: call},
+		EventCallTerminate:        &events.CallTerminate{BasicCallMeta: call, Reason: "timeout"},
+		EventUnknownCallEvent:     &events.UnknownCallEvent{},
+		EventNewsletterJoin:       &events.NewsletterJoin{NewsletterMetadata: types.NewsletterMetadata{ID: newsletter}},
+		EventNewsletterLeave:      &events.NewsletterLeave{ID: newsletter, Role: types.NewsletterRoleSubscriber},
+		EventNewsletterMuteChange: &events.NewsletterMuteChange{ID: newsletter, Mute: types.NewsletterMuteOn},
+		EventNewsletterLiveUpdate: &events.NewsletterLiveUpdate{JID: newsletter, Time: now},
+		EventMediaRetry:           &events.MediaRetry{MessageID: info.ID, ChatID: chat, Timestamp: now},
+		EventMessageSent: &queuedSendResult{
+			MessageID: info.ID,
+			To:        chat,
+			Type:      "text",
+			QueuedAt:  now,
+			SentAt:    now,
+		},
+		EventMessageSendFailed: &queuedSendResult{
+			MessageID: info.ID,
+			To:        chat,
+			Type:      "text",
+			QueuedAt:  now,
+			Err:       ErrNotConnected,
+		},
+		EventMessageStatus: &messageStatusChange{
+			MessageID: info.ID,
+			Chat:      chat,
+			Status:    "delivered",
+			Timestamp: now,
+		},
+		EventScheduledMessageSent:   &publishedEvent{Type: string(EventScheduledMessageSent), Payload: &webhook.ScheduledMessagePayload{}},
+		EventScheduledMessageFailed: &publishedEvent{Type: string(EventScheduledMessageFailed), Payload: &webhook.ScheduledMessagePayload{}},
+		EventMediaStored:            &publishedEvent{Type: string(EventMediaStored), Payload: &webhook.MediaStoredPayload{}},
+	}
+}
+
+// TestAdvertisedEventsAreSent drives a sample of every advertised event
+// through the handler, so the catalogue cannot list an event the handler
+// does not deliver under that name.
+func TestAdvertisedEventsAreSent(t *testing.T) {
+	sender := &recordingSender{}
+	handler := NewDefaultEventHandler(logger.New(), sender, subscribedToAll{}, nil, nil)
+	client := &Client{SessionID: "session-1"}
+	samples := sampleEvents()
+
+	for _, eventType := range webhook.NewService().GetValidEventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			sample, ok := samples[EventType(eventType)]
+			if !ok {
+				t.Fatalf("no sample event for advertised event %s", eventType)
+			}
+
+			sender.reset()
+
+			if err := handler.HandleEvent(client, sample); err != nil {
+				t.Fatalf("HandleEvent(%T): %v", sample, err)
+			}
+
+			if !sender.waitFor(eventType, time.Second) {
+				t.Fatalf("HandleEvent(%T) sent %v, want %s", sample, sender.types, eventType)
+			}
+		})
+	}
+}
+
+func TestEventCatalogueMatchesAdvertisedEvents(t *testing.T) {
+	if err := CheckEventCatalogue(webhook.NewService().GetValidEventTypes()); err != nil {
+		t.Fatal(err)
+	}
+}
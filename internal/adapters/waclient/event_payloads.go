@@ -0,0 +1,414 @@
+package waclient
+
+import (
+	"sort"
+	"time"
+
+	"zpwoot/internal/core/domain/webhook"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func isRevokeMessage(msg *waE2E.Message) bool {
+	protocol := msg.GetProtocolMessage()
+
+	return protocol != nil && protocol.Type != nil && protocol.GetType() == waE2E.ProtocolMessage_REVOKE
+}
+
+func newMessageRevokedPayload(evt *events.Message) *webhook.MessageRevokedPayload {
+	key := evt.Message.GetProtocolMessage().GetKey()
+
+	return &webhook.MessageRevokedPayload{
+		MessageInfo:      newMessageInfo(evt),
+		RevokedMessageID: key.GetID(),
+		RevokedFromMe:    key.GetFromMe(),
+		RevokedSender:    key.GetParticipant(),
+	}
+}
+
+func newMessageReactionPayload(evt *events.Message) *webhook.MessageReactionPayload {
+	reaction := evt.Message.GetReactionMessage()
+	key := reaction.GetKey()
+
+	return &webhook.MessageReactionPayload{
+		MessageInfo:     newMessageInfo(evt),
+		TargetMessageID: key.GetID(),
+		TargetFromMe:    key.GetFromMe(),
+		TargetSender:    key.GetParticipant(),
+		Reaction:        reaction.GetText(),
+		Removed:         reaction.GetText() == "",
+	}
+}
+
+func newNewsletterMessageMetaPayload(evt *events.Message) *webhook.NewsletterMessageMetaPayload {
+	return &webhook.NewsletterMessageMetaPayload{
+		MessageInfo: newMessageInfo(evt),
+		EditedAt:    optionalTime(evt.NewsletterMeta.EditTS),
+		OriginalAt:  optionalTime(evt.NewsletterMeta.OriginalTS),
+	}
+}
+
+func newReceiptPayload(evt *events.Receipt) *webhook.ReceiptPayload {
+	messageIDs := make([]string, len(evt.MessageIDs))
+	copy(messageIDs, evt.MessageIDs)
+
+	receiptType := string(evt.Type)
+	if evt.Type == types.ReceiptTypeDelivered {
+		receiptType = "delivered"
+	}
+
+	return &webhook.ReceiptPayload{
+		Chat:          evt.Chat.String(),
+		Sender:        evt.Sender.String(),
+		MessageSender: jidString(evt.MessageSender),
+		MessageIDs:    messageIDs,
+		Type:          receiptType,
+		FromMe:        evt.IsFromMe,
+		IsGroup:       evt.IsGroup,
+		Timestamp:     evt.Timestamp,
+	}
+}
+
+func newConnectedPayload(client *Client) *webhook.ConnectedPayload {
+	return &webhook.ConnectedPayload{
+		DeviceJID:   client.GetDeviceJID(),
+		ConnectedAt: time.Now(),
+	}
+}
+
+func newLoggedOutPayload(evt *events.LoggedOut) *webhook.LoggedOutPayload {
+	return &webhook.LoggedOutPayload{
+		OnConnect:  evt.OnConnect,
+		ReasonCode: int(evt.Reason),
+		Reason:     evt.Reason.String(),
+	}
+}
+
+func newPairSuccessPayload(evt *events.PairSuccess) *webhook.PairSuccessPayload {
+	return &webhook.PairSuccessPayload{
+		DeviceJID:    evt.ID.String(),
+		LID:          jidString(evt.LID),
+		BusinessName: evt.BusinessName,
+		Platform:     evt.Platform,
+	}
+}
+
+func newGroupInfoPayload(evt *events.GroupInfo) *webhook.GroupInfoPayload {
+	payload := &webhook.GroupInfoPayload{
+		JID:           evt.JID.String(),
+		Sender:        jidPtrString(evt.Sender),
+		Timestamp:     evt.Timestamp,
+		NewInviteLink: evt.NewInviteLink,
+		JoinReason:    evt.JoinReason,
+		Join:          jidStrings(evt.Join),
+		Leave:         jidStrings(evt.Leave),
+		Promote:       jidStrings(evt.Promote),
+		Demote:        jidStrings(evt.Demote),
+	}
+
+	if evt.Name != nil {
+		payload.Name = &evt.Name.Name
+	}
+
+	if evt.Topic != nil {
+		payload.Topic = &evt.Topic.Topic
+	}
+
+	if evt.Locked != nil {
+		payload.Locked = &evt.Locked.IsLocked
+	}
+
+	if evt.Announce != nil {
+		payload.Announce = &evt.Announce.IsAnnounce
+	}
+
+	if evt.Ephemeral != nil {
+		timer := evt.Ephemeral.DisappearingTimer
+		if !evt.Ephemeral.IsEphemeral {
+			timer = 0
+		}
+
+		payload.EphemeralTimer = &timer
+	}
+
+	if evt.Delete != nil {
+		payload.Deleted = evt.Delete.Deleted
+	}
+
+	return payload
+}
+
+func newJoinedGroupPayload(evt *events.JoinedGroup) *webhook.JoinedGroupPayload {
+	return &webhook.JoinedGroupPayload{
+		JID:          evt.JID.String(),
+		Name:         evt.GroupName.Name,
+		Topic:        evt.GroupTopic.Topic,
+		Owner:        jidString(evt.OwnerJID),
+		Reason:       evt.Reason,
+		Type:         evt.Type,
+		Sender:       jidPtrString(evt.Sender),
+		Participants: len(evt.Participants),
+		CreatedAt:    evt.GroupCreated,
+	}
+}
+
+func newPicturePayload(evt *events.Picture) *webhook.PicturePayload {
+	return &webhook.PicturePayload{
+		JID:       evt.JID.String(),
+		Author:    jidString(evt.Author),
+		PictureID: evt.PictureID,
+		Removed:   evt.Remove,
+		Timestamp: evt.Timestamp,
+	}
+}
+
+func newPrivacySettingsPayload(evt *events.PrivacySettings) *webhook.PrivacySettingsPayload {
+	settings := evt.NewSettings
+
+	changed := make([]string, 0)
+	for name, isChanged := range map[string]bool{
+		"groupAdd":     evt.GroupAddChanged,
+		"lastSeen":     evt.LastSeenChanged,
+		"status":       evt.StatusChanged,
+		"profile":      evt.ProfileChanged,
+		"readReceipts": evt.ReadReceiptsChanged,
+		"online":       evt.OnlineChanged,
+		"callAdd":      evt.CallAddChanged,
+	} {
+		if isChanged {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return &webhook.PrivacySettingsPayload{
+		Settings: map[string]string{
+			"groupAdd":     string(settings.GroupAdd),
+			"lastSeen":     string(settings.LastSeen),
+			"status":       string(settings.Status),
+			"profile":      string(settings.Profile),
+			"readReceipts": string(settings.ReadReceipts),
+			"online":       string(settings.Online),
+			"callAdd":      string(settings.CallAdd),
+		},
+		Changed: changed,
+	}
+}
+
+func newBlocklistPayload(evt *events.Blocklist) *webhook.BlocklistPayload {
+	changes := make([]webhook.BlocklistChangePayload, len(evt.Changes))
+	for i, change := range evt.Changes {
+		changes[i] = webhook.BlocklistChangePayload{
+			JID:    change.JID.String(),
+			Action: string(change.Action),
+		}
+	}
+
+	return &webhook.BlocklistPayload{
+		Action:  string(evt.Action),
+		Changes: changes,
+	}
+}
+
+func newPresencePayload(evt *events.Presence) *webhook.PresencePayload {
+	return &webhook.PresencePayload{
+		From:      evt.From.String(),
+		Available: !evt.Unavailable,
+		LastSeen:  optionalTime(evt.LastSeen),
+	}
+}
+
+func newChatPresencePayload(evt *events.ChatPresence) *webhook.ChatPresencePayload {
+	return &webhook.ChatPresencePayload{
+		Chat:    evt.Chat.String(),
+		Sender:  evt.Sender.String(),
+		IsGroup: evt.IsGroup,
+		State:   string(evt.State),
+		Media:   string(evt.Media),
+	}
+}
+
+func newAppStatePayload(evt *events.AppState) *webhook.AppStatePayload {
+	payload := &webhook.AppStatePayload{
+		Index: evt.Index,
+	}
+
+	if len(evt.Index) > 0 {
+		payload.Action = evt.Index[0]
+	}
+
+	if evt.SyncActionValue != nil {
+		if value, err := protojson.Marshal(evt.SyncActionValue); err == nil {
+			payload.Value = value
+		}
+	}
+
+	if evt.SyncActionValue != nil && evt.GetTimestamp() > 0 {
+		timestamp := time.UnixMilli(evt.GetTimestamp())
+		payload.Timestamp = &timestamp
+	}
+
+	return payload
+}
+
+func newCallPayload(meta types.BasicCallMeta, remote *types.CallRemoteMeta) *webhook.CallPayload {
+	payload := &webhook.CallPayload{
+		CallID:    meta.CallID,
+		From:      meta.From.String(),
+		Creator:   jidString(meta.CallCreator),
+		GroupJID:  jidString(meta.GroupJID),
+		Timestamp: meta.Timestamp,
+	}
+
+	if remote != nil {
+		payload.RemotePlatform = remote.RemotePlatform
+		payload.RemoteVersion = remote.RemoteVersion
+	}
+
+	return payload
+}
+
+func newUnknownCallEventPayload(node *waBinary.Node) *webhook.UnknownCallEventPayload {
+	if node == nil {
+		return &webhook.UnknownCallEventPayload{}
+	}
+
+	payload := &webhook.UnknownCallEventPayload{Tag: node.Tag}
+	if len(node.Attrs) > 0 {
+		payload.Attrs = make(map[string]interface{}, len(node.Attrs))
+		for key, value := range node.Attrs {
+			if jid, ok := value.(types.JID); ok {
+				value = jid.String()
+			}
+
+			payload.Attrs[key] = value
+		}
+	}
+
+	return payload
+}
+
+func newNewsletterJoinPayload(evt *events.NewsletterJoin) *webhook.NewsletterJoinPayload {
+	payload := &webhook.NewsletterJoinPayload{
+		ID:          evt.ID.String(),
+		Name:        evt.ThreadMeta.Name.Text,
+		Description: evt.ThreadMeta.Description.Text,
+		InviteCode:  evt.ThreadMeta.InviteCode,
+		Subscribers: evt.ThreadMeta.SubscriberCount,
+		State:       string(evt.State.Type),
+	}
+
+	if evt.ViewerMeta != nil {
+		payload.Role = string(evt.ViewerMeta.Role)
+		payload.Mute = string(evt.ViewerMeta.Mute)
+	}
+
+	return payload
+}
+
+func newNewsletterLiveUpdatePayload(evt *events.NewsletterLiveUpdate) *webhook.NewsletterLiveUpdatePayload {
+	messages := make([]*webhook.NewsletterLiveMessage, len(evt.Messages))
+	for i, msg := range evt.Messages {
+		messages[i] = &webhook.NewsletterLiveMessage{
+			ServerID:  int(msg.MessageServerID),
+			MessageID: msg.MessageID,
+			Type:      msg.Type,
+			Timestamp: msg.Timestamp,
+			Views:     msg.ViewsCount,
+			Reactions: msg.ReactionCounts,
+		}
+	}
+
+	return &webhook.NewsletterLiveUpdatePayload{
+		JID:      evt.JID.String(),
+		Time:     evt.Time,
+		Messages: messages,
+	}
+}
+
+func newMediaRetryPayload(evt *events.MediaRetry) *webhook.MediaRetryPayload {
+	payload := &webhook.MediaRetryPayload{
+		MessageID: evt.MessageID,
+		Chat:      evt.ChatID.String(),
+		Sender:    jidString(evt.SenderID),
+		FromMe:    evt.FromMe,
+		Timestamp: evt.Timestamp,
+	}
+
+	if evt.Error != nil {
+		code := evt.Error.Code
+		payload.ErrorCode = &code
+	}
+
+	return payload
+}
+
+func jidString(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+
+	return jid.String()
+}
+
+func jidPtrString(jid *types.JID) string {
+	if jid == nil {
+		return ""
+	}
+
+	return jidString(*jid)
+}
+
+func jidStrings(jids []types.JID) []string {
+	if len(jids) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(jids))
+	for i, jid := range jids {
+		values[i] = jid.String()
+	}
+
+	return values
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func newQueuedMessagePayload(result *queuedSendResult) *webhook.QueuedMessagePayload {
+	payload := &webhook.QueuedMessagePayload{
+		MessageID: result.MessageID,
+		Chat:      result.To.String(),
+		Type:      result.Type,
+		QueuedAt:  result.QueuedAt,
+	}
+
+	if result.Err != nil {
+		payload.Error = result.Err.Error()
+	} else {
+		sentAt := result.SentAt
+		payload.SentAt = &sentAt
+	}
+
+	return payload
+}
+
+func newMessageStatusPayload(change *messageStatusChange) *webhook.MessageStatusPayload {
+	return &webhook.MessageStatusPayload{
+		MessageID: change.MessageID,
+		Chat:      change.Chat.String(),
+		Recipient: jidString(change.Recipient),
+		Status:    string(change.Status),
+		Timestamp: change.Timestamp,
+	}
+}
@@ -3,15 +3,20 @@ package waclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/chat"
+	"zpwoot/internal/core/domain/message"
 	"zpwoot/internal/core/domain/webhook"
 	"zpwoot/internal/core/ports/output"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -23,13 +28,20 @@ type DefaultEventHandler struct {
 	logger        *logger.Logger
 	webhookSender output.WebhookSender
 	webhookRepo   webhook.Repository
+	messageRepo   message.Repository
+	chatRepo      chat.Repository
+	messageSync   MessageSync
+	receiptSync   ReceiptSync
+	media         *MediaStore
 }
 
-func NewDefaultEventHandler(logger *logger.Logger, webhookSender output.WebhookSender, webhookRepo webhook.Repository) *DefaultEventHandler {
+func NewDefaultEventHandler(logger *logger.Logger, webhookSender output.WebhookSender, webhookRepo webhook.Repository, messageRepo message.Repository, chatRepo chat.Repository) *DefaultEventHandler {
 	return &DefaultEventHandler{
 		logger:        logger,
 		webhookSender: webhookSender,
 		webhookRepo:   webhookRepo,
+		messageRepo:   messageRepo,
+		chatRepo:      chatRepo,
 	}
 }
 
@@ -45,18 +57,106 @@ func (eh *DefaultEventHandler) HandleEvent(client *Client, event interface{}) er
 		return eh.handleChatPresence(client, evt)
 	case *events.HistorySync:
 		return eh.handleHistorySync(client, evt)
-	case *events.AppStateSyncComplete:
-		return eh.handleAppStateSyncComplete(client, evt)
-	case *events.PushNameSetting:
-		return eh.handlePushNameSetting(client, evt)
-	case *events.BlocklistChange:
-		return eh.handleBlocklistChange(client, evt)
+	case *events.MarkChatAsRead:
+		return eh.handleMarkChatAsRead(client, evt)
+
+	case *events.Connected:
+		return eh.emit(client, EventConnected, newConnectedPayload(client))
+	case *events.Disconnected:
+		return eh.emit(client, EventDisconnected, &webhook.DisconnectedPayload{DisconnectedAt: time.Now()})
+	case *events.LoggedOut:
+		return eh.emit(client, EventLoggedOut, newLoggedOutPayload(evt))
+	case *events.PairSuccess:
+		eh.scheduleImport(client.SessionID)
+		return eh.emit(client, EventPairSuccess, newPairSuccessPayload(evt))
+	case *QREvent:
+		return eh.emit(client, EventQRCode, &rawPayload{
+			normalized: &webhook.QRCodePayload{Code: evt.Code, ExpiresAt: evt.ExpiresAt},
+			raw:        evt,
+			legacyType: legacyEventQR,
+		})
+	case *events.KeepAliveTimeout:
+		return eh.emit(client, EventKeepAliveTimeout, &webhook.KeepAliveTimeoutPayload{ErrorCount: evt.ErrorCount, LastSuccess: evt.LastSuccess})
+	case *events.KeepAliveRestored:
+		return eh.emit(client, EventKeepAliveRestored, &webhook.KeepAliveRestoredPayload{RestoredAt: time.Now()})
+
 	case *events.GroupInfo:
-		return eh.handleGroupInfo(client, evt)
+		return eh.emit(client, EventGroupInfo, newGroupInfoPayload(evt))
 	case *events.JoinedGroup:
-		return eh.handleJoinedGroup(client, evt)
+		return eh.emit(client, EventJoinedGroup, newJoinedGroupPayload(evt))
+	case *events.Picture:
+		return eh.emit(client, EventPicture, newPicturePayload(evt))
+	case *events.IdentityChange:
+		return eh.emit(client, EventIdentityChange, &webhook.IdentityChangePayload{JID: evt.JID.String(), Implicit: evt.Implicit, Timestamp: evt.Timestamp})
+	case *events.PrivacySettings:
+		return eh.emit(client, EventPrivacySettings, newPrivacySettingsPayload(evt))
+	case *events.Blocklist:
+		return eh.emit(client, EventBlocklist, newBlocklistPayload(evt))
+
 	case *events.OfflineSyncPreview:
-		return eh.handleOfflineSyncPreview(client, evt)
+		return eh.emit(client, EventOfflineSyncPreview, &webhook.OfflineSyncPreviewPayload{
+			Total:          evt.Total,
+			AppDataChanges: evt.AppDataChanges,
+			Messages:       evt.Messages,
+			Notifications:  evt.Notifications,
+			Receipts:       evt.Receipts,
+		})
+	case *events.OfflineSyncCompleted:
+		return eh.emit(client, EventOfflineSyncCompleted, &webhook.OfflineSyncCompletedPayload{Count: evt.Count})
+	case *events.AppState:
+		return eh.emit(client, EventAppState, newAppStatePayload(evt))
+
+	case *events.CallOffer:
+		return eh.emit(client, EventCallOffer, newCallPayload(evt.BasicCallMeta, &evt.CallRemoteMeta))
+	case *events.CallAccept:
+		return eh.emit(client, EventCallAccept, newCallPayload(evt.BasicCallMeta, &evt.CallRemoteMeta))
+	case *events.CallPreAccept:
+		return eh.emit(client, EventCallPreAccept, newCallPayload(evt.BasicCallMeta, &evt.CallRemoteMeta))
+	case *events.CallTransport:
+		return eh.emit(client, EventCallTransport, newCallPayload(evt.BasicCallMeta, &evt.CallRemoteMeta))
+	case *events.CallOfferNotice:
+		payload := newCallPayload(evt.BasicCallMeta, nil)
+		payload.Media = evt.Media
+		payload.CallType = evt.Type
+
+		return eh.emit(client, EventCallOfferNotice, payload)
+	case *events.CallRelayLatency:
+		return eh.emit(client, EventCallRelayLatency, newCallPayload(evt.BasicCallMeta, nil))
+	case *events.CallTerminate:
+		payload := newCallPayload(evt.BasicCallMeta, nil)
+		payload.Reason = evt.Reason
+
+		return eh.emit(client, EventCallTerminate, payload)
+	case *events.UnknownCallEvent:
+		return eh.emit(client, EventUnknownCallEvent, newUnknownCallEventPayload(evt.Node))
+
+	case *events.NewsletterJoin:
+		return eh.emit(client, EventNewsletterJoin, newNewsletterJoinPayload(evt))
+	case *events.NewsletterLeave:
+		return eh.emit(client, EventNewsletterLeave, &webhook.NewsletterLeavePayload{ID: evt.ID.String(), Role: string(evt.Role)})
+	case *events.NewsletterMuteChange:
+		return eh.emit(client, EventNewsletterMuteChange, &webhook.NewsletterMuteChangePayload{ID: evt.ID.String(), Mute: string(evt.Mute)})
+	case *events.NewsletterLiveUpdate:
+		return eh.emit(client, EventNewsletterLiveUpdate, newNewsletterLiveUpdatePayload(evt))
+
+	case *events.MediaRetry:
+		if eh.media != nil {
+			eh.media.retried(client, evt)
+		}
+
+		return eh.emit(client, EventMediaRetry, newMediaRetryPayload(evt))
+
+	case *queuedSendResult:
+		if evt.Err != nil {
+			return eh.emit(client, EventMessageSendFailed, newQueuedMessagePayload(evt))
+		}
+
+		return eh.emit(client, EventMessageSent, newQueuedMessagePayload(evt))
+	case *messageStatusChange:
+		return eh.emit(client, EventMessageStatus, newMessageStatusPayload(evt))
+	case *publishedEvent:
+		return eh.emit(client, EventType(evt.Type), evt.Payload)
+
 	default:
 		// Log payload de eventos não tratados em DEBUG (payload no final)
 		if payload, err := json.Marshal(event); err == nil {
@@ -71,29 +171,37 @@ func (eh *DefaultEventHandler) HandleEvent(client *Client, event interface{}) er
 	}
 }
 
+// emit sends an event that needs no processing besides its webhook.
+func (eh *DefaultEventHandler) emit(client *Client, eventType EventType, payload interface{}) error {
+	eh.logger.Debug().
+		Str("session_id", client.SessionID).
+		Str("event", string(eventType)).
+		Msg("Event received")
+
+	return eh.sendWebhookIfEnabled(client, eventType, payload)
+}
+
 func (eh *DefaultEventHandler) handleMessage(client *Client, evt *events.Message) error {
-	messageInfo := &MessageInfo{
-		ID:        evt.Info.ID,
-		Chat:      evt.Info.Chat.String(),
-		Sender:    evt.Info.Sender.String(),
-		PushName:  evt.Info.PushName,
-		Timestamp: evt.Info.Timestamp,
-		FromMe:    evt.Info.IsFromMe,
-		Type:      getMessageType(evt.Message),
-		IsGroup:   evt.Info.IsGroup,
+	domainMessage := newDomainMessage(client.SessionID, evt)
+
+	inserted := eh.storeMessage(context.Background(), domainMessage)
+	if inserted && countsAsUnread(evt) {
+		eh.incrementUnread(client.SessionID, evt.Info.Chat)
 	}
 
-	webhookData := map[string]interface{}{
-		"messageInfo": messageInfo,
-		"message":     evt.Message,
+	if inserted {
+		eh.syncMessage(domainMessage, evt.Info.PushName)
 	}
 
+	messagePayload := newMessagePayload(evt)
+	eh.recordMedia(client, evt, messagePayload)
+
 	// Log completo em uma linha (INFO + payload no final)
-	if payload, err := json.Marshal(webhookData); err == nil {
+	if payload, err := json.Marshal(messagePayload); err == nil {
 		log.Info().
 			Str("chat", evt.Info.Chat.String()).
 			Str("from", evt.Info.Sender.String()).
-			Str("type", getMessageType(evt.Message)).
+			Str("type", messagePayload.MessageInfo.Type).
 			Bool("from_me", evt.Info.IsFromMe).
 			Bool("is_group", evt.Info.IsGroup).
 			Str("session_id", client.SessionID).
@@ -101,10 +209,60 @@ func (eh *DefaultEventHandler) handleMessage(client *Client, evt *events.Message
 			Msg("Message received")
 	}
 
-	return eh.sendWebhookIfEnabled(client, EventMessage, webhookData)
+	if evt.NewsletterMeta != nil {
+		if err := eh.sendWebhookIfEnabled(client, EventNewsletterMessageMeta, newNewsletterMessageMetaPayload(evt)); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case isRevokeMessage(evt.Message):
+		return eh.sendWebhookIfEnabled(client, EventMessageRevoked, newMessageRevokedPayload(evt))
+	case evt.Message.GetReactionMessage() != nil:
+		return eh.sendWebhookIfEnabled(client, EventMessageReaction, newMessageReactionPayload(evt))
+	default:
+		return eh.sendWebhookIfEnabled(client, EventMessage, &rawPayload{
+			normalized: messagePayload,
+			raw: map[string]interface{}{
+				"messageInfo": messagePayload.MessageInfo,
+				"message":     evt.Message,
+			},
+		})
+	}
+}
+
+// recordMedia keeps track of the file of a media message and points the
+// payload at the API, which serves it. Inbound files are downloaded in the
+// background when auto-download is enabled; a MediaStored event follows
+// once the file is stored.
+func (eh *DefaultEventHandler) recordMedia(client *Client, evt *events.Message, payload *webhook.MessagePayload) {
+	if eh.media == nil || payload.Media == nil {
+		return
+	}
+
+	m, inserted := eh.media.record(client.SessionID, evt)
+	if m == nil {
+		return
+	}
+
+	payload.Media.MediaURL = eh.media.URL(client.SessionID, evt.Info.ID)
+
+	if inserted && eh.media.config.AutoDownload && !evt.Info.IsFromMe {
+		eh.media.downloadLater(client, m)
+	}
 }
 
 func (eh *DefaultEventHandler) handleReceipt(client *Client, evt *events.Receipt) error {
+	if isOwnReadReceipt(evt) {
+		eh.markChatRead(client.SessionID, evt.Chat, evt.Timestamp)
+	}
+
+	eh.syncReceipt(client.SessionID, evt)
+
+	if err := eh.recordReceipt(client, evt); err != nil {
+		return err
+	}
+
 	// Log completo em uma linha (INFO + payload no final)
 	if payload, err := json.Marshal(evt); err == nil {
 		log.Info().
@@ -116,7 +274,11 @@ func (eh *DefaultEventHandler) handleReceipt(client *Client, evt *events.Receipt
 			Msg("Receipt received")
 	}
 
-	return eh.sendWebhookIfEnabled(client, EventReadReceipt, evt)
+	return eh.sendWebhookIfEnabled(client, EventReceipt, &rawPayload{
+		normalized: newReceiptPayload(evt),
+		raw:        evt,
+		legacyType: legacyEventReadReceipt,
+	})
 }
 
 func (eh *DefaultEventHandler) handlePresence(client *Client, evt *events.Presence) error {
@@ -130,7 +292,7 @@ func (eh *DefaultEventHandler) handlePresence(client *Client, evt *events.Presen
 			Msg("Event received")
 	}
 
-	return eh.sendWebhookIfEnabled(client, EventPresence, evt)
+	return eh.sendWebhookIfEnabled(client, EventPresence, &rawPayload{normalized: newPresencePayload(evt), raw: evt})
 }
 
 func (eh *DefaultEventHandler) handleChatPresence(client *Client, evt *events.ChatPresence) error {
@@ -144,99 +306,77 @@ func (eh *DefaultEventHandler) handleChatPresence(client *Client, evt *events.Ch
 			Msg("Event received")
 	}
 
-	return eh.sendWebhookIfEnabled(client, EventChatPresence, evt)
-}
-
-func (eh *DefaultEventHandler) handleHistorySync(client *Client, evt *events.HistorySync) error {
-	syncInfo := map[string]interface{}{
-		"type":              evt.Data.SyncType,
-		"conversationCount": len(evt.Data.Conversations),
-	}
-
-	// Log completo em DEBUG apenas (payload no final)
-	if payload, err := json.Marshal(syncInfo); err == nil {
-		log.Debug().
-			Str("event", "history_sync").
-			Str("session_id", client.SessionID).
-			RawJSON("payload", payload).
-			Msg("Event received")
-	}
-
-	return eh.sendWebhookIfEnabled(client, EventHistorySync, syncInfo)
-}
-
-func (eh *DefaultEventHandler) handleAppStateSyncComplete(client *Client, evt *events.AppStateSyncComplete) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "app_state_sync").
-		Msg("Event received")
-
-	return nil
+	return eh.sendWebhookIfEnabled(client, EventChatPresence, &rawPayload{normalized: newChatPresencePayload(evt), raw: evt})
 }
 
-func (eh *DefaultEventHandler) handlePushNameSetting(client *Client, evt *events.PushNameSetting) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "push_name").
-		Msg("Event received")
-
-	return nil
-}
-
-func (eh *DefaultEventHandler) handleBlocklistChange(client *Client, evt *events.BlocklistChange) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "blocklist").
-		Msg("Event received")
-
-	return nil
-}
-
-func (eh *DefaultEventHandler) handleGroupInfo(client *Client, evt *events.GroupInfo) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "group_info").
-		Msg("Event received")
-
-	return nil
+// rawPayload pairs a normalized payload with the legacy, whatsmeow based
+// payload that webhooks in raw format still receive. legacyType, when set, is
+// the name the event had before it was renamed; raw webhooks get that name.
+type rawPayload struct {
+	normalized interface{}
+	raw        interface{}
+	legacyType EventType
 }
 
-func (eh *DefaultEventHandler) handleJoinedGroup(client *Client, evt *events.JoinedGroup) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "joined_group").
-		Msg("Event received")
-
-	return nil
-}
-
-func (eh *DefaultEventHandler) handleOfflineSyncPreview(client *Client, evt *events.OfflineSyncPreview) error {
-	eh.logger.Debug().
-		Str("session_id", client.SessionID).
-		Str("event", "offline_sync").
-		Msg("Event received")
-
-	return nil
-}
+// Former names of renamed events, still sent to webhooks in raw format.
+const (
+	legacyEventQR          EventType = "QR"
+	legacyEventReadReceipt EventType = "ReadReceipt"
+)
 
+// sendWebhookIfEnabled fans the event out to every enabled webhook of the
+// session, and to the global webhook, that subscribes to it. All deliveries
+// share the same event ID whatever their payload format.
 func (eh *DefaultEventHandler) sendWebhookIfEnabled(client *Client, eventType EventType, eventData interface{}) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	webhookConfig, err := eh.webhookRepo.GetBySessionID(ctx, client.SessionID)
+	webhooks, err := eh.webhookRepo.ListForSession(ctx, client.SessionID)
 	if err != nil {
-		if err.Error() == "webhook not found" {
-			return nil
-		}
 		eh.logger.Error().Err(err).Str("session_id", client.SessionID).Msg("Failed to load webhook config")
 		return nil
 	}
 
-	if !eh.shouldSendWebhook(webhookConfig, eventType) {
+	targets := make([]*webhook.Webhook, 0, len(webhooks))
+	for _, webhookConfig := range webhooks {
+		if eh.shouldSendWebhook(webhookConfig, eventType) {
+			targets = append(targets, webhookConfig)
+		}
+	}
+
+	if len(targets) == 0 {
 		return nil
 	}
 
-	return eh.sendWebhook(webhookConfig, eventType, eventData, client.SessionID)
+	eventID := uuid.New().String()
+	eventsByFormat := make(map[bool]*output.WebhookEvent, 2)
+
+	var errs []error
+
+	for _, webhookConfig := range targets {
+		webhookEvent, ok := eventsByFormat[webhookConfig.IsRaw()]
+		if !ok {
+			webhookEvent, err = eh.newWebhookEvent(eventID, eventType, eventData, client.SessionID, webhookConfig.IsRaw())
+			if err != nil {
+				return err
+			}
+
+			eventsByFormat[webhookConfig.IsRaw()] = webhookEvent
+		}
+
+		if err := eh.sendWebhook(webhookConfig, webhookEvent); err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", client.SessionID).
+				Str("webhook_id", webhookConfig.ID).
+				Str("event_type", string(eventType)).
+				Msg("Failed to queue webhook")
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func (eh *DefaultEventHandler) shouldSendWebhook(webhookConfig *webhook.Webhook, eventType EventType) bool {
@@ -257,7 +397,25 @@ func (eh *DefaultEventHandler) shouldSendWebhook(webhookConfig *webhook.Webhook,
 	return false
 }
 
-func (eh *DefaultEventHandler) sendWebhook(webhookConfig *webhook.Webhook, eventType EventType, eventData interface{}, sessionID string) error {
+// newWebhookEvent builds the envelope of an event. Raw webhooks get the legacy
+// payload and event name, when the event has them, and no schema version.
+func (eh *DefaultEventHandler) newWebhookEvent(eventID string, eventType EventType, eventData interface{}, sessionID string, raw bool) (*output.WebhookEvent, error) {
+	schemaVersion := webhook.SchemaVersion
+	if raw {
+		schemaVersion = 0
+	}
+
+	if payload, ok := eventData.(*rawPayload); ok {
+		eventData = payload.normalized
+		if raw {
+			eventData = payload.raw
+
+			if payload.legacyType != "" {
+				eventType = payload.legacyType
+			}
+		}
+	}
+
 	var data map[string]interface{}
 	if mapData, ok := eventData.(map[string]interface{}); ok {
 		data = mapData
@@ -265,102 +423,78 @@ func (eh *DefaultEventHandler) sendWebhook(webhookConfig *webhook.Webhook, event
 		jsonData, err := json.Marshal(eventData)
 		if err != nil {
 			eh.logger.Error().Err(err).Msg("Failed to marshal event data")
-			return err
+			return nil, err
 		}
 
 		if err := json.Unmarshal(jsonData, &data); err != nil {
 			eh.logger.Error().Err(err).Msg("Failed to unmarshal event data")
-			return err
+			return nil, err
 		}
 	}
 
-	webhookEvent := &output.WebhookEvent{
-		ID:        uuid.New().String(),
-		Type:      string(eventType),
-		SessionID: sessionID,
-		Timestamp: time.Now(),
-		Data:      data,
-	}
+	return &output.WebhookEvent{
+		ID:            eventID,
+		Type:          string(eventType),
+		SessionID:     sessionID,
+		Timestamp:     time.Now(),
+		SchemaVersion: schemaVersion,
+		Data:          data,
+	}, nil
+}
 
+func (eh *DefaultEventHandler) sendWebhook(webhookConfig *webhook.Webhook, webhookEvent *output.WebhookEvent) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	return eh.webhookSender.SendWebhook(ctx, webhookConfig.URL, webhookConfig.Secret, webhookEvent)
 }
 
-func getMessageType(msg interface{}) string {
-	msgMap, ok := convertMessageToMap(msg)
-	if !ok {
-		return unknownMessageType
-	}
-	return detectMessageType(msgMap)
-}
-
-func convertMessageToMap(msg interface{}) (map[string]interface{}, bool) {
+func getMessageContent(msg *waE2E.Message) string {
 	if msg == nil {
-		return nil, false
-	}
-
-	msgJSON, err := json.Marshal(msg)
-	if err != nil {
-		return nil, false
-	}
-
-	var msgMap map[string]interface{}
-	if err := json.Unmarshal(msgJSON, &msgMap); err != nil {
-		return nil, false
-	}
-	return msgMap, true
-}
-
-func detectMessageType(msgMap map[string]interface{}) string {
-	if hasMessageField(msgMap, "conversation", "extendedTextMessage") {
-		return "text"
-	}
-	if hasMessageField(msgMap, "imageMessage") {
-		return "image"
-	}
-	if hasMessageField(msgMap, "audioMessage") {
-		return "audio"
-	}
-	if hasMessageField(msgMap, "videoMessage") {
-		return "video"
-	}
-	if hasMessageField(msgMap, "documentMessage") {
-		return "document"
-	}
-	if hasMessageField(msgMap, "stickerMessage") {
-		return "sticker"
-	}
-	if hasMessageField(msgMap, "locationMessage") {
-		return "location"
-	}
-	if hasMessageField(msgMap, "liveLocationMessage") {
-		return "liveLocation"
-	}
-	if hasMessageField(msgMap, "contactMessage") {
-		return "contact"
-	}
-	if hasMessageField(msgMap, "contactsArrayMessage") {
-		return "contacts"
-	}
-	if hasMessageField(msgMap, "buttonsMessage") {
-		return "buttons"
-	}
-	if hasMessageField(msgMap, "listMessage") {
-		return "list"
-	}
-	if hasMessageField(msgMap, "templateMessage") {
-		return "template"
-	}
-	return unknownMessageType
-}
+		return ""
+	}
+
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		if caption := msg.GetDocumentMessage().GetCaption(); caption != "" {
+			return caption
+		}
 
-func hasMessageField(msgMap map[string]interface{}, fields ...string) bool {
-	for _, field := range fields {
-		if _, ok := msgMap[field]; ok {
-			return true
+		return msg.GetDocumentMessage().GetFileName()
+	case msg.GetLocationMessage() != nil:
+		location := msg.GetLocationMessage()
+		if location.GetAddress() != "" {
+			return strings.TrimSpace(location.GetName() + " " + location.GetAddress())
 		}
+
+		return location.GetName()
+	case msg.GetContactMessage() != nil:
+		return msg.GetContactMessage().GetDisplayName()
+	case msg.GetContactsArrayMessage() != nil:
+		return msg.GetContactsArrayMessage().GetDisplayName()
+	case msg.GetButtonsMessage() != nil:
+		return msg.GetButtonsMessage().GetContentText()
+	case msg.GetListMessage() != nil:
+		return msg.GetListMessage().GetDescription()
+	case msg.GetTemplateMessage() != nil:
+		return msg.GetTemplateMessage().GetHydratedTemplate().GetHydratedContentText()
+	case msg.GetReactionMessage() != nil:
+		return msg.GetReactionMessage().GetText()
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage().GetName()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2().GetName()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3().GetName()
+	default:
+		return ""
 	}
-	return false
 }
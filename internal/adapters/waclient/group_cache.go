@@ -0,0 +1,74 @@
+package waclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// joinedGroupsTTL bounds how long a session's group list is reused. Group
+// events drop it sooner; the TTL only covers events that were missed.
+const joinedGroupsTTL = 5 * time.Minute
+
+// GroupCache keeps the groups each session has joined, so listing chats does
+// not ask WhatsApp for every group on every page.
+type GroupCache struct {
+	mutex sync.Mutex
+	cache map[string]cachedGroups
+	ttl   time.Duration
+	// forgotten counts the calls to Forget, so a fetch that raced with one
+	// is not cached.
+	forgotten uint64
+}
+
+type cachedGroups struct {
+	groups    []*types.GroupInfo
+	expiresAt time.Time
+}
+
+func NewGroupCache() *GroupCache {
+	return &GroupCache{
+		cache: make(map[string]cachedGroups),
+		ttl:   joinedGroupsTTL,
+	}
+}
+
+// Joined returns the cached groups of a session, calling fetch when there are
+// none or they expired. Failed fetches are not cached.
+func (c *GroupCache) Joined(ctx context.Context, sessionID string, fetch func(context.Context) ([]*types.GroupInfo, error)) ([]*types.GroupInfo, error) {
+	c.mutex.Lock()
+	cached, ok := c.cache[sessionID]
+	forgotten := c.forgotten
+	c.mutex.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.groups, nil
+	}
+
+	groups, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	if c.forgotten == forgotten {
+		c.cache[sessionID] = cachedGroups{
+			groups:    groups,
+			expiresAt: time.Now().Add(c.ttl),
+		}
+	}
+	c.mutex.Unlock()
+
+	return groups, nil
+}
+
+// Forget drops the groups of a session, e.g. when one of them changed.
+func (c *GroupCache) Forget(sessionID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.cache, sessionID)
+	c.forgotten++
+}
@@ -0,0 +1,115 @@
+package waclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// countingFetch returns a fetch function answering with one group and counts
+// its calls.
+func countingFetch(calls *int, err error) func(context.Context) ([]*types.GroupInfo, error) {
+	return func(context.Context) ([]*types.GroupInfo, error) {
+		*calls++
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*types.GroupInfo{{JID: types.NewJID("120363000000000000", types.GroupServer)}}, nil
+	}
+}
+
+func expectFetches(t *testing.T, cache *GroupCache, sessionID string, calls *int, want int) {
+	t.Helper()
+
+	groups, err := cache.Joined(context.Background(), sessionID, countingFetch(calls, nil))
+	if err != nil {
+		t.Fatalf("Joined: %v", err)
+	}
+
+	if len(groups) != 1 {
+		t.Fatalf("Joined returned %d groups, want 1", len(groups))
+	}
+
+	if *calls != want {
+		t.Fatalf("fetched %d times, want %d", *calls, want)
+	}
+}
+
+func TestGroupCacheReusesGroups(t *testing.T) {
+	cache := NewGroupCache()
+
+	var calls int
+
+	expectFetches(t, cache, "session-1", &calls, 1)
+	expectFetches(t, cache, "session-1", &calls, 1)
+
+	// Sessions are cached apart.
+	expectFetches(t, cache, "session-2", &calls, 2)
+	expectFetches(t, cache, "session-1", &calls, 2)
+}
+
+func TestGroupCacheForget(t *testing.T) {
+	cache := NewGroupCache()
+
+	var calls int
+
+	expectFetches(t, cache, "session-1", &calls, 1)
+	expectFetches(t, cache, "session-2", &calls, 2)
+
+	cache.Forget("session-1")
+
+	expectFetches(t, cache, "session-1", &calls, 3)
+	expectFetches(t, cache, "session-2", &calls, 3)
+}
+
+func TestGroupCacheExpires(t *testing.T) {
+	cache := NewGroupCache()
+
+	var calls int
+
+	expectFetches(t, cache, "session-1", &calls, 1)
+
+	entry := cache.cache["session-1"]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	cache.cache["session-1"] = entry
+
+	expectFetches(t, cache, "session-1", &calls, 2)
+	expectFetches(t, cache, "session-1", &calls, 2)
+}
+
+func TestGroupCacheDoesNotCacheFailures(t *testing.T) {
+	cache := NewGroupCache()
+	failure := errors.New("not connected")
+
+	var calls int
+
+	if _, err := cache.Joined(context.Background(), "session-1", countingFetch(&calls, failure)); !errors.Is(err, failure) {
+		t.Fatalf("Joined = %v, want %v", err, failure)
+	}
+
+	expectFetches(t, cache, "session-1", &calls, 2)
+}
+
+func TestGroupCacheDropsFetchesRacingWithForget(t *testing.T) {
+	cache := NewGroupCache()
+
+	var calls int
+
+	// A group changes while the list is being fetched, so the fetched list
+	// may already be stale.
+	_, err := cache.Joined(context.Background(), "session-1", func(ctx context.Context) ([]*types.GroupInfo, error) {
+		cache.Forget("session-1")
+		return countingFetch(&calls, nil)(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Joined: %v", err)
+	}
+
+	expectFetches(t, cache, "session-1", &calls, 2)
+	expectFetches(t, cache, "session-1", &calls, 2)
+}
@@ -134,6 +134,8 @@ func (gs *GroupService) JoinGroup(ctx context.Context, sessionID string, code st
 		return fmt.Errorf("failed to join group: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	return nil
 }
 func (gs *GroupService) CreateGroup(ctx context.Context, sessionID string, name string, participants []string) (*dto.WhatsAppGroupInfo, error) {
@@ -171,6 +173,8 @@ func (gs *GroupService) CreateGroup(ctx context.Context, sessionID string, name
 		return nil, fmt.Errorf("failed to create group: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	participantStrings := make([]string, len(group.Participants))
 	for i, p := range group.Participants {
 		participantStrings[i] = p.JID.String()
@@ -202,6 +206,8 @@ func (gs *GroupService) LeaveGroup(ctx context.Context, sessionID string, groupJ
 		return fmt.Errorf("failed to leave group: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	return nil
 }
 func (gs *GroupService) UpdateGroupParticipants(ctx context.Context, sessionID string, groupJID string, participants []string, action string) error {
@@ -249,6 +255,8 @@ func (gs *GroupService) UpdateGroupParticipants(ctx context.Context, sessionID s
 		return fmt.Errorf("failed to update group participants: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	return nil
 }
 func (gs *GroupService) SetGroupName(ctx context.Context, sessionID string, groupJID string, name string) error {
@@ -271,6 +279,8 @@ func (gs *GroupService) SetGroupName(ctx context.Context, sessionID string, grou
 		return fmt.Errorf("failed to set group name: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	return nil
 }
 func (gs *GroupService) SetGroupTopic(ctx context.Context, sessionID string, groupJID string, topic string) error {
@@ -289,6 +299,8 @@ func (gs *GroupService) SetGroupTopic(ctx context.Context, sessionID string, gro
 		return fmt.Errorf("failed to set group topic: %w", err)
 	}
 
+	gs.waClient.groups.Forget(sessionID)
+
 	return nil
 }
 func (gs *GroupService) SetGroupLocked(ctx context.Context, sessionID string, groupJID string, locked bool) error {
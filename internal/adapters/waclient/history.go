@@ -0,0 +1,212 @@
+package waclient
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/webhook"
+
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	HistorySyncPageSize = 100
+	DefaultHistoryCount = 50
+)
+
+// rawHistoryMessage and rawHistoryConversation keep the history layout used
+// before the normalized schema, for webhooks in raw format.
+type rawHistoryMessage struct {
+	MessageInfo *webhook.MessageInfo `json:"messageInfo"`
+	Message     interface{}          `json:"message"`
+}
+
+type rawHistoryConversation struct {
+	*webhook.HistorySyncConversation
+	Messages []*rawHistoryMessage `json:"messages"`
+}
+
+type rawHistoryBatch struct {
+	*webhook.HistorySyncBatch
+	Conversations []*rawHistoryConversation `json:"conversations"`
+}
+
+type historyEntry struct {
+	conversation *waHistorySync.Conversation
+	chat         types.JID
+	message      *events.Message
+}
+
+func (eh *DefaultEventHandler) handleHistorySync(client *Client, evt *events.HistorySync) error {
+	entries := eh.decodeHistorySync(client, evt.Data)
+
+	eh.logger.Info().
+		Str("session_id", client.SessionID).
+		Str("sync_type", evt.Data.GetSyncType().String()).
+		Int("conversations", len(evt.Data.GetConversations())).
+		Int("messages", len(entries)).
+		Msg("History sync received")
+
+	eh.persistHistory(client, entries)
+	eh.syncUnreadCounts(client, evt.Data)
+
+	// Large syncs are split into many webhook pages; queuing them must not
+	// hold up the whatsmeow event loop.
+	go eh.emitHistoryBatches(client, evt.Data, entries)
+
+	return nil
+}
+
+func (eh *DefaultEventHandler) decodeHistorySync(client *Client, data *waHistorySync.HistorySync) []*historyEntry {
+	var entries []*historyEntry
+
+	for _, conv := range data.GetConversations() {
+		chatJID, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			eh.logger.Warn().Err(err).Str("session_id", client.SessionID).Str("chat", conv.GetID()).Msg("Skipping history conversation with invalid JID")
+			continue
+		}
+
+		for _, historyMsg := range conv.GetMessages() {
+			webMsg := historyMsg.GetMessage()
+			if webMsg == nil || webMsg.GetMessage() == nil {
+				continue
+			}
+
+			parsed, err := client.WAClient.ParseWebMessage(chatJID, webMsg)
+			if err != nil {
+				eh.logger.Debug().Err(err).Str("session_id", client.SessionID).Str("chat", chatJID.String()).Msg("Skipping undecodable history message")
+				continue
+			}
+
+			entries = append(entries, &historyEntry{
+				conversation: conv,
+				chat:         chatJID,
+				message:      parsed,
+			})
+		}
+	}
+
+	return entries
+}
+
+func (eh *DefaultEventHandler) persistHistory(client *Client, entries []*historyEntry) {
+	if eh.messageRepo == nil || len(entries) == 0 {
+		return
+	}
+
+	messages := make([]*message.Message, 0, len(entries))
+	for _, entry := range entries {
+		messages = append(messages, newDomainMessage(client.SessionID, entry.message))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	inserted, err := eh.messageRepo.CreateBatch(ctx, messages)
+	if err != nil {
+		eh.logger.Error().Err(err).Str("session_id", client.SessionID).Int("messages", len(messages)).Msg("Failed to persist history sync messages")
+		return
+	}
+
+	eh.logger.Debug().
+		Str("session_id", client.SessionID).
+		Int("received", len(messages)).
+		Int("inserted", inserted).
+		Msg("History sync messages persisted")
+}
+
+func (eh *DefaultEventHandler) emitHistoryBatches(client *Client, data *waHistorySync.HistorySync, entries []*historyEntry) {
+	totalPages := (len(entries) + HistorySyncPageSize - 1) / HistorySyncPageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	for page := 0; page < totalPages; page++ {
+		start := page * HistorySyncPageSize
+		end := min(start+HistorySyncPageSize, len(entries))
+
+		batch := &webhook.HistorySyncBatch{
+			SyncType:      data.GetSyncType().String(),
+			ChunkOrder:    data.GetChunkOrder(),
+			Progress:      data.GetProgress(),
+			Page:          page + 1,
+			TotalPages:    totalPages,
+			TotalMessages: len(entries),
+		}
+
+		conversations, rawConversations := groupHistoryEntries(entries[start:end])
+		batch.Conversations = conversations
+
+		payload := &rawPayload{
+			normalized: batch,
+			raw: &rawHistoryBatch{
+				HistorySyncBatch: batch,
+				Conversations:    rawConversations,
+			},
+		}
+
+		if err := eh.sendWebhookIfEnabled(client, EventHistorySync, payload); err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", client.SessionID).
+				Int("page", batch.Page).
+				Int("total_pages", totalPages).
+				Msg("Failed to send history sync batch")
+		}
+	}
+}
+
+// groupHistoryEntries groups messages by chat, both in the normalized and in
+// the raw layout.
+func groupHistoryEntries(entries []*historyEntry) ([]*webhook.HistorySyncConversation, []*rawHistoryConversation) {
+	conversations := make([]*webhook.HistorySyncConversation, 0)
+	rawConversations := make([]*rawHistoryConversation, 0)
+	byChat := make(map[string]int)
+
+	for _, entry := range entries {
+		chat := entry.chat.String()
+
+		index, ok := byChat[chat]
+		if !ok {
+			conv := &webhook.HistorySyncConversation{
+				JID:         chat,
+				Name:        entry.conversation.GetName(),
+				UnreadCount: entry.conversation.GetUnreadCount(),
+				Archived:    entry.conversation.GetArchived(),
+				Pinned:      entry.conversation.GetPinned() > 0,
+			}
+
+			index = len(conversations)
+			byChat[chat] = index
+			conversations = append(conversations, conv)
+			rawConversations = append(rawConversations, &rawHistoryConversation{HistorySyncConversation: conv})
+		}
+
+		messagePayload := newMessagePayload(entry.message)
+
+		conversations[index].Messages = append(conversations[index].Messages, messagePayload)
+		rawConversations[index].Messages = append(rawConversations[index].Messages, &rawHistoryMessage{
+			MessageInfo: messagePayload.MessageInfo,
+			Message:     entry.message.Message,
+		})
+	}
+
+	return conversations, rawConversations
+}
+
+func newMessageInfo(evt *events.Message) *webhook.MessageInfo {
+	return &webhook.MessageInfo{
+		ID:        evt.Info.ID,
+		Chat:      evt.Info.Chat.String(),
+		Sender:    evt.Info.Sender.String(),
+		PushName:  evt.Info.PushName,
+		Timestamp: evt.Info.Timestamp,
+		FromMe:    evt.Info.IsFromMe,
+		Type:      getMessageType(evt.Message),
+		IsGroup:   evt.Info.IsGroup,
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/chat"
+	"zpwoot/internal/core/domain/message"
 	"zpwoot/internal/core/domain/session"
 	"zpwoot/internal/core/domain/webhook"
 	"zpwoot/internal/core/ports/output"
@@ -37,8 +39,15 @@ type WAClient struct {
 	container     *sqlstore.Container
 	logger        *logger.Logger
 	eventHandler  EventHandler
-	webhookSender WebhookSender
 	sessionRepo   SessionRepository
+	messageRepo   message.Repository
+	chatRepo      chat.Repository
+	numbers       *NumberResolver
+	groups        *GroupCache
+	sendQueue     *SendQueue
+	media         *MediaStore
+	transcoder    output.MediaTranscoder
+	quota         output.SendQuota
 }
 
 type SessionRepository interface {
@@ -50,7 +59,7 @@ type SessionRepository interface {
 	List(ctx context.Context, limit, offset int) ([]*session.Session, error)
 }
 
-func NewWAClient(container *sqlstore.Container, logger *logger.Logger, sessionRepo SessionRepository, webhookSender output.WebhookSender, webhookRepo webhook.Repository) *WAClient {
+func NewWAClient(container *sqlstore.Container, logger *logger.Logger, sessionRepo SessionRepository, webhookSender output.WebhookSender, webhookRepo webhook.Repository, messageRepo message.Repository, chatRepo chat.Repository) *WAClient {
 	store.DeviceProps.PlatformType = waCompanionReg.DeviceProps_UNKNOWN.Enum()
 	store.DeviceProps.Os = proto.String(runtime.GOOS)
 
@@ -59,16 +68,55 @@ func NewWAClient(container *sqlstore.Container, logger *logger.Logger, sessionRe
 		container:   container,
 		logger:      logger,
 		sessionRepo: sessionRepo,
+		messageRepo: messageRepo,
+		chatRepo:    chatRepo,
+		numbers:     NewNumberResolver(),
+		groups:      NewGroupCache(),
 	}
+	wac.sendQueue = NewSendQueue(DefaultSendQueueConfig(), logger, wac.messageSent)
 
 	if webhookSender != nil && webhookRepo != nil {
-		wac.eventHandler = NewDefaultEventHandler(logger, webhookSender, webhookRepo)
+		wac.eventHandler = NewDefaultEventHandler(logger, webhookSender, webhookRepo, messageRepo, chatRepo)
 	}
 
 	go wac.loadSessionsFromDatabase()
 	return wac
 }
 
+// SetMessageSync registers the receiver of stored incoming messages. It must
+// be called before sessions start receiving events.
+func (wac *WAClient) SetMessageSync(messageSync MessageSync) {
+	if handler, ok := wac.eventHandler.(*DefaultEventHandler); ok {
+		handler.messageSync = messageSync
+	}
+}
+
+// SetReceiptSync registers the receiver of delivery and read receipts. It
+// must be called before sessions start receiving events.
+func (wac *WAClient) SetReceiptSync(receiptSync ReceiptSync) {
+	if handler, ok := wac.eventHandler.(*DefaultEventHandler); ok {
+		handler.receiptSync = receiptSync
+	}
+}
+
+// SetSendQueueConfig replaces the pacing of outgoing messages. It must be
+// called before any message is sent.
+func (wac *WAClient) SetSendQueueConfig(config SendQueueConfig) {
+	wac.sendQueue = NewSendQueue(config, wac.logger, wac.messageSent)
+}
+
+// SetMediaTranscoder enables converting and previewing media before it is
+// sent. It must be called before any message is sent.
+func (wac *WAClient) SetMediaTranscoder(transcoder output.MediaTranscoder) {
+	wac.transcoder = transcoder
+}
+
+// SetSendQuota enables the daily message quotas of tenants. It must be
+// called before any message is sent.
+func (wac *WAClient) SetSendQuota(quota output.SendQuota) {
+	wac.quota = quota
+}
+
 func (wac *WAClient) loadSessionsFromDatabase() {
 	ctx := context.Background()
 	sessions, err := wac.sessionRepo.List(ctx, 1000, 0)
@@ -95,7 +143,11 @@ func (wac *WAClient) loadSessionsFromDatabase() {
 			continue
 		}
 
-		client := wac.createClient(ctx, sess, deviceStore)
+		client, err := wac.createClient(ctx, sess, deviceStore)
+		if err != nil {
+			wac.logger.Error().Err(err).Str("session_id", sess.ID).Msg("Failed to create client, skipping session")
+			continue
+		}
 
 		wac.sessionsMutex.Lock()
 		wac.sessions[sess.ID] = client
@@ -128,7 +180,9 @@ func (wac *WAClient) autoReconnect(client *Client) {
 	wac.updateSessionStatus(client.ctx, client)
 }
 
-func (wac *WAClient) createClient(ctx context.Context, sess *session.Session, deviceStore *store.Device) *Client {
+// createClient builds the client of a session. It fails when the session's
+// proxy cannot be applied, rather than connecting without it.
+func (wac *WAClient) createClient(ctx context.Context, sess *session.Session, deviceStore *store.Device) (*Client, error) {
 	waClient := whatsmeow.NewClient(deviceStore, waLog.Noop)
 	clientCtx, cancel := context.WithCancel(ctx)
 
@@ -141,6 +195,7 @@ func (wac *WAClient) createClient(ctx context.Context, sess *session.Session, de
 		QRExpiresAt: getTimeValue(sess.QRCodeExpiresAt),
 		ConnectedAt: getTimeValue(sess.ConnectedAt),
 		LastSeen:    getTimeValue(sess.LastSeen),
+		ProxyConfig: sess.ProxyConfig,
 		Config: &SessionConfig{
 			SessionID: sess.ID,
 			Name:      sess.Name,
@@ -149,8 +204,79 @@ func (wac *WAClient) createClient(ctx context.Context, sess *session.Session, de
 		cancel: cancel,
 	}
 
+	if sess.ProxyConfig != nil {
+		if err := wac.applyProxy(client, sess); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to apply proxy configuration: %w", err)
+		}
+	}
+
 	client.EventHandler = waClient.AddEventHandler(wac.createEventHandler(client))
-	return client
+	return client, nil
+}
+
+func (wac *WAClient) applyProxy(client *Client, sess *session.Session) error {
+	proxy, err := sess.GetProxy()
+	if err != nil {
+		return err
+	}
+
+	if proxy == nil {
+		client.WAClient.SetProxy(nil)
+		return nil
+	}
+
+	if err := client.WAClient.SetProxyAddress(proxy.URL()); err != nil {
+		return fmt.Errorf("failed to set proxy address: %w", err)
+	}
+
+	wac.logger.Info().
+		Str("session_id", sess.ID).
+		Str("proxy_type", string(proxy.Type)).
+		Str("proxy_host", proxy.Host).
+		Msg("Proxy configured for session")
+
+	return nil
+}
+
+// ReloadProxy re-reads the proxy configuration of a session and applies it to
+// the websocket and media transports. A connected session is disconnected and
+// connected again, since whatsmeow only picks up proxy changes on Connect.
+func (wac *WAClient) ReloadProxy(ctx context.Context, sessionID string) error {
+	sess, err := wac.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session from database: %w", err)
+	}
+
+	client, err := wac.GetSession(ctx, sessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	client.ProxyConfig = sess.ProxyConfig
+
+	wasConnected := client.WAClient.IsConnected()
+	if wasConnected {
+		client.WAClient.Disconnect()
+	}
+
+	if err := wac.applyProxy(client, sess); err != nil {
+		return err
+	}
+
+	if !wasConnected {
+		return nil
+	}
+
+	wac.logger.Info().Str("session_id", sessionID).Msg("Reconnecting session to apply proxy configuration")
+
+	if client.WAClient.Store.ID == nil {
+		return wac.connectNewSession(ctx, client)
+	}
+
+	return wac.reconnectExistingSession(ctx, client)
 }
 
 func getTimeValue(t *time.Time) time.Time {
@@ -174,7 +300,11 @@ func (wac *WAClient) CreateSession(ctx context.Context, config *SessionConfig) (
 	}
 
 	deviceStore := wac.container.NewDevice()
-	client := wac.createClient(ctx, sess, deviceStore)
+	client, err := wac.createClient(ctx, sess, deviceStore)
+	if err != nil {
+		return nil, err
+	}
+
 	client.Config = config
 	client.Events = config.Events
 	client.WebhookURL = config.WebhookURL
@@ -279,7 +409,10 @@ func (wac *WAClient) getOrRecreateClient(ctx context.Context, sess *session.Sess
 			deviceStore = wac.container.NewDevice()
 		}
 
-		client = wac.createClient(ctx, sess, deviceStore)
+		client, err = wac.createClient(ctx, sess, deviceStore)
+		if err != nil {
+			return nil, err
+		}
 
 		wac.sessionsMutex.Lock()
 		wac.sessions[sess.ID] = client
@@ -385,6 +518,7 @@ func (wac *WAClient) LogoutSession(ctx context.Context, sessionID string) error
 	client.cancel()
 	client.WAClient.RemoveEventHandler(client.EventHandler)
 	delete(wac.sessions, sessionID)
+	wac.groups.Forget(sessionID)
 
 	now := time.Now()
 	sess := &session.Session{
@@ -393,6 +527,7 @@ func (wac *WAClient) LogoutSession(ctx context.Context, sessionID string) error
 		DeviceJID:   "",
 		IsConnected: false,
 		QRCode:      "",
+		ProxyConfig: client.ProxyConfig,
 		UpdatedAt:   now,
 		LastSeen:    &now,
 	}
@@ -429,6 +564,7 @@ func (wac *WAClient) DeleteSession(ctx context.Context, sessionID string) error
 
 	client.WAClient.RemoveEventHandler(client.EventHandler)
 	delete(wac.sessions, sessionID)
+	wac.groups.Forget(sessionID)
 
 	wac.logger.Info().Str("session_id", sessionID).Msg("Session deleted")
 
@@ -456,12 +592,11 @@ func (wac *WAClient) createEventHandler(client *Client) func(interface{}) {
 			wac.handlePresence(client, v)
 		case *events.ChatPresence:
 			wac.handleChatPresence(client, v)
+		case *events.JoinedGroup, *events.GroupInfo:
+			wac.groups.Forget(client.SessionID)
+			wac.forwardEvent(client, evt)
 		default:
-			if wac.eventHandler != nil {
-				if err := wac.eventHandler.HandleEvent(client, evt); err != nil {
-					wac.logger.Error().Err(err).Str("session_id", client.SessionID).Msg("Event handler error")
-				}
-			}
+			wac.forwardEvent(client, evt)
 		}
 	}
 }
@@ -478,7 +613,7 @@ func (wac *WAClient) handleConnected(client *Client, evt *events.Connected) {
 	go func(ctx context.Context) {
 		wac.updateSessionStatus(ctx, client)
 	}(client.ctx)
-	wac.sendWebhook(client, EventConnected, evt)
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handleDisconnected(client *Client, evt *events.Disconnected) {
@@ -490,7 +625,7 @@ func (wac *WAClient) handleDisconnected(client *Client, evt *events.Disconnected
 	go func() {
 		wac.updateSessionStatus(context.Background(), client)
 	}()
-	wac.sendWebhook(client, EventDisconnected, evt)
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handleLoggedOut(client *Client, evt *events.LoggedOut) {
@@ -499,10 +634,12 @@ func (wac *WAClient) handleLoggedOut(client *Client, evt *events.LoggedOut) {
 
 	wac.logger.Info().Str("session_id", client.SessionID).Msg("Logged out")
 
+	wac.groups.Forget(client.SessionID)
+
 	go func() {
 		wac.updateSessionStatus(context.Background(), client)
 	}()
-	wac.sendWebhook(client, EventLoggedOut, evt)
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handlePairSuccess(client *Client, evt *events.PairSuccess) {
@@ -521,11 +658,7 @@ func (wac *WAClient) handlePairSuccess(client *Client, evt *events.PairSuccess)
 		return
 	}
 
-	wac.sendWebhook(client, EventPairSuccess, map[string]interface{}{
-		"deviceJID":    evt.ID.String(),
-		"businessName": evt.BusinessName,
-		"platform":     evt.Platform,
-	})
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handleQREvent(client *Client, evt *events.QR) {
@@ -549,31 +682,19 @@ func (wac *WAClient) sendQRWebhook(client *Client, code string) {
 		Code:      code,
 		ExpiresAt: client.QRExpiresAt,
 	}
-	wac.sendWebhook(client, EventQR, qrEvent)
+	wac.forwardEvent(client, qrEvent)
 }
 
 func (wac *WAClient) handleMessage(client *Client, evt *events.Message) {
 	client.LastSeen = time.Now()
 
-	wac.sendWebhook(client, EventMessage, evt)
-
-	if wac.eventHandler != nil {
-		if err := wac.eventHandler.HandleEvent(client, evt); err != nil {
-			wac.logger.Error().Err(err).Str("session_id", client.SessionID).Msg("Event handler error for message")
-		}
-	}
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handleReceipt(client *Client, evt *events.Receipt) {
 	client.LastSeen = time.Now()
 
-	wac.sendWebhook(client, EventReceipt, evt)
-
-	if wac.eventHandler != nil {
-		if err := wac.eventHandler.HandleEvent(client, evt); err != nil {
-			wac.logger.Error().Err(err).Str("session_id", client.SessionID).Msg("Event handler error for receipt")
-		}
-	}
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handlePresence(client *Client, evt *events.Presence) {
@@ -590,7 +711,7 @@ func (wac *WAClient) handlePresence(client *Client, evt *events.Presence) {
 		Str("status", status).
 		Msg("Presence update")
 
-	wac.sendWebhook(client, EventPresence, evt)
+	wac.forwardEvent(client, evt)
 }
 
 func (wac *WAClient) handleChatPresence(client *Client, evt *events.ChatPresence) {
@@ -604,26 +725,42 @@ func (wac *WAClient) handleChatPresence(client *Client, evt *events.ChatPresence
 		Str("media", string(evt.Media)).
 		Msg("Chat presence update")
 
-	wac.sendWebhook(client, EventChatPresence, evt)
+	wac.forwardEvent(client, evt)
 }
 
-func (wac *WAClient) sendWebhook(client *Client, eventType EventType, event interface{}) {
-	if wac.webhookSender == nil || client.WebhookURL == "" {
+// forwardEvent hands an event to the event handler, which stores it and
+// turns it into webhooks.
+func (wac *WAClient) forwardEvent(client *Client, evt interface{}) {
+	if wac.eventHandler == nil {
 		return
 	}
 
-	webhookEvent := &WebhookEvent{
-		Type:      eventType,
-		SessionID: client.SessionID,
-		Event:     event,
-		Timestamp: time.Now(),
+	if err := wac.eventHandler.HandleEvent(client, evt); err != nil {
+		wac.logger.Error().Err(err).Str("session_id", client.SessionID).Str("event", fmt.Sprintf("%T", evt)).Msg("Event handler error")
 	}
+}
 
-	go func() {
-		if err := wac.webhookSender.SendWebhook(client.ctx, webhookEvent); err != nil {
-			wac.logger.Error().Err(err).Msg("Failed to send webhook")
-		}
-	}()
+// publishedEvent is an application event handed to the event handler to be
+// delivered like any WhatsApp event.
+type publishedEvent struct {
+	Type    string
+	Payload interface{}
+}
+
+// PublishEvent delivers an application event to the webhooks of a session.
+// The session does not need a live client, so events about a disconnected
+// session are still delivered.
+func (wac *WAClient) PublishEvent(ctx context.Context, sessionID, eventType string, payload interface{}) error {
+	if wac.eventHandler == nil {
+		return nil
+	}
+
+	client, err := wac.GetSession(ctx, sessionID)
+	if err != nil {
+		client = &Client{SessionID: sessionID}
+	}
+
+	return wac.eventHandler.HandleEvent(client, &publishedEvent{Type: eventType, Payload: payload})
 }
 
 func (wac *WAClient) clearQRCode(client *Client) {
@@ -644,6 +781,7 @@ func (wac *WAClient) updateSessionStatus(ctx context.Context, client *Client) {
 		DeviceJID:   deviceJID,
 		IsConnected: client.Status == session.StatusConnected,
 		QRCode:      client.QRCode,
+		ProxyConfig: client.ProxyConfig,
 		UpdatedAt:   now,
 	}
 
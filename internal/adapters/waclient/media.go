@@ -0,0 +1,500 @@
+package waclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/media"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waMmsRetry"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// mediaDownloadWorkers bounds the automatic downloads running at once.
+	mediaDownloadWorkers = 4
+	mediaDownloadTimeout = 5 * time.Minute
+
+	// mediaRetryWait is how long a re-upload request is given before a new
+	// request for the media asks the phone again.
+	mediaRetryWait = time.Minute
+)
+
+// MediaConfig controls how the files of media messages are kept.
+type MediaConfig struct {
+	// AutoDownload downloads inbound media as soon as it arrives. Otherwise
+	// files are only downloaded when requested.
+	AutoDownload bool
+	// MaxSize is the largest file downloaded, in bytes; 0 means no limit.
+	MaxSize int64
+	// BaseURL prefixes the mediaUrl of webhook payloads. Without it the URL
+	// is relative to the API.
+	BaseURL string
+	// URLExpiry is how long presigned URLs given in webhooks stay valid, for
+	// blob stores that can presign.
+	URLExpiry time.Duration
+}
+
+// downloadableMedia is the part of a message holding a media file.
+type downloadableMedia interface {
+	proto.Message
+	whatsmeow.DownloadableMessage
+	GetMimetype() string
+	GetFileLength() uint64
+}
+
+// MediaStore records the media of incoming messages and downloads their
+// files into a blob store.
+type MediaStore struct {
+	repo   media.Repository
+	blobs  output.BlobStore
+	config MediaConfig
+	logger *logger.Logger
+	slots  chan struct{}
+	// publish hands MediaStored events to the event handler.
+	publish func(client *Client, evt interface{})
+}
+
+func NewMediaStore(repo media.Repository, blobs output.BlobStore, config MediaConfig, logger *logger.Logger) *MediaStore {
+	return &MediaStore{
+		repo:   repo,
+		blobs:  blobs,
+		config: config,
+		logger: logger,
+		slots:  make(chan struct{}, mediaDownloadWorkers),
+	}
+}
+
+// SetMediaStore enables recording and downloading media. It must be called
+// before sessions start receiving events.
+func (wac *WAClient) SetMediaStore(store *MediaStore) {
+	wac.media = store
+	store.publish = wac.forwardEvent
+
+	if handler, ok := wac.eventHandler.(*DefaultEventHandler); ok {
+		handler.media = store
+	}
+}
+
+// FetchMedia downloads the file of a media message now, unless it is
+// already stored or a re-upload was just requested from the phone.
+func (wac *WAClient) FetchMedia(ctx context.Context, sessionID, messageID string) error {
+	if wac.media == nil {
+		return shared.ErrMediaNotFound
+	}
+
+	m, err := wac.media.repo.GetByMessageID(ctx, sessionID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if m.IsStored() {
+		return nil
+	}
+
+	if m.Status == media.StatusRetrying && time.Since(m.UpdatedAt) < mediaRetryWait {
+		return shared.ErrMediaRetrying
+	}
+
+	client, err := wac.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if !client.IsConnected() {
+		return ErrNotConnected
+	}
+
+	return wac.media.download(ctx, client, m)
+}
+
+// URL is where the API serves the file of a media message.
+func (ms *MediaStore) URL(sessionID, messageID string) string {
+	return strings.TrimSuffix(ms.config.BaseURL, "/") + "/sessions/" + sessionID + "/media/" + url.PathEscape(messageID)
+}
+
+// record stores the media of a message and reports whether it was new. It
+// returns nil for messages without media.
+func (ms *MediaStore) record(sessionID string, evt *events.Message) (*media.Media, bool) {
+	m, err := newMediaRecord(sessionID, evt.Info.ID, evt.Info.MessageSource, evt.Message)
+	if m == nil || err != nil {
+		if err != nil {
+			ms.logger.Error().Err(err).Str("session_id", sessionID).Str("message_id", evt.Info.ID).Msg("Failed to encode media")
+		}
+
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	inserted, err := ms.repo.Create(ctx, m)
+	if err != nil {
+		ms.logger.Error().Err(err).Str("session_id", sessionID).Str("message_id", evt.Info.ID).Msg("Failed to store media")
+		return nil, false
+	}
+
+	return m, inserted
+}
+
+// storeSentLater keeps the file of a media message this session sent, so
+// it can be served like received media.
+func (ms *MediaStore) storeSentLater(client *Client, to types.JID, messageID string, msg *waE2E.Message, data []byte) {
+	m := ms.sentRecord(client, to, messageID, msg)
+	if m == nil {
+		return
+	}
+
+	go func() {
+		ms.slots <- struct{}{}
+		defer func() { <-ms.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mediaDownloadTimeout)
+		defer cancel()
+
+		if err := ms.storeSent(ctx, m, bytes.NewReader(data), int64(len(data))); err != nil {
+			ms.logger.Warn().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to store sent media")
+		}
+	}()
+}
+
+// storeSentFile is storeSentLater for media streamed to a file, which is
+// stored before the file goes away.
+func (ms *MediaStore) storeSentFile(ctx context.Context, client *Client, to types.JID, messageID string, msg *waE2E.Message, file io.ReadSeeker, size int64) {
+	m := ms.sentRecord(client, to, messageID, msg)
+	if m == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mediaDownloadTimeout)
+	defer cancel()
+
+	_, err := file.Seek(0, io.SeekStart)
+	if err == nil {
+		err = ms.storeSent(ctx, m, file, size)
+	}
+
+	if err != nil {
+		ms.logger.Warn().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to store sent media")
+	}
+}
+
+func (ms *MediaStore) sentRecord(client *Client, to types.JID, messageID string, msg *waE2E.Message) *media.Media {
+	source := types.MessageSource{
+		Chat:     to,
+		IsFromMe: true,
+		IsGroup:  to.Server == types.GroupServer,
+	}
+
+	if client.WAClient.Store.ID != nil {
+		source.Sender = client.WAClient.Store.ID.ToNonAD()
+	}
+
+	m, err := newMediaRecord(client.SessionID, messageID, source, msg)
+	if err != nil {
+		return nil
+	}
+
+	return m
+}
+
+func (ms *MediaStore) storeSent(ctx context.Context, m *media.Media, r io.Reader, size int64) error {
+	if _, err := ms.repo.Create(ctx, m); err != nil {
+		return err
+	}
+
+	if err := ms.blobs.Put(ctx, m.Key(), r, size, m.MimeType); err != nil {
+		return fmt.Errorf("failed to store media file: %w", err)
+	}
+
+	m.MarkStored(m.Key(), size)
+
+	return ms.repo.Save(ctx, m)
+}
+
+// downloadLater downloads media in the background, a few files at a time,
+// and announces the stored file with a MediaStored event.
+func (ms *MediaStore) downloadLater(client *Client, m *media.Media) {
+	go func() {
+		ms.slots <- struct{}{}
+		defer func() { <-ms.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mediaDownloadTimeout)
+		defer cancel()
+
+		if err := ms.download(ctx, client, m); err != nil {
+			if !errors.Is(err, shared.ErrMediaRetrying) {
+				ms.logger.Warn().
+					Err(err).
+					Str("session_id", m.SessionID).
+					Str("message_id", m.MessageID).
+					Msg("Failed to download media")
+			}
+
+			return
+		}
+
+		if ms.publish != nil {
+			ms.publish(client, &publishedEvent{
+				Type:    string(EventMediaStored),
+				Payload: ms.storedPayload(ctx, m),
+			})
+		}
+	}()
+}
+
+// storedPayload describes a stored file. Its URL links straight to the blob
+// store when it can presign URLs, and to the API otherwise.
+func (ms *MediaStore) storedPayload(ctx context.Context, m *media.Media) *webhook.MediaStoredPayload {
+	payload := &webhook.MediaStoredPayload{
+		MessageID: m.MessageID,
+		Chat:      m.Chat,
+		Sender:    m.Sender,
+		Type:      m.Type,
+		MimeType:  m.MimeType,
+		Size:      m.Size,
+		MediaURL:  ms.URL(m.SessionID, m.MessageID),
+	}
+
+	presigner, ok := ms.blobs.(output.BlobPresigner)
+	if !ok {
+		return payload
+	}
+
+	url, err := presigner.PresignGet(ctx, m.StorageKey, ms.config.URLExpiry)
+	if err != nil {
+		ms.logger.Warn().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to presign media URL")
+		return payload
+	}
+
+	expiresAt := time.Now().Add(ms.config.URLExpiry)
+	payload.MediaURL = url
+	payload.ExpiresAt = &expiresAt
+
+	return payload
+}
+
+// download fetches and decrypts the file of media and puts it in the blob
+// store. Media gone from the WhatsApp servers is requested again from the
+// phone; the answer arrives as a MediaRetry event.
+func (ms *MediaStore) download(ctx context.Context, client *Client, m *media.Media) error {
+	if ms.config.MaxSize > 0 && m.Size > ms.config.MaxSize {
+		return shared.ErrMediaTooLarge
+	}
+
+	file, err := decodeMedia(m)
+	if err != nil {
+		return err
+	}
+
+	data, err := client.WAClient.Download(ctx, file)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith403),
+		errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith404),
+		errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith410):
+		return ms.requestRetry(ctx, client, m, file)
+	default:
+		ms.fail(ctx, m, err.Error())
+		return fmt.Errorf("%w: %w", shared.ErrMediaFailed, err)
+	}
+
+	if err := ms.blobs.Put(ctx, m.Key(), bytes.NewReader(data), int64(len(data)), m.MimeType); err != nil {
+		return fmt.Errorf("failed to store media file: %w", err)
+	}
+
+	m.MarkStored(m.Key(), int64(len(data)))
+
+	if err := ms.repo.Save(ctx, m); err != nil {
+		return err
+	}
+
+	ms.logger.Debug().
+		Str("session_id", m.SessionID).
+		Str("message_id", m.MessageID).
+		Int("size", len(data)).
+		Msg("Media downloaded")
+
+	return nil
+}
+
+func (ms *MediaStore) requestRetry(ctx context.Context, client *Client, m *media.Media, file downloadableMedia) error {
+	chat, err := types.ParseJID(m.Chat)
+	if err != nil {
+		return fmt.Errorf("failed to parse media chat: %w", err)
+	}
+
+	sender, _ := types.ParseJID(m.Sender)
+
+	info := &types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     chat,
+			Sender:   sender,
+			IsFromMe: m.FromMe,
+			IsGroup:  m.IsGroup,
+		},
+		ID: m.MessageID,
+	}
+
+	if err := client.WAClient.SendMediaRetryReceipt(info, file.GetMediaKey()); err != nil {
+		ms.fail(ctx, m, err.Error())
+		return fmt.Errorf("failed to request media re-upload: %w", err)
+	}
+
+	m.MarkRetrying()
+
+	if err := ms.repo.Save(ctx, m); err != nil {
+		return err
+	}
+
+	return shared.ErrMediaRetrying
+}
+
+// retried handles the phone's answer to a re-upload request: the file is
+// downloaded again from the path it was re-uploaded to.
+func (ms *MediaStore) retried(client *Client, evt *events.MediaRetry) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := ms.repo.GetByMessageID(ctx, client.SessionID, evt.MessageID)
+	if err != nil || m.Status != media.StatusRetrying {
+		return
+	}
+
+	file, err := decodeMedia(m)
+	if err != nil {
+		ms.fail(ctx, m, err.Error())
+		return
+	}
+
+	notification, err := whatsmeow.DecryptMediaRetryNotification(evt, file.GetMediaKey())
+	if err != nil {
+		ms.fail(ctx, m, err.Error())
+		return
+	}
+
+	if notification.GetResult() != waMmsRetry.MediaRetryNotification_SUCCESS || notification.GetDirectPath() == "" {
+		ms.fail(ctx, m, "phone could not re-upload the media: "+notification.GetResult().String())
+		return
+	}
+
+	// The old URL points at the expired upload, so only the new path is kept.
+	fields := file.ProtoReflect().Descriptor().Fields()
+	file.ProtoReflect().Clear(fields.ByName("URL"))
+	file.ProtoReflect().Set(fields.ByName("directPath"), protoreflect.ValueOfString(notification.GetDirectPath()))
+
+	if m.Descriptor, err = proto.Marshal(file); err != nil {
+		ms.fail(ctx, m, err.Error())
+		return
+	}
+
+	m.Status = media.StatusPending
+
+	if err := ms.repo.Save(ctx, m); err != nil {
+		ms.logger.Error().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to save re-uploaded media")
+		return
+	}
+
+	ms.downloadLater(client, m)
+}
+
+func (ms *MediaStore) fail(ctx context.Context, m *media.Media, reason string) {
+	m.Fail(reason)
+
+	if err := ms.repo.Save(ctx, m); err != nil {
+		ms.logger.Error().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to save media")
+	}
+}
+
+// newMediaRecord describes the media of a message, or returns nil when the
+// message has none.
+func newMediaRecord(sessionID, messageID string, source types.MessageSource, msg *waE2E.Message) (*media.Media, error) {
+	file, mediaType := mediaOf(msg)
+	if file == nil {
+		return nil, nil
+	}
+
+	descriptor, err := proto.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+
+	m := media.NewMedia(
+		sessionID,
+		messageID,
+		source.Chat.String(),
+		source.Sender.String(),
+		mediaType,
+		source.IsFromMe,
+		source.IsGroup,
+		descriptor,
+	)
+	m.MimeType = file.GetMimetype()
+	m.Size = int64(file.GetFileLength())
+	m.SHA256 = encodeHash(file.GetFileSHA256())
+
+	if document := msg.GetDocumentMessage(); document != nil {
+		m.FileName = document.GetFileName()
+	}
+
+	return m, nil
+}
+
+// mediaOf returns the media file of a message and its type.
+func mediaOf(msg *waE2E.Message) (downloadableMedia, string) {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage(), "image"
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage(), "video"
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage(), "audio"
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage(), "document"
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage(), "sticker"
+	default:
+		return nil, ""
+	}
+}
+
+func decodeMedia(m *media.Media) (downloadableMedia, error) {
+	var file downloadableMedia
+
+	switch m.Type {
+	case "image":
+		file = &waE2E.ImageMessage{}
+	case "video":
+		file = &waE2E.VideoMessage{}
+	case "audio":
+		file = &waE2E.AudioMessage{}
+	case "document":
+		file = &waE2E.DocumentMessage{}
+	case "sticker":
+		file = &waE2E.StickerMessage{}
+	default:
+		return nil, fmt.Errorf("unknown media type %q", m.Type)
+	}
+
+	if err := proto.Unmarshal(m.Descriptor, file); err != nil {
+		return nil, fmt.Errorf("failed to decode media: %w", err)
+	}
+
+	return file, nil
+}
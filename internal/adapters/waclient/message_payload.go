@@ -0,0 +1,250 @@
+package waclient
+
+import (
+	"encoding/base64"
+
+	"zpwoot/internal/core/domain/webhook"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func getMessageType(msg *waE2E.Message) string {
+	switch {
+	case msg == nil:
+		return unknownMessageType
+	case msg.Conversation != nil || msg.GetExtendedTextMessage() != nil:
+		return "text"
+	case msg.GetImageMessage() != nil:
+		return "image"
+	case msg.GetAudioMessage() != nil:
+		return "audio"
+	case msg.GetVideoMessage() != nil:
+		return "video"
+	case msg.GetDocumentMessage() != nil:
+		return "document"
+	case msg.GetStickerMessage() != nil:
+		return "sticker"
+	case msg.GetLocationMessage() != nil:
+		return "location"
+	case msg.GetLiveLocationMessage() != nil:
+		return "liveLocation"
+	case msg.GetContactMessage() != nil:
+		return "contact"
+	case msg.GetContactsArrayMessage() != nil:
+		return "contacts"
+	case msg.GetButtonsMessage() != nil:
+		return "buttons"
+	case msg.GetListMessage() != nil:
+		return "list"
+	case msg.GetTemplateMessage() != nil:
+		return "template"
+	case msg.GetReactionMessage() != nil:
+		return "reaction"
+	case pollCreation(msg) != nil:
+		return "poll"
+	default:
+		return unknownMessageType
+	}
+}
+
+// newMessagePayload converts a message to the normalized webhook payload.
+func newMessagePayload(evt *events.Message) *webhook.MessagePayload {
+	msg := evt.Message
+
+	payload := &webhook.MessagePayload{
+		MessageInfo: newMessageInfo(evt),
+		IsEdit:      evt.IsEdit,
+		IsViewOnce:  evt.IsViewOnce,
+		IsEphemeral: evt.IsEphemeral,
+	}
+
+	switch {
+	case msg.GetImageMessage() != nil:
+		image := msg.GetImageMessage()
+		payload.Media = &webhook.MediaBody{
+			MimeType: image.GetMimetype(),
+			Caption:  image.GetCaption(),
+			FileSize: image.GetFileLength(),
+			SHA256:   encodeHash(image.GetFileSHA256()),
+			Width:    image.GetWidth(),
+			Height:   image.GetHeight(),
+		}
+	case msg.GetVideoMessage() != nil:
+		video := msg.GetVideoMessage()
+		payload.Media = &webhook.MediaBody{
+			MimeType:   video.GetMimetype(),
+			Caption:    video.GetCaption(),
+			FileSize:   video.GetFileLength(),
+			SHA256:     encodeHash(video.GetFileSHA256()),
+			Duration:   video.GetSeconds(),
+			Width:      video.GetWidth(),
+			Height:     video.GetHeight(),
+			IsAnimated: video.GetGifPlayback(),
+		}
+	case msg.GetAudioMessage() != nil:
+		audio := msg.GetAudioMessage()
+		payload.Media = &webhook.MediaBody{
+			MimeType: audio.GetMimetype(),
+			FileSize: audio.GetFileLength(),
+			SHA256:   encodeHash(audio.GetFileSHA256()),
+			Duration: audio.GetSeconds(),
+			IsVoice:  audio.GetPTT(),
+		}
+	case msg.GetDocumentMessage() != nil:
+		document := msg.GetDocumentMessage()
+		payload.Media = &webhook.MediaBody{
+			MimeType: document.GetMimetype(),
+			Caption:  document.GetCaption(),
+			FileName: document.GetFileName(),
+			FileSize: document.GetFileLength(),
+			SHA256:   encodeHash(document.GetFileSHA256()),
+		}
+	case msg.GetStickerMessage() != nil:
+		sticker := msg.GetStickerMessage()
+		payload.Media = &webhook.MediaBody{
+			MimeType:   sticker.GetMimetype(),
+			FileSize:   sticker.GetFileLength(),
+			SHA256:     encodeHash(sticker.GetFileSHA256()),
+			Width:      sticker.GetWidth(),
+			Height:     sticker.GetHeight(),
+			IsAnimated: sticker.GetIsAnimated(),
+		}
+	case msg.GetLocationMessage() != nil:
+		location := msg.GetLocationMessage()
+		payload.Location = &webhook.LocationBody{
+			Latitude:  location.GetDegreesLatitude(),
+			Longitude: location.GetDegreesLongitude(),
+			Name:      location.GetName(),
+			Address:   location.GetAddress(),
+			URL:       location.GetURL(),
+			IsLive:    location.GetIsLive(),
+			Accuracy:  location.GetAccuracyInMeters(),
+		}
+	case msg.GetLiveLocationMessage() != nil:
+		location := msg.GetLiveLocationMessage()
+		payload.Location = &webhook.LocationBody{
+			Latitude:  location.GetDegreesLatitude(),
+			Longitude: location.GetDegreesLongitude(),
+			Caption:   location.GetCaption(),
+			IsLive:    true,
+			Accuracy:  location.GetAccuracyInMeters(),
+		}
+	case msg.GetContactMessage() != nil:
+		contact := msg.GetContactMessage()
+		payload.Contacts = []*webhook.ContactBody{{
+			DisplayName: contact.GetDisplayName(),
+			VCard:       contact.GetVcard(),
+		}}
+	case msg.GetContactsArrayMessage() != nil:
+		for _, contact := range msg.GetContactsArrayMessage().GetContacts() {
+			payload.Contacts = append(payload.Contacts, &webhook.ContactBody{
+				DisplayName: contact.GetDisplayName(),
+				VCard:       contact.GetVcard(),
+			})
+		}
+	case msg.GetReactionMessage() != nil:
+		reaction := msg.GetReactionMessage()
+		key := reaction.GetKey()
+		payload.Reaction = &webhook.ReactionBody{
+			TargetMessageID: key.GetID(),
+			TargetFromMe:    key.GetFromMe(),
+			TargetSender:    key.GetParticipant(),
+			Emoji:           reaction.GetText(),
+			Removed:         reaction.GetText() == "",
+		}
+	case pollCreation(msg) != nil:
+		poll := pollCreation(msg)
+		options := make([]string, len(poll.GetOptions()))
+		for i, option := range poll.GetOptions() {
+			options[i] = option.GetOptionName()
+		}
+
+		payload.Poll = &webhook.PollBody{
+			Name:            poll.GetName(),
+			Options:         options,
+			SelectableCount: poll.GetSelectableOptionsCount(),
+		}
+	default:
+		if text := getMessageContent(msg); text != "" || msg.Conversation != nil {
+			payload.Text = &webhook.TextBody{Body: text}
+		}
+	}
+
+	payload.Context = newMessageContext(messageContextInfo(msg))
+
+	return payload
+}
+
+// newMessageContext extracts the quoted message, mentions and forwarding
+// details. It returns nil when the message has none of them.
+func newMessageContext(info *waE2E.ContextInfo) *webhook.MessageContext {
+	if info == nil {
+		return nil
+	}
+
+	msgContext := &webhook.MessageContext{
+		QuotedMessageID: info.GetStanzaID(),
+		QuotedSender:    info.GetParticipant(),
+		Mentions:        info.GetMentionedJID(),
+		Forwarded:       info.GetIsForwarded(),
+		ForwardingScore: info.GetForwardingScore(),
+		Expiration:      info.GetExpiration(),
+	}
+
+	if quoted := info.GetQuotedMessage(); quoted != nil {
+		msgContext.QuotedType = getMessageType(quoted)
+		msgContext.QuotedText = getMessageContent(quoted)
+	}
+
+	if msgContext.QuotedMessageID == "" && len(msgContext.Mentions) == 0 && !msgContext.Forwarded && msgContext.Expiration == 0 {
+		return nil
+	}
+
+	return msgContext
+}
+func messageContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	case msg.GetLocationMessage() != nil:
+		return msg.GetLocationMessage().GetContextInfo()
+	case msg.GetLiveLocationMessage() != nil:
+		return msg.GetLiveLocationMessage().GetContextInfo()
+	case msg.GetContactMessage() != nil:
+		return msg.GetContactMessage().GetContextInfo()
+	case msg.GetContactsArrayMessage() != nil:
+		return msg.GetContactsArrayMessage().GetContextInfo()
+	case pollCreation(msg) != nil:
+		return pollCreation(msg).GetContextInfo()
+	default:
+		return nil
+	}
+}
+func pollCreation(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	default:
+		return msg.GetPollCreationMessageV3()
+	}
+}
+func encodeHash(hash []byte) string {
+	if len(hash) == 0 {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(hash)
+}
@@ -0,0 +1,99 @@
+package waclient
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zpwoot/internal/core/domain/message"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// messageStatusChange is a sent message moving forward, handed to the event
+// handler to be reported through the MessageStatus webhook.
+type messageStatusChange struct {
+	MessageID types.MessageID
+	Chat      types.JID
+	Recipient types.JID
+	Status    message.Status
+	Timestamp time.Time
+}
+
+// receiptStatus maps the receipts other users send for the session's messages
+// to a message status.
+func receiptStatus(receiptType types.ReceiptType) (message.Status, bool) {
+	switch receiptType {
+	case types.ReceiptTypeDelivered:
+		return message.StatusDelivered, true
+	case types.ReceiptTypeRead:
+		return message.StatusRead, true
+	case types.ReceiptTypePlayed:
+		return message.StatusPlayed, true
+	default:
+		return "", false
+	}
+}
+
+// receiptRecipient is the user a receipt came from: the other user of a
+// private chat or the participant of a group. Phone number JIDs are preferred
+// so receipts match the JIDs messages were sent to.
+func receiptRecipient(evt *events.Receipt) types.JID {
+	recipient := evt.Sender
+	if recipient.Server == types.HiddenUserServer && !evt.SenderAlt.IsEmpty() {
+		recipient = evt.SenderAlt
+	}
+
+	return recipient.ToNonAD()
+}
+
+// recordReceipt stores a receipt for the session's own messages and reports
+// every message it moved forward. Without a message store every receipt is
+// reported.
+func (eh *DefaultEventHandler) recordReceipt(client *Client, evt *events.Receipt) error {
+	if evt.IsFromMe {
+		return nil
+	}
+
+	status, ok := receiptStatus(evt.Type)
+	if !ok {
+		return nil
+	}
+
+	recipient := receiptRecipient(evt)
+	messageIDs := evt.MessageIDs
+
+	if eh.messageRepo != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		updated, err := eh.messageRepo.ApplyReceipt(ctx, client.SessionID, evt.MessageIDs, recipient.String(), status, evt.Timestamp)
+		if err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", client.SessionID).
+				Strs("message_ids", evt.MessageIDs).
+				Msg("Failed to store receipt")
+		} else {
+			messageIDs = updated
+		}
+	}
+
+	var errs []error
+
+	for _, messageID := range messageIDs {
+		err := eh.emit(client, EventMessageStatus, newMessageStatusPayload(&messageStatusChange{
+			MessageID: messageID,
+			Chat:      evt.Chat,
+			Recipient: recipient,
+			Status:    status,
+			Timestamp: evt.Timestamp,
+		}))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
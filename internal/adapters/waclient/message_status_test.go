@@ -0,0 +1,272 @@
+package waclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/message"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// receiptStore keeps the furthest status of each sent message per
+// recipient, moving it forward the way the database does.
+type receiptStore struct {
+	message.Repository
+
+	sent     map[string]bool
+	statuses map[string]message.Status
+}
+
+func newReceiptStore(sentIDs ...string) *receiptStore {
+	store := &receiptStore{
+		sent:     make(map[string]bool),
+		statuses: make(map[string]message.Status),
+	}
+
+	for _, id := range sentIDs {
+		store.sent[id] = true
+	}
+
+	return store
+}
+
+func (s *receiptStore) ApplyReceipt(_ context.Context, _ string, messageIDs []string, recipient string, status message.Status, _ time.Time) ([]string, error) {
+	var updated []string
+
+	for _, id := range messageIDs {
+		key := id + "/" + recipient
+
+		current, ok := s.statuses[key]
+		if !s.sent[id] || ok && !status.After(current) {
+			continue
+		}
+
+		s.statuses[key] = status
+		updated = append(updated, id)
+	}
+
+	return updated, nil
+}
+
+type statusTest struct {
+	t       *testing.T
+	sender  *capturingSender
+	handler *DefaultEventHandler
+	client  *Client
+}
+
+func newStatusTest(t *testing.T, messageRepo message.Repository) *statusTest {
+	sender := &capturingSender{}
+
+	return &statusTest{
+		t:       t,
+		sender:  sender,
+		handler: NewDefaultEventHandler(logger.New(), sender, rawAndNormalized{eventType: EventMessageStatus}, messageRepo, nil),
+		client:  &Client{SessionID: "session-1"},
+	}
+}
+
+var (
+	statusChat        = types.NewJID("5511999999999", types.DefaultUserServer)
+	statusGroup       = types.NewJID("120363000000000000", types.GroupServer)
+	statusParticipant = types.NewJID("5522888888888", types.DefaultUserServer)
+)
+
+func newTestReceipt(chat, sender types.JID, receiptType types.ReceiptType, messageIDs ...string) *events.Receipt {
+	return &events.Receipt{
+		MessageSource: types.MessageSource{
+			Chat:    chat,
+			Sender:  sender,
+			IsGroup: chat.Server == types.GroupServer,
+		},
+		MessageIDs: messageIDs,
+		Timestamp:  time.Now(),
+		Type:       receiptType,
+	}
+}
+
+// receive handles evt and returns the MessageStatus payloads it emitted.
+func (st *statusTest) receive(evt interface{}) []map[string]interface{} {
+	st.t.Helper()
+
+	if err := st.handler.HandleEvent(st.client, evt); err != nil {
+		st.t.Fatalf("HandleEvent(%T): %v", evt, err)
+	}
+
+	sent := st.sender.take(normalizedHookURL)
+	st.sender.take(rawHookURL)
+
+	payloads := make([]map[string]interface{}, len(sent))
+	for i, event := range sent {
+		if event.Type != string(EventMessageStatus) {
+			st.t.Fatalf("emitted %s, want %s", event.Type, EventMessageStatus)
+		}
+
+		payloads[i] = event.Data
+	}
+
+	return payloads
+}
+
+// expectStatuses checks the emitted payloads are statuses of messageIDs, in
+// order.
+func (st *statusTest) expectStatuses(payloads []map[string]interface{}, status message.Status, messageIDs ...string) {
+	st.t.Helper()
+
+	if len(payloads) != len(messageIDs) {
+		st.t.Fatalf("emitted %d statuses, want %d: %v", len(payloads), len(messageIDs), payloads)
+	}
+
+	for i, payload := range payloads {
+		if payload["messageId"] != messageIDs[i] || payload["status"] != string(status) {
+			st.t.Errorf("emitted %v, want %s %s", payload, messageIDs[i], status)
+		}
+	}
+}
+
+func TestReceiptStatus(t *testing.T) {
+	tests := []struct {
+		receiptType types.ReceiptType
+		want        message.Status
+		ok          bool
+	}{
+		{types.ReceiptTypeDelivered, message.StatusDelivered, true},
+		{types.ReceiptTypeRead, message.StatusRead, true},
+		{types.ReceiptTypePlayed, message.StatusPlayed, true},
+		{types.ReceiptTypeSender, "", false},
+		{types.ReceiptTypeRetry, "", false},
+		{types.ReceiptTypeReadSelf, "", false},
+		{types.ReceiptTypeServerError, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := receiptStatus(tt.receiptType)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("receiptStatus(%q) = %q, %v; want %q, %v", tt.receiptType, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReceiptRecipientPrefersPhoneNumbers(t *testing.T) {
+	lid := types.NewJID("123456789", types.HiddenUserServer)
+	device := types.NewADJID("5522888888888", 0, 3)
+
+	tests := []struct {
+		name   string
+		sender types.JID
+		alt    types.JID
+		want   types.JID
+	}{
+		{"phone number", statusChat, types.EmptyJID, statusChat},
+		{"device of a phone number", device, types.EmptyJID, statusParticipant},
+		{"LID with its phone number", lid, statusParticipant, statusParticipant},
+		{"LID alone", lid, types.EmptyJID, lid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := newTestReceipt(statusGroup, tt.sender, types.ReceiptTypeDelivered, "MSG1")
+			evt.SenderAlt = tt.alt
+
+			if got := receiptRecipient(evt); got != tt.want {
+				t.Errorf("receiptRecipient = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentMessageReportsItsServerAck(t *testing.T) {
+	st := newStatusTest(t, newReceiptStore())
+
+	payloads := st.receive(&messageStatusChange{
+		MessageID: "MSG1",
+		Chat:      statusChat,
+		Status:    message.StatusSent,
+		Timestamp: time.Now(),
+	})
+
+	st.expectStatuses(payloads, message.StatusSent, "MSG1")
+
+	if _, ok := payloads[0]["recipient"]; ok {
+		t.Errorf("the server ack names recipient %v, want none", payloads[0]["recipient"])
+	}
+}
+
+func TestReceiptsMoveMessagesForward(t *testing.T) {
+	st := newStatusTest(t, newReceiptStore("MSG1", "MSG2"))
+
+	delivered := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeDelivered, "MSG1", "MSG2"))
+	st.expectStatuses(delivered, message.StatusDelivered, "MSG1", "MSG2")
+
+	if delivered[0]["recipient"] != statusChat.String() || delivered[0]["chat"] != statusChat.String() {
+		t.Errorf("emitted %v, want the receipt of %s", delivered[0], statusChat)
+	}
+
+	read := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeRead, "MSG1"))
+	st.expectStatuses(read, message.StatusRead, "MSG1")
+
+	// A delivery receipt arriving after the read receipt moves nothing
+	// back, so it is not reported.
+	late := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeDelivered, "MSG1"))
+	st.expectStatuses(late, message.StatusDelivered)
+
+	repeated := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeRead, "MSG1"))
+	st.expectStatuses(repeated, message.StatusRead)
+
+	played := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypePlayed, "MSG1"))
+	st.expectStatuses(played, message.StatusPlayed, "MSG1")
+
+	// Read can skip delivered.
+	skipped := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeRead, "MSG2"))
+	st.expectStatuses(skipped, message.StatusRead, "MSG2")
+}
+
+func TestGroupReceiptsAreTrackedPerParticipant(t *testing.T) {
+	st := newStatusTest(t, newReceiptStore("MSG1"))
+	other := types.NewJID("5533777777777", types.DefaultUserServer)
+
+	first := st.receive(newTestReceipt(statusGroup, statusParticipant, types.ReceiptTypeRead, "MSG1"))
+	st.expectStatuses(first, message.StatusRead, "MSG1")
+
+	if first[0]["recipient"] != statusParticipant.String() || first[0]["chat"] != statusGroup.String() {
+		t.Errorf("emitted %v, want the receipt of %s in %s", first[0], statusParticipant, statusGroup)
+	}
+
+	// Another participant is still behind the first one, which does not
+	// keep their receipt from being reported.
+	second := st.receive(newTestReceipt(statusGroup, other, types.ReceiptTypeDelivered, "MSG1"))
+	st.expectStatuses(second, message.StatusDelivered, "MSG1")
+
+	if second[0]["recipient"] != other.String() {
+		t.Errorf("emitted %v, want the receipt of %s", second[0], other)
+	}
+}
+
+func TestReceiptsForOtherMessagesAreNotReported(t *testing.T) {
+	st := newStatusTest(t, newReceiptStore("MSG1"))
+
+	// Messages the session did not send are not in the store.
+	payloads := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeRead, "MSG1", "INCOMING"))
+	st.expectStatuses(payloads, message.StatusRead, "MSG1")
+
+	// Receipts the session sends for messages it received are ignored.
+	own := newTestReceipt(statusChat, statusChat, types.ReceiptTypeRead, "MSG1")
+	own.IsFromMe = true
+
+	st.expectStatuses(st.receive(own), message.StatusRead)
+
+	// So are receipts that do not move a message forward.
+	retry := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeRetry, "MSG1"))
+	st.expectStatuses(retry, message.StatusRead)
+}
+
+func TestReceiptsWithoutAMessageStoreAreReported(t *testing.T) {
+	st := newStatusTest(t, nil)
+
+	payloads := st.receive(newTestReceipt(statusChat, statusChat, types.ReceiptTypeDelivered, "MSG1", "MSG2"))
+	st.expectStatuses(payloads, message.StatusDelivered, "MSG1", "MSG2")
+}
@@ -0,0 +1,154 @@
+package waclient
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// importDelay is how long after pairing the import of a session starts.
+const importDelay = 2 * time.Minute
+
+func newDomainMessage(sessionID string, evt *events.Message) *message.Message {
+	return message.NewMessage(
+		sessionID,
+		evt.Info.ID,
+		evt.Info.Chat.String(),
+		evt.Info.Sender.String(),
+		getMessageType(evt.Message),
+		getMessageContent(evt.Message),
+		evt.Info.IsFromMe,
+		evt.Info.Timestamp,
+	)
+}
+
+func newOutgoingMessage(client *Client, to types.JID, msg *waE2E.Message, resp whatsmeow.SendResponse) *message.Message {
+	sender := ""
+	if client.WAClient.Store.ID != nil {
+		sender = client.WAClient.Store.ID.ToNonAD().String()
+	}
+
+	return message.NewMessage(
+		client.SessionID,
+		resp.ID,
+		to.String(),
+		sender,
+		getMessageType(msg),
+		getMessageContent(msg),
+		true,
+		resp.Timestamp,
+	)
+}
+
+// storeMessage persists msg and reports whether it was new. Redelivered
+// messages are ignored by the repository.
+func storeMessage(ctx context.Context, repo message.Repository, log *logger.Logger, msg *message.Message) bool {
+	if repo == nil || msg == nil || msg.MessageID == "" {
+		return false
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	inserted, err := repo.Create(dbCtx, msg)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("session_id", msg.SessionID).
+			Str("message_id", msg.MessageID).
+			Msg("Failed to store message")
+
+		return false
+	}
+
+	return inserted
+}
+
+func (wac *WAClient) storeMessage(ctx context.Context, msg *message.Message) bool {
+	return storeMessage(ctx, wac.messageRepo, wac.logger, msg)
+}
+
+func (eh *DefaultEventHandler) storeMessage(ctx context.Context, msg *message.Message) bool {
+	return storeMessage(ctx, eh.messageRepo, eh.logger, msg)
+}
+
+// syncMessage hands a stored message to the message sync in the background so
+// a slow Chatwoot does not hold up the event loop.
+func (eh *DefaultEventHandler) syncMessage(msg *message.Message, pushName string) {
+	if eh.messageSync == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := eh.messageSync.SyncMessage(ctx, msg, pushName); err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", msg.SessionID).
+				Str("message_id", msg.MessageID).
+				Msg("Failed to sync message")
+		}
+	}()
+}
+
+// syncReceipt hands delivery and read receipts of the session's own messages
+// to the receipt sync.
+func (eh *DefaultEventHandler) syncReceipt(sessionID string, evt *events.Receipt) {
+	if eh.receiptSync == nil || evt.IsFromMe {
+		return
+	}
+
+	var status string
+
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		status = output.MessageStatusDelivered
+	case types.ReceiptTypeRead, types.ReceiptTypePlayed:
+		status = output.MessageStatusRead
+	default:
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := eh.receiptSync.SyncReceipt(ctx, sessionID, evt.MessageIDs, status, evt.Timestamp); err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", sessionID).
+				Strs("message_ids", evt.MessageIDs).
+				Msg("Failed to sync receipt")
+		}
+	}()
+}
+
+// scheduleImport starts the import of a newly paired session once the
+// initial contact and history sync had time to arrive.
+func (eh *DefaultEventHandler) scheduleImport(sessionID string) {
+	if eh.messageSync == nil {
+		return
+	}
+
+	time.AfterFunc(importDelay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := eh.messageSync.StartImportAfterPairing(ctx, sessionID); err != nil {
+			eh.logger.Error().
+				Err(err).
+				Str("session_id", sessionID).
+				Msg("Failed to start import")
+		}
+	})
+}
@@ -2,13 +2,13 @@ package waclient
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/shared"
 	"zpwoot/internal/core/ports/input"
 	"zpwoot/internal/core/ports/output"
 
@@ -33,7 +33,7 @@ func (ms *Sender) SendTextMessage(ctx context.Context, sessionID, to, text strin
 		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
 		return nil, ErrInvalidJID
 	}
@@ -50,38 +50,59 @@ func (ms *Sender) SendTextMessage(ctx context.Context, sessionID, to, text strin
 		message = &waE2E.Message{Conversation: proto.String(text)}
 	}
 
-	resp, err := client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send text message: %w", err)
 	}
-	return &resp, nil
+	return resp, nil
 }
 
 func (ms *Sender) SendMediaMessage(ctx context.Context, sessionID, to string, media *output.MediaData) (*whatsmeow.SendResponse, error) {
-	client, recipientJID, fileData, err := ms.validateMediaInputs(ctx, sessionID, to, media)
+	client, recipientJID, err := ms.validateMediaInputs(ctx, sessionID, to, media)
 	if err != nil {
 		return nil, err
 	}
 
+	media, release := ms.transcode(ctx, media)
+	defer release()
+
 	mimeType, mediaType := ms.prepareMediaData(media)
-	uploaded, err := ms.uploadMediaToWhatsApp(ctx, client, fileData, mediaType)
+	uploaded, err := ms.uploadMediaToWhatsApp(ctx, client, media, mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	message := ms.buildMediaMessage(mediaType, uploaded, mimeType, media)
+
+	resp, err := ms.sendPreparedMessage(ctx, client, recipientJID, message)
 	if err != nil {
 		return nil, err
 	}
 
-	message := ms.buildMediaMessage(mediaType, uploaded, mimeType, fileData, media)
-	return ms.sendPreparedMessage(ctx, client, recipientJID, message)
+	if ms.waClient.media == nil {
+		return resp, nil
+	}
+
+	// A streamed file is removed once the request ends, so it is stored
+	// before returning.
+	if media.File != nil {
+		ms.waClient.media.storeSentFile(ctx, client, recipientJID, resp.ID, message, media.File, int64(uploaded.FileLength))
+	} else {
+		ms.waClient.media.storeSentLater(client, recipientJID, resp.ID, message, media.Data)
+	}
+
+	return resp, nil
 }
 
-func (ms *Sender) SendLocationMessage(ctx context.Context, sessionID, to string, lat, lng float64, name string) error {
+func (ms *Sender) SendLocationMessage(ctx context.Context, sessionID, to string, lat, lng float64, name string) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	message := &waE2E.Message{
@@ -92,23 +113,23 @@ func (ms *Sender) SendLocationMessage(ctx context.Context, sessionID, to string,
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send location message: %w", err)
+		return nil, fmt.Errorf("failed to send location message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
-func (ms *Sender) SendContactMessage(ctx context.Context, sessionID string, to string, contact *ContactInfo) error {
+func (ms *Sender) SendContactMessage(ctx context.Context, sessionID string, to string, contact *ContactInfo) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	vcard := contact.VCard
@@ -123,12 +144,12 @@ func (ms *Sender) SendContactMessage(ctx context.Context, sessionID string, to s
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send contact message: %w", err)
+		return nil, fmt.Errorf("failed to send contact message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
 func (ms *Sender) getConnectedClient(ctx context.Context, sessionID string) (*Client, error) {
@@ -150,7 +171,7 @@ func parseJID(jidStr string) (types.JID, error) {
 	}
 
 	if !strings.Contains(jidStr, "@") {
-		phone := normalizePhoneNumber(jidStr)
+		phone := shared.NormalizePhoneNumber(jidStr)
 		jidStr = phone + "@s.whatsapp.net"
 	}
 
@@ -162,16 +183,6 @@ func parseJID(jidStr string) (types.JID, error) {
 	return jid, nil
 }
 
-func normalizePhoneNumber(phone string) string {
-	phone = strings.ReplaceAll(phone, " ", "")
-	phone = strings.ReplaceAll(phone, "-", "")
-	phone = strings.ReplaceAll(phone, "(", "")
-	phone = strings.ReplaceAll(phone, ")", "")
-	phone = strings.TrimPrefix(phone, "+")
-
-	return phone
-}
-
 func (ms *Sender) GetChatInfo(ctx context.Context, sessionID string, chatJID string) (*ChatInfo, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
@@ -201,11 +212,16 @@ func (ms *Sender) GetChatInfo(ctx context.Context, sessionID string, chatJID str
 }
 
 type ChatInfo struct {
-	JID              string `json:"jid"`
-	Name             string `json:"name,omitempty"`
-	Topic            string `json:"topic,omitempty"`
-	IsGroup          bool   `json:"isGroup"`
-	ParticipantCount int    `json:"participantCount,omitempty"`
+	JID              string           `json:"jid"`
+	Name             string           `json:"name,omitempty"`
+	Topic            string           `json:"topic,omitempty"`
+	IsGroup          bool             `json:"isGroup"`
+	ParticipantCount int              `json:"participantCount,omitempty"`
+	UnreadCount      int              `json:"unreadCount"`
+	Archived         bool             `json:"archived"`
+	Pinned           bool             `json:"pinned"`
+	MutedUntil       *time.Time       `json:"mutedUntil,omitempty"`
+	LastMessage      *message.Message `json:"-"`
 }
 
 func (ms *Sender) GetContacts(ctx context.Context, sessionID string) ([]*ContactInfo, error) {
@@ -236,7 +252,7 @@ func (ms *Sender) GetContacts(ctx context.Context, sessionID string) ([]*Contact
 	return contactList, nil
 }
 
-func (ms *Sender) SendContactMessageFromInput(ctx context.Context, sessionID string, to string, contact *input.ContactInfo) error {
+func (ms *Sender) SendContactMessageFromInput(ctx context.Context, sessionID string, to string, contact *input.ContactInfo) (*whatsmeow.SendResponse, error) {
 	internalContact := &ContactInfo{
 		Name:  contact.Name,
 		Phone: contact.Phone,
@@ -246,15 +262,15 @@ func (ms *Sender) SendContactMessageFromInput(ctx context.Context, sessionID str
 	return ms.SendContactMessage(ctx, sessionID, to, internalContact)
 }
 
-func (ms *Sender) SendContactsArrayMessage(ctx context.Context, sessionID string, to string, contacts []*input.ContactInfo) error {
+func (ms *Sender) SendContactsArrayMessage(ctx context.Context, sessionID string, to string, contacts []*input.ContactInfo) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	contactMessages := make([]*waE2E.ContactMessage, len(contacts))
@@ -278,12 +294,12 @@ func (ms *Sender) SendContactsArrayMessage(ctx context.Context, sessionID string
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send contacts array message: %w", err)
+		return nil, fmt.Errorf("failed to send contacts array message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
 func (ms *Sender) GetChatInfoAsInput(ctx context.Context, sessionID, chatJID string) (*input.ChatInfo, error) {
@@ -333,6 +349,9 @@ func (ms *Sender) GetChatsAsInput(ctx context.Context, sessionID string) ([]*inp
 			Topic:            chat.Topic,
 			IsGroup:          chat.IsGroup,
 			ParticipantCount: chat.ParticipantCount,
+			UnreadCount:      chat.UnreadCount,
+			Archived:         chat.Archived,
+			Pinned:           chat.Pinned,
 		})
 	}
 
@@ -349,17 +368,28 @@ func NewMessageService(messageSender *Sender) input.MessageService {
 	}
 }
 
+// newMessageResult reports a send. Queued sends carry the ID the message
+// will be sent under and the time it was queued.
+func newMessageResult(ctx context.Context, resp *whatsmeow.SendResponse) *output.MessageResult {
+	status := output.MessageStatusSent
+	if output.IsQueuedSend(ctx) {
+		status = output.MessageStatusQueued
+	}
+
+	return &output.MessageResult{
+		MessageID: resp.ID,
+		Status:    status,
+		SentAt:    resp.Timestamp,
+	}
+}
+
 func (w *MessageService) SendTextMessage(ctx context.Context, sessionID string, to string, text string, contextInfo *output.MessageContextInfo) (*output.MessageResult, error) {
 	resp, err := w.Sender.SendTextMessage(ctx, sessionID, to, text, contextInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: resp.ID,
-		Status:    "sent",
-		SentAt:    resp.Timestamp,
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendMediaMessage(ctx context.Context, sessionID string, to string, media *output.MediaData, contextInfo *output.MessageContextInfo) (*output.MessageResult, error) {
@@ -368,76 +398,52 @@ func (w *MessageService) SendMediaMessage(ctx context.Context, sessionID string,
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: resp.ID,
-		Status:    "sent",
-		SentAt:    resp.Timestamp,
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendLocationMessage(ctx context.Context, sessionID, to string, latitude, longitude float64, name string, contextInfo *output.MessageContextInfo) (*output.MessageResult, error) {
-	err := w.Sender.SendLocationMessage(ctx, sessionID, to, latitude, longitude, name)
+	resp, err := w.Sender.SendLocationMessage(ctx, sessionID, to, latitude, longitude, name)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendContactMessage(ctx context.Context, sessionID string, to string, contact *input.ContactInfo, contextInfo *output.MessageContextInfo) (*output.MessageResult, error) {
-	err := w.SendContactMessageFromInput(ctx, sessionID, to, contact)
+	resp, err := w.SendContactMessageFromInput(ctx, sessionID, to, contact)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendContactsArrayMessage(ctx context.Context, sessionID, to string, contacts []*input.ContactInfo) (*output.MessageResult, error) {
-	err := w.Sender.SendContactsArrayMessage(ctx, sessionID, to, contacts)
+	resp, err := w.Sender.SendContactsArrayMessage(ctx, sessionID, to, contacts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendReactionMessage(ctx context.Context, sessionID, to, messageID, reaction string, fromMe bool) (*output.MessageResult, error) {
-	err := w.Sender.SendReactionMessage(ctx, sessionID, to, messageID, reaction, fromMe)
+	resp, err := w.Sender.SendReactionMessage(ctx, sessionID, to, messageID, reaction, fromMe)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendPollMessage(ctx context.Context, sessionID, to, name string, options []string, selectableCount int) (*output.MessageResult, error) {
-	err := w.Sender.SendPollMessage(ctx, sessionID, to, name, options, selectableCount)
+	resp, err := w.Sender.SendPollMessage(ctx, sessionID, to, name, options, selectableCount)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendButtonsMessage(ctx context.Context, sessionID, to, text string, buttons []input.ButtonInfo) (*output.MessageResult, error) {
@@ -449,16 +455,12 @@ func (w *MessageService) SendButtonsMessage(ctx context.Context, sessionID, to,
 		})
 	}
 
-	err := w.Sender.SendButtonsMessage(ctx, sessionID, to, text, waButtons)
+	resp, err := w.Sender.SendButtonsMessage(ctx, sessionID, to, text, waButtons)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendListMessage(ctx context.Context, sessionID, to, text, title string, sections []input.ListSectionInfo) (*output.MessageResult, error) {
@@ -480,16 +482,12 @@ func (w *MessageService) SendListMessage(ctx context.Context, sessionID, to, tex
 		})
 	}
 
-	err := w.Sender.SendListMessage(ctx, sessionID, to, text, title, "", waSections)
+	resp, err := w.Sender.SendListMessage(ctx, sessionID, to, text, title, "", waSections)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) SendTemplateMessage(ctx context.Context, sessionID, to string, template input.TemplateInfo) (*output.MessageResult, error) {
@@ -498,16 +496,12 @@ func (w *MessageService) SendTemplateMessage(ctx context.Context, sessionID, to
 		Footer:  template.Footer,
 	}
 
-	err := w.Sender.SendTemplateMessage(ctx, sessionID, to, waTemplate)
+	resp, err := w.Sender.SendTemplateMessage(ctx, sessionID, to, waTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.MessageResult{
-		MessageID: fallbackID(),
-		Status:    "sent",
-		SentAt:    time.Now(),
-	}, nil
+	return newMessageResult(ctx, resp), nil
 }
 
 func (w *MessageService) GetChatInfo(ctx context.Context, sessionID, chatJID string) (*input.ChatInfo, error) {
@@ -534,30 +528,19 @@ func (w *MessageService) MarkRead(ctx context.Context, sessionID, phone string,
 	return w.Sender.MarkRead(ctx, sessionID, phone, messageIDs)
 }
 
-func (w *MessageService) RequestHistorySync(ctx context.Context, sessionID string, count int) error {
-	return w.Sender.RequestHistorySync(ctx, sessionID, count)
-}
-
-func (ms *Sender) GetChats(ctx context.Context, sessionID string) ([]*ChatInfo, error) {
-	_, err := ms.getConnectedClient(ctx, sessionID)
-	if err != nil {
-		return nil, err
-	}
-
-	var chatList []*ChatInfo
-
-	return chatList, nil
+func (w *MessageService) RequestHistorySync(ctx context.Context, sessionID, chatJID string, count int) error {
+	return w.Sender.RequestHistorySync(ctx, sessionID, chatJID, count)
 }
 
-func (ms *Sender) SendReactionMessage(ctx context.Context, sessionID string, to string, messageID string, reaction string, fromMe bool) error {
+func (ms *Sender) SendReactionMessage(ctx context.Context, sessionID string, to string, messageID string, reaction string, fromMe bool) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	if reaction == "remove" {
@@ -577,44 +560,44 @@ func (ms *Sender) SendReactionMessage(ctx context.Context, sessionID string, to
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, reactionMsg)
+	resp, err := ms.send(ctx, client, recipientJID, reactionMsg)
 	if err != nil {
-		return fmt.Errorf("failed to send reaction: %w", err)
+		return nil, fmt.Errorf("failed to send reaction: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
-func (ms *Sender) SendPollMessage(ctx context.Context, sessionID string, to string, name string, options []string, selectableCount int) error {
+func (ms *Sender) SendPollMessage(ctx context.Context, sessionID string, to string, name string, options []string, selectableCount int) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	pollMsg := client.WAClient.BuildPollCreation(name, options, selectableCount)
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, pollMsg)
+	resp, err := ms.send(ctx, client, recipientJID, pollMsg)
 	if err != nil {
-		return fmt.Errorf("failed to send poll: %w", err)
+		return nil, fmt.Errorf("failed to send poll: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
-func (ms *Sender) SendButtonsMessage(ctx context.Context, sessionID string, to string, text string, buttons []ButtonInfo) error {
+func (ms *Sender) SendButtonsMessage(ctx context.Context, sessionID string, to string, text string, buttons []ButtonInfo) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	waButtons := make([]*waE2E.ButtonsMessage_Button, 0, len(buttons))
@@ -635,23 +618,23 @@ func (ms *Sender) SendButtonsMessage(ctx context.Context, sessionID string, to s
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send buttons message: %w", err)
+		return nil, fmt.Errorf("failed to send buttons message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
-func (ms *Sender) SendListMessage(ctx context.Context, sessionID string, to string, text string, title string, buttonText string, sections []ListSection) error {
+func (ms *Sender) SendListMessage(ctx context.Context, sessionID string, to string, text string, title string, buttonText string, sections []ListSection) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	waSections := make([]*waE2E.ListMessage_Section, 0, len(sections))
@@ -682,23 +665,23 @@ func (ms *Sender) SendListMessage(ctx context.Context, sessionID string, to stri
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send list message: %w", err)
+		return nil, fmt.Errorf("failed to send list message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
-func (ms *Sender) SendTemplateMessage(ctx context.Context, sessionID string, to string, template TemplateInfo) error {
+func (ms *Sender) SendTemplateMessage(ctx context.Context, sessionID string, to string, template TemplateInfo) (*whatsmeow.SendResponse, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return ErrInvalidJID
+		return nil, ErrInvalidJID
 	}
 
 	message := &waE2E.Message{
@@ -710,12 +693,12 @@ func (ms *Sender) SendTemplateMessage(ctx context.Context, sessionID string, to
 		},
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
-		return fmt.Errorf("failed to send template message: %w", err)
+		return nil, fmt.Errorf("failed to send template message: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
 type ButtonInfo struct {
@@ -739,15 +722,6 @@ type TemplateInfo struct {
 	Footer  string
 }
 
-func fallbackID() string {
-	bytes := make([]byte, 16)
-	if _, err := rand.Read(bytes); err != nil {
-		return strings.ToUpper(hex.EncodeToString([]byte(strconv.FormatInt(time.Now().UnixNano(), 10))))
-	}
-
-	return strings.ToUpper(hex.EncodeToString(bytes))
-}
-
 func buildContextInfo(contextInfo *output.MessageContextInfo) *waE2E.ContextInfo {
 	if contextInfo == nil {
 		return nil
@@ -771,13 +745,13 @@ func (ms *Sender) DeleteMessage(ctx context.Context, sessionID string, phone str
 		return err
 	}
 
-	recipientJID, err := parseJID(phone)
+	recipientJID, err := ms.waClient.resolveJID(client, phone)
 	if err != nil {
 		return ErrInvalidJID
 	}
 
 	revokeMsg := client.WAClient.BuildRevoke(recipientJID, types.EmptyJID, messageID)
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, revokeMsg)
+	_, err = ms.sendProtocol(ctx, client, recipientJID, revokeMsg)
 	if err != nil {
 		return fmt.Errorf("failed to delete message: %w", err)
 	}
@@ -790,12 +764,12 @@ func (ms *Sender) EditMessage(ctx context.Context, sessionID string, phone strin
 		return err
 	}
 
-	recipientJID, err := parseJID(phone)
+	recipientJID, err := ms.waClient.resolveJID(client, phone)
 	if err != nil {
 		return ErrInvalidJID
 	}
 
-	_, err = client.WAClient.SendMessage(ctx, recipientJID, &waE2E.Message{
+	_, err = ms.sendProtocol(ctx, client, recipientJID, &waE2E.Message{
 		EditedMessage: &waE2E.FutureProofMessage{
 			Message: &waE2E.Message{
 				Conversation: proto.String(text),
@@ -823,52 +797,139 @@ func (ms *Sender) MarkRead(ctx context.Context, sessionID string, phone string,
 		return err
 	}
 
-	recipientJID, err := parseJID(phone)
+	recipientJID, err := ms.waClient.resolveJID(client, phone)
 	if err != nil {
 		return ErrInvalidJID
 	}
 
-	err = client.WAClient.MarkRead(messageIDs, time.Now(), recipientJID, recipientJID)
+	readAt := time.Now()
+
+	err = client.WAClient.MarkRead(messageIDs, readAt, recipientJID, recipientJID)
 	if err != nil {
 		return fmt.Errorf("failed to mark as read: %w", err)
 	}
 
+	ms.waClient.markChatRead(sessionID, recipientJID, readAt)
+
 	return nil
 }
-func (ms *Sender) RequestHistorySync(ctx context.Context, sessionID string, count int) error {
-	_, err := ms.getConnectedClient(ctx, sessionID)
+
+// RequestHistorySync asks the primary device for messages older than the
+// oldest message stored for the chat. The response arrives asynchronously as
+// an events.HistorySync of type ON_DEMAND.
+func (ms *Sender) RequestHistorySync(ctx context.Context, sessionID string, chatJID string, count int) error {
+	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
 		return err
 	}
 
+	if !client.IsLoggedIn() {
+		return ErrNotConnected
+	}
+
+	chat, err := parseJID(chatJID)
+	if err != nil {
+		return ErrInvalidJID
+	}
+
 	if count <= 0 {
-		count = 50
+		count = DefaultHistoryCount
+	}
+
+	if ms.waClient.messageRepo == nil {
+		return ErrNoHistoryAnchor
+	}
+
+	oldest, err := ms.waClient.messageRepo.GetOldestInChat(ctx, sessionID, chat.String())
+	if err != nil {
+		if errors.Is(err, shared.ErrMessageNotFound) {
+			return ErrNoHistoryAnchor
+		}
+
+		return fmt.Errorf("failed to find oldest message: %w", err)
+	}
+
+	anchor := &types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     chat,
+			IsFromMe: oldest.FromMe,
+			IsGroup:  chat.Server == types.GroupServer,
+		},
+		ID:        oldest.MessageID,
+		Timestamp: oldest.Timestamp,
 	}
 
-	_ = count
+	request := client.WAClient.BuildHistorySyncRequest(anchor, count)
+
+	_, err = ms.sendPeer(ctx, client, request)
+	if err != nil {
+		return fmt.Errorf("failed to send history sync request: %w", err)
+	}
 
 	return nil
 }
 
-func (ms *Sender) validateMediaInputs(ctx context.Context, sessionID string, to string, media *output.MediaData) (*Client, types.JID, []byte, error) {
+func (ms *Sender) validateMediaInputs(ctx context.Context, sessionID string, to string, media *output.MediaData) (*Client, types.JID, error) {
 	client, err := ms.getConnectedClient(ctx, sessionID)
 	if err != nil {
-		return nil, types.JID{}, nil, err
+		return nil, types.JID{}, err
 	}
 
-	recipientJID, err := parseJID(to)
+	recipientJID, err := ms.waClient.resolveJID(client, to)
 	if err != nil {
-		return nil, types.JID{}, nil, ErrInvalidJID
+		return nil, types.JID{}, ErrInvalidJID
 	}
 
-	var fileData []byte
-	if len(media.Data) > 0 {
-		fileData = media.Data
-	} else {
-		return nil, types.JID{}, nil, fmt.Errorf("media data is required")
+	if len(media.Data) == 0 && media.File == nil {
+		return nil, types.JID{}, fmt.Errorf("media data is required")
+	}
+
+	return client, recipientJID, nil
+}
+
+// transcode converts audio to a voice note and images to stickers, and
+// previews images and videos, when a transcoder is set. Media that fails to
+// convert is sent as it is. The returned function closes converted media.
+func (ms *Sender) transcode(ctx context.Context, media *output.MediaData) (*output.MediaData, func()) {
+	transcoder := ms.waClient.transcoder
+	if transcoder == nil {
+		return media, func() {}
+	}
+
+	var converted *output.MediaData
+
+	var err error
+
+	switch {
+	case media.Sticker:
+		if media.MimeType == "image/webp" {
+			return media, func() {}
+		}
+
+		converted, err = transcoder.Sticker(ctx, media)
+	case strings.HasPrefix(media.MimeType, "audio/"):
+		converted, err = transcoder.VoiceNote(ctx, media)
+	case strings.HasPrefix(media.MimeType, "image/"), strings.HasPrefix(media.MimeType, "video/"):
+		var details *output.MediaDetails
+
+		details, err = transcoder.Preview(ctx, media)
+		if err == nil {
+			media.Details = details
+		}
+	default:
+		return media, func() {}
+	}
+
+	if err != nil {
+		ms.waClient.logger.Warn().Err(err).Str("mime_type", media.MimeType).Msg("Failed to transcode media, sending it unchanged")
+		return media, func() {}
+	}
+
+	if converted == nil {
+		return media, func() {}
 	}
 
-	return client, recipientJID, fileData, nil
+	return converted, func() { _ = converted.File.Close() }
 }
 
 func (ms *Sender) prepareMediaData(media *output.MediaData) (string, whatsmeow.MediaType) {
@@ -892,64 +953,92 @@ func (ms *Sender) prepareMediaData(media *output.MediaData) (string, whatsmeow.M
 	return mimeType, mediaType
 }
 
-func (ms *Sender) uploadMediaToWhatsApp(ctx context.Context, client *Client, fileData []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
-	uploaded, err := client.WAClient.Upload(ctx, fileData, mediaType)
+// uploadMediaToWhatsApp encrypts and uploads media. Streamed files are
+// encrypted through a temporary file rather than in memory.
+func (ms *Sender) uploadMediaToWhatsApp(ctx context.Context, client *Client, media *output.MediaData, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	var uploaded whatsmeow.UploadResponse
+
+	var err error
+
+	if media.File != nil {
+		uploaded, err = client.WAClient.UploadReader(ctx, media.File, nil, mediaType)
+	} else {
+		uploaded, err = client.WAClient.Upload(ctx, media.Data, mediaType)
+	}
+
 	if err != nil {
 		return whatsmeow.UploadResponse{}, fmt.Errorf("failed to upload media: %w", err)
 	}
 	return uploaded, nil
 }
 
-func (ms *Sender) buildMediaMessage(mediaType whatsmeow.MediaType, uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte, media *output.MediaData) *waE2E.Message {
+func (ms *Sender) buildMediaMessage(mediaType whatsmeow.MediaType, uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
 	switch mediaType {
 	case whatsmeow.MediaImage:
-		return ms.buildImageMessage(uploaded, mimeType, fileData, media)
+		// Stickers must be WebP; anything else goes out as a photo.
+		if media.Sticker && mimeType == "image/webp" {
+			return ms.buildStickerMessage(uploaded, mimeType, media)
+		}
+
+		return ms.buildImageMessage(uploaded, mimeType, media)
 	case whatsmeow.MediaVideo:
-		return ms.buildVideoMessage(uploaded, mimeType, fileData, media)
+		return ms.buildVideoMessage(uploaded, mimeType, media)
 	case whatsmeow.MediaAudio:
-		return ms.buildAudioMessage(uploaded, mimeType, fileData, media)
+		return ms.buildAudioMessage(uploaded, mimeType, media)
 	case whatsmeow.MediaDocument:
-		return ms.buildDocumentMessage(uploaded, mimeType, fileData, media)
+		return ms.buildDocumentMessage(uploaded, mimeType, media)
 	default:
 		return nil
 	}
 }
 
-func (ms *Sender) buildImageMessage(uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte, media *output.MediaData) *waE2E.Message {
+func (ms *Sender) buildImageMessage(uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
 	imgMsg := &waE2E.ImageMessage{
 		Caption: proto.String(media.Caption),
 	}
 
-	ms.setCommonMediaFields(imgMsg, uploaded, mimeType, fileData)
+	ms.setCommonMediaFields(imgMsg, uploaded, mimeType)
+	ms.setMediaDetails(imgMsg, media.Details)
 	ms.setViewOnceIfNeeded(imgMsg, media.ViewOnce)
 
 	return &waE2E.Message{ImageMessage: imgMsg}
 }
 
-func (ms *Sender) buildVideoMessage(uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte, media *output.MediaData) *waE2E.Message {
+func (ms *Sender) buildStickerMessage(uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
+	stickerMsg := &waE2E.StickerMessage{}
+
+	ms.setCommonMediaFields(stickerMsg, uploaded, mimeType)
+	ms.setMediaDetails(stickerMsg, media.Details)
+
+	return &waE2E.Message{StickerMessage: stickerMsg}
+}
+
+func (ms *Sender) buildVideoMessage(uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
 	vidMsg := &waE2E.VideoMessage{
 		Caption: proto.String(media.Caption),
 	}
 
-	ms.setCommonMediaFields(vidMsg, uploaded, mimeType, fileData)
+	ms.setCommonMediaFields(vidMsg, uploaded, mimeType)
+	ms.setMediaDetails(vidMsg, media.Details)
 	ms.setViewOnceIfNeeded(vidMsg, media.ViewOnce)
 
 	return &waE2E.Message{VideoMessage: vidMsg}
 }
 
-func (ms *Sender) buildAudioMessage(uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte, media *output.MediaData) *waE2E.Message {
+func (ms *Sender) buildAudioMessage(uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
 	ptt := true
 	audioMsg := &waE2E.AudioMessage{
 		PTT: &ptt,
 	}
 
-	ms.setCommonMediaFields(audioMsg, uploaded, mimeType, fileData)
+	ms.setCommonMediaFields(audioMsg, uploaded, mimeType)
+	ms.setMediaDetails(audioMsg, media.Details)
 	ms.setViewOnceIfNeeded(audioMsg, media.ViewOnce)
 
 	return &waE2E.Message{AudioMessage: audioMsg}
 }
 
-func (ms *Sender) buildDocumentMessage(uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte, media *output.MediaData) *waE2E.Message {
+func (ms *Sender) buildDocumentMessage(uploaded whatsmeow.UploadResponse, mimeType string, media *output.MediaData) *waE2E.Message {
 	fileName := media.FileName
 	if fileName == "" {
 		fileName = "document"
@@ -960,12 +1049,12 @@ func (ms *Sender) buildDocumentMessage(uploaded whatsmeow.UploadResponse, mimeTy
 		Caption:  proto.String(media.Caption),
 	}
 
-	ms.setCommonMediaFields(docMsg, uploaded, mimeType, fileData)
+	ms.setCommonMediaFields(docMsg, uploaded, mimeType)
 
 	return &waE2E.Message{DocumentMessage: docMsg}
 }
 
-func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.UploadResponse, mimeType string, fileData []byte) {
+func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.UploadResponse, mimeType string) {
 	switch m := msg.(type) {
 	case *waE2E.ImageMessage:
 		m.URL = proto.String(uploaded.URL)
@@ -974,7 +1063,7 @@ func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.Uploa
 		m.Mimetype = proto.String(mimeType)
 		m.FileEncSHA256 = uploaded.FileEncSHA256
 		m.FileSHA256 = uploaded.FileSHA256
-		m.FileLength = proto.Uint64(uint64(len(fileData)))
+		m.FileLength = proto.Uint64(uploaded.FileLength)
 	case *waE2E.VideoMessage:
 		m.URL = proto.String(uploaded.URL)
 		m.DirectPath = proto.String(uploaded.DirectPath)
@@ -982,7 +1071,7 @@ func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.Uploa
 		m.Mimetype = proto.String(mimeType)
 		m.FileEncSHA256 = uploaded.FileEncSHA256
 		m.FileSHA256 = uploaded.FileSHA256
-		m.FileLength = proto.Uint64(uint64(len(fileData)))
+		m.FileLength = proto.Uint64(uploaded.FileLength)
 	case *waE2E.AudioMessage:
 		m.URL = proto.String(uploaded.URL)
 		m.DirectPath = proto.String(uploaded.DirectPath)
@@ -990,7 +1079,15 @@ func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.Uploa
 		m.Mimetype = proto.String(mimeType)
 		m.FileEncSHA256 = uploaded.FileEncSHA256
 		m.FileSHA256 = uploaded.FileSHA256
-		m.FileLength = proto.Uint64(uint64(len(fileData)))
+		m.FileLength = proto.Uint64(uploaded.FileLength)
+	case *waE2E.StickerMessage:
+		m.URL = proto.String(uploaded.URL)
+		m.DirectPath = proto.String(uploaded.DirectPath)
+		m.MediaKey = uploaded.MediaKey
+		m.Mimetype = proto.String(mimeType)
+		m.FileEncSHA256 = uploaded.FileEncSHA256
+		m.FileSHA256 = uploaded.FileSHA256
+		m.FileLength = proto.Uint64(uploaded.FileLength)
 	case *waE2E.DocumentMessage:
 		m.URL = proto.String(uploaded.URL)
 		m.DirectPath = proto.String(uploaded.DirectPath)
@@ -998,10 +1095,45 @@ func (ms *Sender) setCommonMediaFields(msg interface{}, uploaded whatsmeow.Uploa
 		m.Mimetype = proto.String(mimeType)
 		m.FileEncSHA256 = uploaded.FileEncSHA256
 		m.FileSHA256 = uploaded.FileSHA256
-		m.FileLength = proto.Uint64(uint64(len(fileData)))
+		m.FileLength = proto.Uint64(uploaded.FileLength)
 	}
 }
 
+// setMediaDetails adds what the transcoder measured to a media message.
+func (ms *Sender) setMediaDetails(msg interface{}, details *output.MediaDetails) {
+	if details == nil {
+		return
+	}
+
+	seconds := optionalUint32(uint32(details.Duration.Round(time.Second) / time.Second))
+
+	switch m := msg.(type) {
+	case *waE2E.ImageMessage:
+		m.Width = optionalUint32(details.Width)
+		m.Height = optionalUint32(details.Height)
+		m.JPEGThumbnail = details.Thumbnail
+	case *waE2E.VideoMessage:
+		m.Width = optionalUint32(details.Width)
+		m.Height = optionalUint32(details.Height)
+		m.JPEGThumbnail = details.Thumbnail
+		m.Seconds = seconds
+	case *waE2E.AudioMessage:
+		m.Seconds = seconds
+		m.Waveform = details.Waveform
+	case *waE2E.StickerMessage:
+		m.Width = optionalUint32(details.Width)
+		m.Height = optionalUint32(details.Height)
+	}
+}
+
+func optionalUint32(value uint32) *uint32 {
+	if value == 0 {
+		return nil
+	}
+
+	return proto.Uint32(value)
+}
+
 func (ms *Sender) setViewOnceIfNeeded(msg interface{}, viewOnce bool) {
 	if !viewOnce {
 		return
@@ -1017,10 +1149,42 @@ func (ms *Sender) setViewOnceIfNeeded(msg interface{}, viewOnce bool) {
 	}
 }
 
+// send counts msg against the tenant's daily quota and queues it behind the
+// other messages of the session. Every message a session sends goes through
+// send, sendProtocol or sendPeer.
+func (ms *Sender) send(ctx context.Context, client *Client, to types.JID, msg *waE2E.Message) (*whatsmeow.SendResponse, error) {
+	if err := ms.consume(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return ms.waClient.sendQueue.Send(ctx, client, to, msg)
+}
+
+// sendProtocol queues a protocol message, such as a revoke or an edit,
+// without counting it against the quota: it changes a message already
+// counted.
+func (ms *Sender) sendProtocol(ctx context.Context, client *Client, to types.JID, msg *waE2E.Message) (*whatsmeow.SendResponse, error) {
+	return ms.waClient.sendQueue.Send(ctx, client, to, msg)
+}
+
+// sendPeer queues a message for the session's own devices. It reaches no
+// contact, so it is not counted against the quota either.
+func (ms *Sender) sendPeer(ctx context.Context, client *Client, msg *waE2E.Message) (*whatsmeow.SendResponse, error) {
+	return ms.waClient.sendQueue.SendPeer(ctx, client, msg)
+}
+
+func (ms *Sender) consume(ctx context.Context, client *Client) error {
+	if quota := ms.waClient.quota; quota != nil {
+		return quota.Consume(ctx, client.SessionID)
+	}
+
+	return nil
+}
+
 func (ms *Sender) sendPreparedMessage(ctx context.Context, client *Client, recipientJID types.JID, message *waE2E.Message) (*whatsmeow.SendResponse, error) {
-	resp, err := client.WAClient.SendMessage(ctx, recipientJID, message)
+	resp, err := ms.send(ctx, client, recipientJID, message)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send media message: %w", err)
 	}
-	return &resp, nil
+	return resp, nil
 }
@@ -0,0 +1,205 @@
+package waclient
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/internal/core/domain/shared"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// resolvedNumberTTL is how long a number found on WhatsApp keeps its JID.
+	resolvedNumberTTL = 24 * time.Hour
+
+	// unknownNumberTTL is how long a number not found on WhatsApp is left
+	// alone before it is looked up again.
+	unknownNumberTTL = time.Hour
+
+	// numberCacheSize is the most numbers cached. A full cache drops its
+	// expired entries and then arbitrary ones to make room.
+	numberCacheSize = 10000
+)
+
+// NumberResolution is the JID WhatsApp knows a phone number under.
+type NumberResolution struct {
+	Query        string
+	Candidates   []string
+	JID          types.JID
+	IsInWhatsApp bool
+	VerifiedName string
+}
+
+// NumberResolver maps phone numbers to the JID they are registered under.
+// Brazilian mobile numbers may be registered with or without the ninth
+// digit, so every form is looked up with IsOnWhatsApp and the registered one
+// is cached per session.
+type NumberResolver struct {
+	mutex      sync.RWMutex
+	cache      map[string]cachedNumber
+	maxEntries int
+}
+
+type cachedNumber struct {
+	resolution NumberResolution
+	expiresAt  time.Time
+}
+
+func NewNumberResolver() *NumberResolver {
+	return &NumberResolver{
+		cache:      make(map[string]cachedNumber),
+		maxEntries: numberCacheSize,
+	}
+}
+
+// Resolve looks up phone numbers on WhatsApp. Results are returned in query
+// order; numbers not on WhatsApp keep the JID of the number as given.
+func (r *NumberResolver) Resolve(client *Client, queries []string) ([]*NumberResolution, error) {
+	results := make([]*NumberResolution, len(queries))
+
+	var (
+		pending []*NumberResolution
+		lookup  []string
+	)
+
+	for i, query := range queries {
+		phone := shared.NormalizePhoneNumber(query)
+
+		if cached, ok := r.get(client.SessionID, phone); ok {
+			cached.Query = query
+			results[i] = &cached
+
+			continue
+		}
+
+		result := &NumberResolution{
+			Query:      query,
+			Candidates: shared.PhoneVariants(phone),
+			JID:        types.NewJID(phone, types.DefaultUserServer),
+		}
+
+		for _, candidate := range result.Candidates {
+			lookup = append(lookup, "+"+candidate)
+		}
+
+		results[i] = result
+		pending = append(pending, result)
+	}
+
+	if len(lookup) == 0 {
+		return results, nil
+	}
+
+	response, err := client.WAClient.IsOnWhatsApp(lookup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check numbers: %w", err)
+	}
+
+	registered := make(map[string]types.IsOnWhatsAppResponse, len(response))
+
+	for _, item := range response {
+		if item.IsIn {
+			registered[shared.NormalizePhoneNumber(item.Query)] = item
+		}
+	}
+
+	for _, result := range pending {
+		for _, candidate := range result.Candidates {
+			if item, ok := registered[candidate]; ok {
+				result.JID = item.JID
+				result.IsInWhatsApp = true
+
+				if item.VerifiedName != nil {
+					result.VerifiedName = item.VerifiedName.Details.GetVerifiedName()
+				}
+
+				break
+			}
+		}
+
+		r.set(client.SessionID, result)
+	}
+
+	return results, nil
+}
+
+// ResolveJID returns the JID to address a recipient by. JIDs and numbers
+// with a single possible form are used as given; a failed lookup falls back
+// to the number as given.
+func (r *NumberResolver) ResolveJID(client *Client, to string) (types.JID, error) {
+	if strings.Contains(to, "@") || len(shared.PhoneVariants(shared.NormalizePhoneNumber(to))) == 1 {
+		return parseJID(to)
+	}
+
+	results, err := r.Resolve(client, []string{to})
+	if err != nil {
+		return parseJID(to)
+	}
+
+	return results[0].JID, nil
+}
+
+func (r *NumberResolver) get(sessionID, phone string) (NumberResolution, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	cached, ok := r.cache[sessionID+"|"+phone]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return NumberResolution{}, false
+	}
+
+	return cached.resolution, true
+}
+
+// set caches a resolution under every form of the number, so a later lookup
+// of either form finds it.
+func (r *NumberResolver) set(sessionID string, resolution *NumberResolution) {
+	ttl := unknownNumberTTL
+	if resolution.IsInWhatsApp {
+		ttl = resolvedNumberTTL
+	}
+
+	now := time.Now()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.makeRoom(len(resolution.Candidates), now)
+
+	for _, candidate := range resolution.Candidates {
+		r.cache[sessionID+"|"+candidate] = cachedNumber{
+			resolution: *resolution,
+			expiresAt:  now.Add(ttl),
+		}
+	}
+}
+
+// makeRoom evicts entries until n more fit in the cache: the expired ones
+// first, then arbitrary ones in map order. The caller holds the lock.
+func (r *NumberResolver) makeRoom(n int, now time.Time) {
+	if len(r.cache)+n <= r.maxEntries {
+		return
+	}
+
+	for key, cached := range r.cache {
+		if now.After(cached.expiresAt) {
+			delete(r.cache, key)
+		}
+	}
+
+	for key := range r.cache {
+		if len(r.cache)+n <= r.maxEntries {
+			return
+		}
+
+		delete(r.cache, key)
+	}
+}
+
+// resolveJID returns the JID to address a recipient of client by.
+func (wac *WAClient) resolveJID(client *Client, to string) (types.JID, error) {
+	return wac.numbers.ResolveJID(client, to)
+}
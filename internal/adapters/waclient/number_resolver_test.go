@@ -0,0 +1,65 @@
+package waclient
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"zpwoot/internal/core/domain/shared"
+)
+
+func newTestResolution(phone string) *NumberResolution {
+	return &NumberResolution{Query: phone, Candidates: shared.PhoneVariants(phone)}
+}
+
+func TestNumberCacheSize(t *testing.T) {
+	resolver := NewNumberResolver()
+	resolver.maxEntries = 6
+
+	for i := range 20 {
+		resolution := newTestResolution("55119" + strconv.Itoa(10000000+i))
+		resolver.set("session-1", resolution)
+
+		if len(resolver.cache) > resolver.maxEntries {
+			t.Fatalf("cache holds %d entries, want at most %d", len(resolver.cache), resolver.maxEntries)
+		}
+
+		// The number just resolved is always found, under every form.
+		for _, candidate := range resolution.Candidates {
+			if _, ok := resolver.get("session-1", candidate); !ok {
+				t.Fatalf("%s not cached right after it was resolved", candidate)
+			}
+		}
+	}
+}
+
+func TestNumberCacheEvictsExpiredFirst(t *testing.T) {
+	resolver := NewNumberResolver()
+	resolver.maxEntries = 4
+
+	live := newTestResolution("5511988888888")
+	resolver.set("session-1", live)
+
+	expired := newTestResolution("5511977777777")
+	resolver.set("session-1", expired)
+
+	for _, candidate := range expired.Candidates {
+		entry := resolver.cache["session-1|"+candidate]
+		entry.expiresAt = time.Now().Add(-time.Minute)
+		resolver.cache["session-1|"+candidate] = entry
+	}
+
+	resolver.set("session-1", newTestResolution("5511966666666"))
+
+	for _, candidate := range live.Candidates {
+		if _, ok := resolver.get("session-1", candidate); !ok {
+			t.Errorf("live entry %s evicted while expired ones were cached", candidate)
+		}
+	}
+
+	for _, candidate := range expired.Candidates {
+		if _, ok := resolver.cache["session-1|"+candidate]; ok {
+			t.Errorf("expired entry %s kept in a full cache", candidate)
+		}
+	}
+}
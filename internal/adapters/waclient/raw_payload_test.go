@@ -0,0 +1,143 @@
+package waclient
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
+)
+
+// capturingSender keeps every webhook event it is given, by webhook URL.
+type capturingSender struct {
+	mutex  sync.Mutex
+	events map[string][]*output.WebhookEvent
+}
+
+func (s *capturingSender) SendWebhook(_ context.Context, url string, _ *string, event *output.WebhookEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.events == nil {
+		s.events = make(map[string][]*output.WebhookEvent)
+	}
+	s.events[url] = append(s.events[url], event)
+
+	return nil
+}
+
+func (s *capturingSender) take(url string) []*output.WebhookEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sent := s.events[url]
+	delete(s.events, url)
+
+	return sent
+}
+
+const (
+	rawHookURL        = "https://example.com/raw"
+	normalizedHookURL = "https://example.com/normalized"
+)
+
+// rawAndNormalized is a webhook repository with one webhook of each format,
+// both subscribed to eventType.
+type rawAndNormalized struct {
+	webhook.Repository
+
+	eventType EventType
+}
+
+func (r rawAndNormalized) ListForSession(_ context.Context, sessionID string) ([]*webhook.Webhook, error) {
+	events := []string{string(r.eventType)}
+
+	raw := webhook.NewWebhook(sessionID, rawHookURL, events)
+	raw.Format = webhook.FormatRaw
+
+	return []*webhook.Webhook{raw, webhook.NewWebhook(sessionID, normalizedHookURL, events)}, nil
+}
+
+// asJSONMap decodes v the way a webhook receiver would see it.
+func asJSONMap(t *testing.T, v interface{}) map[string]interface{} {
+	t.Helper()
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", v, err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode %T: %v", v, err)
+	}
+
+	return decoded
+}
+
+// TestRawWebhooksKeepLegacyPayloads checks that raw webhooks receive the
+// whatsmeow structure, under the name the event had before payloads were
+// normalized, while normalized webhooks get the new name and schema.
+func TestRawWebhooksKeepLegacyPayloads(t *testing.T) {
+	samples := sampleEvents()
+
+	tests := []struct {
+		eventType EventType
+		rawType   EventType
+	}{
+		{EventQRCode, legacyEventQR},
+		{EventReceipt, legacyEventReadReceipt},
+		{EventPresence, EventPresence},
+		{EventChatPresence, EventChatPresence},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.eventType), func(t *testing.T) {
+			sender := &capturingSender{}
+			handler := NewDefaultEventHandler(logger.New(), sender, rawAndNormalized{eventType: tt.eventType}, nil, nil)
+			sample := samples[tt.eventType]
+
+			if err := handler.HandleEvent(&Client{SessionID: "session-1"}, sample); err != nil {
+				t.Fatalf("HandleEvent(%T): %v", sample, err)
+			}
+
+			raw := sender.take(rawHookURL)
+			if len(raw) != 1 {
+				t.Fatalf("raw webhook got %d events, want 1", len(raw))
+			}
+
+			if raw[0].Type != string(tt.rawType) {
+				t.Errorf("raw event type = %s, want %s", raw[0].Type, tt.rawType)
+			}
+
+			if raw[0].SchemaVersion != 0 {
+				t.Errorf("raw event schema version = %d, want none", raw[0].SchemaVersion)
+			}
+
+			if want := asJSONMap(t, sample); !reflect.DeepEqual(raw[0].Data, want) {
+				t.Errorf("raw event data = %v, want the %T %v", raw[0].Data, sample, want)
+			}
+
+			normalized := sender.take(normalizedHookURL)
+			if len(normalized) != 1 {
+				t.Fatalf("normalized webhook got %d events, want 1", len(normalized))
+			}
+
+			if normalized[0].Type != string(tt.eventType) {
+				t.Errorf("normalized event type = %s, want %s", normalized[0].Type, tt.eventType)
+			}
+
+			if normalized[0].ID != raw[0].ID {
+				t.Errorf("event IDs differ between formats: %s and %s", normalized[0].ID, raw[0].ID)
+			}
+
+			if reflect.DeepEqual(normalized[0].Data, raw[0].Data) {
+				t.Errorf("normalized webhook got the raw payload %v", normalized[0].Data)
+			}
+		})
+	}
+}
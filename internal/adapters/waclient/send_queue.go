@@ -0,0 +1,402 @@
+package waclient
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// sendQueueIdleTimeout is how long a session worker waits for new
+	// messages before it exits.
+	sendQueueIdleTimeout = 5 * time.Minute
+
+	// queuedSendTimeout bounds a single send once it leaves the queue.
+	queuedSendTimeout = 2 * time.Minute
+
+	// typingPerChar, minTyping and maxTyping size the typing indicator shown
+	// before a text message.
+	typingPerChar = 50 * time.Millisecond
+	minTyping     = time.Second
+	maxTyping     = 5 * time.Second
+)
+
+// SendQueueConfig paces the outgoing messages of every session.
+type SendQueueConfig struct {
+	// MessagesPerMinute caps the send rate of a session; 0 disables the cap.
+	MessagesPerMinute int
+
+	// MinDelay and MaxDelay bound the random pause added before every send.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	// Size is how many messages a session may have waiting before sends are
+	// rejected with ErrSendQueueFull.
+	Size int
+
+	// Typing shows the typing indicator before text messages for a time that
+	// grows with the text length.
+	Typing bool
+}
+
+func DefaultSendQueueConfig() SendQueueConfig {
+	return SendQueueConfig{
+		MessagesPerMinute: 60,
+		MinDelay:          500 * time.Millisecond,
+		MaxDelay:          2 * time.Second,
+		Size:              500,
+	}
+}
+
+// SendQueue serializes the outgoing messages of each session and spaces them
+// out so a session does not send at a rate WhatsApp flags as automated. Each
+// session gets a worker that starts with its first message and exits once
+// the session has been idle for a while.
+type SendQueue struct {
+	config SendQueueConfig
+	logger *logger.Logger
+
+	// onSent is called with the outcome of every send.
+	onSent func(job *sendJob, resp whatsmeow.SendResponse, err error)
+
+	// senderOf returns what the messages of a client are sent through: its
+	// whatsmeow client, unless a test replaces it.
+	senderOf    func(client *Client) messageSender
+	idleTimeout time.Duration
+
+	mutex    sync.Mutex
+	sessions map[string]*sessionQueue
+}
+
+// messageSender is the part of whatsmeow.Client the queue sends through.
+type messageSender interface {
+	SendMessage(ctx context.Context, to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error)
+	SendChatPresence(jid types.JID, state types.ChatPresence, media types.ChatPresenceMedia) error
+}
+
+type sessionQueue struct {
+	jobs     chan *sendJob
+	lastSent time.Time
+}
+
+type sendJob struct {
+	ctx      context.Context
+	client   *Client
+	to       types.JID
+	message  *waE2E.Message
+	id       types.MessageID
+	queuedAt time.Time
+	queued   bool
+	// peer sends to the session's own devices, as for history sync
+	// requests.
+	peer   bool
+	result chan sendResult
+}
+
+type sendResult struct {
+	resp whatsmeow.SendResponse
+	err  error
+}
+
+func NewSendQueue(config SendQueueConfig, logger *logger.Logger, onSent func(job *sendJob, resp whatsmeow.SendResponse, err error)) *SendQueue {
+	if config.MaxDelay < config.MinDelay {
+		config.MaxDelay = config.MinDelay
+	}
+
+	if config.Size <= 0 {
+		config.Size = DefaultSendQueueConfig().Size
+	}
+
+	return &SendQueue{
+		config:      config,
+		logger:      logger,
+		onSent:      onSent,
+		senderOf:    whatsmeowSender,
+		idleTimeout: sendQueueIdleTimeout,
+		sessions:    make(map[string]*sessionQueue),
+	}
+}
+
+func whatsmeowSender(client *Client) messageSender {
+	return client.WAClient
+}
+
+// Send queues msg behind the other messages of its session. A queued send
+// (see output.WithQueuedSend) returns at once with the ID the message will
+// be sent under; any other send waits for the message to go out. A send
+// whose context ends while it waits returns the context's error, and the
+// message is dropped when its turn comes.
+func (q *SendQueue) Send(ctx context.Context, client *Client, to types.JID, msg *waE2E.Message) (*whatsmeow.SendResponse, error) {
+	return q.submit(ctx, &sendJob{
+		client:  client,
+		to:      to,
+		message: msg,
+	})
+}
+
+// SendPeer queues msg for the session's own devices, like Send.
+func (q *SendQueue) SendPeer(ctx context.Context, client *Client, msg *waE2E.Message) (*whatsmeow.SendResponse, error) {
+	return q.submit(ctx, &sendJob{
+		client:  client,
+		to:      client.WAClient.Store.ID.ToNonAD(),
+		message: msg,
+		peer:    true,
+	})
+}
+
+func (q *SendQueue) submit(ctx context.Context, job *sendJob) (*whatsmeow.SendResponse, error) {
+	client := job.client
+
+	job.ctx = ctx
+	job.id = client.WAClient.GenerateMessageID()
+	job.queuedAt = time.Now()
+	job.queued = output.IsQueuedSend(ctx)
+
+	if job.queued {
+		job.ctx = context.WithoutCancel(ctx)
+	} else {
+		job.result = make(chan sendResult, 1)
+	}
+
+	if err := q.enqueue(client.SessionID, job); err != nil {
+		return nil, err
+	}
+
+	if job.queued {
+		return &whatsmeow.SendResponse{ID: job.id, Timestamp: job.queuedAt}, nil
+	}
+
+	select {
+	case result := <-job.result:
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		return &result.resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Pending returns how many messages of a session are waiting to be sent.
+func (q *SendQueue) Pending(sessionID string) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if sq, ok := q.sessions[sessionID]; ok {
+		return len(sq.jobs)
+	}
+
+	return 0
+}
+
+func (q *SendQueue) enqueue(sessionID string, job *sendJob) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	sq, ok := q.sessions[sessionID]
+	if !ok {
+		sq = &sessionQueue{jobs: make(chan *sendJob, q.config.Size)}
+		q.sessions[sessionID] = sq
+
+		go q.run(sessionID, sq)
+	}
+
+	select {
+	case sq.jobs <- job:
+		return nil
+	default:
+		return ErrSendQueueFull
+	}
+}
+
+// run sends the messages of a session one at a time. The worker removes its
+// queue only while holding the lock and with no message waiting, so enqueue
+// never hands a message to a worker that has exited.
+func (q *SendQueue) run(sessionID string, sq *sessionQueue) {
+	idle := time.NewTimer(q.idleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case job := <-sq.jobs:
+			q.process(sq, job)
+			idle.Reset(q.idleTimeout)
+		case <-idle.C:
+			q.mutex.Lock()
+			if len(sq.jobs) == 0 {
+				delete(q.sessions, sessionID)
+				q.mutex.Unlock()
+
+				return
+			}
+			q.mutex.Unlock()
+
+			idle.Reset(q.idleTimeout)
+		}
+	}
+}
+
+func (q *SendQueue) process(sq *sessionQueue, job *sendJob) {
+	// The caller of a send it no longer waits for has already been given
+	// an error, so the message must not go out.
+	if err := job.ctx.Err(); err != nil {
+		q.finish(job, whatsmeow.SendResponse{}, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(job.ctx, queuedSendTimeout)
+	defer cancel()
+
+	if err := q.wait(ctx, sq); err != nil {
+		q.finish(job, whatsmeow.SendResponse{}, err)
+		return
+	}
+
+	if q.config.Typing {
+		q.showTyping(ctx, job)
+	}
+
+	resp, err := q.senderOf(job.client).SendMessage(ctx, job.to, job.message, whatsmeow.SendRequestExtra{ID: job.id, Peer: job.peer})
+	sq.lastSent = time.Now()
+
+	q.finish(job, resp, err)
+}
+
+// wait holds a send until the session's rate allows it, plus a random delay
+// so consecutive messages do not go out at a fixed interval.
+func (q *SendQueue) wait(ctx context.Context, sq *sessionQueue) error {
+	delay := q.jitter()
+
+	if q.config.MessagesPerMinute > 0 && !sq.lastSent.IsZero() {
+		interval := time.Minute / time.Duration(q.config.MessagesPerMinute)
+		if remaining := time.Until(sq.lastSent.Add(interval)); remaining > 0 {
+			delay += remaining
+		}
+	}
+
+	return sleep(ctx, delay)
+}
+
+func (q *SendQueue) jitter() time.Duration {
+	spread := q.config.MaxDelay - q.config.MinDelay
+	if spread <= 0 {
+		return q.config.MinDelay
+	}
+
+	return q.config.MinDelay + rand.N(spread)
+}
+
+// showTyping shows the typing indicator in the chat of a text message for a
+// time proportional to its length. The indicator is cosmetic, so failures
+// only get logged.
+func (q *SendQueue) showTyping(ctx context.Context, job *sendJob) {
+	text := job.message.GetConversation()
+	if text == "" {
+		text = job.message.GetExtendedTextMessage().GetText()
+	}
+
+	if text == "" {
+		return
+	}
+
+	wa := q.senderOf(job.client)
+
+	if err := wa.SendChatPresence(job.to, types.ChatPresenceComposing, types.ChatPresenceMediaText); err != nil {
+		q.logger.Debug().Err(err).Str("session_id", job.client.SessionID).Msg("Failed to send typing indicator")
+		return
+	}
+
+	duration := min(max(time.Duration(len(text))*typingPerChar, minTyping), maxTyping)
+	_ = sleep(ctx, duration)
+
+	if err := wa.SendChatPresence(job.to, types.ChatPresencePaused, types.ChatPresenceMediaText); err != nil {
+		q.logger.Debug().Err(err).Str("session_id", job.client.SessionID).Msg("Failed to clear typing indicator")
+	}
+}
+
+func (q *SendQueue) finish(job *sendJob, resp whatsmeow.SendResponse, err error) {
+	if q.onSent != nil {
+		q.onSent(job, resp, err)
+	}
+
+	if job.result != nil {
+		job.result <- sendResult{resp: resp, err: err}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// queuedSendResult is the outcome of a queued send, handed to the event
+// handler to report it through webhooks.
+type queuedSendResult struct {
+	MessageID types.MessageID
+	To        types.JID
+	Type      string
+	QueuedAt  time.Time
+	SentAt    time.Time
+	Err       error
+}
+
+// messageSent records a sent message in the message store so outbound
+// traffic is kept alongside what the event handler stores for inbound,
+// reports the server ack through the MessageStatus webhook and reports the
+// outcome of queued sends. Protocol messages, such as revokes, edits and
+// history sync requests, are not messages of their own and are not
+// recorded.
+func (wac *WAClient) messageSent(job *sendJob, resp whatsmeow.SendResponse, err error) {
+	if err == nil && job.message.GetProtocolMessage() == nil {
+		wac.storeMessage(job.ctx, newOutgoingMessage(job.client, job.to, job.message, resp))
+		wac.forwardEvent(job.client, &messageStatusChange{
+			MessageID: resp.ID,
+			Chat:      job.to,
+			Status:    message.StatusSent,
+			Timestamp: resp.Timestamp,
+		})
+	}
+
+	if !job.queued {
+		return
+	}
+
+	if err != nil {
+		wac.logger.Warn().
+			Err(err).
+			Str("session_id", job.client.SessionID).
+			Str("message_id", job.id).
+			Str("to", job.to.String()).
+			Msg("Queued message failed to send")
+	}
+
+	wac.forwardEvent(job.client, &queuedSendResult{
+		MessageID: job.id,
+		To:        job.to,
+		Type:      getMessageType(job.message),
+		QueuedAt:  job.queuedAt,
+		SentAt:    resp.Timestamp,
+		Err:       err,
+	})
+}
@@ -0,0 +1,522 @@
+package waclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/ports/output"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	testOwnJID    = types.NewJID("5511888888888", types.DefaultUserServer)
+	testRecipient = types.NewJID("5511999999999", types.DefaultUserServer)
+)
+
+type sentMessage struct {
+	to   types.JID
+	id   types.MessageID
+	peer bool
+	at   time.Time
+}
+
+// fakeSender records the messages the queue sends instead of sending them.
+// When release is set, every send waits for a value on it.
+type fakeSender struct {
+	mutex   sync.Mutex
+	sent    []sentMessage
+	err     error
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *fakeSender) SendMessage(ctx context.Context, to types.JID, _ *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	if s.started != nil {
+		s.started <- struct{}{}
+	}
+
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return whatsmeow.SendResponse{}, ctx.Err()
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sent := sentMessage{to: to, id: extra[0].ID, peer: extra[0].Peer, at: time.Now()}
+	s.sent = append(s.sent, sent)
+
+	if s.err != nil {
+		return whatsmeow.SendResponse{}, s.err
+	}
+
+	return whatsmeow.SendResponse{ID: sent.id, Timestamp: sent.at}, nil
+}
+
+func (s *fakeSender) SendChatPresence(types.JID, types.ChatPresence, types.ChatPresenceMedia) error {
+	return nil
+}
+
+func (s *fakeSender) messages() []sentMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]sentMessage(nil), s.sent...)
+}
+
+// sentOutcome is what the queue reported to its onSent hook.
+type sentOutcome struct {
+	job  *sendJob
+	resp whatsmeow.SendResponse
+	err  error
+}
+
+type sendQueueTest struct {
+	queue    *SendQueue
+	sender   *fakeSender
+	outcomes chan sentOutcome
+}
+
+func newSendQueueTest(config SendQueueConfig, sender *fakeSender) *sendQueueTest {
+	tt := &sendQueueTest{sender: sender, outcomes: make(chan sentOutcome, 16)}
+
+	tt.queue = NewSendQueue(config, logger.New(), func(job *sendJob, resp whatsmeow.SendResponse, err error) {
+		tt.outcomes <- sentOutcome{job: job, resp: resp, err: err}
+	})
+	tt.queue.senderOf = func(*Client) messageSender { return sender }
+
+	return tt
+}
+
+func (tt *sendQueueTest) outcome(t *testing.T) sentOutcome {
+	t.Helper()
+
+	select {
+	case outcome := <-tt.outcomes:
+		return outcome
+	case <-time.After(time.Second):
+		t.Fatal("no send reported")
+		return sentOutcome{}
+	}
+}
+
+func newTestClient() *Client {
+	own := testOwnJID
+
+	return &Client{SessionID: "session-1", WAClient: &whatsmeow.Client{Store: &store.Device{ID: &own}}}
+}
+
+func textMessage(text string) *waE2E.Message {
+	return &waE2E.Message{Conversation: proto.String(text)}
+}
+
+func TestSendQueueJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max time.Duration
+	}{
+		{"no delay", 0, 0},
+		{"fixed delay", 20 * time.Millisecond, 20 * time.Millisecond},
+		{"random delay", 10 * time.Millisecond, 30 * time.Millisecond},
+		{"max below min", 20 * time.Millisecond, 5 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewSendQueue(SendQueueConfig{MinDelay: tt.min, MaxDelay: tt.max}, logger.New(), nil)
+
+			for range 100 {
+				delay := queue.jitter()
+				if delay < tt.min || delay > max(tt.min, tt.max) {
+					t.Fatalf("jitter() = %v, want between %v and %v", delay, tt.min, max(tt.min, tt.max))
+				}
+			}
+		})
+	}
+}
+
+func TestSendQueueWait(t *testing.T) {
+	tests := []struct {
+		name              string
+		messagesPerMinute int
+		lastSent          time.Duration
+		minWait, maxWait  time.Duration
+	}{
+		{"first message", 600, 0, 0, 50 * time.Millisecond},
+		{"no rate limit", 0, -time.Millisecond, 0, 50 * time.Millisecond},
+		{"right after a send", 600, -time.Millisecond, 90 * time.Millisecond, 200 * time.Millisecond},
+		{"interval already passed", 600, -time.Second, 0, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewSendQueue(SendQueueConfig{MessagesPerMinute: tt.messagesPerMinute}, logger.New(), nil)
+
+			sq := &sessionQueue{}
+			if tt.lastSent != 0 {
+				sq.lastSent = time.Now().Add(tt.lastSent)
+			}
+
+			start := time.Now()
+
+			if err := queue.wait(context.Background(), sq); err != nil {
+				t.Fatalf("wait: %v", err)
+			}
+
+			if waited := time.Since(start); waited < tt.minWait || waited > tt.maxWait {
+				t.Errorf("waited %v, want between %v and %v", waited, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestSendQueueWaitStopsWithContext(t *testing.T) {
+	queue := NewSendQueue(SendQueueConfig{MinDelay: time.Minute}, logger.New(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := queue.wait(ctx, &sessionQueue{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestSendQueuePacesMessages(t *testing.T) {
+	const interval = 50 * time.Millisecond
+
+	tt := newSendQueueTest(SendQueueConfig{MessagesPerMinute: int(time.Minute / interval)}, &fakeSender{})
+	client := newTestClient()
+	ctx := output.WithQueuedSend(context.Background())
+
+	for i := range 3 {
+		if _, err := tt.queue.Send(ctx, client, testRecipient, textMessage("hello")); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+
+	for range 3 {
+		tt.outcome(t)
+	}
+
+	sent := tt.sender.messages()
+	for i := 1; i < len(sent); i++ {
+		// Timer resolution may cut a few hundred microseconds short.
+		if gap := sent[i].at.Sub(sent[i-1].at); gap < interval-time.Millisecond {
+			t.Errorf("message %d sent %v after the previous one, want at least %v", i, gap, interval)
+		}
+	}
+}
+
+func TestSendQueueSend(t *testing.T) {
+	sendErr := errors.New("not connected")
+
+	tests := []struct {
+		name    string
+		queued  bool
+		sendErr error
+	}{
+		{"sync", false, nil},
+		{"sync failure", false, sendErr},
+		{"queued", true, nil},
+		{"queued failure", true, sendErr},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sender := &fakeSender{err: tc.sendErr}
+			if tc.queued {
+				// The send may not happen before a queued Send returns.
+				sender.release = make(chan struct{})
+			}
+
+			tt := newSendQueueTest(SendQueueConfig{}, sender)
+
+			ctx := context.Background()
+			if tc.queued {
+				ctx = output.WithQueuedSend(ctx)
+			}
+
+			resp, err := tt.queue.Send(ctx, newTestClient(), testRecipient, textMessage("hello"))
+
+			switch {
+			case tc.queued:
+				if err != nil {
+					t.Fatalf("queued Send: %v", err)
+				}
+
+				if resp.ID == "" || resp.Timestamp.IsZero() {
+					t.Fatalf("queued Send = %+v, want the ID and time it was queued", resp)
+				}
+
+				if sent := sender.messages(); len(sent) != 0 {
+					t.Fatalf("queued Send returned after sending %v", sent)
+				}
+
+				close(sender.release)
+			case tc.sendErr != nil:
+				if !errors.Is(err, tc.sendErr) {
+					t.Fatalf("Send = %v, want %v", err, tc.sendErr)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+
+			outcome := tt.outcome(t)
+
+			if outcome.job.queued != tc.queued {
+				t.Errorf("job.queued = %v, want %v", outcome.job.queued, tc.queued)
+			}
+
+			if !errors.Is(outcome.err, tc.sendErr) {
+				t.Errorf("onSent error = %v, want %v", outcome.err, tc.sendErr)
+			}
+
+			sent := sender.messages()
+			if len(sent) != 1 || sent[0].to != testRecipient || sent[0].id != outcome.job.id {
+				t.Fatalf("sent %v, want one message to %s as %s", sent, testRecipient, outcome.job.id)
+			}
+
+			if resp != nil && resp.ID != outcome.job.id {
+				t.Errorf("Send returned ID %s, sent as %s", resp.ID, outcome.job.id)
+			}
+		})
+	}
+}
+
+func TestSendQueueSendPeer(t *testing.T) {
+	tt := newSendQueueTest(SendQueueConfig{}, &fakeSender{})
+
+	if _, err := tt.queue.SendPeer(context.Background(), newTestClient(), textMessage("history")); err != nil {
+		t.Fatalf("SendPeer: %v", err)
+	}
+
+	sent := tt.sender.messages()
+	if len(sent) != 1 || !sent[0].peer || sent[0].to != testOwnJID {
+		t.Fatalf("sent %v, want one peer message to %s", sent, testOwnJID)
+	}
+}
+
+func TestSendQueueFull(t *testing.T) {
+	sender := &fakeSender{started: make(chan struct{}, 1), release: make(chan struct{})}
+	tt := newSendQueueTest(SendQueueConfig{Size: 1}, sender)
+	client := newTestClient()
+	ctx := output.WithQueuedSend(context.Background())
+
+	// The worker takes the first message and holds it, the second one
+	// fills the queue.
+	if _, err := tt.queue.Send(ctx, client, testRecipient, textMessage("first")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	<-sender.started
+
+	if _, err := tt.queue.Send(ctx, client, testRecipient, textMessage("second")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if _, err := tt.queue.Send(ctx, client, testRecipient, textMessage("third")); !errors.Is(err, ErrSendQueueFull) {
+		t.Fatalf("Send on a full queue = %v, want %v", err, ErrSendQueueFull)
+	}
+
+	if pending := tt.queue.Pending(client.SessionID); pending != 1 {
+		t.Errorf("Pending = %d, want 1", pending)
+	}
+
+	close(sender.release)
+
+	for range 2 {
+		tt.outcome(t)
+	}
+}
+
+func TestSendQueueWorkerExitsWhenIdle(t *testing.T) {
+	tt := newSendQueueTest(SendQueueConfig{}, &fakeSender{})
+	tt.queue.idleTimeout = 20 * time.Millisecond
+	client := newTestClient()
+
+	if _, err := tt.queue.Send(context.Background(), client, testRecipient, textMessage("hello")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	tt.outcome(t)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		tt.queue.mutex.Lock()
+		_, running := tt.queue.sessions[client.SessionID]
+		tt.queue.mutex.Unlock()
+
+		if !running {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("worker still running after the idle timeout")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A new message starts a new worker.
+	if _, err := tt.queue.Send(context.Background(), client, testRecipient, textMessage("again")); err != nil {
+		t.Fatalf("Send after the worker exited: %v", err)
+	}
+
+	if sent := tt.sender.messages(); len(sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sent))
+	}
+}
+
+func TestSendQueueDropsCancelledSends(t *testing.T) {
+	sender := &fakeSender{started: make(chan struct{}, 1), release: make(chan struct{})}
+	tt := newSendQueueTest(SendQueueConfig{}, sender)
+	client := newTestClient()
+
+	if _, err := tt.queue.Send(output.WithQueuedSend(context.Background()), client, testRecipient, textMessage("first")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	<-sender.started
+
+	// The second message waits behind the first one until its caller
+	// gives up.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := tt.queue.Send(ctx, client, testRecipient, textMessage("second")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(sender.release)
+
+	tt.outcome(t)
+
+	dropped := tt.outcome(t)
+	if !errors.Is(dropped.err, context.DeadlineExceeded) {
+		t.Errorf("onSent error = %v, want %v", dropped.err, context.DeadlineExceeded)
+	}
+
+	if sent := sender.messages(); len(sent) != 1 {
+		t.Fatalf("sent %d messages, want only the first one", len(sent))
+	}
+}
+
+// recordingMessageRepo records the messages stored through it.
+type recordingMessageRepo struct {
+	message.Repository
+
+	mutex   sync.Mutex
+	created []*message.Message
+}
+
+func (r *recordingMessageRepo) Create(_ context.Context, msg *message.Message) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.created = append(r.created, msg)
+
+	return true, nil
+}
+
+// recordingEventHandler records the events handed to it.
+type recordingEventHandler struct {
+	events []interface{}
+}
+
+func (h *recordingEventHandler) HandleEvent(_ *Client, event interface{}) error {
+	h.events = append(h.events, event)
+	return nil
+}
+
+func TestMessageSent(t *testing.T) {
+	sendErr := errors.New("not connected")
+	revoke := &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{Type: waE2E.ProtocolMessage_REVOKE.Enum()}}
+
+	tests := []struct {
+		name        string
+		message     *waE2E.Message
+		queued      bool
+		err         error
+		wantStored  bool
+		wantStatus  bool
+		wantOutcome bool
+	}{
+		{"sent", textMessage("hello"), false, nil, true, true, false},
+		{"failed", textMessage("hello"), false, sendErr, false, false, false},
+		{"protocol message", revoke, false, nil, false, false, false},
+		{"queued and sent", textMessage("hello"), true, nil, true, true, true},
+		{"queued and failed", textMessage("hello"), true, sendErr, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recordingMessageRepo{}
+			handler := &recordingEventHandler{}
+			wac := &WAClient{logger: logger.New(), messageRepo: repo, eventHandler: handler}
+
+			job := &sendJob{
+				ctx:      context.Background(),
+				client:   newTestClient(),
+				to:       testRecipient,
+				message:  tt.message,
+				id:       "3EB0000000000001",
+				queuedAt: time.Now(),
+				queued:   tt.queued,
+			}
+
+			var resp whatsmeow.SendResponse
+			if tt.err == nil {
+				resp = whatsmeow.SendResponse{ID: job.id, Timestamp: time.Now()}
+			}
+
+			wac.messageSent(job, resp, tt.err)
+
+			if stored := len(repo.created) == 1; stored != tt.wantStored {
+				t.Errorf("stored %d messages, want stored = %v", len(repo.created), tt.wantStored)
+			}
+
+			var status *messageStatusChange
+			var outcome *queuedSendResult
+
+			for _, event := range handler.events {
+				switch e := event.(type) {
+				case *messageStatusChange:
+					status = e
+				case *queuedSendResult:
+					outcome = e
+				}
+			}
+
+			if (status != nil) != tt.wantStatus {
+				t.Errorf("status change = %+v, want one: %v", status, tt.wantStatus)
+			}
+
+			if status != nil && (status.MessageID != job.id || status.Status != message.StatusSent) {
+				t.Errorf("status change = %+v, want %s sent", status, job.id)
+			}
+
+			if (outcome != nil) != tt.wantOutcome {
+				t.Fatalf("queued send result = %+v, want one: %v", outcome, tt.wantOutcome)
+			}
+
+			if outcome != nil && (outcome.MessageID != job.id || !errors.Is(outcome.Err, tt.err)) {
+				t.Errorf("queued send result = %+v, want %s with error %v", outcome, job.id, tt.err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"zpwoot/internal/core/domain/message"
 	"zpwoot/internal/core/domain/session"
 	"zpwoot/internal/core/ports/output"
 
@@ -12,18 +13,52 @@ import (
 
 type EventType string
 
+// Webhook event types. Every constant must be listed in webhookEventTypes and
+// advertised by webhook.Service.GetValidEventTypes.
 const (
-	EventMessage      EventType = "Message"
-	EventConnected    EventType = "Connected"
-	EventDisconnected EventType = "Disconnected"
-	EventQR           EventType = "QR"
-	EventPairSuccess  EventType = "PairSuccess"
-	EventReceipt      EventType = "Receipt"
-	EventReadReceipt  EventType = "ReadReceipt"
-	EventPresence     EventType = "Presence"
-	EventChatPresence EventType = "ChatPresence"
-	EventHistorySync  EventType = "HistorySync"
-	EventLoggedOut    EventType = "LoggedOut"
+	EventMessage                EventType = "Message"
+	EventMessageRevoked         EventType = "MessageRevoked"
+	EventMessageReaction        EventType = "MessageReaction"
+	EventReceipt                EventType = "Receipt"
+	EventConnected              EventType = "Connected"
+	EventDisconnected           EventType = "Disconnected"
+	EventQRCode                 EventType = "QRCode"
+	EventPairSuccess            EventType = "PairSuccess"
+	EventLoggedOut              EventType = "LoggedOut"
+	EventKeepAliveTimeout       EventType = "KeepAliveTimeout"
+	EventKeepAliveRestored      EventType = "KeepAliveRestored"
+	EventGroupInfo              EventType = "GroupInfo"
+	EventJoinedGroup            EventType = "JoinedGroup"
+	EventPicture                EventType = "Picture"
+	EventIdentityChange         EventType = "IdentityChange"
+	EventPrivacySettings        EventType = "PrivacySettings"
+	EventBlocklist              EventType = "Blocklist"
+	EventPresence               EventType = "Presence"
+	EventChatPresence           EventType = "ChatPresence"
+	EventHistorySync            EventType = "HistorySync"
+	EventOfflineSyncPreview     EventType = "OfflineSyncPreview"
+	EventOfflineSyncCompleted   EventType = "OfflineSyncCompleted"
+	EventAppState               EventType = "AppState"
+	EventCallOffer              EventType = "CallOffer"
+	EventCallAccept             EventType = "CallAccept"
+	EventCallPreAccept          EventType = "CallPreAccept"
+	EventCallTransport          EventType = "CallTransport"
+	EventCallOfferNotice        EventType = "CallOfferNotice"
+	EventCallRelayLatency       EventType = "CallRelayLatency"
+	EventCallTerminate          EventType = "CallTerminate"
+	EventUnknownCallEvent       EventType = "UnknownCallEvent"
+	EventNewsletterJoin         EventType = "NewsletterJoin"
+	EventNewsletterLeave        EventType = "NewsletterLeave"
+	EventNewsletterMuteChange   EventType = "NewsletterMuteChange"
+	EventNewsletterLiveUpdate   EventType = "NewsletterLiveUpdate"
+	EventNewsletterMessageMeta  EventType = "NewsletterMessageMeta"
+	EventMediaRetry             EventType = "MediaRetry"
+	EventMediaStored            EventType = "MediaStored"
+	EventMessageSent            EventType = "MessageSent"
+	EventMessageSendFailed      EventType = "MessageSendFailed"
+	EventScheduledMessageSent   EventType = "ScheduledMessageSent"
+	EventScheduledMessageFailed EventType = "ScheduledMessageFailed"
+	EventMessageStatus          EventType = "MessageStatus"
 )
 
 type QREvent struct {
@@ -33,24 +68,6 @@ type QREvent struct {
 	ExpiresAt time.Time `json:"expiresAt,omitempty"`
 }
 
-type MessageInfo struct {
-	ID        string    `json:"id"`
-	Chat      string    `json:"chat"`
-	Sender    string    `json:"sender"`
-	PushName  string    `json:"pushName"`
-	Timestamp time.Time `json:"timestamp"`
-	FromMe    bool      `json:"fromMe"`
-	Type      string    `json:"type"`
-	IsGroup   bool      `json:"isGroup"`
-}
-
-type WebhookEvent struct {
-	Type      EventType   `json:"type"`
-	SessionID string      `json:"sessionId"`
-	Event     interface{} `json:"event"`
-	Timestamp time.Time   `json:"timestamp"`
-}
-
 type SessionConfig struct {
 	SessionID     string            `json:"sessionId"`
 	Name          string            `json:"name"`
@@ -71,6 +88,7 @@ type Client struct {
 	QRExpiresAt  time.Time
 	ConnectedAt  time.Time
 	LastSeen     time.Time
+	ProxyConfig  *string
 	Config       *SessionConfig
 	Events       []EventType
 	WebhookURL   string
@@ -97,8 +115,18 @@ type EventHandler interface {
 	HandleEvent(client *Client, event interface{}) error
 }
 
-type WebhookSender interface {
-	SendWebhook(ctx context.Context, event *WebhookEvent) error
+// MessageSync receives every newly stored incoming message, e.g. to mirror
+// it into Chatwoot, and is told when a session is paired so it can import
+// the session's contacts and history.
+type MessageSync interface {
+	SyncMessage(ctx context.Context, msg *message.Message, pushName string) error
+	StartImportAfterPairing(ctx context.Context, sessionID string) error
+}
+
+// ReceiptSync is told when messages sent by a session are delivered or
+// read, e.g. to track the recipients of a campaign.
+type ReceiptSync interface {
+	SyncReceipt(ctx context.Context, sessionID string, messageIDs []string, status string, timestamp time.Time) error
 }
 
 type ContactInfo struct {
@@ -114,4 +142,6 @@ var (
 	ErrInvalidJID       = output.ErrInvalidJID
 	ErrConnectionFailed = output.ErrConnectionFailed
 	ErrAlreadyPaired    = &output.WhatsAppError{Code: "ALREADY_PAIRED", Message: "session is already paired"}
+	ErrNoHistoryAnchor  = &output.WhatsAppError{Code: "NO_HISTORY_ANCHOR", Message: "no stored message to anchor the history request for this chat"}
+	ErrSendQueueFull    = output.ErrSendQueueFull
 )
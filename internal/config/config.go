@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -22,7 +23,19 @@ type Config struct {
 
 	WALogLevel string
 
-	GlobalWebhookURL string
+	GlobalWebhookURL    string
+	GlobalWebhookSecret string
+	GlobalWebhookEvents []string
+
+	Webhook WebhookConfig
+
+	SendQueue SendQueueConfig
+
+	Media MediaConfig
+
+	Upload UploadConfig
+
+	Outbound OutboundConfig
 
 	Environment string
 }
@@ -31,6 +44,73 @@ type DatabaseConfig struct {
 	URL string
 }
 
+type WebhookConfig struct {
+	Workers     int
+	MaxAttempts int
+}
+
+// SendQueueConfig paces outgoing messages per session. Delays are in
+// milliseconds; a MessagesPerMinute of 0 disables the rate cap.
+type SendQueueConfig struct {
+	MessagesPerMinute int
+	MinDelayMs        int
+	MaxDelayMs        int
+	Size              int
+	Typing            bool
+}
+
+// MediaConfig controls the files of media messages. A RetentionDays of 0
+// keeps files forever and a MaxSizeMB of 0 downloads files of any size.
+// Storage is "local", keeping files below StoragePath, or "s3". Transcoding
+// converts and previews sent media with FFmpegPath and FFprobePath.
+type MediaConfig struct {
+	AutoDownload   bool
+	Storage        string
+	StoragePath    string
+	MaxSizeMB      int
+	RetentionDays  int
+	BaseURL        string
+	URLExpiryHours int
+	S3             S3Config
+	Transcoding    bool
+	FFmpegPath     string
+	FFprobePath    string
+}
+
+// S3Config points at an S3-compatible bucket such as AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Prefix    string
+	PathStyle bool
+}
+
+// UploadConfig caps the size of the media sent through the API, in MB per
+// media type. A limit of 0 accepts files of any size.
+type UploadConfig struct {
+	ImageMaxMB    int
+	AudioMaxMB    int
+	VideoMaxMB    int
+	DocumentMaxMB int
+	StickerMaxMB  int
+}
+
+// OutboundConfig restricts the requests made on behalf of API callers, to
+// media URLs and webhooks. AllowCIDRs stay reachable when BlockPrivate is
+// set, and DenyCIDRs are never reachable. Local media files are only read
+// below MediaRoot, and not at all when it is empty.
+type OutboundConfig struct {
+	AllowCIDRs    []string
+	DenyCIDRs     []string
+	BlockPrivate  bool
+	MaxRedirects  int
+	MaxDownloadMB int
+	MediaRoot     string
+}
+
 type PostgresConfig struct {
 	DB       string
 	User     string
@@ -66,7 +146,61 @@ func Load() *Config {
 
 		WALogLevel: getEnv("WA_LOG_LEVEL", "INFO"),
 
-		GlobalWebhookURL: getEnv("GLOBAL_WEBHOOK_URL", ""),
+		GlobalWebhookURL:    getEnv("GLOBAL_WEBHOOK_URL", ""),
+		GlobalWebhookSecret: getEnv("GLOBAL_WEBHOOK_SECRET", ""),
+		GlobalWebhookEvents: getEnvAsList("GLOBAL_WEBHOOK_EVENTS"),
+
+		Webhook: WebhookConfig{
+			Workers:     getEnvAsInt("WEBHOOK_WORKERS", 4),
+			MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 10),
+		},
+
+		SendQueue: SendQueueConfig{
+			MessagesPerMinute: getEnvAsInt("SEND_RATE_PER_MINUTE", 60),
+			MinDelayMs:        getEnvAsInt("SEND_MIN_DELAY_MS", 500),
+			MaxDelayMs:        getEnvAsInt("SEND_MAX_DELAY_MS", 2000),
+			Size:              getEnvAsInt("SEND_QUEUE_SIZE", 500),
+			Typing:            getEnvAsBool("SEND_TYPING", false),
+		},
+
+		Media: MediaConfig{
+			AutoDownload:   getEnvAsBool("MEDIA_AUTO_DOWNLOAD", false),
+			Storage:        getEnv("MEDIA_STORAGE", "local"),
+			StoragePath:    getEnv("MEDIA_STORAGE_PATH", "./data/media"),
+			MaxSizeMB:      getEnvAsInt("MEDIA_MAX_SIZE_MB", 100),
+			RetentionDays:  getEnvAsInt("MEDIA_RETENTION_DAYS", 30),
+			BaseURL:        getEnv("MEDIA_BASE_URL", ""),
+			URLExpiryHours: getEnvAsInt("MEDIA_URL_EXPIRY_HOURS", 24),
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+				Prefix:    getEnv("S3_PREFIX", "media"),
+				PathStyle: getEnvAsBool("S3_PATH_STYLE", false),
+			},
+			Transcoding: getEnvAsBool("MEDIA_TRANSCODING", false),
+			FFmpegPath:  getEnv("FFMPEG_PATH", "ffmpeg"),
+			FFprobePath: getEnv("FFPROBE_PATH", "ffprobe"),
+		},
+
+		Upload: UploadConfig{
+			ImageMaxMB:    getEnvAsInt("UPLOAD_MAX_IMAGE_MB", 16),
+			AudioMaxMB:    getEnvAsInt("UPLOAD_MAX_AUDIO_MB", 16),
+			VideoMaxMB:    getEnvAsInt("UPLOAD_MAX_VIDEO_MB", 64),
+			DocumentMaxMB: getEnvAsInt("UPLOAD_MAX_DOCUMENT_MB", 100),
+			StickerMaxMB:  getEnvAsInt("UPLOAD_MAX_STICKER_MB", 1),
+		},
+
+		Outbound: OutboundConfig{
+			AllowCIDRs:    getEnvAsList("OUTBOUND_ALLOW_CIDRS"),
+			DenyCIDRs:     getEnvAsList("OUTBOUND_DENY_CIDRS"),
+			BlockPrivate:  getEnvAsBool("OUTBOUND_BLOCK_PRIVATE", true),
+			MaxRedirects:  getEnvAsInt("OUTBOUND_MAX_REDIRECTS", 5),
+			MaxDownloadMB: getEnvAsInt("OUTBOUND_MAX_DOWNLOAD_MB", 100),
+			MediaRoot:     getEnv("MEDIA_LOCAL_ROOT", ""),
+		},
 
 		Environment: getEnv("NODE_ENV", "development"),
 	}
@@ -87,6 +221,16 @@ func (c *Config) Validate() error {
 		return errors.New("DATABASE_URL is required")
 	}
 
+	switch c.Media.Storage {
+	case "local":
+	case "s3":
+		if c.Media.S3.Bucket == "" {
+			return errors.New("S3_BUCKET is required when MEDIA_STORAGE is s3")
+		}
+	default:
+		return errors.New("MEDIA_STORAGE must be local or s3")
+	}
+
 	return nil
 }
 
@@ -108,6 +252,29 @@ func getEnvAsInt(key string, fallback int) int {
 	return fallback
 }
 
+func getEnvAsBool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+
+	return fallback
+}
+
+// getEnvAsList splits a comma separated variable, dropping empty items.
+func getEnvAsList(key string) []string {
+	var items []string
+
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 func (c *Config) GetServerAddress() string {
 	return c.ServerHost + ":" + c.Port
 }
@@ -0,0 +1,48 @@
+package config
+
+import "testing"
+
+// setRequiredEnv sets the variables Load cannot do without.
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("ZP_API_KEY", "test-key")
+	t.Setenv("DATABASE_URL", "postgres://localhost/zpwoot")
+	t.Setenv("MEDIA_STORAGE", "local")
+}
+
+func TestOutboundDefaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	for _, key := range []string{"OUTBOUND_ALLOW_CIDRS", "OUTBOUND_DENY_CIDRS", "OUTBOUND_BLOCK_PRIVATE", "OUTBOUND_MAX_REDIRECTS"} {
+		t.Setenv(key, "")
+	}
+
+	outbound := Load().Outbound
+
+	if !outbound.BlockPrivate {
+		t.Error("private addresses are not blocked by default")
+	}
+
+	if outbound.MaxRedirects != 5 {
+		t.Errorf("MaxRedirects = %d, want 5", outbound.MaxRedirects)
+	}
+
+	if len(outbound.AllowCIDRs) != 0 || len(outbound.DenyCIDRs) != 0 {
+		t.Errorf("CIDRs = %v allowed, %v denied, want none", outbound.AllowCIDRs, outbound.DenyCIDRs)
+	}
+}
+
+func TestOutboundBlockPrivateCanBeDisabled(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("OUTBOUND_BLOCK_PRIVATE", "false")
+	t.Setenv("OUTBOUND_ALLOW_CIDRS", "127.0.0.1/32, ,172.18.0.0/16")
+
+	outbound := Load().Outbound
+
+	if outbound.BlockPrivate {
+		t.Error("OUTBOUND_BLOCK_PRIVATE=false ignored")
+	}
+
+	if len(outbound.AllowCIDRs) != 2 || outbound.AllowCIDRs[0] != "127.0.0.1/32" || outbound.AllowCIDRs[1] != "172.18.0.0/16" {
+		t.Errorf("AllowCIDRs = %q, want 127.0.0.1/32 and 172.18.0.0/16", outbound.AllowCIDRs)
+	}
+}
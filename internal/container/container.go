@@ -2,19 +2,34 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"zpwoot/internal/adapters/database"
 	"zpwoot/internal/adapters/database/repository"
+	"zpwoot/internal/adapters/integration/chatwoot"
 	"zpwoot/internal/adapters/integration/webhook"
 	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/adapters/outbound"
+	"zpwoot/internal/adapters/storage"
+	"zpwoot/internal/adapters/transcoder"
 	"zpwoot/internal/adapters/waclient"
 	"zpwoot/internal/config"
+	"zpwoot/internal/core/application/dto"
+	apiKeyUseCase "zpwoot/internal/core/application/usecase/apikey"
+	campaignUseCase "zpwoot/internal/core/application/usecase/campaign"
+	chatwootUseCase "zpwoot/internal/core/application/usecase/chatwoot"
+	mediaUseCase "zpwoot/internal/core/application/usecase/media"
 	"zpwoot/internal/core/application/usecase/message"
+	scheduleUseCase "zpwoot/internal/core/application/usecase/schedule"
 	"zpwoot/internal/core/application/usecase/session"
+	tenantUseCase "zpwoot/internal/core/application/usecase/tenant"
 	webhookUseCase "zpwoot/internal/core/application/usecase/webhook"
+	"zpwoot/internal/core/application/utils"
+	domainMessage "zpwoot/internal/core/domain/message"
 	domainSession "zpwoot/internal/core/domain/session"
 	domainWebhook "zpwoot/internal/core/domain/webhook"
 	"zpwoot/internal/core/ports/input"
@@ -28,14 +43,28 @@ type Container struct {
 	migrator *database.Migrator
 
 	sessionService *domainSession.Service
+	messageService *domainMessage.Service
 	webhookService *domainWebhook.Service
 
-	whatsappClient output.WhatsAppClient
-	webhookSender  output.WebhookSender
-
-	sessionUseCases input.SessionUseCases
-	messageUseCases input.MessageUseCases
-	webhookUseCases input.WebhookUseCases
+	waClient          *waclient.WAClient
+	whatsappClient    output.WhatsAppClient
+	webhookSender     output.WebhookSender
+	webhookDispatcher *webhook.Dispatcher
+	scheduler         *scheduleUseCase.Scheduler
+	campaignRunner    *campaignUseCase.Runner
+	mediaRetention    *mediaUseCase.Retention
+	outboundPolicy    *outbound.Policy
+	mediaProcessor    *utils.MediaProcessor
+
+	sessionUseCases  input.SessionUseCases
+	messageUseCases  input.MessageUseCases
+	webhookUseCases  input.WebhookUseCases
+	chatwootUseCases input.ChatwootUseCases
+	scheduleUseCases input.ScheduleUseCases
+	campaignUseCases input.CampaignUseCases
+	mediaUseCases    input.MediaUseCases
+	apiKeyUseCases   input.APIKeyUseCases
+	tenantUseCases   input.TenantUseCases
 }
 
 func NewContainer(cfg *config.Config) *Container {
@@ -75,8 +104,17 @@ func (c *Container) InitWithContext(ctx context.Context) error {
 	c.logger.Info().Msg("Initializing domain services")
 	sessionRepo := repository.NewSessionRepository(c.database.DB)
 	c.sessionService = domainSession.NewService(sessionRepo)
+	c.messageService = domainMessage.NewService(repository.NewMessageRepository(c.database.DB))
 	c.webhookService = domainWebhook.NewService()
 
+	if err := waclient.CheckEventCatalogue(c.webhookService.GetValidEventTypes()); err != nil {
+		return err
+	}
+
+	if err := c.initOutboundPolicy(); err != nil {
+		return err
+	}
+
 	c.logger.Info().Msg("Initializing webhook sender")
 	c.initWebhookSender()
 
@@ -84,9 +122,28 @@ func (c *Container) InitWithContext(ctx context.Context) error {
 	c.initWAClient()
 
 	c.logger.Info().Msg("Initializing use cases")
-	c.sessionUseCases = session.NewUseCases(c.sessionService, c.whatsappClient, c.logger)
-	c.messageUseCases = message.NewUseCases(c.sessionService, c.whatsappClient, c.logger)
+	tenantRepo := repository.NewTenantRepository(c.database.DB)
+	c.tenantUseCases = tenantUseCase.NewUseCases(tenantRepo, c.logger)
+	c.sessionUseCases = session.NewUseCases(c.sessionService, tenantRepo, c.whatsappClient, c.logger)
+	c.messageUseCases = message.NewUseCases(c.sessionService, c.messageService, c.whatsappClient, c.logger)
 	c.webhookUseCases = c.initWebhookUseCases()
+	c.chatwootUseCases = c.initChatwootUseCases()
+	c.scheduleUseCases = c.initScheduleUseCases()
+	c.campaignUseCases = c.initCampaignUseCases()
+	c.apiKeyUseCases = apiKeyUseCase.NewUseCases(repository.NewAPIKeyRepository(c.database.DB), tenantRepo, c.logger)
+
+	c.mediaUseCases, err = c.initMediaUseCases(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.waClient.SetMessageSync(c.chatwootUseCases)
+	c.waClient.SetReceiptSync(c.campaignUseCases)
+	c.waClient.SetSendQuota(c.tenantUseCases)
+
+	if err := c.applyGlobalWebhook(ctx); err != nil {
+		return err
+	}
 
 	c.logger.Info().Msg("Container initialization completed successfully")
 
@@ -97,14 +154,23 @@ func (c *Container) initWAClient() {
 	sessionRepository := repository.NewSessionRepository(c.database.DB)
 	sessionRepo := repository.NewSessionRepo(sessionRepository)
 	webhookRepo := repository.NewWebhookRepository(c.database.DB)
+	messageRepo := repository.NewMessageRepository(c.database.DB)
+	chatRepo := repository.NewChatRepository(c.database.DB)
 
 	waContainer := waclient.NewWAStoreContainer(
 		c.database.DB,
 		c.logger,
 		c.config.Database.URL,
 	)
-	waClient := waclient.NewWAClient(waContainer, c.logger, sessionRepo, c.webhookSender, webhookRepo)
-	c.whatsappClient = waclient.NewWAClientAdapter(waClient)
+	c.waClient = waclient.NewWAClient(waContainer, c.logger, sessionRepo, c.webhookSender, webhookRepo, messageRepo, chatRepo)
+	c.waClient.SetSendQueueConfig(waclient.SendQueueConfig{
+		MessagesPerMinute: c.config.SendQueue.MessagesPerMinute,
+		MinDelay:          time.Duration(c.config.SendQueue.MinDelayMs) * time.Millisecond,
+		MaxDelay:          time.Duration(c.config.SendQueue.MaxDelayMs) * time.Millisecond,
+		Size:              c.config.SendQueue.Size,
+		Typing:            c.config.SendQueue.Typing,
+	})
+	c.whatsappClient = waclient.NewWAClientAdapter(c.waClient)
 }
 
 func (c *Container) Start(ctx context.Context) error {
@@ -112,6 +178,22 @@ func (c *Container) Start(ctx context.Context) error {
 }
 
 func (c *Container) Stop(ctx context.Context) error {
+	if c.scheduler != nil {
+		c.scheduler.Stop()
+	}
+
+	if c.campaignRunner != nil {
+		c.campaignRunner.Stop()
+	}
+
+	if c.mediaRetention != nil {
+		c.mediaRetention.Stop()
+	}
+
+	if c.webhookDispatcher != nil {
+		c.webhookDispatcher.Stop()
+	}
+
 	if c.database != nil {
 		return c.database.Close()
 	}
@@ -155,18 +237,256 @@ func (c *Container) GetWebhookUseCases() input.WebhookUseCases {
 	return c.webhookUseCases
 }
 
+func (c *Container) GetChatwootUseCases() input.ChatwootUseCases {
+	return c.chatwootUseCases
+}
+
+func (c *Container) GetScheduleUseCases() input.ScheduleUseCases {
+	return c.scheduleUseCases
+}
+
+func (c *Container) GetCampaignUseCases() input.CampaignUseCases {
+	return c.campaignUseCases
+}
+
+func (c *Container) GetMediaUseCases() input.MediaUseCases {
+	return c.mediaUseCases
+}
+
+func (c *Container) GetAPIKeyUseCases() input.APIKeyUseCases {
+	return c.apiKeyUseCases
+}
+
+func (c *Container) GetTenantUseCases() input.TenantUseCases {
+	return c.tenantUseCases
+}
+
+func (c *Container) GetMediaProcessor() *utils.MediaProcessor {
+	return c.mediaProcessor
+}
+
 func (c *Container) GetWebhookSender() output.WebhookSender {
 	return c.webhookSender
 }
 
-func (c *Container) initWebhookSender() {
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+// initOutboundPolicy sets up the policy for requests made on behalf of API
+// callers, and the media processor that fetches media under it.
+func (c *Container) initOutboundPolicy() error {
+	cfg := c.config.Outbound
+
+	policy, err := outbound.NewPolicy(outbound.Config{
+		AllowCIDRs:   cfg.AllowCIDRs,
+		DenyCIDRs:    cfg.DenyCIDRs,
+		BlockPrivate: cfg.BlockPrivate,
+		MaxRedirects: cfg.MaxRedirects,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize outbound policy: %w", err)
+	}
+
+	mediaRoot := cfg.MediaRoot
+	if mediaRoot != "" {
+		if mediaRoot, err = filepath.Abs(mediaRoot); err == nil {
+			mediaRoot, err = filepath.EvalSymlinks(mediaRoot)
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid MEDIA_LOCAL_ROOT: %w", err)
+		}
 	}
-	c.webhookSender = webhook.NewHTTPWebhookSender(httpClient, c.logger)
+
+	c.outboundPolicy = policy
+	c.mediaProcessor = utils.NewMediaProcessorWithConfig(utils.MediaProcessorConfig{
+		HTTPClient:      policy.HTTPClient(30 * time.Second),
+		MaxDownloadSize: int64(cfg.MaxDownloadMB) << 20,
+		LocalRoot:       mediaRoot,
+		Limits: utils.MediaLimits{
+			utils.MediaKindImage:    int64(c.config.Upload.ImageMaxMB) << 20,
+			utils.MediaKindAudio:    int64(c.config.Upload.AudioMaxMB) << 20,
+			utils.MediaKindVideo:    int64(c.config.Upload.VideoMaxMB) << 20,
+			utils.MediaKindDocument: int64(c.config.Upload.DocumentMaxMB) << 20,
+			utils.MediaKindSticker:  int64(c.config.Upload.StickerMaxMB) << 20,
+		},
+	})
+
+	return nil
+}
+
+func (c *Container) initWebhookSender() {
+	httpClient := c.outboundPolicy.HTTPClient(30 * time.Second)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(c.database.DB)
+
+	c.webhookDispatcher = webhook.NewDispatcher(deliveryRepo, httpClient, c.logger, webhook.DispatcherConfig{
+		Workers:     c.config.Webhook.Workers,
+		MaxAttempts: c.config.Webhook.MaxAttempts,
+	})
+	c.webhookDispatcher.Start()
+
+	c.webhookSender = webhook.NewOutboxWebhookSender(deliveryRepo, c.webhookDispatcher)
 }
 
 func (c *Container) initWebhookUseCases() input.WebhookUseCases {
 	webhookRepo := repository.NewWebhookRepository(c.database.DB)
-	return webhookUseCase.NewWebhookUseCases(webhookRepo, c.webhookService)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(c.database.DB)
+
+	return webhookUseCase.NewWebhookUseCases(webhookRepo, c.webhookService, deliveryRepo, c.outboundPolicy)
+}
+
+func (c *Container) initChatwootUseCases() input.ChatwootUseCases {
+	chatwootRepo := repository.NewChatwootRepository(c.database.DB)
+	messageRepo := repository.NewMessageRepository(c.database.DB)
+	client := chatwoot.NewHTTPClient(c.outboundPolicy.HTTPClient(30 * time.Second))
+
+	messageService := waclient.NewMessageService(waclient.NewSender(c.waClient))
+	contactService := waclient.NewContactService(c.waClient)
+
+	return chatwootUseCase.NewUseCases(chatwootRepo, messageRepo, c.sessionService, messageService, contactService, client, c.outboundPolicy, c.logger)
+}
+
+// initScheduleUseCases also starts the scheduler that sends the scheduled
+// messages once they are due.
+func (c *Container) initScheduleUseCases() input.ScheduleUseCases {
+	scheduleRepo := repository.NewScheduledMessageRepository(c.database.DB)
+	messageService := waclient.NewMessageService(waclient.NewSender(c.waClient))
+
+	c.scheduler = scheduleUseCase.NewScheduler(scheduleRepo, messageService, c.mediaProcessor, c.whatsappClient, c.waClient, c.logger)
+	c.scheduler.Start()
+
+	return scheduleUseCase.NewUseCases(scheduleRepo, c.sessionService)
+}
+
+// initCampaignUseCases also starts the runner that sends the running
+// campaigns.
+func (c *Container) initCampaignUseCases() input.CampaignUseCases {
+	campaignRepo := repository.NewCampaignRepository(c.database.DB)
+	messageService := waclient.NewMessageService(waclient.NewSender(c.waClient))
+	contactService := waclient.NewContactService(c.waClient)
+
+	c.campaignRunner = campaignUseCase.NewRunner(campaignRepo, messageService, c.whatsappClient, c.logger)
+	c.campaignRunner.Start()
+
+	return campaignUseCase.NewUseCases(campaignRepo, c.sessionService, contactService, c.logger)
+}
+
+// initMediaUseCases opens the blob store for the files of media messages,
+// hands it to the WhatsApp client and starts the media retention.
+func (c *Container) initMediaUseCases(ctx context.Context) (input.MediaUseCases, error) {
+	blobs, err := c.newBlobStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize media storage: %w", err)
+	}
+
+	// Object stores also expire files on their own, which catches files
+	// whose rows are gone, such as those of deleted sessions.
+	if expirer, ok := blobs.(output.BlobExpirer); ok {
+		if err := expirer.ExpireAfter(ctx, c.config.Media.RetentionDays); err != nil {
+			c.logger.Warn().Err(err).Msg("Failed to configure media expiry in the blob store")
+		}
+	}
+
+	mediaRepo := repository.NewMediaRepository(c.database.DB)
+
+	c.waClient.SetMediaStore(waclient.NewMediaStore(mediaRepo, blobs, waclient.MediaConfig{
+		AutoDownload: c.config.Media.AutoDownload,
+		MaxSize:      int64(c.config.Media.MaxSizeMB) << 20,
+		BaseURL:      c.config.Media.BaseURL,
+		URLExpiry:    time.Duration(c.config.Media.URLExpiryHours) * time.Hour,
+	}, c.logger))
+
+	c.initMediaTranscoder()
+
+	c.mediaRetention = mediaUseCase.NewRetention(mediaRepo, blobs, time.Duration(c.config.Media.RetentionDays)*24*time.Hour, c.logger)
+	c.mediaRetention.Start()
+
+	return mediaUseCase.NewUseCases(mediaRepo, blobs, c.waClient, c.sessionService), nil
+}
+
+// initMediaTranscoder hands the WhatsApp client an ffmpeg transcoder when
+// transcoding is enabled and ffmpeg is installed; otherwise media is sent
+// as it is.
+func (c *Container) initMediaTranscoder() {
+	if !c.config.Media.Transcoding {
+		return
+	}
+
+	ffmpeg := transcoder.NewFFmpeg(transcoder.Config{
+		FFmpegPath:  c.config.Media.FFmpegPath,
+		FFprobePath: c.config.Media.FFprobePath,
+	})
+
+	if err := ffmpeg.Check(); err != nil {
+		c.logger.Warn().Err(err).Msg("Media transcoding disabled: ffmpeg not found")
+		return
+	}
+
+	c.waClient.SetMediaTranscoder(ffmpeg)
+	c.logger.Info().Msg("Media transcoding enabled")
+}
+
+func (c *Container) newBlobStore() (output.BlobStore, error) {
+	if c.config.Media.Storage != "s3" {
+		return storage.NewLocalBlobStore(c.config.Media.StoragePath)
+	}
+
+	s3 := c.config.Media.S3
+
+	c.logger.Info().
+		Str("endpoint", s3.Endpoint).
+		Str("bucket", s3.Bucket).
+		Str("prefix", s3.Prefix).
+		Msg("Storing media in S3")
+
+	// Uploads and downloads of large files are bounded by their context
+	// rather than a client timeout.
+	return storage.NewS3BlobStore(storage.S3Config{
+		Endpoint:  s3.Endpoint,
+		Region:    s3.Region,
+		Bucket:    s3.Bucket,
+		AccessKey: s3.AccessKey,
+		SecretKey: s3.SecretKey,
+		Prefix:    s3.Prefix,
+		PathStyle: s3.PathStyle,
+	}, &http.Client{})
+}
+
+// applyGlobalWebhook stores the global webhook configured through
+// GLOBAL_WEBHOOK_URL. The environment wins over changes made through the API
+// while it is set; without it the API alone manages the global webhook.
+func (c *Container) applyGlobalWebhook(ctx context.Context) error {
+	if c.config.GlobalWebhookURL == "" {
+		return nil
+	}
+
+	// OUTBOUND_BLOCK_PRIVATE is on by default, so a receiver on the local
+	// network that used to work is refused until its range is allowed.
+	if err := c.outboundPolicy.CheckURL(ctx, c.config.GlobalWebhookURL); errors.Is(err, output.ErrOutboundDenied) {
+		c.logger.Error().
+			Err(err).
+			Str("webhook_url", c.config.GlobalWebhookURL).
+			Msg("GLOBAL_WEBHOOK_URL is blocked by the outbound policy; add its network to OUTBOUND_ALLOW_CIDRS or set OUTBOUND_BLOCK_PRIVATE=false")
+
+		return fmt.Errorf("GLOBAL_WEBHOOK_URL is blocked by the outbound policy: %w", err)
+	}
+
+	request := &dto.CreateWebhookRequest{
+		URL:    c.config.GlobalWebhookURL,
+		Events: c.config.GlobalWebhookEvents,
+	}
+
+	if c.config.GlobalWebhookSecret != "" {
+		request.Secret = &c.config.GlobalWebhookSecret
+	}
+
+	response, err := c.webhookUseCases.SetGlobal(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to configure global webhook: %w", err)
+	}
+
+	c.logger.Info().
+		Str("webhook_id", response.ID).
+		Str("webhook_url", response.URL).
+		Int("events", len(response.Events)).
+		Msg("Global webhook configured from environment")
+
+	return nil
 }
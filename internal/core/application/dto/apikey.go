@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/apikey"
+)
+
+// CreateAPIKeyRequest creates a key allowed to use permissions on the
+// sessions in sessionIds, or on every session when sessionIds is empty.
+// Admin keys always cover every session. Keys made by a tenant belong to
+// that tenant; the server operator may give a key to any tenant with
+// tenantId.
+type CreateAPIKeyRequest struct {
+	TenantID    string     `json:"tenantId,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name        string     `json:"name" validate:"required" example:"CRM integration"`
+	SessionIDs  []string   `json:"sessionIds,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Permissions []string   `json:"permissions" validate:"required" example:"send,read"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty" example:"2026-12-31T23:59:59Z"`
+} // @name CreateAPIKeyRequest
+
+type APIKeyResponse struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenantId,omitempty"`
+	Name        string     `json:"name" example:"CRM integration"`
+	Prefix      string     `json:"prefix" example:"zpk_3fA9xQ2L"`
+	SessionIDs  []string   `json:"sessionIds"`
+	Permissions []string   `json:"permissions" example:"send,read"`
+	Status      string     `json:"status" example:"active"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+} // @name APIKeyResponse
+
+// APIKeySecretResponse is a key together with its secret, which is only
+// returned when the key is created or rotated.
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"zpk_3fA9xQ2LrT8vKc1mWn5bYd7eHs0uJg4iOp6aZx2qVy8"`
+} // @name APIKeySecretResponse
+
+type APIKeyListResponse struct {
+	Keys  []*APIKeyResponse `json:"keys"`
+	Total int               `json:"total" example:"3"`
+} // @name APIKeyListResponse
+
+// Validate checks the request and returns its permissions.
+func (r *CreateAPIKeyRequest) Validate() ([]apikey.Permission, error) {
+	r.TenantID = strings.TrimSpace(r.TenantID)
+
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return nil, NewValidationError("name", "name is required")
+	}
+
+	if len(r.Permissions) == 0 {
+		return nil, NewValidationError("permissions", "at least one permission is required")
+	}
+
+	valid := apikey.ValidPermissions()
+	permissions := make([]apikey.Permission, 0, len(r.Permissions))
+
+	for _, value := range r.Permissions {
+		permission := apikey.Permission(strings.ToLower(strings.TrimSpace(value)))
+
+		if !slices.Contains(valid, permission) {
+			return nil, NewValidationError("permissions", fmt.Sprintf("unknown permission %q, expected one of %v", value, valid))
+		}
+
+		if !slices.Contains(permissions, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	sessionIDs := make([]string, 0, len(r.SessionIDs))
+
+	for _, id := range r.SessionIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, NewValidationError("sessionIds", "session IDs cannot be empty")
+		}
+
+		if !slices.Contains(sessionIDs, id) {
+			sessionIDs = append(sessionIDs, id)
+		}
+	}
+
+	r.SessionIDs = sessionIDs
+
+	if len(sessionIDs) > 0 && slices.Contains(permissions, apikey.PermissionAdmin) {
+		return nil, NewValidationError("sessionIds", "admin keys cover every session and cannot be limited to some")
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return nil, NewValidationError("expiresAt", "expiresAt must be in the future")
+	}
+
+	return permissions, nil
+}
+
+func NewAPIKeyResponse(k *apikey.APIKey) *APIKeyResponse {
+	status := "active"
+
+	switch {
+	case k.IsRevoked():
+		status = "revoked"
+	case k.IsExpired(time.Now()):
+		status = "expired"
+	}
+
+	sessionIDs := k.SessionIDs
+	if sessionIDs == nil {
+		sessionIDs = []string{}
+	}
+
+	permissions := make([]string, len(k.Permissions))
+	for i, permission := range k.Permissions {
+		permissions[i] = string(permission)
+	}
+
+	return &APIKeyResponse{
+		ID:          k.ID,
+		TenantID:    k.TenantID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		SessionIDs:  sessionIDs,
+		Permissions: permissions,
+		Status:      status,
+		ExpiresAt:   k.ExpiresAt,
+		LastUsedAt:  k.LastUsedAt,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+	}
+}
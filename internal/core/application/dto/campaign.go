@@ -0,0 +1,343 @@
+package dto
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/shared"
+)
+
+const (
+	DefaultCampaignInterval = 5
+	MaxCampaignInterval     = 3600
+	MaxCampaignRecipients   = 10000
+)
+
+// CreateCampaignRequest creates a campaign sending text to every recipient.
+// Recipients come either as a JSON list or as CSV with a header row: the
+// phone column holds the number and every other column becomes a variable.
+// Placeholders such as {{name}} in text are filled from the variables of
+// each recipient; {{phone}} is always available.
+type CreateCampaignRequest struct {
+	Name            string                      `json:"name" validate:"required" example:"Black Friday"`
+	Text            string                      `json:"text" validate:"required" example:"Hi {{name}}, your coupon is {{coupon}}"`
+	IntervalSeconds int                         `json:"intervalSeconds,omitempty" example:"5"`
+	Recipients      []*CampaignRecipientRequest `json:"recipients,omitempty"`
+	CSV             string                      `json:"csv,omitempty" example:"phone,name,coupon\n5511999999999,Ana,BF10"`
+} // @name CreateCampaignRequest
+
+type CampaignRecipientRequest struct {
+	Phone     string            `json:"phone" validate:"required" example:"5511999999999"`
+	Variables map[string]string `json:"variables,omitempty"`
+} // @name CampaignRecipientRequest
+
+type ListCampaignsRequest struct {
+	PaginationRequest
+	Status string `json:"status,omitempty"`
+}
+
+type ListCampaignRecipientsRequest struct {
+	PaginationRequest
+	Status string `json:"status,omitempty"`
+}
+
+type CampaignResponse struct {
+	ID              string                 `json:"id"`
+	SessionID       string                 `json:"sessionId"`
+	Name            string                 `json:"name" example:"Black Friday"`
+	Text            string                 `json:"text" example:"Hi {{name}}, your coupon is {{coupon}}"`
+	Status          string                 `json:"status" example:"running"`
+	IntervalSeconds int                    `json:"intervalSeconds" example:"5"`
+	CompletedAt     *time.Time             `json:"completedAt,omitempty"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+	Report          *CampaignReportSummary `json:"report,omitempty"`
+} // @name CampaignResponse
+
+// CampaignReportSummary counts the recipients of a campaign by status.
+type CampaignReportSummary struct {
+	Total     int `json:"total" example:"100"`
+	Queued    int `json:"queued" example:"10"`
+	Sent      int `json:"sent" example:"20"`
+	Delivered int `json:"delivered" example:"30"`
+	Read      int `json:"read" example:"35"`
+	Failed    int `json:"failed" example:"5"`
+} // @name CampaignReportSummary
+
+// CampaignReportResponse summarizes how far a campaign got. Delivered counts
+// recipients that were delivered or read, and rates are percentages of the
+// messages that were sent.
+type CampaignReportResponse struct {
+	CampaignID string `json:"campaignId"`
+	Name       string `json:"name" example:"Black Friday"`
+	Status     string `json:"status" example:"completed"`
+	CampaignReportSummary
+	Progress     float64        `json:"progress" example:"90"`
+	DeliveryRate float64        `json:"deliveryRate" example:"76.5"`
+	ReadRate     float64        `json:"readRate" example:"41.2"`
+	Errors       map[string]int `json:"errors,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	CompletedAt  *time.Time     `json:"completedAt,omitempty"`
+} // @name CampaignReportResponse
+
+type CampaignRecipientResponse struct {
+	Phone       string            `json:"phone" example:"5511999999999"`
+	JID         string            `json:"jid,omitempty" example:"5511999999999@s.whatsapp.net"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	Status      string            `json:"status" example:"delivered"`
+	Attempts    int               `json:"attempts"`
+	MessageID   *string           `json:"messageId,omitempty"`
+	Error       *string           `json:"error,omitempty"`
+	SentAt      *time.Time        `json:"sentAt,omitempty"`
+	DeliveredAt *time.Time        `json:"deliveredAt,omitempty"`
+	ReadAt      *time.Time        `json:"readAt,omitempty"`
+} // @name CampaignRecipientResponse
+
+// Validate checks the request and returns its recipients, without
+// duplicates, ready to be checked against WhatsApp.
+func (r *CreateCampaignRequest) Validate() ([]*campaign.Recipient, error) {
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, NewValidationError("name", "name is required")
+	}
+
+	if strings.TrimSpace(r.Text) == "" {
+		return nil, NewValidationError("text", "text is required")
+	}
+
+	if r.IntervalSeconds == 0 {
+		r.IntervalSeconds = DefaultCampaignInterval
+	}
+
+	if r.IntervalSeconds < 1 || r.IntervalSeconds > MaxCampaignInterval {
+		return nil, NewValidationError("intervalSeconds", fmt.Sprintf("intervalSeconds must be between 1 and %d", MaxCampaignInterval))
+	}
+
+	requests := r.Recipients
+
+	switch {
+	case len(requests) > 0 && r.CSV != "":
+		return nil, NewValidationError("recipients", "give either recipients or csv, not both")
+	case r.CSV != "":
+		parsed, err := parseCampaignCSV(r.CSV)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = parsed
+	case len(requests) == 0:
+		return nil, NewValidationError("recipients", "recipients or csv is required")
+	}
+
+	placeholders := campaign.Placeholders(r.Text)
+	seen := make(map[string]bool, len(requests))
+	recipients := make([]*campaign.Recipient, 0, len(requests))
+
+	for i, request := range requests {
+		if request == nil || strings.TrimSpace(request.Phone) == "" {
+			return nil, NewValidationError(fmt.Sprintf("recipients[%d].phone", i), "phone is required")
+		}
+
+		phone := shared.NormalizePhoneNumber(strings.TrimSpace(request.Phone))
+		if strings.Contains(phone, "@") {
+			return nil, NewValidationError(fmt.Sprintf("recipients[%d].phone", i), "campaigns can only be sent to phone numbers")
+		}
+
+		if seen[phone] {
+			continue
+		}
+
+		seen[phone] = true
+
+		for _, name := range placeholders {
+			if _, ok := request.Variables[name]; !ok && name != "phone" {
+				return nil, NewValidationError(fmt.Sprintf("recipients[%d].variables", i), fmt.Sprintf("%s has no value for {{%s}}", phone, name))
+			}
+		}
+
+		recipients = append(recipients, campaign.NewRecipient(phone, request.Variables))
+	}
+
+	if len(recipients) > MaxCampaignRecipients {
+		return nil, NewValidationError("recipients", fmt.Sprintf("a campaign can have at most %d recipients", MaxCampaignRecipients))
+	}
+
+	return recipients, nil
+}
+
+// parseCampaignCSV reads recipients from CSV with a header row. Commas and
+// semicolons are both accepted as separators.
+func parseCampaignCSV(data string) ([]*CampaignRecipientRequest, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	if header, _, _ := strings.Cut(data, "\n"); !strings.Contains(header, ",") && strings.Contains(header, ";") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, NewValidationError("csv", "csv must start with a header row")
+	}
+
+	phoneColumn := -1
+
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if strings.EqualFold(header[i], "phone") {
+			phoneColumn = i
+		}
+	}
+
+	if phoneColumn < 0 {
+		return nil, NewValidationError("csv", "csv header must have a phone column")
+	}
+
+	var recipients []*CampaignRecipientRequest
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, NewValidationError("csv", fmt.Sprintf("invalid csv: %v", err))
+		}
+
+		recipient := &CampaignRecipientRequest{
+			Phone:     record[phoneColumn],
+			Variables: make(map[string]string, len(header)-1),
+		}
+
+		for i, value := range record {
+			if i != phoneColumn {
+				recipient.Variables[header[i]] = strings.TrimSpace(value)
+			}
+		}
+
+		if strings.TrimSpace(recipient.Phone) == "" {
+			return nil, NewValidationError("csv", fmt.Sprintf("line %d has no phone", line))
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return nil, NewValidationError("csv", "csv has no recipients")
+	}
+
+	return recipients, nil
+}
+
+func (r *ListCampaignsRequest) Validate() error {
+	switch campaign.Status(r.Status) {
+	case "", campaign.StatusRunning, campaign.StatusPaused, campaign.StatusCompleted, campaign.StatusCancelled:
+		return nil
+	default:
+		return NewValidationError("status", "status must be running, paused, completed or cancelled")
+	}
+}
+
+func (r *ListCampaignRecipientsRequest) Validate() error {
+	if r.Status == "" {
+		return nil
+	}
+
+	for _, status := range campaign.ValidRecipientStatuses() {
+		if campaign.RecipientStatus(r.Status) == status {
+			return nil
+		}
+	}
+
+	return NewValidationError("status", "status must be queued, sent, delivered, read or failed")
+}
+
+func NewCampaignResponse(c *campaign.Campaign, report *campaign.Report) *CampaignResponse {
+	response := &CampaignResponse{
+		ID:              c.ID,
+		SessionID:       c.SessionID,
+		Name:            c.Name,
+		Text:            c.Text,
+		Status:          string(c.Status),
+		IntervalSeconds: int(c.Interval / time.Second),
+		CompletedAt:     c.CompletedAt,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+
+	if report != nil {
+		summary := newCampaignReportSummary(report)
+		response.Report = &summary
+	}
+
+	return response
+}
+
+func NewCampaignReportResponse(c *campaign.Campaign, report *campaign.Report) *CampaignReportResponse {
+	response := &CampaignReportResponse{
+		CampaignID:            c.ID,
+		Name:                  c.Name,
+		Status:                string(c.Status),
+		CampaignReportSummary: newCampaignReportSummary(report),
+		CreatedAt:             c.CreatedAt,
+		CompletedAt:           c.CompletedAt,
+	}
+
+	if len(report.Errors) > 0 {
+		response.Errors = report.Errors
+	}
+
+	sent := report.Sent + report.Delivered + report.Read
+	delivered := report.Delivered + report.Read
+
+	response.Progress = percentage(report.Total-report.Queued, report.Total)
+	response.DeliveryRate = percentage(delivered, sent)
+	response.ReadRate = percentage(report.Read, sent)
+
+	return response
+}
+
+func NewCampaignRecipientResponse(recipient *campaign.Recipient) *CampaignRecipientResponse {
+	response := &CampaignRecipientResponse{
+		Phone:       recipient.Phone,
+		JID:         recipient.JID,
+		Status:      string(recipient.Status),
+		Attempts:    recipient.Attempts,
+		MessageID:   recipient.MessageID,
+		Error:       recipient.Error,
+		SentAt:      recipient.SentAt,
+		DeliveredAt: recipient.DeliveredAt,
+		ReadAt:      recipient.ReadAt,
+	}
+
+	if len(recipient.Variables) > 0 {
+		response.Variables = recipient.Variables
+	}
+
+	return response
+}
+
+func newCampaignReportSummary(report *campaign.Report) CampaignReportSummary {
+	return CampaignReportSummary{
+		Total:     report.Total,
+		Queued:    report.Queued,
+		Sent:      report.Sent,
+		Delivered: report.Delivered,
+		Read:      report.Read,
+		Failed:    report.Failed,
+	}
+}
+
+// percentage returns part as a percentage of total, rounded to one decimal.
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part*1000/total) / 10
+}
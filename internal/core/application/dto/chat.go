@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	ChatSortLastMessage = "lastMessage"
+	ChatSortUnread      = "unread"
+	ChatSortName        = "name"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+type ListChatsRequest struct {
+	PaginationRequest
+	Sort  string `json:"sort,omitempty" example:"lastMessage" description:"lastMessage, unread or name"`
+	Order string `json:"order,omitempty" example:"desc" description:"asc or desc"`
+}
+
+func (r *ListChatsRequest) ApplyDefaults() {
+	r.PaginationRequest.ApplyDefaults()
+
+	if r.Sort == "" {
+		r.Sort = ChatSortLastMessage
+	}
+
+	if r.Order == "" {
+		if r.Sort == ChatSortName {
+			r.Order = SortOrderAsc
+		} else {
+			r.Order = SortOrderDesc
+		}
+	}
+}
+
+func (r *ListChatsRequest) Validate() error {
+	switch r.Sort {
+	case ChatSortLastMessage, ChatSortUnread, ChatSortName:
+	default:
+		return NewValidationError("sort", "sort must be lastMessage, unread or name")
+	}
+
+	switch r.Order {
+	case SortOrderAsc, SortOrderDesc:
+	default:
+		return NewValidationError("order", "order must be asc or desc")
+	}
+
+	return r.PaginationRequest.Validate()
+}
+
+type ChatResponse struct {
+	JID              string       `json:"jid" example:"5511999999999@s.whatsapp.net"`
+	Name             string       `json:"name,omitempty" example:"João Silva"`
+	Topic            string       `json:"topic,omitempty"`
+	IsGroup          bool         `json:"isGroup" example:"false"`
+	ParticipantCount int          `json:"participantCount,omitempty"`
+	UnreadCount      int          `json:"unreadCount" example:"3"`
+	Archived         bool         `json:"archived" example:"false"`
+	Pinned           bool         `json:"pinned" example:"false"`
+	Muted            bool         `json:"muted" example:"false"`
+	MutedUntil       *time.Time   `json:"mutedUntil,omitempty"`
+	LastMessage      *MessageInfo `json:"lastMessage,omitempty"`
+} // @name ChatResponse
+
+func NewChatResponse(chat *output.ChatInfo) *ChatResponse {
+	response := &ChatResponse{
+		JID:              chat.JID,
+		Name:             chat.Name,
+		Topic:            chat.Topic,
+		IsGroup:          chat.IsGroup,
+		ParticipantCount: chat.ParticipantCount,
+		UnreadCount:      chat.UnreadCount,
+		Archived:         chat.Archived,
+		Pinned:           chat.Pinned,
+		Muted:            chat.MutedUntil != nil,
+		MutedUntil:       chat.MutedUntil,
+	}
+
+	if last := chat.LastMessage; last != nil {
+		response.LastMessage = &MessageInfo{
+			ID:        last.ID,
+			Chat:      chat.JID,
+			Sender:    last.Sender,
+			Timestamp: last.Timestamp,
+			FromMe:    last.FromMe,
+			Type:      last.Type,
+			IsGroup:   chat.IsGroup,
+			Content:   last.Content,
+		}
+	}
+
+	return response
+}
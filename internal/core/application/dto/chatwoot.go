@@ -0,0 +1,282 @@
+package dto
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/chatwoot"
+)
+
+// ChatwootConfigRequest creates or updates the Chatwoot configuration of a
+// session. Omitted fields keep their current value, or the default on
+// creation.
+type ChatwootConfigRequest struct {
+	URL            string   `json:"url,omitempty" example:"https://chatwoot.example.com"`
+	Token          string   `json:"token,omitempty" example:"aBcD1234"`
+	AccountID      string   `json:"accountId,omitempty" example:"1"`
+	InboxID        string   `json:"inboxId,omitempty" example:"3"`
+	Enabled        *bool    `json:"enabled,omitempty" example:"true"`
+	InboxName      string   `json:"inboxName,omitempty" example:"WhatsApp"`
+	AutoCreate     *bool    `json:"autoCreate,omitempty" example:"false"`
+	SignMsg        *bool    `json:"signMsg,omitempty" example:"false"`
+	SignDelimiter  *string  `json:"signDelimiter,omitempty"`
+	ReopenConv     *bool    `json:"reopenConv,omitempty" example:"true"`
+	ConvPending    *bool    `json:"convPending,omitempty" example:"false"`
+	ImportContacts *bool    `json:"importContacts,omitempty" example:"false"`
+	ImportMessages *bool    `json:"importMessages,omitempty" example:"false"`
+	ImportDays     *int     `json:"importDays,omitempty" example:"60"`
+	MergeBrazil    *bool    `json:"mergeBrazil,omitempty" example:"true"`
+	Organization   string   `json:"organization,omitempty"`
+	Logo           string   `json:"logo,omitempty"`
+	Number         string   `json:"number,omitempty" example:"5511999999999"`
+	IgnoreJIDs     []string `json:"ignoreJids,omitempty" example:"@g.us,status@broadcast"`
+	// RotateWebhookSecret replaces the secret of the webhook URL, which
+	// then has to be updated in the Chatwoot inbox.
+	RotateWebhookSecret bool `json:"rotateWebhookSecret,omitempty" example:"false"`
+} // @name ChatwootConfigRequest
+
+type ChatwootConfigResponse struct {
+	ID             string    `json:"id"`
+	SessionID      string    `json:"sessionId"`
+	URL            string    `json:"url"`
+	AccountID      string    `json:"accountId"`
+	InboxID        string    `json:"inboxId,omitempty"`
+	Enabled        bool      `json:"enabled"`
+	InboxName      string    `json:"inboxName,omitempty"`
+	AutoCreate     bool      `json:"autoCreate"`
+	SignMsg        bool      `json:"signMsg"`
+	SignDelimiter  string    `json:"signDelimiter"`
+	ReopenConv     bool      `json:"reopenConv"`
+	ConvPending    bool      `json:"convPending"`
+	ImportContacts bool      `json:"importContacts"`
+	ImportMessages bool      `json:"importMessages"`
+	ImportDays     int       `json:"importDays"`
+	MergeBrazil    bool      `json:"mergeBrazil"`
+	Organization   string    `json:"organization,omitempty"`
+	Logo           string    `json:"logo,omitempty"`
+	Number         string    `json:"number,omitempty"`
+	IgnoreJIDs     []string  `json:"ignoreJids"`
+	WebhookSecret  string    `json:"webhookSecret"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+} // @name ChatwootConfigResponse
+
+// Validate checks the fields given in the request. Whether required fields
+// are present is checked on the resulting configuration by ValidateConfig.
+func (r *ChatwootConfigRequest) Validate() error {
+	if r.URL != "" {
+		parsed, err := url.Parse(r.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return NewValidationError("url", "url must be an http or https URL")
+		}
+	}
+
+	if r.ImportDays != nil && *r.ImportDays < 0 {
+		return NewValidationError("importDays", "importDays cannot be negative")
+	}
+
+	for _, jid := range r.IgnoreJIDs {
+		if strings.TrimSpace(jid) == "" {
+			return NewValidationError("ignoreJids", "ignoreJids cannot contain empty entries")
+		}
+	}
+
+	return nil
+}
+
+// Apply copies the fields given in the request onto cfg.
+func (r *ChatwootConfigRequest) Apply(cfg *chatwoot.Config) {
+	setString := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+
+	setBool := func(target *bool, value *bool) {
+		if value != nil {
+			*target = *value
+		}
+	}
+
+	if r.URL != "" {
+		cfg.URL = strings.TrimRight(r.URL, "/")
+	}
+
+	setString(&cfg.Token, r.Token)
+	setString(&cfg.AccountID, r.AccountID)
+	setString(&cfg.InboxID, r.InboxID)
+	setString(&cfg.InboxName, r.InboxName)
+	setString(&cfg.Organization, r.Organization)
+	setString(&cfg.Logo, r.Logo)
+	setString(&cfg.Number, r.Number)
+
+	setBool(&cfg.Enabled, r.Enabled)
+	setBool(&cfg.AutoCreate, r.AutoCreate)
+	setBool(&cfg.SignMsg, r.SignMsg)
+	setBool(&cfg.ReopenConv, r.ReopenConv)
+	setBool(&cfg.ConvPending, r.ConvPending)
+	setBool(&cfg.ImportContacts, r.ImportContacts)
+	setBool(&cfg.ImportMessages, r.ImportMessages)
+	setBool(&cfg.MergeBrazil, r.MergeBrazil)
+
+	if r.SignDelimiter != nil {
+		cfg.SignDelimiter = *r.SignDelimiter
+	}
+
+	if r.ImportDays != nil {
+		cfg.ImportDays = *r.ImportDays
+	}
+
+	if r.IgnoreJIDs != nil {
+		cfg.IgnoreJIDs = r.IgnoreJIDs
+	}
+
+	if r.RotateWebhookSecret {
+		cfg.RotateWebhookSecret()
+	}
+
+	cfg.UpdatedAt = time.Now()
+}
+
+// ValidateConfig checks that a configuration can reach an inbox.
+func ValidateConfig(cfg *chatwoot.Config) error {
+	switch {
+	case cfg.URL == "":
+		return NewValidationError("url", "url is required")
+	case cfg.Token == "":
+		return NewValidationError("token", "token is required")
+	case cfg.AccountID == "":
+		return NewValidationError("accountId", "accountId is required")
+	case cfg.InboxID == "" && !(cfg.AutoCreate && cfg.InboxName != ""):
+		return NewValidationError("inboxId", "inboxId is required unless autoCreate is set with an inboxName")
+	}
+
+	return nil
+}
+
+func NewChatwootConfigResponse(cfg *chatwoot.Config) *ChatwootConfigResponse {
+	ignoreJIDs := cfg.IgnoreJIDs
+	if ignoreJIDs == nil {
+		ignoreJIDs = []string{}
+	}
+
+	return &ChatwootConfigResponse{
+		ID:             cfg.ID,
+		SessionID:      cfg.SessionID,
+		URL:            cfg.URL,
+		AccountID:      cfg.AccountID,
+		InboxID:        cfg.InboxID,
+		Enabled:        cfg.Enabled,
+		InboxName:      cfg.InboxName,
+		AutoCreate:     cfg.AutoCreate,
+		SignMsg:        cfg.SignMsg,
+		SignDelimiter:  cfg.SignDelimiter,
+		ReopenConv:     cfg.ReopenConv,
+		ConvPending:    cfg.ConvPending,
+		ImportContacts: cfg.ImportContacts,
+		ImportMessages: cfg.ImportMessages,
+		ImportDays:     cfg.ImportDays,
+		MergeBrazil:    cfg.MergeBrazil,
+		Organization:   cfg.Organization,
+		Logo:           cfg.Logo,
+		Number:         cfg.Number,
+		IgnoreJIDs:     ignoreJIDs,
+		WebhookSecret:  cfg.WebhookSecret,
+		CreatedAt:      cfg.CreatedAt,
+		UpdatedAt:      cfg.UpdatedAt,
+	}
+}
+
+// ChatwootWebhookRequest is the part of a Chatwoot webhook event zpwoot reads
+// to forward agent replies to WhatsApp.
+type ChatwootWebhookRequest struct {
+	Event        string                      `json:"event" example:"message_created"`
+	ID           int                         `json:"id" example:"42"`
+	Content      string                      `json:"content" example:"Hello!"`
+	MessageType  string                      `json:"message_type" example:"outgoing"`
+	Private      bool                        `json:"private" example:"false"`
+	SourceID     string                      `json:"source_id,omitempty"`
+	Account      ChatwootWebhookAccount      `json:"account"`
+	Inbox        ChatwootWebhookInbox        `json:"inbox"`
+	Conversation ChatwootWebhookConversation `json:"conversation"`
+	Sender       ChatwootWebhookSender       `json:"sender"`
+	Attachments  []ChatwootWebhookAttachment `json:"attachments,omitempty"`
+} // @name ChatwootWebhookRequest
+
+type ChatwootWebhookAccount struct {
+	ID int `json:"id" example:"1"`
+} // @name ChatwootWebhookAccount
+
+type ChatwootWebhookInbox struct {
+	ID int `json:"id" example:"3"`
+} // @name ChatwootWebhookInbox
+
+type ChatwootWebhookConversation struct {
+	ID int `json:"id" example:"7"`
+} // @name ChatwootWebhookConversation
+
+type ChatwootWebhookSender struct {
+	ID   int    `json:"id" example:"1"`
+	Name string `json:"name" example:"Jane"`
+	Type string `json:"type,omitempty" example:"user"`
+} // @name ChatwootWebhookSender
+
+type ChatwootWebhookAttachment struct {
+	FileType string `json:"file_type" example:"image"`
+	DataURL  string `json:"data_url"`
+} // @name ChatwootWebhookAttachment
+
+type ChatwootWebhookResponse struct {
+	Status     string   `json:"status" example:"sent"`
+	Reason     string   `json:"reason,omitempty"`
+	MessageIDs []string `json:"messageIds,omitempty"`
+} // @name ChatwootWebhookResponse
+
+const (
+	ChatwootWebhookStatusSent    = "sent"
+	ChatwootWebhookStatusIgnored = "ignored"
+)
+
+func NewChatwootWebhookIgnored(reason string) *ChatwootWebhookResponse {
+	return &ChatwootWebhookResponse{
+		Status: ChatwootWebhookStatusIgnored,
+		Reason: reason,
+	}
+}
+
+// ChatwootImportResponse reports the progress of a session's Chatwoot
+// import.
+type ChatwootImportResponse struct {
+	ID               string     `json:"id"`
+	SessionID        string     `json:"sessionId"`
+	Status           string     `json:"status" example:"running"`
+	Phase            string     `json:"phase" example:"messages"`
+	ContactsTotal    int        `json:"contactsTotal" example:"120"`
+	ContactsImported int        `json:"contactsImported" example:"120"`
+	MessagesTotal    int        `json:"messagesTotal" example:"5400"`
+	MessagesImported int        `json:"messagesImported" example:"2310"`
+	MessagesFailed   int        `json:"messagesFailed" example:"2"`
+	Error            string     `json:"error,omitempty"`
+	StartedAt        time.Time  `json:"startedAt"`
+	FinishedAt       *time.Time `json:"finishedAt,omitempty"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+} // @name ChatwootImportResponse
+
+func NewChatwootImportResponse(job *chatwoot.ImportJob) *ChatwootImportResponse {
+	return &ChatwootImportResponse{
+		ID:               job.ID,
+		SessionID:        job.SessionID,
+		Status:           string(job.Status),
+		Phase:            string(job.Phase),
+		ContactsTotal:    job.ContactsTotal,
+		ContactsImported: job.ContactsImported,
+		MessagesTotal:    job.MessagesTotal,
+		MessagesImported: job.MessagesImported,
+		MessagesFailed:   job.MessagesFailed,
+		Error:            job.LastError,
+		StartedAt:        job.StartedAt,
+		FinishedAt:       job.FinishedAt,
+		UpdatedAt:        job.UpdatedAt,
+	}
+}
@@ -8,6 +8,9 @@ type WhatsAppUserInfo struct {
 	IsInWhatsApp bool   `json:"isInWhatsApp" example:"true"`
 	JID          string `json:"jid" example:"5511999999999@s.whatsapp.net"`
 	VerifiedName string `json:"verifiedName,omitempty" example:"John Doe Business"`
+	// Candidates lists the forms of the number that were looked up; JID is
+	// the one registered on WhatsApp.
+	Candidates []string `json:"candidates,omitempty" example:"5511999999999,551199999999"`
 } // @name WhatsAppUserInfo
 type CheckUserResponse struct {
 	Users []WhatsAppUserInfo `json:"users"`
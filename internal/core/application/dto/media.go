@@ -0,0 +1,12 @@
+package dto
+
+import "io"
+
+// MediaFile is the file of a media message. Content must be closed by the
+// caller.
+type MediaFile struct {
+	Content  io.ReadCloser
+	MimeType string
+	FileName string
+	Size     int64
+}
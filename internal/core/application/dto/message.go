@@ -3,10 +3,12 @@ package dto
 import (
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"zpwoot/internal/core/application/validators"
+	"zpwoot/internal/core/domain/message"
 	"zpwoot/internal/core/ports/output"
 )
 
@@ -156,21 +158,130 @@ type MessageInfo struct {
 	Content   string    `json:"content,omitempty"`
 }
 
+func (m *MessageInfo) ToDomain(sessionID string) *message.Message {
+	return message.NewMessage(sessionID, m.ID, m.Chat, m.Sender, m.Type, m.Content, m.FromMe, m.Timestamp)
+}
+
 type ReceiveMessageRequest struct {
 	SessionID string      `json:"sessionId"`
 	Message   MessageInfo `json:"message"`
 }
 
 type MessageHistoryRequest struct {
-	ChatJID string `json:"chatJid" validate:"required"`
-	Limit   int    `json:"limit,omitempty"`
-	Offset  int    `json:"offset,omitempty"`
+	ChatJID string     `json:"chatJid" validate:"required"`
+	Limit   int        `json:"limit,omitempty"`
+	Cursor  string     `json:"cursor,omitempty"`
+	Type    string     `json:"type,omitempty"`
+	FromMe  *bool      `json:"fromMe,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+	Search  string     `json:"q,omitempty"`
+}
+
+func (r *MessageHistoryRequest) Validate() error {
+	if r.ChatJID == "" {
+		return NewValidationError("chatJid", "chat JID is required")
+	}
+
+	if r.Limit < 0 || r.Limit > message.MaxQueryLimit {
+		return NewValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", message.MaxQueryLimit))
+	}
+
+	if r.Since != nil && r.Until != nil && r.Since.After(*r.Until) {
+		return NewValidationError("since", "since must not be after until")
+	}
+
+	if r.Cursor != "" {
+		if _, err := message.DecodeCursor(r.Cursor); err != nil {
+			return NewValidationError("cursor", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (r *MessageHistoryRequest) ToQuery(sessionID string) *message.Query {
+	query := &message.Query{
+		SessionID: sessionID,
+		Chat:      r.ChatJID,
+		Type:      r.Type,
+		FromMe:    r.FromMe,
+		Since:     r.Since,
+		Until:     r.Until,
+		Search:    r.Search,
+		Limit:     r.Limit,
+	}
+
+	if r.Cursor != "" {
+		query.Before, _ = message.DecodeCursor(r.Cursor)
+	}
+
+	return query
 }
 
 type MessageHistoryResponse struct {
-	Messages []MessageInfo `json:"messages"`
-	Total    int           `json:"total"`
-	HasMore  bool          `json:"hasMore"`
+	SessionID  string         `json:"sessionId"`
+	ChatJID    string         `json:"chatJid"`
+	Messages   []*MessageInfo `json:"messages"`
+	Count      int            `json:"count"`
+	HasMore    bool           `json:"hasMore"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+} // @name MessageHistoryResponse
+
+// MessageStatusResponse is how far a sent message got. Status is the
+// furthest status any recipient reached; in groups Recipients has one entry
+// per participant that sent a receipt.
+type MessageStatusResponse struct {
+	MessageID  string                    `json:"messageId"`
+	Chat       string                    `json:"chat" example:"5511999999999@s.whatsapp.net"`
+	Type       string                    `json:"type" example:"text"`
+	Status     string                    `json:"status" example:"read"`
+	SentAt     time.Time                 `json:"sentAt"`
+	Recipients []*MessageReceiptResponse `json:"recipients"`
+} // @name MessageStatusResponse
+
+type MessageReceiptResponse struct {
+	Recipient   string     `json:"recipient" example:"5511999999999@s.whatsapp.net"`
+	Status      string     `json:"status" example:"read"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+	ReadAt      *time.Time `json:"readAt,omitempty"`
+	PlayedAt    *time.Time `json:"playedAt,omitempty"`
+} // @name MessageReceiptResponse
+
+func NewMessageStatusResponse(msg *message.Message, receipts []*message.Receipt) *MessageStatusResponse {
+	response := &MessageStatusResponse{
+		MessageID:  msg.MessageID,
+		Chat:       msg.Chat,
+		Type:       msg.Type,
+		Status:     string(message.OverallStatus(receipts)),
+		SentAt:     msg.Timestamp,
+		Recipients: make([]*MessageReceiptResponse, 0, len(receipts)),
+	}
+
+	for _, receipt := range receipts {
+		response.Recipients = append(response.Recipients, &MessageReceiptResponse{
+			Recipient:   receipt.Recipient,
+			Status:      string(receipt.Status),
+			DeliveredAt: receipt.DeliveredAt,
+			ReadAt:      receipt.ReadAt,
+			PlayedAt:    receipt.PlayedAt,
+		})
+	}
+
+	return response
+}
+
+func NewMessageInfo(msg *message.Message) *MessageInfo {
+	return &MessageInfo{
+		ID:        msg.MessageID,
+		Chat:      msg.Chat,
+		Sender:    msg.Sender,
+		Timestamp: msg.Timestamp,
+		FromMe:    msg.FromMe,
+		Type:      msg.Type,
+		IsGroup:   strings.HasSuffix(msg.Chat, "@g.us"),
+		Content:   msg.Content,
+	}
 }
 
 func (r *SendMessageRequest) Validate() error {
@@ -425,7 +536,8 @@ type MarkReadResponse struct {
 	Success bool `json:"success" example:"true"`
 } // @name MarkReadResponse
 type HistorySyncRequest struct {
-	Count int `json:"count,omitempty" example:"50" description:"Number of messages to sync (default: 50)"`
+	ChatJID string `json:"chatJid" validate:"required" example:"5511999999999@s.whatsapp.net" description:"Chat to fetch older messages for (phone number or JID)"`
+	Count   int    `json:"count,omitempty" example:"50" description:"Number of messages to sync (default: 50)"`
 } // @name HistorySyncRequest
 type HistorySyncResponse struct {
 	Success   bool  `json:"success" example:"true"`
@@ -0,0 +1,262 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/schedule"
+)
+
+// sendAtTolerance is how far in the past sendAt may be, so a message meant
+// for "now" is not rejected for the time the request took to arrive.
+const sendAtTolerance = time.Minute
+
+// sendAtLayouts are the local time formats accepted for sendAt, read in the
+// request's timezone. RFC3339 times carry their own offset.
+var sendAtLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ScheduleMessageRequest schedules a message for later. Message is the body
+// of the send endpoint matching type, e.g. a SendTextMessageRequest for
+// "text".
+type ScheduleMessageRequest struct {
+	Type           string          `json:"type" validate:"required" example:"text"`
+	SendAt         string          `json:"sendAt" validate:"required" example:"2025-12-24T09:00:00"`
+	Timezone       string          `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+	OnDisconnected string          `json:"onDisconnected,omitempty" example:"retry"`
+	Message        json.RawMessage `json:"message" validate:"required" swaggertype:"object"`
+} // @name ScheduleMessageRequest
+
+// RescheduleMessageRequest moves a pending scheduled message to a new time.
+type RescheduleMessageRequest struct {
+	SendAt   string `json:"sendAt" validate:"required" example:"2025-12-24T18:00:00"`
+	Timezone string `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+} // @name RescheduleMessageRequest
+
+type ScheduledMessageResponse struct {
+	ID             string          `json:"id"`
+	SessionID      string          `json:"sessionId"`
+	Phone          string          `json:"phone" example:"5511999999999"`
+	Type           string          `json:"type" example:"text"`
+	SendAt         time.Time       `json:"sendAt"`
+	Timezone       string          `json:"timezone" example:"America/Sao_Paulo"`
+	OnDisconnected string          `json:"onDisconnected" example:"retry"`
+	Status         string          `json:"status" example:"pending"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	MessageID      *string         `json:"messageId,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	SentAt         *time.Time      `json:"sentAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	Message        json.RawMessage `json:"message,omitempty" swaggertype:"object"`
+} // @name ScheduledMessageResponse
+
+type ListScheduledMessagesRequest struct {
+	PaginationRequest
+	Status string `json:"status,omitempty"`
+}
+
+// Validate checks the request and returns the recipient of the message, the
+// time to send it and its time zone.
+func (r *ScheduleMessageRequest) Validate() (string, time.Time, string, error) {
+	phone, err := ValidateScheduledPayload(r.Type, r.Message)
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+
+	switch schedule.DisconnectedPolicy(r.OnDisconnected) {
+	case "", schedule.DisconnectedRetry, schedule.DisconnectedSkip:
+	default:
+		return "", time.Time{}, "", NewValidationError("onDisconnected", "onDisconnected must be retry or skip")
+	}
+
+	sendAt, timezone, err := ParseSendAt(r.SendAt, r.Timezone)
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+
+	return phone, sendAt, timezone, nil
+}
+
+// Policy returns what to do with the message when its session is not
+// connected; retry unless asked otherwise.
+func (r *ScheduleMessageRequest) Policy() schedule.DisconnectedPolicy {
+	if r.OnDisconnected == "" {
+		return schedule.DisconnectedRetry
+	}
+
+	return schedule.DisconnectedPolicy(r.OnDisconnected)
+}
+
+func (r *ListScheduledMessagesRequest) Validate() error {
+	switch schedule.Status(r.Status) {
+	case "", schedule.StatusPending, schedule.StatusSent, schedule.StatusFailed, schedule.StatusSkipped, schedule.StatusCancelled:
+		return nil
+	default:
+		return NewValidationError("status", "status must be pending, sent, failed, skipped or cancelled")
+	}
+}
+
+// ParseSendAt reads sendAt as an RFC3339 time or as a local time in timezone
+// (UTC when empty) and checks that it is not in the past. It returns the time
+// and the name of its time zone.
+func ParseSendAt(sendAt, timezone string) (time.Time, string, error) {
+	if sendAt == "" {
+		return time.Time{}, "", NewValidationError("sendAt", "sendAt is required")
+	}
+
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, "", NewValidationError("timezone", "timezone must be an IANA time zone such as America/Sao_Paulo")
+	}
+
+	t, err := time.Parse(time.RFC3339, sendAt)
+	for _, layout := range sendAtLayouts {
+		if err == nil {
+			break
+		}
+
+		t, err = time.ParseInLocation(layout, sendAt, location)
+	}
+
+	if err != nil {
+		return time.Time{}, "", NewValidationError("sendAt", "sendAt must be RFC3339 or a local time like 2025-12-24T09:00:00")
+	}
+
+	if t.Before(time.Now().Add(-sendAtTolerance)) {
+		return time.Time{}, "", NewValidationError("sendAt", "sendAt must not be in the past")
+	}
+
+	return t, timezone, nil
+}
+
+// ValidateScheduledPayload checks that payload is a valid body for the send
+// endpoint of messageType and returns its recipient.
+func ValidateScheduledPayload(messageType string, payload json.RawMessage) (string, error) {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return "", NewValidationError("message", "message is required")
+	}
+
+	decode := func(target interface{}) error {
+		if err := json.Unmarshal(payload, target); err != nil {
+			return NewValidationError("message", "message must be a "+messageType+" message body")
+		}
+
+		return nil
+	}
+
+	var (
+		phone   string
+		missing string
+	)
+
+	switch messageType {
+	case schedule.TypeText:
+		var req SendTextMessageRequest
+		if err := decode(&req); err != nil {
+			return "", err
+		}
+
+		phone = req.Phone
+		if req.Text == "" {
+			missing = "text"
+		}
+	case schedule.TypeImage, schedule.TypeAudio, schedule.TypeVideo, schedule.TypeDocument, schedule.TypeSticker:
+		var req struct {
+			Phone string `json:"phone"`
+			File  string `json:"file"`
+		}
+		if err := decode(&req); err != nil {
+			return "", err
+		}
+
+		phone = req.Phone
+		if req.File == "" {
+			missing = "file"
+		}
+	case schedule.TypeLocation:
+		var req SendLocationMessageRequest
+		if err := decode(&req); err != nil {
+			return "", err
+		}
+
+		phone = req.Phone
+	case schedule.TypeContact:
+		var req SendContactMessageRequest
+		if err := decode(&req); err != nil {
+			return "", err
+		}
+
+		phone = req.Phone
+		if req.Contact == nil {
+			missing = "contact"
+		}
+	case schedule.TypePoll:
+		var req SendPollMessageRequest
+		if err := decode(&req); err != nil {
+			return "", err
+		}
+
+		phone = req.Phone
+
+		switch {
+		case req.Name == "":
+			missing = "name"
+		case len(req.Options) < 2:
+			return "", NewValidationError("message.options", "a poll needs at least two options")
+		}
+	default:
+		return "", NewValidationError("type", "type must be one of "+strings.Join(schedule.ValidTypes(), ", "))
+	}
+
+	if phone == "" {
+		missing = "phone"
+	}
+
+	if missing != "" {
+		return "", NewValidationError("message."+missing, missing+" is required")
+	}
+
+	return phone, nil
+}
+
+func NewScheduledMessageResponse(msg *schedule.ScheduledMessage, withMessage bool) *ScheduledMessageResponse {
+	response := &ScheduledMessageResponse{
+		ID:             msg.ID,
+		SessionID:      msg.SessionID,
+		Phone:          msg.Phone,
+		Type:           msg.Type,
+		SendAt:         msg.SendAt,
+		Timezone:       msg.Timezone,
+		OnDisconnected: string(msg.OnDisconnected),
+		Status:         string(msg.Status),
+		Attempts:       msg.Attempts,
+		MessageID:      msg.MessageID,
+		LastError:      msg.LastError,
+		SentAt:         msg.SentAt,
+		CreatedAt:      msg.CreatedAt,
+		UpdatedAt:      msg.UpdatedAt,
+	}
+
+	if msg.IsPending() {
+		nextAttemptAt := msg.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+
+	if withMessage {
+		response.Message = msg.Payload
+	}
+
+	return response
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSendAt(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+
+	// A year ahead, so the times are never in the past.
+	year := time.Now().Year() + 1
+	local := time.Date(year, time.December, 24, 9, 0, 0, 0, saoPaulo)
+
+	tests := []struct {
+		name         string
+		sendAt       string
+		timezone     string
+		want         time.Time
+		wantTimezone string
+	}{
+		{"local time in the time zone", local.Format("2006-01-02T15:04:05"), "America/Sao_Paulo", local, "America/Sao_Paulo"},
+		{"local time without seconds", local.Format("2006-01-02T15:04"), "America/Sao_Paulo", local, "America/Sao_Paulo"},
+		{"local time with a space", local.Format("2006-01-02 15:04:05"), "America/Sao_Paulo", local, "America/Sao_Paulo"},
+		{"local time defaults to UTC", local.Format("2006-01-02T15:04:05"), "", time.Date(year, time.December, 24, 9, 0, 0, 0, time.UTC), "UTC"},
+		{"RFC3339 keeps its offset", local.Format(time.RFC3339), "Asia/Tokyo", local, "Asia/Tokyo"},
+		{"RFC3339 in UTC", local.UTC().Format(time.RFC3339), "America/Sao_Paulo", local, "America/Sao_Paulo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, timezone, err := ParseSendAt(tt.sendAt, tt.timezone)
+			if err != nil {
+				t.Fatalf("ParseSendAt(%q, %q): %v", tt.sendAt, tt.timezone, err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSendAt(%q, %q) = %s, want %s", tt.sendAt, tt.timezone, got, tt.want)
+			}
+
+			if timezone != tt.wantTimezone {
+				t.Errorf("timezone = %q, want %q", timezone, tt.wantTimezone)
+			}
+		})
+	}
+}
+
+func TestParseSendAtRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		sendAt   string
+		timezone string
+		field    string
+	}{
+		{"missing", "", "", "sendAt"},
+		{"unknown time zone", "2099-12-24T09:00:00", "Mars/Olympus_Mons", "timezone"},
+		{"unknown format", "24/12/2099 09:00", "", "sendAt"},
+		{"in the past", time.Now().Add(-time.Hour).Format(time.RFC3339), "", "sendAt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseSendAt(tt.sendAt, tt.timezone)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ParseSendAt(%q, %q) = %v, want a validation error", tt.sendAt, tt.timezone, err)
+			}
+
+			if validationErr.Field != tt.field {
+				t.Errorf("error on %q, want %q", validationErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestParseSendAtToleratesNow(t *testing.T) {
+	sendAt := time.Now().Add(-sendAtTolerance / 2).Format(time.RFC3339)
+
+	if _, _, err := ParseSendAt(sendAt, ""); err != nil {
+		t.Errorf("ParseSendAt(%q) = %v, want it accepted", sendAt, err)
+	}
+}
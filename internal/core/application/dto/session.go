@@ -32,6 +32,7 @@ type SessionSettings struct {
 } // @name SessionSettings
 
 type CreateRequest struct {
+	TenantID string           `json:"tenantId,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" description:"Tenant owning the session; only the server operator may set it, tenants always create sessions of their own"`
 	Name     string           `json:"name" example:"my-session" validate:"required,min=1,max=100" description:"Session name for identification"`
 	Settings *SessionSettings `json:"settings,omitempty" description:"Session settings (proxy, webhook)"`
 } // @name CreateSessionRequest
@@ -43,6 +44,7 @@ type UpdateRequest struct {
 
 type SessionResponse struct {
 	SessionID       string           `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000" description:"Unique session identifier"`
+	TenantID        string           `json:"tenantId,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" description:"Tenant owning the session"`
 	Name            string           `json:"name" example:"my-session" description:"Session name"`
 	Status          string           `json:"status" example:"connected" description:"Current session status (disconnected, connecting, connected, qr_code, error)"`
 	Connected       bool             `json:"connected" example:"true" description:"Whether session is connected"`
@@ -59,6 +61,7 @@ type SessionResponse struct {
 
 type SessionListInfo struct {
 	SessionID   string           `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000" description:"Unique session identifier"`
+	TenantID    string           `json:"tenantId,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" description:"Tenant owning the session"`
 	Name        string           `json:"name" example:"my-session" description:"Session name"`
 	Status      string           `json:"status" example:"connected" description:"Current session status (disconnected, connecting, connected, qr_code, error)"`
 	Connected   bool             `json:"connected" example:"true" description:"Whether session is connected"`
@@ -91,6 +94,7 @@ type QRCodeResponse struct {
 
 type CreateSessionResponse struct {
 	ID              string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" description:"Session identifier"`
+	TenantID        string     `json:"tenantId" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" description:"Tenant owning the session"`
 	Name            string     `json:"name" example:"My WhatsApp Session" description:"Session name"`
 	Status          string     `json:"status" example:"disconnected" description:"Initial session status"`
 	Connected       bool       `json:"connected" example:"false" description:"Whether session is connected"`
@@ -101,19 +105,19 @@ type CreateSessionResponse struct {
 }
 
 type SessionDetailResponse struct {
-	ID              string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" description:"Session identifier"`
-	Name            string     `json:"name" example:"My WhatsApp Session" description:"Session name"`
-	DeviceJID       string     `json:"deviceJid,omitempty" example:"5511999999999@s.whatsapp.net" description:"WhatsApp device JID when connected"`
-	Status          string     `json:"status" example:"connected" description:"Current session status"`
-	Connected       bool       `json:"connected" example:"true" description:"Whether session is connected"`
-	ConnectionError string     `json:"connectionError,omitempty" example:"Connection timeout" description:"Connection error message if any"`
-	QRCode          string     `json:"qrCode,omitempty" description:"QR code for authentication"`
-	QRCodeExpiresAt *time.Time `json:"qrCodeExpiresAt,omitempty" example:"2025-01-15T10:35:00Z" description:"QR code expiration time"`
-	ProxyConfig     *string    `json:"proxyConfig,omitempty" description:"Proxy configuration as JSON string"`
-	CreatedAt       time.Time  `json:"createdAt" example:"2025-01-15T10:30:00Z" description:"Session creation timestamp"`
-	UpdatedAt       time.Time  `json:"updatedAt" example:"2025-01-15T10:35:00Z" description:"Last update timestamp"`
-	ConnectedAt     *time.Time `json:"connectedAt,omitempty" example:"2025-01-15T10:32:00Z" description:"Connection timestamp"`
-	LastSeen        *time.Time `json:"lastSeen,omitempty" example:"2025-01-15T10:35:00Z" description:"Last activity timestamp"`
+	ID              string         `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" description:"Session identifier"`
+	Name            string         `json:"name" example:"My WhatsApp Session" description:"Session name"`
+	DeviceJID       string         `json:"deviceJid,omitempty" example:"5511999999999@s.whatsapp.net" description:"WhatsApp device JID when connected"`
+	Status          string         `json:"status" example:"connected" description:"Current session status"`
+	Connected       bool           `json:"connected" example:"true" description:"Whether session is connected"`
+	ConnectionError string         `json:"connectionError,omitempty" example:"Connection timeout" description:"Connection error message if any"`
+	QRCode          string         `json:"qrCode,omitempty" description:"QR code for authentication"`
+	QRCodeExpiresAt *time.Time     `json:"qrCodeExpiresAt,omitempty" example:"2025-01-15T10:35:00Z" description:"QR code expiration time"`
+	Proxy           *ProxyResponse `json:"proxy,omitempty" description:"Proxy configuration, without its password"`
+	CreatedAt       time.Time      `json:"createdAt" example:"2025-01-15T10:30:00Z" description:"Session creation timestamp"`
+	UpdatedAt       time.Time      `json:"updatedAt" example:"2025-01-15T10:35:00Z" description:"Last update timestamp"`
+	ConnectedAt     *time.Time     `json:"connectedAt,omitempty" example:"2025-01-15T10:32:00Z" description:"Connection timestamp"`
+	LastSeen        *time.Time     `json:"lastSeen,omitempty" example:"2025-01-15T10:35:00Z" description:"Last activity timestamp"`
 }
 
 type SessionStatusResponse struct {
@@ -137,19 +141,85 @@ func (r *CreateRequest) Validate() error {
 		}
 	}
 
-	if r.Settings != nil && r.Settings.Proxy != nil && r.Settings.Proxy.Enabled {
-		if r.Settings.Proxy.Host == "" {
-			return NewValidationError("settings.proxy.host", "proxy host is required when proxy is enabled")
+	if r.Settings != nil && r.Settings.Proxy != nil {
+		if err := r.Settings.Proxy.validate("settings.proxy"); err != nil {
+			return err
 		}
+	}
 
-		if r.Settings.Proxy.Port == "" {
-			return NewValidationError("settings.proxy.port", "proxy port is required when proxy is enabled")
-		}
+	return nil
+}
+
+func (p *ProxySettings) Validate() error {
+	return p.validate("proxy")
+}
+
+func (p *ProxySettings) validate(field string) error {
+	if !p.Enabled {
+		return nil
+	}
+
+	if p.Host == "" {
+		return NewValidationError(field+".host", "proxy host is required when proxy is enabled")
+	}
+
+	if p.Port == "" {
+		return NewValidationError(field+".port", "proxy port is required when proxy is enabled")
+	}
+
+	if err := p.ToDomain().Validate(); err != nil {
+		return NewValidationError(field, err.Error())
 	}
 
 	return nil
 }
 
+// ToDomain converts the settings into a domain proxy. A disabled proxy maps to nil.
+func (p *ProxySettings) ToDomain() *session.Proxy {
+	if p == nil || !p.Enabled {
+		return nil
+	}
+
+	proxyType := session.ProxyType(p.Type)
+	if proxyType == "" {
+		proxyType = session.ProxyTypeHTTP
+	}
+
+	return &session.Proxy{
+		Type:     proxyType,
+		Host:     p.Host,
+		Port:     p.Port,
+		Username: p.User,
+		Password: p.Pass,
+	}
+}
+
+type ProxyResponse struct {
+	SessionID   string `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000" description:"Session ID"`
+	Enabled     bool   `json:"enabled" example:"true" description:"Whether a proxy is configured"`
+	Type        string `json:"type,omitempty" example:"socks5" description:"Proxy type (http, https, socks5)"`
+	Host        string `json:"host,omitempty" example:"proxy.example.com" description:"Proxy host"`
+	Port        string `json:"port,omitempty" example:"1080" description:"Proxy port"`
+	User        string `json:"user,omitempty" example:"proxyUser123" description:"Proxy username"`
+	HasPassword bool   `json:"hasPassword" example:"true" description:"Whether a proxy password is stored (the password itself is never returned)"`
+	Reconnected bool   `json:"reconnected" example:"true" description:"Whether the session was reconnected to apply the change"`
+} // @name ProxyResponse
+
+func ToProxyResponse(sessionID string, proxy *session.Proxy) *ProxyResponse {
+	response := &ProxyResponse{SessionID: sessionID}
+
+	if proxy != nil {
+		response.Enabled = true
+		response.Type = string(proxy.Type)
+		response.Host = proxy.Host
+		response.Port = proxy.Port
+		response.User = proxy.Username
+		response.HasPassword = proxy.Password != ""
+	}
+
+	return response
+}
+
 func (r *CreateRequest) ToDomain() *session.Session {
 	return session.NewSession(r.Name)
 }
@@ -157,6 +227,7 @@ func (r *CreateRequest) ToDomain() *session.Session {
 func FromDomain(s *session.Session) *SessionResponse {
 	response := &SessionResponse{
 		SessionID:   s.ID,
+		TenantID:    s.TenantID,
 		Name:        s.Name,
 		Status:      string(s.GetStatus()),
 		Connected:   s.IsConnected,
@@ -181,6 +252,7 @@ func FromDomain(s *session.Session) *SessionResponse {
 func (s *SessionResponse) ToListInfo() *SessionListInfo {
 	return &SessionListInfo{
 		SessionID:   s.SessionID,
+		TenantID:    s.TenantID,
 		Name:        s.Name,
 		Status:      s.Status,
 		Connected:   s.Connected,
@@ -208,7 +280,7 @@ func NewQRResponse(qrCode string, expiresAt time.Time, status string) *QRCodeRes
 }
 
 func ToDetailResponse(s *session.Session) *SessionDetailResponse {
-	return &SessionDetailResponse{
+	response := &SessionDetailResponse{
 		ID:              s.ID,
 		Name:            s.Name,
 		DeviceJID:       s.DeviceJID,
@@ -217,17 +289,25 @@ func ToDetailResponse(s *session.Session) *SessionDetailResponse {
 		ConnectionError: s.ConnectionError,
 		QRCode:          s.QRCode,
 		QRCodeExpiresAt: s.QRCodeExpiresAt,
-		ProxyConfig:     s.ProxyConfig,
 		CreatedAt:       s.CreatedAt,
 		UpdatedAt:       s.UpdatedAt,
 		ConnectedAt:     s.ConnectedAt,
 		LastSeen:        s.LastSeen,
 	}
+
+	// The stored proxy holds the password in plain text, so only its
+	// description is returned.
+	if proxy, err := s.GetProxy(); err == nil && proxy != nil {
+		response.Proxy = ToProxyResponse(s.ID, proxy)
+	}
+
+	return response
 }
 
 func ToCreateResponse(s *session.Session) *CreateSessionResponse {
 	response := &CreateSessionResponse{
 		ID:        s.ID,
+		TenantID:  s.TenantID,
 		Name:      s.Name,
 		Status:    string(s.GetStatus()),
 		Connected: s.IsConnected,
@@ -271,6 +351,7 @@ func ToListResponse(s *session.Session) *SessionResponse {
 func ToListInfo(s *session.Session) *SessionListInfo {
 	return &SessionListInfo{
 		SessionID:   s.ID,
+		TenantID:    s.TenantID,
 		Name:        s.Name,
 		Status:      string(s.GetStatus()),
 		Connected:   s.IsConnected,
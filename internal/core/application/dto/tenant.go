@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/tenant"
+)
+
+// CreateTenantRequest creates a tenant. Quotas of 0, the default, are
+// unlimited.
+type CreateTenantRequest struct {
+	Name              string `json:"name" validate:"required" example:"acme"`
+	MaxSessions       int    `json:"maxSessions,omitempty" example:"5"`
+	MaxMessagesPerDay int    `json:"maxMessagesPerDay,omitempty" example:"10000"`
+} // @name CreateTenantRequest
+
+// UpdateTenantRequest changes the fields it sets and leaves the others.
+type UpdateTenantRequest struct {
+	Name              *string `json:"name,omitempty" example:"acme"`
+	MaxSessions       *int    `json:"maxSessions,omitempty" example:"10"`
+	MaxMessagesPerDay *int    `json:"maxMessagesPerDay,omitempty" example:"20000"`
+} // @name UpdateTenantRequest
+
+type TenantResponse struct {
+	ID                string               `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name              string               `json:"name" example:"acme"`
+	Status            string               `json:"status" example:"active"`
+	MaxSessions       int                  `json:"maxSessions" example:"5"`
+	MaxMessagesPerDay int                  `json:"maxMessagesPerDay" example:"10000"`
+	Usage             *TenantUsageResponse `json:"usage,omitempty"`
+	CreatedAt         time.Time            `json:"createdAt"`
+	UpdatedAt         time.Time            `json:"updatedAt"`
+} // @name TenantResponse
+
+// TenantUsageResponse is what a tenant uses now: its sessions and the
+// messages it sent on the current UTC day.
+type TenantUsageResponse struct {
+	Sessions      int       `json:"sessions" example:"3"`
+	MessagesToday int       `json:"messagesToday" example:"1250"`
+	Day           time.Time `json:"day" example:"2025-01-15T00:00:00Z"`
+} // @name TenantUsageResponse
+
+type TenantListResponse struct {
+	Tenants []*TenantResponse `json:"tenants"`
+	Total   int               `json:"total" example:"2"`
+} // @name TenantListResponse
+
+func (r *CreateTenantRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return NewValidationError("name", "name is required")
+	}
+
+	return validateQuotas(r.MaxSessions, r.MaxMessagesPerDay)
+}
+
+// Apply validates the request and changes t accordingly.
+func (r *UpdateTenantRequest) Apply(t *tenant.Tenant) error {
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		if name == "" {
+			return NewValidationError("name", "name cannot be empty")
+		}
+
+		t.Name = name
+	}
+
+	if r.MaxSessions != nil {
+		t.MaxSessions = *r.MaxSessions
+	}
+
+	if r.MaxMessagesPerDay != nil {
+		t.MaxMessagesPerDay = *r.MaxMessagesPerDay
+	}
+
+	t.UpdatedAt = time.Now()
+
+	return validateQuotas(t.MaxSessions, t.MaxMessagesPerDay)
+}
+
+func validateQuotas(maxSessions, maxMessagesPerDay int) error {
+	if maxSessions < 0 {
+		return NewValidationError("maxSessions", "maxSessions cannot be negative")
+	}
+
+	if maxMessagesPerDay < 0 {
+		return NewValidationError("maxMessagesPerDay", "maxMessagesPerDay cannot be negative")
+	}
+
+	return nil
+}
+
+func NewTenantResponse(t *tenant.Tenant) *TenantResponse {
+	return &TenantResponse{
+		ID:                t.ID,
+		Name:              t.Name,
+		Status:            string(t.Status),
+		MaxSessions:       t.MaxSessions,
+		MaxMessagesPerDay: t.MaxMessagesPerDay,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+	}
+}
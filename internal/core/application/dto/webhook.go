@@ -1,21 +1,38 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"zpwoot/internal/core/domain/webhook"
+)
 
 type CreateWebhookRequest struct {
-	URL    string   `json:"url" validate:"required,url"`
-	Secret *string  `json:"secret,omitempty"`
-	Events []string `json:"events,omitempty"`
+	URL     string   `json:"url" validate:"required,url"`
+	Secret  *string  `json:"secret,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+	Format  string   `json:"format,omitempty" enums:"normalized,raw" example:"normalized"`
 } // @name CreateWebhookRequest
 type WebhookResponse struct {
 	ID        string    `json:"id"`
-	SessionID string    `json:"sessionId"`
+	SessionID string    `json:"sessionId,omitempty"`
 	URL       string    `json:"url"`
 	Events    []string  `json:"events"`
 	Enabled   bool      `json:"enabled"`
+	Format    string    `json:"format"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 } // @name WebhookResponse
+type ListWebhooksResponse struct {
+	SessionID string             `json:"sessionId"`
+	Webhooks  []*WebhookResponse `json:"webhooks"`
+	Total     int                `json:"total"`
+} // @name ListWebhooksResponse
+type EventSchemasResponse struct {
+	SchemaVersion int                               `json:"schemaVersion"`
+	Schemas       map[string]map[string]interface{} `json:"schemas"`
+} // @name EventSchemasResponse
 type EventCategoryResponse struct {
 	Category string   `json:"category"`
 	Events   []string `json:"events"`
@@ -25,9 +42,112 @@ type ListEventsResponse struct {
 	AllEvents  []string                `json:"allEvents"`
 } // @name ListEventsResponse
 type WebhookEventPayload struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	Timestamp time.Time              `json:"timestamp"`
-	Data      map[string]interface{} `json:"data"`
+	ID            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	SessionID     string                 `json:"sessionId"`
+	Timestamp     time.Time              `json:"timestamp"`
+	SchemaVersion int                    `json:"schemaVersion,omitempty"`
+	Data          map[string]interface{} `json:"data"`
+}
+type DeadLetterResponse struct {
+	ID             string          `json:"id"`
+	SessionID      string          `json:"sessionId"`
+	URL            string          `json:"url"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Attempts       int             `json:"attempts"`
+	LastError      *string         `json:"lastError,omitempty"`
+	LastStatusCode *int            `json:"lastStatusCode,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	FailedAt       time.Time       `json:"failedAt"`
+	Payload        json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+} // @name DeadLetterResponse
+type ListDeadLettersRequest struct {
+	PaginationRequest
+	SessionID string     `json:"sessionId,omitempty"`
+	EventType string     `json:"eventType,omitempty"`
+	Since     *time.Time `json:"since,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+}
+type ReplayDeadLettersRequest struct {
+	IDs       []string   `json:"ids,omitempty"`
+	SessionID string     `json:"sessionId,omitempty"`
+	EventType string     `json:"eventType,omitempty"`
+	Since     *time.Time `json:"since,omitempty" example:"2025-01-15T10:00:00Z"`
+	Until     *time.Time `json:"until,omitempty" example:"2025-01-15T11:00:00Z"`
+} // @name ReplayDeadLettersRequest
+type ReplayDeadLettersResponse struct {
+	Replayed int `json:"replayed"`
+} // @name ReplayDeadLettersResponse
+
+// Validate requires explicit IDs or a time range, so an empty body cannot
+// replay the whole dead-letter store by accident.
+func (r *ReplayDeadLettersRequest) Validate() error {
+	if len(r.IDs) > 0 {
+		if r.SessionID != "" || r.EventType != "" || r.Since != nil || r.Until != nil {
+			return NewValidationError("ids", "ids cannot be combined with filters")
+		}
+
+		return nil
+	}
+
+	if r.Since == nil && r.Until == nil {
+		return NewValidationError("since", "ids or a time range (since/until) is required")
+	}
+
+	if r.Since != nil && r.Until != nil && r.Since.After(*r.Until) {
+		return NewValidationError("since", "since must not be after until")
+	}
+
+	return nil
+}
+func (r *ReplayDeadLettersRequest) ToFilter() *webhook.DeadLetterFilter {
+	return &webhook.DeadLetterFilter{
+		SessionID: r.SessionID,
+		EventType: r.EventType,
+		Since:     r.Since,
+		Until:     r.Until,
+	}
+}
+func (r *ListDeadLettersRequest) ToFilter() *webhook.DeadLetterFilter {
+	return &webhook.DeadLetterFilter{
+		SessionID: r.SessionID,
+		EventType: r.EventType,
+		Since:     r.Since,
+		Until:     r.Until,
+		Limit:     r.Limit,
+		Offset:    r.Offset,
+	}
+}
+func NewDeadLetterResponse(deadLetter *webhook.DeadLetter, withPayload bool) *DeadLetterResponse {
+	response := &DeadLetterResponse{
+		ID:             deadLetter.ID,
+		SessionID:      deadLetter.SessionID,
+		URL:            deadLetter.Endpoint,
+		EventID:        deadLetter.EventID,
+		EventType:      deadLetter.EventType,
+		Attempts:       deadLetter.Attempts,
+		LastError:      deadLetter.LastError,
+		LastStatusCode: deadLetter.LastStatusCode,
+		CreatedAt:      deadLetter.CreatedAt,
+		FailedAt:       deadLetter.FailedAt,
+	}
+
+	if withPayload {
+		response.Payload = deadLetter.Payload
+	}
+
+	return response
+}
+func NewWebhookResponse(wh *webhook.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:        wh.ID,
+		SessionID: wh.SessionID,
+		URL:       wh.URL,
+		Events:    wh.Events,
+		Enabled:   wh.Enabled,
+		Format:    wh.Format,
+		CreatedAt: wh.CreatedAt,
+		UpdatedAt: wh.UpdatedAt,
+	}
 }
@@ -0,0 +1,197 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/output"
+)
+
+// lastUsedInterval is how stale the last use of a key may get before it is
+// written again, so a busy key does not update its row on every request.
+const lastUsedInterval = time.Minute
+
+type APIKeyUseCase struct {
+	apiKeyRepo apikey.Repository
+	tenantRepo tenant.Repository
+	logger     output.Logger
+}
+
+func NewAPIKeyUseCase(apiKeyRepo apikey.Repository, tenantRepo tenant.Repository, logger output.Logger) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		tenantRepo: tenantRepo,
+		logger:     logger,
+	}
+}
+
+func (uc *APIKeyUseCase) Create(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error) {
+	permissions, err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, err := uc.keyTenant(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, token, err := apikey.NewAPIKey(req.Name, req.SessionIDs, permissions, req.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key.TenantID = tenantID
+
+	if err := uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dto.APIKeySecretResponse{
+		APIKeyResponse: *dto.NewAPIKeyResponse(key),
+		Key:            token,
+	}, nil
+}
+
+func (uc *APIKeyUseCase) List(ctx context.Context) (*dto.APIKeyListResponse, error) {
+	keys, err := uc.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		items[i] = dto.NewAPIKeyResponse(key)
+	}
+
+	return &dto.APIKeyListResponse{
+		Keys:  items,
+		Total: len(items),
+	}, nil
+}
+
+func (uc *APIKeyUseCase) Get(ctx context.Context, keyID string) (*dto.APIKeyResponse, error) {
+	key, err := uc.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewAPIKeyResponse(key), nil
+}
+
+// Rotate gives a key a new secret, keeping its name, scope and expiry. The
+// old secret stops working right away.
+func (uc *APIKeyUseCase) Rotate(ctx context.Context, keyID string) (*dto.APIKeySecretResponse, error) {
+	key, err := uc.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.IsRevoked() {
+		return nil, shared.ErrAPIKeyRevoked
+	}
+
+	token, err := key.Rotate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dto.APIKeySecretResponse{
+		APIKeyResponse: *dto.NewAPIKeyResponse(key),
+		Key:            token,
+	}, nil
+}
+
+// Revoke disables a key for good. The key is kept, so it still shows when
+// it was last used.
+func (uc *APIKeyUseCase) Revoke(ctx context.Context, keyID string) (*dto.APIKeyResponse, error) {
+	key, err := uc.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.IsRevoked() {
+		key.Revoke()
+
+		if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.NewAPIKeyResponse(key), nil
+}
+
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, token string) (*apikey.APIKey, error) {
+	key, err := uc.apiKeyRepo.GetByHash(ctx, apikey.Hash(token))
+	if err != nil {
+		if errors.Is(err, shared.ErrAPIKeyNotFound) {
+			return nil, shared.ErrUnauthorized
+		}
+
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if !key.IsActive(now) {
+		return nil, shared.ErrUnauthorized
+	}
+
+	if key.TenantID != "" {
+		owner, err := uc.tenantRepo.GetByID(ctx, key.TenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !owner.IsActive() {
+			return nil, shared.ErrTenantSuspended
+		}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := uc.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			uc.logger.Warn().Err(err).Str("api_key_id", key.ID).Msg("Failed to record API key use")
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+// keyTenant returns the tenant a new key belongs to. Tenants only create
+// keys of their own; the server operator may create keys for any tenant,
+// or keys of its own when requested is empty.
+func (uc *APIKeyUseCase) keyTenant(ctx context.Context, requested string) (string, error) {
+	if tenantID := shared.TenantFromContext(ctx); tenantID != "" {
+		if requested != "" && requested != tenantID {
+			return "", dto.NewValidationError("tenantId", "keys can only be created for your own tenant")
+		}
+
+		return tenantID, nil
+	}
+
+	if requested == "" {
+		return "", nil
+	}
+
+	if _, err := uc.tenantRepo.GetByID(ctx, requested); err != nil {
+		if errors.Is(err, shared.ErrTenantNotFound) {
+			return "", dto.NewValidationError("tenantId", "tenant not found")
+		}
+
+		return "", err
+	}
+
+	return requested, nil
+}
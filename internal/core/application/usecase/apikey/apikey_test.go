@@ -0,0 +1,143 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
+)
+
+// fakeAPIKeyRepo keeps keys in memory by hash.
+type fakeAPIKeyRepo struct {
+	apikey.Repository
+
+	keys map[string]*apikey.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(_ context.Context, key *apikey.APIKey) error {
+	r.keys[key.Hash] = key
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(_ context.Context, hash string) (*apikey.APIKey, error) {
+	key, ok := r.keys[hash]
+	if !ok {
+		return nil, shared.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(context.Context, string, time.Time) error {
+	return nil
+}
+
+type fakeTenantRepo struct {
+	tenant.Repository
+
+	tenants map[string]*tenant.Tenant
+}
+
+func (r *fakeTenantRepo) GetByID(_ context.Context, id string) (*tenant.Tenant, error) {
+	t, ok := r.tenants[id]
+	if !ok {
+		return nil, shared.ErrTenantNotFound
+	}
+
+	return t, nil
+}
+
+func newTestUseCase() (*APIKeyUseCase, *fakeTenantRepo) {
+	tenants := &fakeTenantRepo{tenants: make(map[string]*tenant.Tenant)}
+
+	for _, id := range []string{"tenant-a", "tenant-b"} {
+		t := tenant.NewTenant(id, 0, 0)
+		t.ID = id
+		tenants.tenants[id] = t
+	}
+
+	keys := &fakeAPIKeyRepo{keys: make(map[string]*apikey.APIKey)}
+
+	return NewAPIKeyUseCase(keys, tenants, logger.New()), tenants
+}
+
+func TestCreateKeyTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		requested  string
+		wantTenant string
+		wantField  string
+	}{
+		{"tenant key for its own tenant", shared.WithTenant(context.Background(), "tenant-a"), "", "tenant-a", ""},
+		{"tenant key naming its own tenant", shared.WithTenant(context.Background(), "tenant-a"), "tenant-a", "tenant-a", ""},
+		{"tenant key for another tenant", shared.WithTenant(context.Background(), "tenant-a"), "tenant-b", "", "tenantId"},
+		{"server operator key for a tenant", context.Background(), "tenant-b", "tenant-b", ""},
+		{"server operator key of its own", context.Background(), "", "", ""},
+		{"server operator key for an unknown tenant", context.Background(), "missing", "", "tenantId"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newTestUseCase()
+
+			response, err := uc.Create(tt.ctx, &dto.CreateAPIKeyRequest{
+				TenantID:    tt.requested,
+				Name:        "key",
+				Permissions: []string{"send"},
+			})
+
+			if tt.wantField != "" {
+				var validationErr *dto.ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+					t.Fatalf("error = %v, want a validation error on %s", err, tt.wantField)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if response.TenantID != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", response.TenantID, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestAuthenticateSuspendedTenant(t *testing.T) {
+	uc, tenants := newTestUseCase()
+	ctx := context.Background()
+
+	created, err := uc.Create(ctx, &dto.CreateAPIKeyRequest{
+		TenantID:    "tenant-a",
+		Name:        "key",
+		Permissions: []string{"send"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	key, err := uc.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if key.TenantID != "tenant-a" {
+		t.Errorf("tenant = %q, want tenant-a", key.TenantID)
+	}
+
+	tenants.tenants["tenant-a"].Suspend()
+
+	if _, err := uc.Authenticate(ctx, created.Key); !errors.Is(err, shared.ErrTenantSuspended) {
+		t.Fatalf("error = %v, want %v", err, shared.ErrTenantSuspended)
+	}
+}
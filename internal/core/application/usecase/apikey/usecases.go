@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/apikey"
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+type UseCases struct {
+	apiKey *APIKeyUseCase
+}
+
+func NewUseCases(apiKeyRepo apikey.Repository, tenantRepo tenant.Repository, logger output.Logger) input.APIKeyUseCases {
+	return &UseCases{
+		apiKey: NewAPIKeyUseCase(apiKeyRepo, tenantRepo, logger),
+	}
+}
+
+func (k *UseCases) Create(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error) {
+	return k.apiKey.Create(ctx, req)
+}
+
+func (k *UseCases) List(ctx context.Context) (*dto.APIKeyListResponse, error) {
+	return k.apiKey.List(ctx)
+}
+
+func (k *UseCases) Get(ctx context.Context, keyID string) (*dto.APIKeyResponse, error) {
+	return k.apiKey.Get(ctx, keyID)
+}
+
+func (k *UseCases) Rotate(ctx context.Context, keyID string) (*dto.APIKeySecretResponse, error) {
+	return k.apiKey.Rotate(ctx, keyID)
+}
+
+func (k *UseCases) Revoke(ctx context.Context, keyID string) (*dto.APIKeyResponse, error) {
+	return k.apiKey.Revoke(ctx, keyID)
+}
+
+func (k *UseCases) Authenticate(ctx context.Context, token string) (*apikey.APIKey, error) {
+	return k.apiKey.Authenticate(ctx, token)
+}
@@ -0,0 +1,283 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+// checkBatchSize is how many recipients are checked against WhatsApp at a
+// time.
+const checkBatchSize = 100
+
+type CampaignUseCase struct {
+	campaignRepo   campaign.Repository
+	sessionService *session.Service
+	contactService input.ContactService
+	logger         output.Logger
+}
+
+func NewCampaignUseCase(
+	campaignRepo campaign.Repository,
+	sessionService *session.Service,
+	contactService input.ContactService,
+	logger output.Logger,
+) *CampaignUseCase {
+	return &CampaignUseCase{
+		campaignRepo:   campaignRepo,
+		sessionService: sessionService,
+		contactService: contactService,
+		logger:         logger,
+	}
+}
+
+// Create stores a campaign and starts sending it. Recipients are checked
+// against WhatsApp first: numbers without an account are stored as failed
+// and the others are sent to the JID they resolved to.
+func (uc *CampaignUseCase) Create(ctx context.Context, sessionID string, req *dto.CreateCampaignRequest) (*dto.CampaignResponse, error) {
+	recipients, err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkRecipients(ctx, sessionID, recipients); err != nil {
+		return nil, err
+	}
+
+	c := campaign.NewCampaign(sessionID, req.Name, req.Text, time.Duration(req.IntervalSeconds)*time.Second)
+
+	if err := uc.campaignRepo.Create(ctx, c, recipients); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return uc.response(ctx, c)
+}
+
+func (uc *CampaignUseCase) List(ctx context.Context, sessionID string, req *dto.ListCampaignsRequest) (*dto.PaginationResponse, error) {
+	if req == nil {
+		req = &dto.ListCampaignsRequest{}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	req.ApplyDefaults()
+
+	if err := uc.checkSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	campaigns, total, err := uc.campaignRepo.List(ctx, &campaign.ListFilter{
+		SessionID: sessionID,
+		Status:    campaign.Status(req.Status),
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	items := make([]*dto.CampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		items = append(items, dto.NewCampaignResponse(c, nil))
+	}
+
+	return &dto.PaginationResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: req.Offset+len(items) < total,
+	}, nil
+}
+
+func (uc *CampaignUseCase) Get(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	c, err := uc.campaignRepo.GetByID(ctx, sessionID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.response(ctx, c)
+}
+
+func (uc *CampaignUseCase) Report(ctx context.Context, sessionID, campaignID string) (*dto.CampaignReportResponse, error) {
+	c, err := uc.campaignRepo.GetByID(ctx, sessionID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := uc.campaignRepo.Report(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign report: %w", err)
+	}
+
+	return dto.NewCampaignReportResponse(c, report), nil
+}
+
+func (uc *CampaignUseCase) ListRecipients(ctx context.Context, sessionID, campaignID string, req *dto.ListCampaignRecipientsRequest) (*dto.PaginationResponse, error) {
+	if req == nil {
+		req = &dto.ListCampaignRecipientsRequest{}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	req.ApplyDefaults()
+
+	c, err := uc.campaignRepo.GetByID(ctx, sessionID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, total, err := uc.campaignRepo.ListRecipients(ctx, &campaign.RecipientFilter{
+		CampaignID: c.ID,
+		Status:     campaign.RecipientStatus(req.Status),
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign recipients: %w", err)
+	}
+
+	items := make([]*dto.CampaignRecipientResponse, 0, len(recipients))
+	for _, recipient := range recipients {
+		items = append(items, dto.NewCampaignRecipientResponse(recipient))
+	}
+
+	return &dto.PaginationResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: req.Offset+len(items) < total,
+	}, nil
+}
+
+// Pause stops sending a campaign until it is resumed. A message that is
+// being sent when the campaign is paused still goes out.
+func (uc *CampaignUseCase) Pause(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return uc.updateStatus(ctx, sessionID, campaignID, (*campaign.Campaign).Pause)
+}
+
+func (uc *CampaignUseCase) Resume(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return uc.updateStatus(ctx, sessionID, campaignID, (*campaign.Campaign).Resume)
+}
+
+// Cancel stops a campaign for good. Recipients that were not sent to stay
+// queued, so the report shows who was left out.
+func (uc *CampaignUseCase) Cancel(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return uc.updateStatus(ctx, sessionID, campaignID, (*campaign.Campaign).Cancel)
+}
+
+// SyncReceipt moves the campaign recipients of delivered or read messages
+// forward.
+func (uc *CampaignUseCase) SyncReceipt(ctx context.Context, sessionID string, messageIDs []string, status string, timestamp time.Time) error {
+	var recipientStatus campaign.RecipientStatus
+
+	switch status {
+	case output.MessageStatusDelivered:
+		recipientStatus = campaign.RecipientDelivered
+	case output.MessageStatusRead:
+		recipientStatus = campaign.RecipientRead
+	default:
+		return nil
+	}
+
+	if _, err := uc.campaignRepo.ApplyReceipt(ctx, sessionID, messageIDs, recipientStatus, timestamp); err != nil {
+		return fmt.Errorf("failed to update campaign recipients: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *CampaignUseCase) updateStatus(ctx context.Context, sessionID, campaignID string, update func(*campaign.Campaign)) (*dto.CampaignResponse, error) {
+	c, err := uc.campaignRepo.GetByID(ctx, sessionID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.IsFinished() {
+		return nil, shared.ErrCampaignFinished
+	}
+
+	update(c)
+
+	if err := uc.campaignRepo.UpdateStatus(ctx, c); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info().
+		Str("session_id", sessionID).
+		Str("campaign_id", campaignID).
+		Str("status", string(c.Status)).
+		Msg("Campaign status changed")
+
+	return uc.response(ctx, c)
+}
+
+// checkRecipients resolves the recipients through CheckUser, failing those
+// that are not on WhatsApp.
+func (uc *CampaignUseCase) checkRecipients(ctx context.Context, sessionID string, recipients []*campaign.Recipient) error {
+	for start := 0; start < len(recipients); start += checkBatchSize {
+		batch := recipients[start:min(start+checkBatchSize, len(recipients))]
+
+		phones := make([]string, len(batch))
+		for i, recipient := range batch {
+			phones[i] = recipient.Phone
+		}
+
+		results, err := uc.contactService.CheckUser(ctx, sessionID, phones)
+		if err != nil {
+			return err
+		}
+
+		for i, result := range results {
+			if i >= len(batch) {
+				break
+			}
+
+			if result.IsInWhatsApp {
+				batch[i].JID = result.JID
+			} else {
+				batch[i].Fail("number is not on WhatsApp")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *CampaignUseCase) response(ctx context.Context, c *campaign.Campaign) (*dto.CampaignResponse, error) {
+	report, err := uc.campaignRepo.Report(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign report: %w", err)
+	}
+
+	return dto.NewCampaignResponse(c, report), nil
+}
+
+func (uc *CampaignUseCase) checkSession(ctx context.Context, sessionID string) error {
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return dto.ErrSessionNotFound
+		}
+
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return nil
+}
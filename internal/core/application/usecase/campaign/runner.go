@@ -0,0 +1,235 @@
+package campaign
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	runnerWorkers      = 4
+	runnerPollInterval = time.Second
+
+	// campaignLease must outlast a send, which may wait in the send queue.
+	campaignLease = 3 * time.Minute
+	sendTimeout   = 2 * time.Minute
+
+	// maxRecipientAttempts bounds the sends tried for one recipient before
+	// it is marked as failed.
+	maxRecipientAttempts = 3
+
+	// disconnectedRetry is how long a campaign waits for its session to
+	// reconnect before checking again.
+	disconnectedRetry = 30 * time.Second
+)
+
+// Runner sends running campaigns one message at a time, waiting the
+// campaign's interval between messages. Campaigns are leased before each
+// send, so several instances sharing the database never send the same
+// campaign at once, and a restart picks up where the campaign stopped.
+type Runner struct {
+	campaignRepo   campaign.Repository
+	messageService input.MessageService
+	whatsappClient output.WhatsAppClient
+	logger         output.Logger
+
+	jobs     chan *campaign.Campaign
+	inFlight atomic.Int32
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewRunner(
+	campaignRepo campaign.Repository,
+	messageService input.MessageService,
+	whatsappClient output.WhatsAppClient,
+	logger output.Logger,
+) *Runner {
+	return &Runner{
+		campaignRepo:   campaignRepo,
+		messageService: messageService,
+		whatsappClient: whatsappClient,
+		logger:         logger,
+		jobs:           make(chan *campaign.Campaign),
+	}
+}
+
+func (r *Runner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for i := 0; i < runnerWorkers; i++ {
+		r.wg.Add(1)
+
+		go r.work(ctx)
+	}
+
+	r.wg.Add(1)
+
+	go r.poll(ctx)
+
+	r.logger.Info().Int("workers", runnerWorkers).Msg("Campaign runner started")
+}
+
+// Stop waits for the messages being sent. Campaigns leased but not started
+// become due again once their lease expires.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+
+	r.cancel()
+	r.wg.Wait()
+
+	r.logger.Info().Msg("Campaign runner stopped")
+}
+
+func (r *Runner) poll(ctx context.Context) {
+	defer r.wg.Done()
+	defer close(r.jobs)
+
+	ticker := time.NewTicker(runnerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.claimDue(ctx)
+	}
+}
+
+func (r *Runner) claimDue(ctx context.Context) {
+	free := runnerWorkers - int(r.inFlight.Load())
+	if free <= 0 {
+		return
+	}
+
+	campaigns, err := r.campaignRepo.ClaimDue(ctx, free, campaignLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error().Err(err).Msg("Failed to claim campaigns")
+		}
+
+		return
+	}
+
+	for _, c := range campaigns {
+		r.inFlight.Add(1)
+
+		select {
+		case r.jobs <- c:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) work(ctx context.Context) {
+	defer r.wg.Done()
+
+	for c := range r.jobs {
+		r.process(ctx, c)
+		r.inFlight.Add(-1)
+	}
+}
+
+// process sends the next message of a claimed campaign, or completes the
+// campaign once no recipient is left.
+func (r *Runner) process(ctx context.Context, c *campaign.Campaign) {
+	// A send that already started is finished even during shutdown, so its
+	// outcome is recorded instead of the message being sent twice.
+	ctx = context.WithoutCancel(ctx)
+
+	if !r.whatsappClient.IsConnected(ctx, c.SessionID) {
+		r.release(ctx, c, disconnectedRetry)
+		return
+	}
+
+	recipient, err := r.campaignRepo.NextRecipient(ctx, c.ID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("campaign_id", c.ID).Msg("Failed to get next campaign recipient")
+		r.release(ctx, c, c.Interval)
+
+		return
+	}
+
+	if recipient == nil {
+		r.complete(ctx, c)
+		return
+	}
+
+	r.send(ctx, c, recipient)
+
+	if err := r.campaignRepo.SaveRecipient(ctx, recipient); err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("campaign_id", c.ID).
+			Str("phone", recipient.Phone).
+			Msg("Failed to save campaign recipient")
+	}
+
+	r.release(ctx, c, c.Interval)
+}
+
+func (r *Runner) send(ctx context.Context, c *campaign.Campaign, recipient *campaign.Recipient) {
+	recipient.Attempts++
+
+	to := recipient.JID
+	if to == "" {
+		to = recipient.Phone
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	result, err := r.messageService.SendTextMessage(sendCtx, c.SessionID, to, campaign.Render(c.Text, recipient), nil)
+	if err == nil {
+		recipient.MarkSent(result.MessageID, result.SentAt)
+		return
+	}
+
+	r.logger.Warn().
+		Err(err).
+		Str("campaign_id", c.ID).
+		Str("phone", recipient.Phone).
+		Int("attempt", recipient.Attempts).
+		Msg("Failed to send campaign message")
+
+	reason := err.Error()
+
+	if recipient.Attempts >= maxRecipientAttempts {
+		recipient.Fail(reason)
+		return
+	}
+
+	// The recipient stays queued and is tried again on the next turn.
+	recipient.Error = &reason
+}
+
+func (r *Runner) release(ctx context.Context, c *campaign.Campaign, wait time.Duration) {
+	c.NextSendAt = time.Now().Add(wait)
+
+	if err := r.campaignRepo.Release(ctx, c); err != nil {
+		r.logger.Error().Err(err).Str("campaign_id", c.ID).Msg("Failed to release campaign")
+	}
+}
+
+func (r *Runner) complete(ctx context.Context, c *campaign.Campaign) {
+	c.Complete()
+
+	if err := r.campaignRepo.Complete(ctx, c); err != nil {
+		r.logger.Error().Err(err).Str("campaign_id", c.ID).Msg("Failed to complete campaign")
+		return
+	}
+
+	r.logger.Info().Str("session_id", c.SessionID).Str("campaign_id", c.ID).Msg("Campaign completed")
+}
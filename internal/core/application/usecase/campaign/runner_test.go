@@ -0,0 +1,404 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+// fakeCampaignRepo keeps one campaign and its recipients in memory, leasing
+// and completing it the way the database does.
+type fakeCampaignRepo struct {
+	campaign.Repository
+
+	stored      campaign.Campaign
+	lockedUntil time.Time
+	recipients  []*campaign.Recipient
+}
+
+func newFakeCampaignRepo(c *campaign.Campaign, phones ...string) *fakeCampaignRepo {
+	repo := &fakeCampaignRepo{stored: *c}
+
+	for i, phone := range phones {
+		recipient := campaign.NewRecipient(phone, map[string]string{"name": "Name " + phone})
+		recipient.ID = int64(i + 1)
+		recipient.CampaignID = c.ID
+
+		repo.recipients = append(repo.recipients, recipient)
+	}
+
+	return repo
+}
+
+func (r *fakeCampaignRepo) UpdateStatus(_ context.Context, c *campaign.Campaign) error {
+	if r.stored.IsFinished() {
+		return shared.ErrCampaignFinished
+	}
+
+	r.stored.Status = c.Status
+	r.stored.NextSendAt = c.NextSendAt
+
+	return nil
+}
+
+func (r *fakeCampaignRepo) ClaimDue(_ context.Context, limit int, lease time.Duration) ([]*campaign.Campaign, error) {
+	now := time.Now()
+
+	if limit < 1 || r.stored.Status != campaign.StatusRunning || r.stored.NextSendAt.After(now) || r.lockedUntil.After(now) {
+		return nil, nil
+	}
+
+	r.lockedUntil = now.Add(lease)
+	claimed := r.stored
+
+	return []*campaign.Campaign{&claimed}, nil
+}
+
+func (r *fakeCampaignRepo) NextRecipient(_ context.Context, _ string) (*campaign.Recipient, error) {
+	for _, recipient := range r.recipients {
+		if recipient.Status == campaign.RecipientQueued {
+			next := *recipient
+			return &next, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *fakeCampaignRepo) SaveRecipient(_ context.Context, recipient *campaign.Recipient) error {
+	for i := range r.recipients {
+		if r.recipients[i].ID == recipient.ID {
+			saved := *recipient
+			r.recipients[i] = &saved
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeCampaignRepo) Release(_ context.Context, c *campaign.Campaign) error {
+	r.stored.NextSendAt = c.NextSendAt
+	r.lockedUntil = time.Time{}
+
+	return nil
+}
+
+func (r *fakeCampaignRepo) Complete(_ context.Context, c *campaign.Campaign) error {
+	if r.stored.Status == campaign.StatusRunning {
+		r.stored.Status = campaign.StatusCompleted
+		r.stored.CompletedAt = c.CompletedAt
+	}
+
+	r.lockedUntil = time.Time{}
+
+	return nil
+}
+
+func (r *fakeCampaignRepo) recipient(phone string) *campaign.Recipient {
+	for _, recipient := range r.recipients {
+		if recipient.Phone == phone {
+			return recipient
+		}
+	}
+
+	return nil
+}
+
+// fakeMessageService fails the sends to the phones in failing and records
+// the text of every other send by recipient.
+type fakeMessageService struct {
+	input.MessageService
+
+	failing map[string]bool
+	sent    map[string][]string
+	sends   int
+}
+
+func (s *fakeMessageService) SendTextMessage(_ context.Context, _, to, text string, _ *output.MessageContextInfo) (*output.MessageResult, error) {
+	s.sends++
+
+	if s.failing[to] {
+		return nil, errors.New("number is unreachable")
+	}
+
+	if s.sent == nil {
+		s.sent = make(map[string][]string)
+	}
+
+	s.sent[to] = append(s.sent[to], text)
+
+	return &output.MessageResult{MessageID: "MSG-" + to, Status: "sent", SentAt: time.Now()}, nil
+}
+
+type fakeWhatsAppClient struct {
+	output.WhatsAppClient
+
+	connected bool
+}
+
+func (c *fakeWhatsAppClient) IsConnected(_ context.Context, _ string) bool {
+	return c.connected
+}
+
+type runnerTest struct {
+	t        *testing.T
+	repo     *fakeCampaignRepo
+	messages *fakeMessageService
+	client   *fakeWhatsAppClient
+	runner   *Runner
+}
+
+func newRunnerTest(t *testing.T, interval time.Duration, phones ...string) *runnerTest {
+	c := campaign.NewCampaign("session-1", "launch", "Hello {{name}}", interval)
+
+	rt := &runnerTest{
+		t:        t,
+		repo:     newFakeCampaignRepo(c, phones...),
+		messages: &fakeMessageService{failing: make(map[string]bool)},
+		client:   &fakeWhatsAppClient{connected: true},
+	}
+	rt.runner = NewRunner(rt.repo, rt.messages, rt.client, logger.New())
+
+	return rt
+}
+
+// turn claims the campaign if it is due and processes it, reporting whether
+// it was claimed.
+func (rt *runnerTest) turn() bool {
+	rt.t.Helper()
+
+	campaigns, err := rt.repo.ClaimDue(context.Background(), runnerWorkers, campaignLease)
+	if err != nil {
+		rt.t.Fatalf("ClaimDue: %v", err)
+	}
+
+	for _, c := range campaigns {
+		rt.runner.process(context.Background(), c)
+	}
+
+	return len(campaigns) > 0
+}
+
+// elapse makes the next send of the campaign due, as if its interval had
+// passed.
+func (rt *runnerTest) elapse() {
+	rt.repo.stored.NextSendAt = time.Now()
+}
+
+func (rt *runnerTest) expectNextSendIn(before time.Time, wait time.Duration) {
+	rt.t.Helper()
+
+	next := rt.repo.stored.NextSendAt
+	if next.Before(before.Add(wait)) || next.After(time.Now().Add(wait)) {
+		rt.t.Errorf("next send in %s, want %s", next.Sub(before), wait)
+	}
+}
+
+func (rt *runnerTest) expectStatus(phone string, status campaign.RecipientStatus, attempts int) {
+	rt.t.Helper()
+
+	recipient := rt.repo.recipient(phone)
+	if recipient.Status != status || recipient.Attempts != attempts {
+		rt.t.Errorf("%s is %s after %d attempts, want %s after %d", phone, recipient.Status, recipient.Attempts, status, attempts)
+	}
+}
+
+func TestRunnerSendsOneRecipientPerInterval(t *testing.T) {
+	rt := newRunnerTest(t, 10*time.Second, "5511111111111", "5522222222222")
+
+	before := time.Now()
+	if !rt.turn() {
+		t.Fatal("a new campaign was not claimed")
+	}
+
+	rt.expectStatus("5511111111111", campaign.RecipientSent, 1)
+	rt.expectStatus("5522222222222", campaign.RecipientQueued, 0)
+	rt.expectNextSendIn(before, 10*time.Second)
+
+	if got := rt.messages.sent["5511111111111"]; len(got) != 1 || got[0] != "Hello Name 5511111111111" {
+		t.Errorf("sent %q, want the rendered text once", got)
+	}
+
+	if recipient := rt.repo.recipient("5511111111111"); recipient.MessageID == nil || *recipient.MessageID != "MSG-5511111111111" {
+		t.Errorf("message ID = %v, want MSG-5511111111111", recipient.MessageID)
+	}
+
+	// Nothing is sent until the interval passes.
+	if rt.turn() {
+		t.Fatal("the campaign was claimed before its interval passed")
+	}
+
+	rt.elapse()
+	rt.turn()
+
+	rt.expectStatus("5522222222222", campaign.RecipientSent, 1)
+
+	if rt.repo.stored.Status != campaign.StatusRunning {
+		t.Errorf("status = %s with the last message just sent, want %s", rt.repo.stored.Status, campaign.StatusRunning)
+	}
+
+	// The turn after the last recipient completes the campaign.
+	rt.elapse()
+	rt.turn()
+
+	if rt.repo.stored.Status != campaign.StatusCompleted || rt.repo.stored.CompletedAt == nil {
+		t.Errorf("status = %s, want %s with its completion time", rt.repo.stored.Status, campaign.StatusCompleted)
+	}
+
+	rt.elapse()
+
+	if rt.turn() {
+		t.Error("a completed campaign was claimed")
+	}
+
+	if rt.messages.sends != 2 {
+		t.Errorf("sent %d messages, want 2", rt.messages.sends)
+	}
+}
+
+func TestRunnerRetriesAndFailsRecipients(t *testing.T) {
+	rt := newRunnerTest(t, time.Second, "5511111111111", "5522222222222")
+	rt.messages.failing["5511111111111"] = true
+
+	for attempt := 1; attempt < maxRecipientAttempts; attempt++ {
+		rt.elapse()
+		rt.turn()
+
+		// A failed recipient stays queued and is tried again on the next
+		// turn, before the recipients after it.
+		rt.expectStatus("5511111111111", campaign.RecipientQueued, attempt)
+		rt.expectStatus("5522222222222", campaign.RecipientQueued, 0)
+
+		if recipient := rt.repo.recipient("5511111111111"); recipient.Error == nil {
+			t.Fatalf("attempt %d did not record its error", attempt)
+		}
+	}
+
+	rt.elapse()
+	rt.turn()
+
+	rt.expectStatus("5511111111111", campaign.RecipientFailed, maxRecipientAttempts)
+
+	if recipient := rt.repo.recipient("5511111111111"); recipient.Error == nil || *recipient.Error != "number is unreachable" {
+		t.Errorf("error = %v, want the send error", recipient.Error)
+	}
+
+	// The campaign goes on with the next recipient.
+	rt.elapse()
+	rt.turn()
+
+	rt.expectStatus("5522222222222", campaign.RecipientSent, 1)
+
+	rt.elapse()
+	rt.turn()
+
+	if rt.repo.stored.Status != campaign.StatusCompleted {
+		t.Errorf("status = %s, want %s", rt.repo.stored.Status, campaign.StatusCompleted)
+	}
+}
+
+func TestRunnerPauseAndResume(t *testing.T) {
+	rt := newRunnerTest(t, time.Second, "5511111111111", "5522222222222")
+	ctx := context.Background()
+
+	rt.turn()
+	rt.expectStatus("5511111111111", campaign.RecipientSent, 1)
+
+	paused := rt.repo.stored
+	paused.Pause()
+
+	if err := rt.repo.UpdateStatus(ctx, &paused); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	rt.elapse()
+
+	if rt.turn() {
+		t.Fatal("a paused campaign was claimed")
+	}
+
+	resumed := rt.repo.stored
+	resumed.Resume()
+
+	if err := rt.repo.UpdateStatus(ctx, &resumed); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	// Resuming makes the campaign due at once and it picks up where it
+	// stopped.
+	if !rt.turn() {
+		t.Fatal("a resumed campaign was not claimed")
+	}
+
+	rt.expectStatus("5522222222222", campaign.RecipientSent, 1)
+
+	if len(rt.messages.sent["5511111111111"]) != 1 {
+		t.Errorf("sent %d messages to the first recipient, want 1", len(rt.messages.sent["5511111111111"]))
+	}
+}
+
+func TestRunnerPausedWhileClaimedIsNotCompleted(t *testing.T) {
+	rt := newRunnerTest(t, time.Second, "5511111111111")
+	ctx := context.Background()
+
+	rt.turn()
+	rt.elapse()
+
+	claimed, err := rt.repo.ClaimDue(ctx, 1, campaignLease)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDue = %d campaigns, %v; want 1", len(claimed), err)
+	}
+
+	// The campaign is paused after it was claimed with no recipient left.
+	paused := rt.repo.stored
+	paused.Pause()
+
+	if err := rt.repo.UpdateStatus(ctx, &paused); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	rt.runner.process(ctx, claimed[0])
+
+	if rt.repo.stored.Status != campaign.StatusPaused {
+		t.Errorf("status = %s, want it left %s", rt.repo.stored.Status, campaign.StatusPaused)
+	}
+}
+
+func TestRunnerWaitsForTheSession(t *testing.T) {
+	rt := newRunnerTest(t, time.Second, "5511111111111")
+	rt.client.connected = false
+
+	before := time.Now()
+	rt.turn()
+
+	if rt.messages.sends != 0 {
+		t.Errorf("sent %d messages while disconnected, want 0", rt.messages.sends)
+	}
+
+	rt.expectStatus("5511111111111", campaign.RecipientQueued, 0)
+	rt.expectNextSendIn(before, disconnectedRetry)
+
+	rt.client.connected = true
+	rt.elapse()
+	rt.turn()
+
+	rt.expectStatus("5511111111111", campaign.RecipientSent, 1)
+}
+
+func TestRunnerSendsToTheResolvedJID(t *testing.T) {
+	rt := newRunnerTest(t, time.Second, "5511111111111")
+	rt.repo.recipients[0].JID = "551111111111@s.whatsapp.net"
+
+	rt.turn()
+
+	if len(rt.messages.sent) != 1 || len(rt.messages.sent["551111111111@s.whatsapp.net"]) != 1 {
+		t.Errorf("sent to %v, want the resolved JID", rt.messages.sent)
+	}
+}
@@ -0,0 +1,63 @@
+package campaign
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/campaign"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+type UseCases struct {
+	campaign *CampaignUseCase
+}
+
+func NewUseCases(
+	campaignRepo campaign.Repository,
+	sessionService *session.Service,
+	contactService input.ContactService,
+	logger output.Logger,
+) input.CampaignUseCases {
+	return &UseCases{
+		campaign: NewCampaignUseCase(campaignRepo, sessionService, contactService, logger),
+	}
+}
+
+func (c *UseCases) Create(ctx context.Context, sessionID string, req *dto.CreateCampaignRequest) (*dto.CampaignResponse, error) {
+	return c.campaign.Create(ctx, sessionID, req)
+}
+
+func (c *UseCases) List(ctx context.Context, sessionID string, req *dto.ListCampaignsRequest) (*dto.PaginationResponse, error) {
+	return c.campaign.List(ctx, sessionID, req)
+}
+
+func (c *UseCases) Get(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return c.campaign.Get(ctx, sessionID, campaignID)
+}
+
+func (c *UseCases) Report(ctx context.Context, sessionID, campaignID string) (*dto.CampaignReportResponse, error) {
+	return c.campaign.Report(ctx, sessionID, campaignID)
+}
+
+func (c *UseCases) ListRecipients(ctx context.Context, sessionID, campaignID string, req *dto.ListCampaignRecipientsRequest) (*dto.PaginationResponse, error) {
+	return c.campaign.ListRecipients(ctx, sessionID, campaignID, req)
+}
+
+func (c *UseCases) Pause(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return c.campaign.Pause(ctx, sessionID, campaignID)
+}
+
+func (c *UseCases) Resume(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return c.campaign.Resume(ctx, sessionID, campaignID)
+}
+
+func (c *UseCases) Cancel(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error) {
+	return c.campaign.Cancel(ctx, sessionID, campaignID)
+}
+
+func (c *UseCases) SyncReceipt(ctx context.Context, sessionID string, messageIDs []string, status string, timestamp time.Time) error {
+	return c.campaign.SyncReceipt(ctx, sessionID, messageIDs, status, timestamp)
+}
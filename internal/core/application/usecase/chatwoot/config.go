@@ -0,0 +1,94 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type ConfigUseCase struct {
+	chatwootRepo   chatwoot.Repository
+	sessionService *session.Service
+	urlPolicy      output.OutboundPolicy
+}
+
+func NewConfigUseCase(chatwootRepo chatwoot.Repository, sessionService *session.Service, urlPolicy output.OutboundPolicy) *ConfigUseCase {
+	return &ConfigUseCase{
+		chatwootRepo:   chatwootRepo,
+		sessionService: sessionService,
+		urlPolicy:      urlPolicy,
+	}
+}
+
+func (uc *ConfigUseCase) Get(ctx context.Context, sessionID string) (*dto.ChatwootConfigResponse, error) {
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	return dto.NewChatwootConfigResponse(cfg), nil
+}
+
+// Set creates the Chatwoot configuration of a session or updates the fields
+// given in the request.
+func (uc *ConfigUseCase) Set(ctx context.Context, sessionID string, req *dto.ChatwootConfigRequest) (*dto.ChatwootConfigResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		if err := uc.urlPolicy.CheckURL(ctx, req.URL); err != nil {
+			return nil, dto.NewValidationError("url", err.Error())
+		}
+	}
+
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, sessionID)
+	if err != nil && !errors.Is(err, shared.ErrChatwootConfigNotFound) {
+		return nil, fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	exists := cfg != nil
+	if !exists {
+		cfg = chatwoot.NewConfig(sessionID, "", "", "")
+	}
+
+	req.Apply(cfg)
+
+	if err := dto.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		err = uc.chatwootRepo.Update(ctx, cfg)
+	} else {
+		err = uc.chatwootRepo.Create(ctx, cfg)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chatwoot config: %w", err)
+	}
+
+	return dto.NewChatwootConfigResponse(cfg), nil
+}
+
+func (uc *ConfigUseCase) Delete(ctx context.Context, sessionID string) error {
+	if err := uc.chatwootRepo.DeleteBySessionID(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete chatwoot config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	importBatchSize = 100
+
+	// importSaveInterval is how many contacts are handled between progress
+	// saves. Messages save their progress once per batch.
+	importSaveInterval = 25
+)
+
+// ImportUseCase pushes a session's WhatsApp contacts and stored message
+// history into Chatwoot. A session runs at most one import at a time; an
+// import that failed or was interrupted by a restart resumes from its
+// persisted phase and cursor.
+type ImportUseCase struct {
+	chatwootRepo   chatwoot.Repository
+	messageRepo    message.Repository
+	contactService input.ContactService
+	syncer         *SyncUseCase
+	logger         output.Logger
+
+	running sync.Map
+}
+
+func NewImportUseCase(
+	chatwootRepo chatwoot.Repository,
+	messageRepo message.Repository,
+	contactService input.ContactService,
+	syncer *SyncUseCase,
+	logger output.Logger,
+) *ImportUseCase {
+	return &ImportUseCase{
+		chatwootRepo:   chatwootRepo,
+		messageRepo:    messageRepo,
+		contactService: contactService,
+		syncer:         syncer,
+		logger:         logger,
+	}
+}
+
+// Start runs the import of a session in the background. A completed import
+// is started over, a failed or interrupted one is resumed, and a running one
+// is left alone.
+func (uc *ImportUseCase) Start(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error) {
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	if !cfg.Enabled {
+		return nil, dto.NewValidationError("enabled", "chatwoot integration is disabled")
+	}
+
+	if !cfg.ImportContacts && !cfg.ImportMessages {
+		return nil, dto.NewValidationError("importContacts", "enable importContacts or importMessages to import")
+	}
+
+	if _, running := uc.running.LoadOrStore(sessionID, struct{}{}); running {
+		return uc.Status(ctx, sessionID)
+	}
+
+	job, err := uc.chatwootRepo.GetImport(ctx, sessionID)
+
+	switch {
+	case errors.Is(err, shared.ErrChatwootImportNotFound):
+		job = chatwoot.NewImportJob(sessionID)
+	case err != nil:
+		uc.running.Delete(sessionID)
+		return nil, fmt.Errorf("failed to get chatwoot import: %w", err)
+	case job.Status == chatwoot.ImportStatusCompleted:
+		job = chatwoot.NewImportJob(sessionID)
+	default:
+		job.Resume()
+	}
+
+	if err := uc.chatwootRepo.SaveImport(ctx, job); err != nil {
+		uc.running.Delete(sessionID)
+		return nil, err
+	}
+
+	uc.logger.Info().
+		Str("session_id", sessionID).
+		Str("phase", string(job.Phase)).
+		Msg("Chatwoot import started")
+
+	go uc.run(cfg, job)
+
+	return dto.NewChatwootImportResponse(job), nil
+}
+
+// StartAfterPairing starts the import of a newly paired session when its
+// Chatwoot configuration asks for one.
+func (uc *ImportUseCase) StartAfterPairing(ctx context.Context, sessionID string) error {
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, shared.ErrChatwootConfigNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	if !cfg.Enabled || (!cfg.ImportContacts && !cfg.ImportMessages) {
+		return nil
+	}
+
+	_, err = uc.Start(ctx, sessionID)
+
+	return err
+}
+
+func (uc *ImportUseCase) Status(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error) {
+	job, err := uc.chatwootRepo.GetImport(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot import: %w", err)
+	}
+
+	return dto.NewChatwootImportResponse(job), nil
+}
+
+func (uc *ImportUseCase) run(cfg *chatwoot.Config, job *chatwoot.ImportJob) {
+	defer uc.running.Delete(job.SessionID)
+
+	ctx := context.Background()
+
+	if err := uc.runPhases(ctx, cfg, job); err != nil {
+		job.Fail(err)
+
+		uc.logger.Error().Err(err).Str("session_id", job.SessionID).Msg("Chatwoot import failed")
+	} else {
+		job.Complete()
+
+		uc.logger.Info().
+			Str("session_id", job.SessionID).
+			Int("contacts", job.ContactsImported).
+			Int("messages", job.MessagesImported).
+			Int("failed", job.MessagesFailed).
+			Msg("Chatwoot import completed")
+	}
+
+	uc.save(ctx, job)
+}
+
+func (uc *ImportUseCase) runPhases(ctx context.Context, cfg *chatwoot.Config, job *chatwoot.ImportJob) error {
+	inboxID, err := uc.syncer.resolveInbox(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if job.Phase == chatwoot.ImportPhaseContacts {
+		if cfg.ImportContacts {
+			if err := uc.importContacts(ctx, cfg, inboxID, job); err != nil {
+				return err
+			}
+		}
+
+		job.Phase = chatwoot.ImportPhaseMessages
+		uc.save(ctx, job)
+	}
+
+	if job.Phase == chatwoot.ImportPhaseMessages && cfg.ImportMessages {
+		return uc.importMessages(ctx, cfg, job)
+	}
+
+	return nil
+}
+
+// importContacts creates a Chatwoot contact for every WhatsApp user contact
+// that is not ignored. Existing contacts are matched rather than duplicated,
+// so a resumed import simply walks the list again.
+func (uc *ImportUseCase) importContacts(ctx context.Context, cfg *chatwoot.Config, inboxID int, job *chatwoot.ImportJob) error {
+	contacts, err := uc.contactService.GetContacts(ctx, cfg.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get whatsapp contacts: %w", err)
+	}
+
+	importable := make([]input.Contact, 0, len(contacts))
+
+	for _, contact := range contacts {
+		if strings.HasSuffix(contact.JID, userServer) && !cfg.IsIgnored(contact.JID) {
+			importable = append(importable, contact)
+		}
+	}
+
+	sort.Slice(importable, func(i, j int) bool {
+		return importable[i].JID < importable[j].JID
+	})
+
+	job.ContactsTotal = len(importable)
+	job.ContactsImported = 0
+	uc.save(ctx, job)
+
+	for i, contact := range importable {
+		if err := uc.syncer.importContact(ctx, cfg, inboxID, contact.JID, contactName(contact)); err != nil {
+			uc.logger.Warn().Err(err).Str("session_id", cfg.SessionID).Str("jid", contact.JID).Msg("Failed to import contact")
+		} else {
+			job.ContactsImported++
+		}
+
+		if (i+1)%importSaveInterval == 0 {
+			uc.save(ctx, job)
+		}
+	}
+
+	return nil
+}
+
+// importMessages syncs the stored messages of the last importDays days that
+// were not synced yet, oldest first. Messages stored after the import started
+// are left to the live sync.
+func (uc *ImportUseCase) importMessages(ctx context.Context, cfg *chatwoot.Config, job *chatwoot.ImportJob) error {
+	query := &message.UnsyncedQuery{
+		SessionID: cfg.SessionID,
+		Until:     job.StartedAt,
+		Limit:     importBatchSize,
+	}
+
+	if cfg.ImportDays > 0 {
+		query.Since = job.StartedAt.AddDate(0, 0, -cfg.ImportDays)
+	}
+
+	if job.CursorTimestamp != nil {
+		query.After = &message.Cursor{
+			Timestamp: *job.CursorTimestamp,
+			ID:        job.CursorID,
+		}
+	}
+
+	remaining, err := uc.messageRepo.CountUnsynced(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	job.MessagesTotal = job.MessagesImported + job.MessagesFailed + remaining
+	uc.save(ctx, job)
+
+	for {
+		messages, err := uc.messageRepo.ListUnsynced(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		for _, msg := range messages {
+			uc.importMessage(ctx, cfg, job, msg)
+
+			job.Advance(msg.Timestamp, msg.ID)
+			query.After = message.CursorOf(msg)
+		}
+
+		uc.save(ctx, job)
+	}
+}
+
+func (uc *ImportUseCase) importMessage(ctx context.Context, cfg *chatwoot.Config, job *chatwoot.ImportJob, msg *message.Message) {
+	if !isSyncable(msg) || cfg.IsIgnored(msg.Chat) {
+		job.MessagesTotal--
+		return
+	}
+
+	if err := uc.syncer.sync(ctx, cfg, msg, ""); err != nil {
+		job.MessagesFailed++
+
+		uc.logger.Warn().Err(err).Str("session_id", cfg.SessionID).Str("message_id", msg.MessageID).Msg("Failed to import message")
+
+		return
+	}
+
+	job.MessagesImported++
+}
+
+// save persists the progress of job. A failed save only costs progress
+// reporting, so the import carries on.
+func (uc *ImportUseCase) save(ctx context.Context, job *chatwoot.ImportJob) {
+	job.UpdatedAt = time.Now()
+
+	if err := uc.chatwootRepo.SaveImport(ctx, job); err != nil {
+		uc.logger.Warn().Err(err).Str("session_id", job.SessionID).Msg("Failed to save chatwoot import progress")
+	}
+}
+
+func contactName(contact input.Contact) string {
+	switch {
+	case contact.Name != "":
+		return contact.Name
+	case contact.BusinessName != "":
+		return contact.BusinessName
+	case contact.VerifiedName != "":
+		return contact.VerifiedName
+	default:
+		return contact.Notify
+	}
+}
@@ -0,0 +1,185 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+const eventMessageCreated = "message_created"
+
+// ReplyUseCase forwards replies written by Chatwoot agents to the WhatsApp
+// chat of their conversation.
+type ReplyUseCase struct {
+	chatwootRepo   chatwoot.Repository
+	messageRepo    message.Repository
+	messageService input.MessageService
+	client         output.ChatwootClient
+	logger         output.Logger
+}
+
+func NewReplyUseCase(
+	chatwootRepo chatwoot.Repository,
+	messageRepo message.Repository,
+	messageService input.MessageService,
+	client output.ChatwootClient,
+	logger output.Logger,
+) *ReplyUseCase {
+	return &ReplyUseCase{
+		chatwootRepo:   chatwootRepo,
+		messageRepo:    messageRepo,
+		messageService: messageService,
+		client:         client,
+		logger:         logger,
+	}
+}
+
+// HandleWebhook sends the agent message of a Chatwoot message_created event
+// to WhatsApp. Other events, private notes and messages zpwoot posted itself
+// are acknowledged and ignored. Events without the webhook secret of the
+// config are refused with shared.ErrChatwootWebhookSecret.
+func (uc *ReplyUseCase) HandleWebhook(
+	ctx context.Context,
+	sessionID, secret string,
+	req *dto.ChatwootWebhookRequest,
+) (*dto.ChatwootWebhookResponse, error) {
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	if !cfg.CheckWebhookSecret(secret) {
+		return nil, shared.ErrChatwootWebhookSecret
+	}
+
+	if reason := ignoreReason(cfg, req); reason != "" {
+		return dto.NewChatwootWebhookIgnored(reason), nil
+	}
+
+	chatJID, err := uc.resolveChat(ctx, sessionID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if chatJID == "" {
+		return dto.NewChatwootWebhookIgnored("conversation is not linked to a WhatsApp chat"), nil
+	}
+
+	if cfg.IsIgnored(chatJID) {
+		return dto.NewChatwootWebhookIgnored("chat is ignored"), nil
+	}
+
+	messageIDs, err := uc.send(ctx, cfg, chatJID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, messageID := range messageIDs {
+		if err := uc.messageRepo.MarkSynced(ctx, sessionID, messageID, req.ID, req.Conversation.ID); err != nil {
+			uc.logger.Warn().Err(err).Str("session_id", sessionID).Str("message_id", messageID).Msg("Failed to record chatwoot reply")
+		}
+	}
+
+	uc.logger.Debug().
+		Str("session_id", sessionID).
+		Str("chat", chatJID).
+		Int("cw_conversation_id", req.Conversation.ID).
+		Int("cw_message_id", req.ID).
+		Msg("Chatwoot reply sent to WhatsApp")
+
+	return &dto.ChatwootWebhookResponse{
+		Status:     dto.ChatwootWebhookStatusSent,
+		MessageIDs: messageIDs,
+	}, nil
+}
+
+// send delivers the reply text and attachments. The signed text becomes the
+// caption of the first attachment unless that is audio, which has none.
+func (uc *ReplyUseCase) send(ctx context.Context, cfg *chatwoot.Config, chatJID string, req *dto.ChatwootWebhookRequest) ([]string, error) {
+	text := cfg.Sign(req.Sender.Name, req.Content)
+
+	var messageIDs []string
+
+	if len(req.Attachments) == 0 || (text != "" && req.Attachments[0].FileType == "audio") {
+		result, err := uc.messageService.SendTextMessage(ctx, cfg.SessionID, chatJID, text, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send reply: %w", err)
+		}
+
+		messageIDs = append(messageIDs, result.MessageID)
+		text = ""
+	}
+
+	for _, attachment := range req.Attachments {
+		file, err := uc.client.DownloadAttachment(ctx, cfg, attachment.DataURL)
+		if err != nil {
+			return messageIDs, err
+		}
+
+		result, err := uc.messageService.SendMediaMessage(ctx, cfg.SessionID, chatJID, &output.MediaData{
+			MimeType: file.MimeType,
+			Data:     file.Data,
+			FileName: file.FileName,
+			Caption:  text,
+		}, nil)
+		if err != nil {
+			return messageIDs, fmt.Errorf("failed to send reply attachment: %w", err)
+		}
+
+		messageIDs = append(messageIDs, result.MessageID)
+		text = ""
+	}
+
+	return messageIDs, nil
+}
+
+// resolveChat finds the WhatsApp chat of a conversation from the messages
+// synced to it. The contact in the event is not trusted: a conversation no
+// message was synced to is not linked to any chat.
+func (uc *ReplyUseCase) resolveChat(ctx context.Context, sessionID string, req *dto.ChatwootWebhookRequest) (string, error) {
+	msg, err := uc.messageRepo.GetLatestInConversation(ctx, sessionID, req.Conversation.ID)
+	if err != nil {
+		if errors.Is(err, shared.ErrMessageNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to resolve conversation chat: %w", err)
+	}
+
+	return msg.Chat, nil
+}
+
+// ignoreReason explains why an event is not forwarded, or returns "" when it
+// is. Messages carrying a WAID source ID were posted by zpwoot and would
+// otherwise echo back to WhatsApp.
+func ignoreReason(cfg *chatwoot.Config, req *dto.ChatwootWebhookRequest) string {
+	switch {
+	case !cfg.Enabled:
+		return "chatwoot integration is disabled"
+	case req.Event != eventMessageCreated:
+		return "event is not " + eventMessageCreated
+	case strconv.Itoa(req.Account.ID) != cfg.AccountID:
+		return "account does not match configuration"
+	case cfg.InboxID != "" && strconv.Itoa(req.Inbox.ID) != cfg.InboxID:
+		return "inbox does not match configuration"
+	case req.MessageType != chatwoot.MessageTypeOutgoing:
+		return "message is not outgoing"
+	case req.Private:
+		return "message is a private note"
+	case strings.HasPrefix(req.SourceID, sourceIDPrefix):
+		return "message originated from WhatsApp"
+	case req.Content == "" && len(req.Attachments) == 0:
+		return "message is empty"
+	}
+
+	return ""
+}
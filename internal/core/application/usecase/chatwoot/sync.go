@@ -0,0 +1,364 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	userServer      = "@s.whatsapp.net"
+	groupServer     = "@g.us"
+	statusBroadcast = "status@broadcast"
+
+	// sourceIDPrefix marks Chatwoot messages that mirror a WhatsApp message.
+	sourceIDPrefix = "WAID:"
+)
+
+// SyncUseCase mirrors WhatsApp messages into the Chatwoot inbox of their
+// session, creating contacts and conversations as needed.
+type SyncUseCase struct {
+	chatwootRepo chatwoot.Repository
+	messageRepo  message.Repository
+	client       output.ChatwootClient
+	logger       output.Logger
+
+	// chatLocks serializes syncs per chat so concurrent messages do not
+	// create duplicate contacts or conversations.
+	chatLocks sync.Map
+}
+
+func NewSyncUseCase(
+	chatwootRepo chatwoot.Repository,
+	messageRepo message.Repository,
+	client output.ChatwootClient,
+	logger output.Logger,
+) *SyncUseCase {
+	return &SyncUseCase{
+		chatwootRepo: chatwootRepo,
+		messageRepo:  messageRepo,
+		client:       client,
+		logger:       logger,
+	}
+}
+
+// SyncMessage posts a stored message to Chatwoot and records the resulting
+// Chatwoot IDs on it. Sessions without an enabled configuration and ignored
+// chats are skipped.
+func (uc *SyncUseCase) SyncMessage(ctx context.Context, msg *message.Message, pushName string) error {
+	cfg, err := uc.chatwootRepo.GetBySessionID(ctx, msg.SessionID)
+	if err != nil {
+		if errors.Is(err, shared.ErrChatwootConfigNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get chatwoot config: %w", err)
+	}
+
+	if !cfg.Enabled || !isSyncable(msg) || cfg.IsIgnored(msg.Chat) {
+		return nil
+	}
+
+	return uc.sync(ctx, cfg, msg, pushName)
+}
+
+// sync posts msg under its chat lock and records the outcome on the stored
+// message.
+func (uc *SyncUseCase) sync(ctx context.Context, cfg *chatwoot.Config, msg *message.Message, pushName string) error {
+	unlock := uc.lockChat(msg.SessionID, msg.Chat)
+	defer unlock()
+
+	cwMessageID, conversationID, err := uc.postMessage(ctx, cfg, msg, pushName)
+	if err != nil {
+		if markErr := uc.messageRepo.MarkSyncFailed(ctx, msg.SessionID, msg.MessageID); markErr != nil {
+			uc.logger.Warn().Err(markErr).Str("session_id", msg.SessionID).Str("message_id", msg.MessageID).Msg("Failed to mark message sync as failed")
+		}
+
+		return err
+	}
+
+	if err := uc.messageRepo.MarkSynced(ctx, msg.SessionID, msg.MessageID, cwMessageID, conversationID); err != nil {
+		return fmt.Errorf("failed to record chatwoot message: %w", err)
+	}
+
+	uc.logger.Debug().
+		Str("session_id", msg.SessionID).
+		Str("message_id", msg.MessageID).
+		Int("cw_conversation_id", conversationID).
+		Int("cw_message_id", cwMessageID).
+		Msg("Message synced to Chatwoot")
+
+	return nil
+}
+
+// importContact creates the Chatwoot contact of a chat under its chat lock.
+func (uc *SyncUseCase) importContact(ctx context.Context, cfg *chatwoot.Config, inboxID int, chatJID, name string) error {
+	unlock := uc.lockChat(cfg.SessionID, chatJID)
+	defer unlock()
+
+	_, err := uc.findOrCreateContact(ctx, cfg, inboxID, chatJID, name)
+
+	return err
+}
+
+func (uc *SyncUseCase) postMessage(ctx context.Context, cfg *chatwoot.Config, msg *message.Message, pushName string) (int, int, error) {
+	inboxID, err := uc.resolveInbox(ctx, cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	contactName := pushName
+	if msg.FromMe || strings.HasSuffix(msg.Chat, groupServer) {
+		contactName = ""
+	}
+
+	contact, err := uc.findOrCreateContact(ctx, cfg, inboxID, msg.Chat, contactName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	conversation, err := uc.findOrCreateConversation(ctx, cfg, inboxID, contact.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	messageType := chatwoot.MessageTypeIncoming
+	if msg.FromMe {
+		messageType = chatwoot.MessageTypeOutgoing
+	}
+
+	cwMessageID, err := uc.client.CreateMessage(ctx, cfg, conversation.ID, &chatwoot.Message{
+		Content:     messageContent(msg, pushName),
+		MessageType: messageType,
+		SourceID:    sourceIDPrefix + msg.MessageID,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return cwMessageID, conversation.ID, nil
+}
+
+// resolveInbox returns the configured inbox. With autoCreate, an inbox named
+// inboxName is looked up or created once and stored in the configuration.
+func (uc *SyncUseCase) resolveInbox(ctx context.Context, cfg *chatwoot.Config) (int, error) {
+	if cfg.InboxID != "" {
+		inboxID, err := strconv.Atoi(cfg.InboxID)
+		if err != nil {
+			return 0, fmt.Errorf("invalid chatwoot inbox ID %q", cfg.InboxID)
+		}
+
+		return inboxID, nil
+	}
+
+	if !cfg.AutoCreate || cfg.InboxName == "" {
+		return 0, errors.New("chatwoot inbox is not configured")
+	}
+
+	inboxes, err := uc.client.ListInboxes(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	var inbox *chatwoot.Inbox
+
+	for _, candidate := range inboxes {
+		if candidate.Name == cfg.InboxName {
+			inbox = candidate
+			break
+		}
+	}
+
+	if inbox == nil {
+		if inbox, err = uc.client.CreateInbox(ctx, cfg, cfg.InboxName); err != nil {
+			return 0, err
+		}
+
+		uc.logger.Info().Str("session_id", cfg.SessionID).Int("inbox_id", inbox.ID).Msg("Chatwoot inbox created")
+	}
+
+	cfg.InboxID = strconv.Itoa(inbox.ID)
+	if err := uc.chatwootRepo.Update(ctx, cfg); err != nil {
+		return 0, fmt.Errorf("failed to store chatwoot inbox: %w", err)
+	}
+
+	return inbox.ID, nil
+}
+
+// findOrCreateContact finds the contact of a chat by its JID identifier or
+// phone number and creates it when missing.
+func (uc *SyncUseCase) findOrCreateContact(ctx context.Context, cfg *chatwoot.Config, inboxID int, chatJID, name string) (*chatwoot.Contact, error) {
+	phone := phoneNumber(chatJID)
+
+	candidates := phoneCandidates(phone, cfg.MergeBrazil)
+
+	query := chatJID
+	if phone != "" {
+		query = strings.TrimPrefix(phone, "+")
+	}
+
+	contacts, err := uc.client.SearchContacts(ctx, cfg, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contact := range contacts {
+		if contact.Identifier == chatJID {
+			return contact, nil
+		}
+
+		for _, candidate := range candidates {
+			if contact.PhoneNumber == candidate {
+				return contact, nil
+			}
+		}
+	}
+
+	if name == "" {
+		name = strings.TrimPrefix(phone, "+")
+		if name == "" {
+			name = jidUser(chatJID)
+		}
+	}
+
+	return uc.client.CreateContact(ctx, cfg, inboxID, &chatwoot.Contact{
+		Name:        name,
+		PhoneNumber: phone,
+		Identifier:  chatJID,
+	})
+}
+
+// findOrCreateConversation returns the contact's active conversation in the
+// inbox. A resolved conversation is reopened when reopenConv is set;
+// otherwise a new one is started.
+func (uc *SyncUseCase) findOrCreateConversation(ctx context.Context, cfg *chatwoot.Config, inboxID, contactID int) (*chatwoot.Conversation, error) {
+	conversations, err := uc.client.ListContactConversations(ctx, cfg, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved *chatwoot.Conversation
+
+	for _, conversation := range conversations {
+		if conversation.InboxID != inboxID {
+			continue
+		}
+
+		if conversation.Status != chatwoot.ConversationStatusResolved {
+			return conversation, nil
+		}
+
+		if resolved == nil {
+			resolved = conversation
+		}
+	}
+
+	if resolved != nil && cfg.ReopenConv {
+		if err := uc.client.SetConversationStatus(ctx, cfg, resolved.ID, cfg.ConversationStatus()); err != nil {
+			return nil, err
+		}
+
+		resolved.Status = cfg.ConversationStatus()
+
+		return resolved, nil
+	}
+
+	return uc.client.CreateConversation(ctx, cfg, inboxID, contactID, cfg.ConversationStatus())
+}
+
+func (uc *SyncUseCase) lockChat(sessionID, chatJID string) func() {
+	lock, _ := uc.chatLocks.LoadOrStore(sessionID+"|"+chatJID, &sync.Mutex{})
+
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+
+	return mutex.Unlock
+}
+
+// messageContent renders a message for Chatwoot. Media is announced by type
+// with its caption, and group messages name their sender.
+func messageContent(msg *message.Message, pushName string) string {
+	content := msg.Content
+
+	switch msg.Type {
+	case "", "text", "buttons", "list", "template":
+	default:
+		label := "[" + msg.Type + "]"
+		if content == "" {
+			content = label
+		} else {
+			content = label + "\n" + content
+		}
+	}
+
+	if strings.HasSuffix(msg.Chat, groupServer) && !msg.FromMe {
+		sender := pushName
+		if sender == "" {
+			sender = jidUser(msg.Sender)
+		}
+
+		content = "**" + sender + "**:\n" + content
+	}
+
+	return content
+}
+
+// isSyncable leaves out status updates, reactions and protocol messages such
+// as revokes and edits, which have no conversation content of their own.
+func isSyncable(msg *message.Message) bool {
+	if msg.Chat == statusBroadcast {
+		return false
+	}
+
+	switch msg.Type {
+	case "reaction", "unknown":
+		return false
+	default:
+		return true
+	}
+}
+
+func jidUser(jid string) string {
+	user, _, _ := strings.Cut(jid, "@")
+	user, _, _ = strings.Cut(user, ":")
+
+	return user
+}
+
+// phoneNumber returns the E.164 number of a user JID, or "" for groups and
+// other non-phone JIDs.
+func phoneNumber(jid string) string {
+	if !strings.HasSuffix(jid, userServer) {
+		return ""
+	}
+
+	return "+" + jidUser(jid)
+}
+
+// phoneCandidates lists the numbers a contact may be stored under. With
+// mergeBrazil, both forms of a Brazilian mobile number refer to the same
+// contact.
+func phoneCandidates(phone string, mergeBrazil bool) []string {
+	if phone == "" {
+		return nil
+	}
+
+	if !mergeBrazil {
+		return []string{phone}
+	}
+
+	candidates := shared.PhoneVariants(strings.TrimPrefix(phone, "+"))
+	for i := range candidates {
+		candidates[i] = "+" + candidates[i]
+	}
+
+	return candidates
+}
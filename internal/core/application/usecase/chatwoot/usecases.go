@@ -0,0 +1,71 @@
+package chatwoot
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/chatwoot"
+	"zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+type UseCases struct {
+	config *ConfigUseCase
+	sync   *SyncUseCase
+	reply  *ReplyUseCase
+	imp    *ImportUseCase
+}
+
+func NewUseCases(
+	chatwootRepo chatwoot.Repository,
+	messageRepo message.Repository,
+	sessionService *session.Service,
+	messageService input.MessageService,
+	contactService input.ContactService,
+	client output.ChatwootClient,
+	urlPolicy output.OutboundPolicy,
+	logger output.Logger,
+) input.ChatwootUseCases {
+	syncUseCase := NewSyncUseCase(chatwootRepo, messageRepo, client, logger)
+
+	return &UseCases{
+		config: NewConfigUseCase(chatwootRepo, sessionService, urlPolicy),
+		sync:   syncUseCase,
+		reply:  NewReplyUseCase(chatwootRepo, messageRepo, messageService, client, logger),
+		imp:    NewImportUseCase(chatwootRepo, messageRepo, contactService, syncUseCase, logger),
+	}
+}
+
+func (c *UseCases) GetConfig(ctx context.Context, sessionID string) (*dto.ChatwootConfigResponse, error) {
+	return c.config.Get(ctx, sessionID)
+}
+
+func (c *UseCases) SetConfig(ctx context.Context, sessionID string, req *dto.ChatwootConfigRequest) (*dto.ChatwootConfigResponse, error) {
+	return c.config.Set(ctx, sessionID, req)
+}
+
+func (c *UseCases) DeleteConfig(ctx context.Context, sessionID string) error {
+	return c.config.Delete(ctx, sessionID)
+}
+
+func (c *UseCases) SyncMessage(ctx context.Context, msg *message.Message, pushName string) error {
+	return c.sync.SyncMessage(ctx, msg, pushName)
+}
+
+func (c *UseCases) HandleWebhook(ctx context.Context, sessionID, secret string, req *dto.ChatwootWebhookRequest) (*dto.ChatwootWebhookResponse, error) {
+	return c.reply.HandleWebhook(ctx, sessionID, secret, req)
+}
+
+func (c *UseCases) StartImport(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error) {
+	return c.imp.Start(ctx, sessionID)
+}
+
+func (c *UseCases) GetImport(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error) {
+	return c.imp.Status(ctx, sessionID)
+}
+
+func (c *UseCases) StartImportAfterPairing(ctx context.Context, sessionID string) error {
+	return c.imp.StartAfterPairing(ctx, sessionID)
+}
@@ -0,0 +1,81 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/media"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type MediaUseCase struct {
+	mediaRepo      media.Repository
+	blobs          output.BlobStore
+	fetcher        output.MediaFetcher
+	sessionService *session.Service
+}
+
+func NewMediaUseCase(
+	mediaRepo media.Repository,
+	blobs output.BlobStore,
+	fetcher output.MediaFetcher,
+	sessionService *session.Service,
+) *MediaUseCase {
+	return &MediaUseCase{
+		mediaRepo:      mediaRepo,
+		blobs:          blobs,
+		fetcher:        fetcher,
+		sessionService: sessionService,
+	}
+}
+
+// Get returns the file of a media message. Files that were not downloaded
+// yet, or were removed by retention, are downloaded from WhatsApp first.
+func (uc *MediaUseCase) Get(ctx context.Context, sessionID, messageID string) (*dto.MediaFile, error) {
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	m, err := uc.mediaRepo.GetByMessageID(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.IsStored() {
+		if err := uc.fetcher.FetchMedia(ctx, sessionID, messageID); err != nil {
+			return nil, err
+		}
+
+		if m, err = uc.mediaRepo.GetByMessageID(ctx, sessionID, messageID); err != nil {
+			return nil, err
+		}
+
+		if !m.IsStored() {
+			return nil, shared.ErrMediaFailed
+		}
+	}
+
+	content, err := uc.blobs.Get(ctx, m.StorageKey)
+	if err != nil {
+		if errors.Is(err, output.ErrBlobNotFound) {
+			return nil, shared.ErrMediaNotFound
+		}
+
+		return nil, fmt.Errorf("failed to open media file: %w", err)
+	}
+
+	return &dto.MediaFile{
+		Content:  content,
+		MimeType: m.MimeType,
+		FileName: m.FileName,
+		Size:     m.Size,
+	}, nil
+}
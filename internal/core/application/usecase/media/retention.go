@@ -0,0 +1,122 @@
+package media
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"zpwoot/internal/core/domain/media"
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	retentionInterval  = time.Hour
+	retentionBatchSize = 100
+)
+
+// Retention removes stored files older than the retention period. The media
+// rows stay, so an expired file is downloaded again if it is requested while
+// WhatsApp still has it.
+type Retention struct {
+	mediaRepo media.Repository
+	blobs     output.BlobStore
+	retention time.Duration
+	logger    output.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRetention(mediaRepo media.Repository, blobs output.BlobStore, retention time.Duration, logger output.Logger) *Retention {
+	return &Retention{
+		mediaRepo: mediaRepo,
+		blobs:     blobs,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Start does nothing when the retention period is zero, keeping files
+// forever.
+func (r *Retention) Start() {
+	if r.retention <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+
+	go r.run(ctx)
+
+	r.logger.Info().Dur("retention", r.retention).Msg("Media retention started")
+}
+
+func (r *Retention) Stop() {
+	if r.cancel == nil {
+		return
+	}
+
+	r.cancel()
+	r.wg.Wait()
+
+	r.logger.Info().Msg("Media retention stopped")
+}
+
+func (r *Retention) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		r.expire(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Retention) expire(ctx context.Context) {
+	before := time.Now().Add(-r.retention)
+	expired := 0
+
+	for ctx.Err() == nil {
+		batch, err := r.mediaRepo.ListStoredBefore(ctx, before, retentionBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error().Err(err).Msg("Failed to list expired media")
+			}
+
+			return
+		}
+
+		for _, m := range batch {
+			if err := r.blobs.Delete(ctx, m.StorageKey); err != nil {
+				r.logger.Error().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to delete media file")
+				return
+			}
+
+			m.Expire()
+
+			if err := r.mediaRepo.Save(ctx, m); err != nil {
+				r.logger.Error().Err(err).Str("session_id", m.SessionID).Str("message_id", m.MessageID).Msg("Failed to expire media")
+				return
+			}
+
+			expired++
+		}
+
+		if len(batch) < retentionBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		r.logger.Info().Int("count", expired).Msg("Expired media removed")
+	}
+}
@@ -0,0 +1,25 @@
+package media
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/media"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+type UseCases struct {
+	media *MediaUseCase
+}
+
+func NewUseCases(mediaRepo media.Repository, blobs output.BlobStore, fetcher output.MediaFetcher, sessionService *session.Service) input.MediaUseCases {
+	return &UseCases{
+		media: NewMediaUseCase(mediaRepo, blobs, fetcher, sessionService),
+	}
+}
+
+func (m *UseCases) Get(ctx context.Context, sessionID, messageID string) (*dto.MediaFile, error) {
+	return m.media.Get(ctx, sessionID, messageID)
+}
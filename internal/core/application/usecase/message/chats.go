@@ -0,0 +1,115 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type ChatsUseCase struct {
+	sessionService *session.Service
+	whatsappClient output.WhatsAppClient
+	logger         output.Logger
+}
+
+func NewChatsUseCase(
+	sessionService *session.Service,
+	whatsappClient output.WhatsAppClient,
+	logger output.Logger,
+) *ChatsUseCase {
+	return &ChatsUseCase{
+		sessionService: sessionService,
+		whatsappClient: whatsappClient,
+		logger:         logger,
+	}
+}
+
+func (uc *ChatsUseCase) Execute(ctx context.Context, sessionID string, req *dto.ListChatsRequest) (*dto.PaginationResponse, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+
+	if req == nil {
+		req = &dto.ListChatsRequest{}
+	}
+
+	req.ApplyDefaults()
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	chats, err := uc.whatsappClient.GetChats(ctx, sessionID)
+	if err != nil {
+		uc.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to list chats")
+		return nil, err
+	}
+
+	sort.SliceStable(chats, func(i, j int) bool {
+		if req.Order == dto.SortOrderAsc {
+			return chatLess(req.Sort, chats[i], chats[j])
+		}
+
+		return chatLess(req.Sort, chats[j], chats[i])
+	})
+
+	total := len(chats)
+	start := min(req.Offset, total)
+	end := min(start+req.Limit, total)
+
+	items := make([]*dto.ChatResponse, 0, end-start)
+	for _, chat := range chats[start:end] {
+		items = append(items, dto.NewChatResponse(chat))
+	}
+
+	return &dto.PaginationResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: end < total,
+	}, nil
+}
+
+func chatLess(sortBy string, a, b *output.ChatInfo) bool {
+	switch sortBy {
+	case dto.ChatSortUnread:
+		return a.UnreadCount < b.UnreadCount
+	case dto.ChatSortName:
+		return strings.ToLower(chatName(a)) < strings.ToLower(chatName(b))
+	default:
+		return lastMessageTime(a).Before(lastMessageTime(b))
+	}
+}
+
+func chatName(chat *output.ChatInfo) string {
+	if chat.Name != "" {
+		return chat.Name
+	}
+
+	return chat.JID
+}
+
+func lastMessageTime(chat *output.ChatInfo) time.Time {
+	if chat.LastMessage == nil {
+		return time.Time{}
+	}
+
+	return chat.LastMessage.Timestamp
+}
@@ -0,0 +1,88 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"zpwoot/internal/core/application/dto"
+	domainMessage "zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type HistoryUseCase struct {
+	sessionService *session.Service
+	messageService *domainMessage.Service
+	logger         output.Logger
+}
+
+func NewHistoryUseCase(
+	sessionService *session.Service,
+	messageService *domainMessage.Service,
+	logger output.Logger,
+) *HistoryUseCase {
+	return &HistoryUseCase{
+		sessionService: sessionService,
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// Execute returns one page of the persisted history of a chat, newest first.
+// The NextCursor of the response continues with older messages.
+func (uc *HistoryUseCase) Execute(ctx context.Context, sessionID string, req *dto.MessageHistoryRequest) (*dto.MessageHistoryResponse, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+
+	req.ChatJID = normalizeChatJID(req.ChatJID)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	messages, hasMore, err := uc.messageService.History(ctx, req.ToQuery(sessionID))
+	if err != nil {
+		uc.logger.Error().Err(err).Str("session_id", sessionID).Str("chat", req.ChatJID).Msg("Failed to query message history")
+		return nil, err
+	}
+
+	response := &dto.MessageHistoryResponse{
+		SessionID: sessionID,
+		ChatJID:   req.ChatJID,
+		Messages:  make([]*dto.MessageInfo, 0, len(messages)),
+		Count:     len(messages),
+		HasMore:   hasMore,
+	}
+
+	for _, msg := range messages {
+		response.Messages = append(response.Messages, dto.NewMessageInfo(msg))
+	}
+
+	if hasMore {
+		response.NextCursor = domainMessage.CursorOf(messages[len(messages)-1]).Encode()
+	}
+
+	return response, nil
+}
+
+// normalizeChatJID accepts a bare phone number in place of a user JID.
+func normalizeChatJID(chat string) string {
+	chat = strings.TrimSpace(chat)
+	if chat == "" || strings.Contains(chat, "@") {
+		return chat
+	}
+
+	return strings.TrimPrefix(chat, "+") + "@s.whatsapp.net"
+}
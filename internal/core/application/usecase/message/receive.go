@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"zpwoot/internal/core/application/dto"
+	domainMessage "zpwoot/internal/core/domain/message"
 	"zpwoot/internal/core/domain/session"
 	"zpwoot/internal/core/domain/shared"
 	"zpwoot/internal/core/ports/output"
@@ -14,15 +15,18 @@ import (
 
 type ReceiveUseCase struct {
 	sessionService *session.Service
+	messageService *domainMessage.Service
 	logger         output.Logger
 }
 
 func NewReceiveUseCase(
 	sessionService *session.Service,
+	messageService *domainMessage.Service,
 	logger output.Logger,
 ) *ReceiveUseCase {
 	return &ReceiveUseCase{
 		sessionService: sessionService,
+		messageService: messageService,
 		logger:         logger,
 	}
 }
@@ -45,6 +49,14 @@ func (uc *ReceiveUseCase) ProcessIncomingMessage(ctx context.Context, req *dto.R
 		return fmt.Errorf("failed to get session: %w", err)
 	}
 
+	if err := uc.ValidateMessage(&req.Message); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	if _, err := uc.messageService.Record(ctx, req.Message.ToDomain(req.SessionID)); err != nil {
+		return fmt.Errorf("failed to record message: %w", err)
+	}
+
 	go func(ctx context.Context) {
 		if err := uc.sessionService.UpdateStatus(ctx, req.SessionID, session.StatusConnected); err != nil {
 			uc.logger.Error().Err(err).Str("session_id", req.SessionID).Msg("Failed to update session status")
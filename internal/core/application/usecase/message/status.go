@@ -0,0 +1,58 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	domainMessage "zpwoot/internal/core/domain/message"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type StatusUseCase struct {
+	sessionService *session.Service
+	messageService *domainMessage.Service
+	logger         output.Logger
+}
+
+func NewStatusUseCase(
+	sessionService *session.Service,
+	messageService *domainMessage.Service,
+	logger output.Logger,
+) *StatusUseCase {
+	return &StatusUseCase{
+		sessionService: sessionService,
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// Execute returns the delivery status of a message the session sent, built
+// from the receipts stored for it.
+func (uc *StatusUseCase) Execute(ctx context.Context, sessionID, messageID string) (*dto.MessageStatusResponse, error) {
+	if messageID == "" {
+		return nil, dto.NewValidationError("messageId", "messageId is required")
+	}
+
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	msg, receipts, err := uc.messageService.Status(ctx, sessionID, messageID)
+	if err != nil {
+		if !errors.Is(err, shared.ErrMessageNotFound) {
+			uc.logger.Error().Err(err).Str("session_id", sessionID).Str("message_id", messageID).Msg("Failed to get message status")
+		}
+
+		return nil, err
+	}
+
+	return dto.NewMessageStatusResponse(msg, receipts), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"zpwoot/internal/core/application/dto"
+	domainMessage "zpwoot/internal/core/domain/message"
 	domainSession "zpwoot/internal/core/domain/session"
 	"zpwoot/internal/core/ports/input"
 	"zpwoot/internal/core/ports/output"
@@ -13,12 +14,18 @@ import (
 type UseCases struct {
 	send    *SendUseCase
 	receive *ReceiveUseCase
+	history *HistoryUseCase
+	chats   *ChatsUseCase
+	status  *StatusUseCase
 }
 
-func NewUseCases(sessionService *domainSession.Service, whatsappClient output.WhatsAppClient, logger output.Logger) input.MessageUseCases {
+func NewUseCases(sessionService *domainSession.Service, messageService *domainMessage.Service, whatsappClient output.WhatsAppClient, logger output.Logger) input.MessageUseCases {
 	return &UseCases{
 		send:    NewSendUseCase(sessionService, whatsappClient, logger),
-		receive: NewReceiveUseCase(sessionService, logger),
+		receive: NewReceiveUseCase(sessionService, messageService, logger),
+		history: NewHistoryUseCase(sessionService, messageService, logger),
+		chats:   NewChatsUseCase(sessionService, whatsappClient, logger),
+		status:  NewStatusUseCase(sessionService, messageService, logger),
 	}
 }
 
@@ -74,6 +81,10 @@ func (m *UseCases) Execute(ctx context.Context, req *dto.ReceiveMessageRequest)
 	return m.receive.ProcessIncomingMessage(ctx, req)
 }
 
+func (m *UseCases) GetMessageHistory(ctx context.Context, sessionID string, req *dto.MessageHistoryRequest) (*dto.MessageHistoryResponse, error) {
+	return m.history.Execute(ctx, sessionID, req)
+}
+
 func (m *UseCases) GetChatInfo(ctx context.Context, sessionID, chatJID string) (interface{}, error) {
 	return nil, fmt.Errorf("GetChatInfo not implemented yet")
 }
@@ -82,6 +93,10 @@ func (m *UseCases) GetContacts(ctx context.Context, sessionID string) (interface
 	return nil, fmt.Errorf("GetContacts not implemented yet")
 }
 
-func (m *UseCases) GetChats(ctx context.Context, sessionID string) (interface{}, error) {
-	return nil, fmt.Errorf("GetChats not implemented yet")
+func (m *UseCases) GetChats(ctx context.Context, sessionID string, req *dto.ListChatsRequest) (*dto.PaginationResponse, error) {
+	return m.chats.Execute(ctx, sessionID, req)
+}
+
+func (m *UseCases) GetMessageStatus(ctx context.Context, sessionID, messageID string) (*dto.MessageStatusResponse, error) {
+	return m.status.Execute(ctx, sessionID, messageID)
 }
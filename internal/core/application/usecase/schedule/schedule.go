@@ -0,0 +1,156 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+)
+
+type ScheduleUseCase struct {
+	scheduleRepo   schedule.Repository
+	sessionService *session.Service
+}
+
+func NewScheduleUseCase(scheduleRepo schedule.Repository, sessionService *session.Service) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		scheduleRepo:   scheduleRepo,
+		sessionService: sessionService,
+	}
+}
+
+// Schedule stores a message to be sent by the scheduler at the requested
+// time. The message body is validated now so a malformed request fails here
+// rather than when it is due.
+func (uc *ScheduleUseCase) Schedule(ctx context.Context, sessionID string, req *dto.ScheduleMessageRequest) (*dto.ScheduledMessageResponse, error) {
+	phone, sendAt, timezone, err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	msg := schedule.NewScheduledMessage(sessionID, phone, req.Type, req.Message, sendAt, timezone, req.Policy())
+
+	if err := uc.scheduleRepo.Create(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	return dto.NewScheduledMessageResponse(msg, true), nil
+}
+
+func (uc *ScheduleUseCase) List(ctx context.Context, sessionID string, req *dto.ListScheduledMessagesRequest) (*dto.PaginationResponse, error) {
+	if req == nil {
+		req = &dto.ListScheduledMessagesRequest{}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	req.ApplyDefaults()
+
+	if err := uc.checkSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	messages, total, err := uc.scheduleRepo.List(ctx, &schedule.ListFilter{
+		SessionID: sessionID,
+		Status:    schedule.Status(req.Status),
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	items := make([]*dto.ScheduledMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		items = append(items, dto.NewScheduledMessageResponse(msg, false))
+	}
+
+	return &dto.PaginationResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: req.Offset+len(items) < total,
+	}, nil
+}
+
+func (uc *ScheduleUseCase) Get(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error) {
+	msg, err := uc.scheduleRepo.GetByID(ctx, sessionID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewScheduledMessageResponse(msg, true), nil
+}
+
+// Reschedule moves a pending message to a new time. Messages that were
+// already sent, given up on or are being sent right now cannot be moved.
+func (uc *ScheduleUseCase) Reschedule(ctx context.Context, sessionID, scheduleID string, req *dto.RescheduleMessageRequest) (*dto.ScheduledMessageResponse, error) {
+	sendAt, timezone, err := dto.ParseSendAt(req.SendAt, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := uc.getPending(ctx, sessionID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Reschedule(sendAt, timezone)
+
+	if err := uc.scheduleRepo.UpdatePending(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return dto.NewScheduledMessageResponse(msg, true), nil
+}
+
+func (uc *ScheduleUseCase) Cancel(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error) {
+	msg, err := uc.getPending(ctx, sessionID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Cancel()
+
+	if err := uc.scheduleRepo.UpdatePending(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return dto.NewScheduledMessageResponse(msg, false), nil
+}
+
+func (uc *ScheduleUseCase) getPending(ctx context.Context, sessionID, scheduleID string) (*schedule.ScheduledMessage, error) {
+	msg, err := uc.scheduleRepo.GetByID(ctx, sessionID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !msg.IsPending() {
+		return nil, shared.ErrScheduledMessageNotPending
+	}
+
+	return msg, nil
+}
+
+func (uc *ScheduleUseCase) checkSession(ctx context.Context, sessionID string) error {
+	if _, err := uc.sessionService.Get(ctx, sessionID); err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return dto.ErrSessionNotFound
+		}
+
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/application/utils"
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+const (
+	schedulerWorkers      = 4
+	schedulerPollInterval = 5 * time.Second
+
+	// scheduleLease must outlast a send, which may wait in the send queue
+	// and download media first.
+	scheduleLease = 5 * time.Minute
+	sendTimeout   = 3 * time.Minute
+
+	// maxSendAttempts bounds the attempts made while the session is
+	// connected; retryBackoff doubles between them up to maxRetryBackoff.
+	maxSendAttempts = 5
+	retryBackoff    = 30 * time.Second
+	maxRetryBackoff = 10 * time.Minute
+
+	// A message waiting for its session to reconnect is checked again every
+	// disconnectedRetry and given up on maxLateness after its send time.
+	disconnectedRetry = time.Minute
+	maxLateness       = 24 * time.Hour
+
+	eventScheduledMessageSent   = "ScheduledMessageSent"
+	eventScheduledMessageFailed = "ScheduledMessageFailed"
+)
+
+// errInvalidPayload marks a stored message that cannot be sent at all, so it
+// fails without further attempts.
+var errInvalidPayload = errors.New("invalid scheduled message payload")
+
+// Scheduler sends scheduled messages once they are due. Messages live in
+// Postgres and are leased before sending, so pending messages survive
+// restarts and several instances can share the database.
+type Scheduler struct {
+	scheduleRepo   schedule.Repository
+	messageService input.MessageService
+	mediaProcessor *utils.MediaProcessor
+	whatsappClient output.WhatsAppClient
+	publisher      output.EventPublisher
+	logger         output.Logger
+
+	jobs     chan *schedule.ScheduledMessage
+	inFlight atomic.Int32
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewScheduler(
+	scheduleRepo schedule.Repository,
+	messageService input.MessageService,
+	mediaProcessor *utils.MediaProcessor,
+	whatsappClient output.WhatsAppClient,
+	publisher output.EventPublisher,
+	logger output.Logger,
+) *Scheduler {
+	return &Scheduler{
+		scheduleRepo:   scheduleRepo,
+		messageService: messageService,
+		mediaProcessor: mediaProcessor,
+		whatsappClient: whatsappClient,
+		publisher:      publisher,
+		logger:         logger,
+		jobs:           make(chan *schedule.ScheduledMessage),
+	}
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for i := 0; i < schedulerWorkers; i++ {
+		s.wg.Add(1)
+
+		go s.work(ctx)
+	}
+
+	s.wg.Add(1)
+
+	go s.poll(ctx)
+
+	s.logger.Info().Int("workers", schedulerWorkers).Msg("Message scheduler started")
+}
+
+// Stop waits for the messages being sent. Leased messages that were not
+// started become due again once their lease expires.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.wg.Wait()
+
+	s.logger.Info().Msg("Message scheduler stopped")
+}
+
+func (s *Scheduler) poll(ctx context.Context) {
+	defer s.wg.Done()
+	defer close(s.jobs)
+
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.claimDue(ctx)
+	}
+}
+
+func (s *Scheduler) claimDue(ctx context.Context) {
+	free := schedulerWorkers - int(s.inFlight.Load())
+	if free <= 0 {
+		return
+	}
+
+	messages, err := s.scheduleRepo.ClaimDue(ctx, free, scheduleLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error().Err(err).Msg("Failed to claim scheduled messages")
+		}
+
+		return
+	}
+
+	for _, msg := range messages {
+		s.inFlight.Add(1)
+
+		select {
+		case s.jobs <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) work(ctx context.Context) {
+	defer s.wg.Done()
+
+	for msg := range s.jobs {
+		s.process(ctx, msg)
+		s.inFlight.Add(-1)
+	}
+}
+
+func (s *Scheduler) process(ctx context.Context, msg *schedule.ScheduledMessage) {
+	// A send that already started is finished even during shutdown, so its
+	// outcome is recorded instead of the message being sent twice.
+	ctx = context.WithoutCancel(ctx)
+
+	if s.whatsappClient.IsConnected(ctx, msg.SessionID) {
+		s.send(ctx, msg)
+	} else {
+		s.waitForSession(msg)
+	}
+
+	if err := s.scheduleRepo.Save(ctx, msg); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("session_id", msg.SessionID).
+			Str("schedule_id", msg.ID).
+			Msg("Failed to save scheduled message")
+
+		return
+	}
+
+	switch msg.Status {
+	case schedule.StatusSent:
+		s.publish(ctx, msg, eventScheduledMessageSent)
+	case schedule.StatusFailed, schedule.StatusSkipped:
+		s.publish(ctx, msg, eventScheduledMessageFailed)
+	}
+}
+
+func (s *Scheduler) send(ctx context.Context, msg *schedule.ScheduledMessage) {
+	msg.Attempts++
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	result, err := s.sendMessage(sendCtx, msg)
+	if err == nil {
+		msg.MarkSent(result.MessageID, result.SentAt)
+
+		s.logger.Info().
+			Str("session_id", msg.SessionID).
+			Str("schedule_id", msg.ID).
+			Str("message_id", result.MessageID).
+			Msg("Scheduled message sent")
+
+		return
+	}
+
+	s.logger.Warn().
+		Err(err).
+		Str("session_id", msg.SessionID).
+		Str("schedule_id", msg.ID).
+		Int("attempt", msg.Attempts).
+		Msg("Failed to send scheduled message")
+
+	if errors.Is(err, errInvalidPayload) || msg.Attempts >= maxSendAttempts {
+		msg.Fail(err.Error())
+		return
+	}
+
+	msg.Retry(err.Error(), time.Now().Add(backoff(msg.Attempts)))
+}
+
+// waitForSession handles a due message whose session is not connected,
+// skipping it or checking again later depending on its policy.
+func (s *Scheduler) waitForSession(msg *schedule.ScheduledMessage) {
+	const reason = "session is not connected"
+
+	switch {
+	case msg.OnDisconnected == schedule.DisconnectedSkip:
+		msg.Skip(reason)
+	case time.Since(msg.SendAt) > maxLateness:
+		msg.Fail(fmt.Sprintf("%s after %s", reason, maxLateness))
+	default:
+		msg.Retry(reason, time.Now().Add(disconnectedRetry))
+	}
+}
+
+// sendMessage sends the stored body of a scheduled message the way the
+// matching send endpoint would.
+func (s *Scheduler) sendMessage(ctx context.Context, msg *schedule.ScheduledMessage) (*output.MessageResult, error) {
+	decode := func(target interface{}) error {
+		if err := json.Unmarshal(msg.Payload, target); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidPayload, err)
+		}
+
+		return nil
+	}
+
+	switch msg.Type {
+	case schedule.TypeText:
+		var req dto.SendTextMessageRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+
+		return s.messageService.SendTextMessage(ctx, msg.SessionID, req.Phone, req.Text, toContextInfo(req.ContextInfo))
+	case schedule.TypeImage, schedule.TypeAudio, schedule.TypeVideo, schedule.TypeDocument, schedule.TypeSticker:
+		// The image body holds every field the other media bodies have.
+		var req dto.SendImageMessageRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+
+		media, err := s.mediaProcessor.ProcessMedia(req.File, req.MimeType, req.FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process media: %w", err)
+		}
+
+		media.Caption = req.Caption
+		media.ViewOnce = req.ViewOnce
+		media.Sticker = msg.Type == schedule.TypeSticker
+
+		return s.messageService.SendMediaMessage(ctx, msg.SessionID, req.Phone, media, toContextInfo(req.ContextInfo))
+	case schedule.TypeLocation:
+		var req dto.SendLocationMessageRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+
+		return s.messageService.SendLocationMessage(ctx, msg.SessionID, req.Phone, req.Latitude, req.Longitude, req.Name, toContextInfo(req.ContextInfo))
+	case schedule.TypeContact:
+		var req dto.SendContactMessageRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+
+		if req.Contact == nil {
+			return nil, fmt.Errorf("%w: contact is missing", errInvalidPayload)
+		}
+
+		contact := &input.ContactInfo{
+			Name:  req.Contact.Name,
+			Phone: req.Contact.Phone,
+			VCard: req.Contact.VCard,
+		}
+
+		return s.messageService.SendContactMessage(ctx, msg.SessionID, req.Phone, contact, toContextInfo(req.ContextInfo))
+	case schedule.TypePoll:
+		var req dto.SendPollMessageRequest
+		if err := decode(&req); err != nil {
+			return nil, err
+		}
+
+		return s.messageService.SendPollMessage(ctx, msg.SessionID, req.Phone, req.Name, req.Options, req.SelectableOptionsCount)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", errInvalidPayload, msg.Type)
+	}
+}
+
+func (s *Scheduler) publish(ctx context.Context, msg *schedule.ScheduledMessage, eventType string) {
+	payload := &webhook.ScheduledMessagePayload{
+		ScheduleID: msg.ID,
+		Phone:      msg.Phone,
+		Type:       msg.Type,
+		Status:     string(msg.Status),
+		SendAt:     msg.SendAt,
+		SentAt:     msg.SentAt,
+		Attempts:   msg.Attempts,
+	}
+
+	if msg.MessageID != nil {
+		payload.MessageID = *msg.MessageID
+	}
+
+	if msg.LastError != nil && msg.Status != schedule.StatusSent {
+		payload.Error = *msg.LastError
+	}
+
+	if err := s.publisher.PublishEvent(ctx, msg.SessionID, eventType, payload); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("session_id", msg.SessionID).
+			Str("schedule_id", msg.ID).
+			Str("event", eventType).
+			Msg("Failed to publish scheduled message event")
+	}
+}
+
+func toContextInfo(req *dto.ContextInfoRequest) *output.MessageContextInfo {
+	if req == nil {
+		return nil
+	}
+
+	return &output.MessageContextInfo{
+		StanzaID:    req.StanzaID,
+		Participant: req.Participant,
+	}
+}
+
+func backoff(attempt int) time.Duration {
+	delay := retryBackoff << (attempt - 1)
+	if delay <= 0 || delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return delay
+}
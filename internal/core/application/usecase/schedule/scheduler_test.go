@@ -0,0 +1,260 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+// fakeScheduleRepo records the messages saved by the scheduler.
+type fakeScheduleRepo struct {
+	schedule.Repository
+
+	saved []*schedule.ScheduledMessage
+}
+
+func (r *fakeScheduleRepo) Save(_ context.Context, msg *schedule.ScheduledMessage) error {
+	r.saved = append(r.saved, msg)
+	return nil
+}
+
+// fakeMessageService answers text sends with err, or with a sent message.
+type fakeMessageService struct {
+	input.MessageService
+
+	err   error
+	sends int
+}
+
+func (s *fakeMessageService) SendTextMessage(_ context.Context, _, _, _ string, _ *output.MessageContextInfo) (*output.MessageResult, error) {
+	s.sends++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &output.MessageResult{MessageID: "MSG1", Status: "sent", SentAt: time.Now()}, nil
+}
+
+type fakeWhatsAppClient struct {
+	output.WhatsAppClient
+
+	connected bool
+}
+
+func (c *fakeWhatsAppClient) IsConnected(_ context.Context, _ string) bool {
+	return c.connected
+}
+
+// fakePublisher records the types of the events it is given.
+type fakePublisher struct {
+	events []string
+}
+
+func (p *fakePublisher) PublishEvent(_ context.Context, _, eventType string, _ interface{}) error {
+	p.events = append(p.events, eventType)
+	return nil
+}
+
+func newTestMessage(t *testing.T, sendAt time.Time, policy schedule.DisconnectedPolicy) *schedule.ScheduledMessage {
+	t.Helper()
+
+	payload, err := json.Marshal(map[string]string{"phone": "5511999999999", "text": "hello"})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	return schedule.NewScheduledMessage("session-1", "5511999999999", schedule.TypeText, payload, sendAt, "UTC", policy)
+}
+
+func TestSchedulerProcess(t *testing.T) {
+	sendErr := errors.New("send failed")
+
+	tests := []struct {
+		name      string
+		connected bool
+		sendErr   error
+		policy    schedule.DisconnectedPolicy
+		// late is how long ago the message was due.
+		late time.Duration
+		// attempts were made before this one.
+		attempts    int
+		payload     string
+		wantStatus  schedule.Status
+		wantSends   int
+		wantRetryIn time.Duration
+		wantEvents  []string
+	}{
+		{
+			name:       "sent",
+			connected:  true,
+			wantStatus: schedule.StatusSent,
+			wantSends:  1,
+			wantEvents: []string{eventScheduledMessageSent},
+		},
+		{
+			name:        "first failure backs off",
+			connected:   true,
+			sendErr:     sendErr,
+			wantStatus:  schedule.StatusPending,
+			wantSends:   1,
+			wantRetryIn: retryBackoff,
+		},
+		{
+			name:        "second failure backs off twice as long",
+			connected:   true,
+			sendErr:     sendErr,
+			attempts:    1,
+			wantStatus:  schedule.StatusPending,
+			wantSends:   1,
+			wantRetryIn: 2 * retryBackoff,
+		},
+		{
+			name:       "last attempt fails",
+			connected:  true,
+			sendErr:    sendErr,
+			attempts:   maxSendAttempts - 1,
+			wantStatus: schedule.StatusFailed,
+			wantSends:  1,
+			wantEvents: []string{eventScheduledMessageFailed},
+		},
+		{
+			name:       "invalid payload fails at once",
+			connected:  true,
+			payload:    `{"phone":`,
+			wantStatus: schedule.StatusFailed,
+			wantEvents: []string{eventScheduledMessageFailed},
+		},
+		{
+			name:       "disconnected and skipped",
+			policy:     schedule.DisconnectedSkip,
+			wantStatus: schedule.StatusSkipped,
+			wantEvents: []string{eventScheduledMessageFailed},
+		},
+		{
+			name:        "disconnected and retried",
+			policy:      schedule.DisconnectedRetry,
+			late:        time.Hour,
+			wantStatus:  schedule.StatusPending,
+			wantRetryIn: disconnectedRetry,
+		},
+		{
+			name:       "disconnected for too long",
+			policy:     schedule.DisconnectedRetry,
+			late:       maxLateness + time.Minute,
+			wantStatus: schedule.StatusFailed,
+			wantEvents: []string{eventScheduledMessageFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeScheduleRepo{}
+			messages := &fakeMessageService{err: tt.sendErr}
+			publisher := &fakePublisher{}
+			scheduler := NewScheduler(repo, messages, nil, &fakeWhatsAppClient{connected: tt.connected}, publisher, logger.New())
+
+			msg := newTestMessage(t, time.Now().Add(-tt.late), tt.policy)
+			msg.Attempts = tt.attempts
+
+			if tt.payload != "" {
+				msg.Payload = []byte(tt.payload)
+			}
+
+			before := time.Now()
+			scheduler.process(context.Background(), msg)
+
+			if len(repo.saved) != 1 || repo.saved[0] != msg {
+				t.Fatalf("saved %d messages, want the processed one", len(repo.saved))
+			}
+
+			if msg.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", msg.Status, tt.wantStatus)
+			}
+
+			if messages.sends != tt.wantSends {
+				t.Errorf("sent %d times, want %d", messages.sends, tt.wantSends)
+			}
+
+			if tt.wantStatus != schedule.StatusSent && msg.LastError == nil {
+				t.Error("lastError is not set")
+			}
+
+			if tt.wantRetryIn > 0 {
+				earliest := before.Add(tt.wantRetryIn)
+				latest := time.Now().Add(tt.wantRetryIn)
+
+				if msg.NextAttemptAt.Before(earliest) || msg.NextAttemptAt.After(latest) {
+					t.Errorf("next attempt in %s, want %s", msg.NextAttemptAt.Sub(before), tt.wantRetryIn)
+				}
+			}
+
+			if len(publisher.events) != len(tt.wantEvents) {
+				t.Fatalf("published %v, want %v", publisher.events, tt.wantEvents)
+			}
+
+			for i := range tt.wantEvents {
+				if publisher.events[i] != tt.wantEvents[i] {
+					t.Errorf("published %v, want %v", publisher.events, tt.wantEvents)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerSendCountsAttempts(t *testing.T) {
+	repo := &fakeScheduleRepo{}
+	scheduler := NewScheduler(repo, &fakeMessageService{err: errors.New("send failed")}, nil, &fakeWhatsAppClient{connected: true}, &fakePublisher{}, logger.New())
+
+	msg := newTestMessage(t, time.Now(), schedule.DisconnectedRetry)
+
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		scheduler.process(context.Background(), msg)
+
+		if msg.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", msg.Attempts, attempt)
+		}
+	}
+
+	if msg.Status != schedule.StatusFailed {
+		t.Errorf("status after %d attempts = %s, want %s", maxSendAttempts, msg.Status, schedule.StatusFailed)
+	}
+}
+
+func TestSchedulerWaitingDoesNotCountAttempts(t *testing.T) {
+	scheduler := NewScheduler(&fakeScheduleRepo{}, &fakeMessageService{}, nil, &fakeWhatsAppClient{}, &fakePublisher{}, logger.New())
+
+	msg := newTestMessage(t, time.Now(), schedule.DisconnectedRetry)
+	scheduler.process(context.Background(), msg)
+
+	if msg.Attempts != 0 {
+		t.Errorf("attempts = %d while disconnected, want 0", msg.Attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, maxRetryBackoff},
+		{64, maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/schedule"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/ports/input"
+)
+
+type UseCases struct {
+	schedule *ScheduleUseCase
+}
+
+func NewUseCases(scheduleRepo schedule.Repository, sessionService *session.Service) input.ScheduleUseCases {
+	return &UseCases{
+		schedule: NewScheduleUseCase(scheduleRepo, sessionService),
+	}
+}
+
+func (s *UseCases) Schedule(ctx context.Context, sessionID string, req *dto.ScheduleMessageRequest) (*dto.ScheduledMessageResponse, error) {
+	return s.schedule.Schedule(ctx, sessionID, req)
+}
+
+func (s *UseCases) List(ctx context.Context, sessionID string, req *dto.ListScheduledMessagesRequest) (*dto.PaginationResponse, error) {
+	return s.schedule.List(ctx, sessionID, req)
+}
+
+func (s *UseCases) Get(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error) {
+	return s.schedule.Get(ctx, sessionID, scheduleID)
+}
+
+func (s *UseCases) Reschedule(ctx context.Context, sessionID, scheduleID string, req *dto.RescheduleMessageRequest) (*dto.ScheduledMessageResponse, error) {
+	return s.schedule.Reschedule(ctx, sessionID, scheduleID, req)
+}
+
+func (s *UseCases) Cancel(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error) {
+	return s.schedule.Cancel(ctx, sessionID, scheduleID)
+}
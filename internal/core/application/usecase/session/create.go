@@ -8,22 +8,26 @@ import (
 	"zpwoot/internal/core/application/dto"
 	"zpwoot/internal/core/domain/session"
 	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
 	"zpwoot/internal/core/ports/output"
 )
 
 type CreateUseCase struct {
 	sessionService *session.Service
+	tenantRepo     tenant.Repository
 	whatsappClient output.WhatsAppClient
 	logger         output.Logger
 }
 
 func NewCreateUseCase(
 	sessionService *session.Service,
+	tenantRepo tenant.Repository,
 	whatsappClient output.WhatsAppClient,
 	logger output.Logger,
 ) *CreateUseCase {
 	return &CreateUseCase{
 		sessionService: sessionService,
+		tenantRepo:     tenantRepo,
 		whatsappClient: whatsappClient,
 		logger:         logger,
 	}
@@ -34,17 +38,36 @@ func (uc *CreateUseCase) Execute(ctx context.Context, req *dto.CreateRequest) (*
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	domainSession, err := uc.sessionService.Create(ctx, req.Name)
+	tenantID, err := uc.checkTenant(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	domainSession, err := uc.sessionService.Create(ctx, tenantID, req.Name)
 	if err != nil {
 		if errors.Is(err, shared.ErrSessionAlreadyExists) {
 			return nil, dto.ErrSessionAlreadyExists
 		}
 
+		if errors.Is(err, shared.ErrSessionQuotaExceeded) {
+			return nil, shared.ErrSessionQuotaExceeded
+		}
+
 		return nil, fmt.Errorf("failed to create session in domain: %w", err)
 	}
 
 	sessionID := domainSession.ID
 
+	if req.Settings != nil && req.Settings.Proxy != nil && req.Settings.Proxy.Enabled {
+		if err := uc.storeProxy(ctx, domainSession, req.Settings.Proxy); err != nil {
+			if rollbackErr := uc.sessionService.Delete(ctx, sessionID); rollbackErr != nil {
+				uc.logger.Error().Err(rollbackErr).Str("session_id", sessionID).Msg("Failed to rollback session creation")
+			}
+
+			return nil, err
+		}
+	}
+
 	err = uc.whatsappClient.CreateSession(ctx, sessionID)
 	if err != nil {
 		if rollbackErr := uc.sessionService.Delete(ctx, sessionID); rollbackErr != nil {
@@ -64,7 +87,51 @@ func (uc *CreateUseCase) Execute(ctx context.Context, req *dto.CreateRequest) (*
 		return nil, fmt.Errorf("failed to create WhatsApp session: %w", err)
 	}
 
-	uc.logger.Info().Str("session_id", sessionID).Str("name", req.Name).Msg("Session created successfully")
+	uc.logger.Info().Str("session_id", sessionID).Str("tenant_id", tenantID).Str("name", req.Name).Msg("Session created successfully")
 
 	return dto.ToCreateResponse(domainSession), nil
 }
+
+// checkTenant returns the tenant a new session belongs to, once it is known
+// to be active. Sessions belong to the tenant of the caller; the server
+// operator may pick one, or gets the default tenant. The session quota is
+// enforced by the repository as the session is inserted.
+func (uc *CreateUseCase) checkTenant(ctx context.Context, requested string) (string, error) {
+	tenantID := shared.TenantFromContext(ctx)
+
+	switch {
+	case tenantID != "" && requested != "" && requested != tenantID:
+		return "", dto.NewValidationError("tenantId", "sessions can only be created for your own tenant")
+	case tenantID == "" && requested != "":
+		tenantID = requested
+	case tenantID == "":
+		tenantID = tenant.DefaultID
+	}
+
+	owner, err := uc.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, shared.ErrTenantNotFound) && requested != "" {
+			return "", dto.NewValidationError("tenantId", "tenant not found")
+		}
+
+		return "", fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if !owner.IsActive() {
+		return "", shared.ErrTenantSuspended
+	}
+
+	return owner.ID, nil
+}
+
+func (uc *CreateUseCase) storeProxy(ctx context.Context, domainSession *session.Session, settings *dto.ProxySettings) error {
+	if err := domainSession.SetProxy(settings.ToDomain()); err != nil {
+		return dto.NewValidationError("settings.proxy", err.Error())
+	}
+
+	if err := uc.sessionService.Update(ctx, domainSession); err != nil {
+		return fmt.Errorf("failed to store proxy configuration: %w", err)
+	}
+
+	return nil
+}
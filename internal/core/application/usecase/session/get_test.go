@@ -0,0 +1,67 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/ports/output"
+)
+
+type fakeSessionRepo struct {
+	session.Repository
+
+	session *session.Session
+}
+
+func (r *fakeSessionRepo) GetByID(context.Context, string) (*session.Session, error) {
+	return r.session, nil
+}
+
+// offlineClient knows no session, as when WhatsApp is not connected.
+type offlineClient struct {
+	output.WhatsAppClient
+}
+
+func (offlineClient) GetSessionStatus(context.Context, string) (*output.SessionStatus, error) {
+	return nil, &output.WhatsAppError{Code: sessionNotFoundCode, Message: "session not found"}
+}
+
+func TestGetSessionHidesProxyPassword(t *testing.T) {
+	sess := session.NewSession("proxied")
+	sess.ID = "session-1"
+
+	if err := sess.SetProxy(&session.Proxy{
+		Type:     session.ProxyTypeSOCKS5,
+		Host:     "proxy.example.com",
+		Port:     "1080",
+		Username: "proxy-user",
+		Password: "proxy-secret-password",
+	}); err != nil {
+		t.Fatalf("SetProxy: %v", err)
+	}
+
+	uc := NewGetUseCase(session.NewService(&fakeSessionRepo{session: sess}), offlineClient{}, logger.New())
+
+	response, err := uc.Execute(context.Background(), "session-1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+
+	if strings.Contains(string(body), "proxy-secret-password") {
+		t.Fatalf("response holds the proxy password: %s", body)
+	}
+
+	proxy := response.Proxy
+	if proxy == nil || !proxy.Enabled || proxy.Host != "proxy.example.com" || proxy.User != "proxy-user" || !proxy.HasPassword {
+		t.Errorf("proxy = %+v, want the proxy without its password", proxy)
+	}
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/ports/output"
+)
+
+type ProxyUseCase struct {
+	sessionService *session.Service
+	whatsappClient output.WhatsAppClient
+	logger         output.Logger
+}
+
+func NewProxyUseCase(
+	sessionService *session.Service,
+	whatsappClient output.WhatsAppClient,
+	logger output.Logger,
+) *ProxyUseCase {
+	return &ProxyUseCase{
+		sessionService: sessionService,
+		whatsappClient: whatsappClient,
+		logger:         logger,
+	}
+}
+
+// SetProxy stores the proxy settings of a session and applies them to the
+// running WhatsApp client. Disabled settings remove the proxy.
+func (uc *ProxyUseCase) SetProxy(ctx context.Context, sessionID string, req *dto.ProxySettings) (*dto.ProxyResponse, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+
+	if req == nil {
+		req = &dto.ProxySettings{}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	domainSession, err := uc.sessionService.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, shared.ErrSessionNotFound) {
+			return nil, dto.ErrSessionNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get session from domain: %w", err)
+	}
+
+	proxy := req.ToDomain()
+	if err := domainSession.SetProxy(proxy); err != nil {
+		return nil, dto.NewValidationError("proxy", err.Error())
+	}
+
+	if err := uc.sessionService.Update(ctx, domainSession); err != nil {
+		return nil, fmt.Errorf("failed to store proxy configuration: %w", err)
+	}
+
+	reconnected := uc.whatsappClient.IsConnected(ctx, sessionID)
+
+	if err := uc.whatsappClient.ReloadProxy(ctx, sessionID); err != nil {
+		uc.logger.Error().Err(err).Str("session_id", sessionID).Msg("Failed to apply proxy configuration")
+		return nil, fmt.Errorf("failed to apply proxy configuration: %w", err)
+	}
+
+	uc.logger.Info().
+		Str("session_id", sessionID).
+		Bool("enabled", proxy != nil).
+		Bool("reconnected", reconnected).
+		Msg("Session proxy updated")
+
+	response := dto.ToProxyResponse(sessionID, proxy)
+	response.Reconnected = reconnected
+
+	return response, nil
+}
+
+func (uc *ProxyUseCase) RemoveProxy(ctx context.Context, sessionID string) (*dto.ProxyResponse, error) {
+	return uc.SetProxy(ctx, sessionID, &dto.ProxySettings{Enabled: false})
+}
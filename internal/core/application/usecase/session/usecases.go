@@ -5,6 +5,7 @@ import (
 
 	"zpwoot/internal/core/application/dto"
 	"zpwoot/internal/core/domain/session"
+	"zpwoot/internal/core/domain/tenant"
 	"zpwoot/internal/core/ports/input"
 	"zpwoot/internal/core/ports/output"
 )
@@ -19,15 +20,17 @@ type UseCases struct {
 	Delete     *DeleteUseCase
 	QR         *QRUseCase
 	Pair       *PairUseCase
+	Proxy      *ProxyUseCase
 }
 
 func NewUseCases(
 	sessionService *session.Service,
+	tenantRepo tenant.Repository,
 	whatsappClient output.WhatsAppClient,
 	logger output.Logger,
 ) *UseCases {
 	return &UseCases{
-		Create:     NewCreateUseCase(sessionService, whatsappClient, logger),
+		Create:     NewCreateUseCase(sessionService, tenantRepo, whatsappClient, logger),
 		Connect:    NewConnectUseCase(sessionService, whatsappClient, logger),
 		Disconnect: NewDisconnectUseCase(sessionService, whatsappClient, logger),
 		Logout:     NewLogoutUseCase(sessionService, whatsappClient, logger),
@@ -36,6 +39,7 @@ func NewUseCases(
 		Delete:     NewDeleteUseCase(sessionService, whatsappClient, logger),
 		QR:         NewQRUseCase(sessionService, whatsappClient, logger),
 		Pair:       NewPairUseCase(whatsappClient, logger),
+		Proxy:      NewProxyUseCase(sessionService, whatsappClient, logger),
 	}
 }
 
@@ -79,4 +83,12 @@ func (uc *UseCases) PairPhone(ctx context.Context, sessionID string, phone strin
 	return uc.Pair.Execute(ctx, sessionID, phone)
 }
 
+func (uc *UseCases) SetProxy(ctx context.Context, sessionID string, req *dto.ProxySettings) (*dto.ProxyResponse, error) {
+	return uc.Proxy.SetProxy(ctx, sessionID, req)
+}
+
+func (uc *UseCases) RemoveProxy(ctx context.Context, sessionID string) (*dto.ProxyResponse, error) {
+	return uc.Proxy.RemoveProxy(ctx, sessionID)
+}
+
 var _ input.SessionUseCases = (*UseCases)(nil)
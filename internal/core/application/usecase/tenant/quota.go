@@ -0,0 +1,54 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/output"
+)
+
+// QuotaUseCase counts the messages sent by the sessions of each tenant
+// against its daily quota.
+type QuotaUseCase struct {
+	tenantRepo tenant.Repository
+	logger     output.Logger
+}
+
+func NewQuotaUseCase(tenantRepo tenant.Repository, logger output.Logger) *QuotaUseCase {
+	return &QuotaUseCase{
+		tenantRepo: tenantRepo,
+		logger:     logger,
+	}
+}
+
+// Consume counts a message even if sending it fails later on, so retrying
+// a failing send cannot go around the quota.
+func (uc *QuotaUseCase) Consume(ctx context.Context, sessionID string) error {
+	owner, err := uc.tenantRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session tenant: %w", err)
+	}
+
+	if !owner.IsActive() {
+		return output.ErrTenantSuspended
+	}
+
+	counted, err := uc.tenantRepo.ConsumeMessage(ctx, owner.ID, tenant.Day(time.Now()), owner.MaxMessagesPerDay)
+	if err != nil {
+		return err
+	}
+
+	if !counted {
+		uc.logger.Warn().
+			Str("tenant_id", owner.ID).
+			Str("session_id", sessionID).
+			Int("max_messages_per_day", owner.MaxMessagesPerDay).
+			Msg("Tenant reached its daily message quota")
+
+		return output.ErrMessageQuotaExceeded
+	}
+
+	return nil
+}
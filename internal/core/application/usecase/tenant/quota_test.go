@@ -0,0 +1,174 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zpwoot/internal/adapters/logger"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/output"
+)
+
+// fakeTenantRepo keeps tenants, the sessions they own and the messages
+// each sent today in memory.
+type fakeTenantRepo struct {
+	tenant.Repository
+
+	tenants  map[string]*tenant.Tenant
+	sessions map[string]string
+	messages map[string]int
+}
+
+func newFakeTenantRepo(tenants ...*tenant.Tenant) *fakeTenantRepo {
+	repo := &fakeTenantRepo{
+		tenants:  make(map[string]*tenant.Tenant),
+		sessions: make(map[string]string),
+		messages: make(map[string]int),
+	}
+
+	for _, t := range tenants {
+		repo.tenants[t.ID] = t
+	}
+
+	return repo
+}
+
+func (r *fakeTenantRepo) GetByID(_ context.Context, id string) (*tenant.Tenant, error) {
+	t, ok := r.tenants[id]
+	if !ok {
+		return nil, shared.ErrTenantNotFound
+	}
+
+	return t, nil
+}
+
+func (r *fakeTenantRepo) GetBySessionID(ctx context.Context, sessionID string) (*tenant.Tenant, error) {
+	tenantID, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, shared.ErrTenantNotFound
+	}
+
+	return r.GetByID(ctx, tenantID)
+}
+
+func (r *fakeTenantRepo) ConsumeMessage(_ context.Context, tenantID string, _ time.Time, limit int) (bool, error) {
+	if limit > 0 && r.messages[tenantID] >= limit {
+		return false, nil
+	}
+
+	r.messages[tenantID]++
+
+	return true, nil
+}
+
+func newTestTenant(id string, maxMessagesPerDay int) *tenant.Tenant {
+	t := tenant.NewTenant(id, 0, maxMessagesPerDay)
+	t.ID = id
+
+	return t
+}
+
+func TestQuotaConsume(t *testing.T) {
+	repo := newFakeTenantRepo(newTestTenant("tenant-a", 2), newTestTenant("tenant-b", 0))
+	repo.sessions["session-a"] = "tenant-a"
+	repo.sessions["session-b"] = "tenant-b"
+
+	quota := NewQuotaUseCase(repo, logger.New())
+	ctx := context.Background()
+
+	for i := range 2 {
+		if err := quota.Consume(ctx, "session-a"); err != nil {
+			t.Fatalf("message %d: unexpected error %v", i+1, err)
+		}
+	}
+
+	if err := quota.Consume(ctx, "session-a"); !errors.Is(err, output.ErrMessageQuotaExceeded) {
+		t.Fatalf("message over the quota: error = %v, want %v", err, output.ErrMessageQuotaExceeded)
+	}
+
+	if got := repo.messages["tenant-a"]; got != 2 {
+		t.Errorf("messages counted = %d, want 2", got)
+	}
+
+	for i := range 10 {
+		if err := quota.Consume(ctx, "session-b"); err != nil {
+			t.Fatalf("unlimited tenant, message %d: unexpected error %v", i+1, err)
+		}
+	}
+}
+
+func TestQuotaConsumeSuspendedTenant(t *testing.T) {
+	suspended := newTestTenant("tenant-a", 0)
+	suspended.Suspend()
+
+	repo := newFakeTenantRepo(suspended)
+	repo.sessions["session-a"] = "tenant-a"
+
+	quota := NewQuotaUseCase(repo, logger.New())
+
+	if err := quota.Consume(context.Background(), "session-a"); !errors.Is(err, output.ErrTenantSuspended) {
+		t.Fatalf("error = %v, want %v", err, output.ErrTenantSuspended)
+	}
+
+	if got := repo.messages["tenant-a"]; got != 0 {
+		t.Errorf("messages counted = %d, want 0", got)
+	}
+
+	suspended.Activate()
+
+	if err := quota.Consume(context.Background(), "session-a"); err != nil {
+		t.Fatalf("after activation: unexpected error %v", err)
+	}
+}
+
+func TestQuotaConsumeUnknownSession(t *testing.T) {
+	quota := NewQuotaUseCase(newFakeTenantRepo(), logger.New())
+
+	if err := quota.Consume(context.Background(), "missing"); !errors.Is(err, shared.ErrTenantNotFound) {
+		t.Fatalf("error = %v, want %v", err, shared.ErrTenantNotFound)
+	}
+}
+
+func TestCheckSession(t *testing.T) {
+	repo := newFakeTenantRepo(newTestTenant("tenant-a", 0), newTestTenant("tenant-b", 0))
+	repo.sessions["session-a"] = "tenant-a"
+	repo.sessions["session-b"] = "tenant-b"
+
+	uc := NewTenantUseCase(repo, logger.New())
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		sessionID string
+		wantErr   error
+	}{
+		{"own session", shared.WithTenant(context.Background(), "tenant-a"), "session-a", nil},
+		{"session of another tenant", shared.WithTenant(context.Background(), "tenant-a"), "session-b", shared.ErrSessionNotFound},
+		{"unknown session", shared.WithTenant(context.Background(), "tenant-a"), "missing", shared.ErrSessionNotFound},
+		{"no tenant", context.Background(), "session-b", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.CheckSession(tt.ctx, tt.sessionID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuspendDefaultTenant(t *testing.T) {
+	repo := newFakeTenantRepo(newTestTenant(tenant.DefaultID, 0))
+	uc := NewTenantUseCase(repo, logger.New())
+
+	if _, err := uc.Suspend(context.Background(), tenant.DefaultID); !errors.Is(err, shared.ErrDefaultTenant) {
+		t.Fatalf("error = %v, want %v", err, shared.ErrDefaultTenant)
+	}
+
+	if !repo.tenants[tenant.DefaultID].IsActive() {
+		t.Error("default tenant was suspended")
+	}
+}
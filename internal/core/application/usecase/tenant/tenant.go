@@ -0,0 +1,177 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/output"
+)
+
+type TenantUseCase struct {
+	tenantRepo tenant.Repository
+	logger     output.Logger
+}
+
+func NewTenantUseCase(tenantRepo tenant.Repository, logger output.Logger) *TenantUseCase {
+	return &TenantUseCase{
+		tenantRepo: tenantRepo,
+		logger:     logger,
+	}
+}
+
+func (uc *TenantUseCase) Create(ctx context.Context, req *dto.CreateTenantRequest) (*dto.TenantResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := tenant.NewTenant(req.Name, req.MaxSessions, req.MaxMessagesPerDay)
+
+	if err := uc.tenantRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return dto.NewTenantResponse(t), nil
+}
+
+func (uc *TenantUseCase) List(ctx context.Context) (*dto.TenantListResponse, error) {
+	tenants, err := uc.tenantRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.TenantResponse, len(tenants))
+	for i, t := range tenants {
+		items[i] = dto.NewTenantResponse(t)
+	}
+
+	return &dto.TenantListResponse{
+		Tenants: items,
+		Total:   len(items),
+	}, nil
+}
+
+func (uc *TenantUseCase) Get(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	t, err := uc.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := uc.tenantRepo.CountSessions(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := uc.tenantRepo.GetUsage(ctx, t.ID, tenant.Day(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.NewTenantResponse(t)
+	response.Usage = &dto.TenantUsageResponse{
+		Sessions:      sessions,
+		MessagesToday: usage.Messages,
+		Day:           usage.Day,
+	}
+
+	return response, nil
+}
+
+// Update changes the name and quotas of a tenant. Lowering a quota below
+// what the tenant already uses keeps its sessions and today's messages,
+// but stops new ones.
+func (uc *TenantUseCase) Update(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*dto.TenantResponse, error) {
+	t, err := uc.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Apply(t); err != nil {
+		return nil, err
+	}
+
+	if err := uc.tenantRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return dto.NewTenantResponse(t), nil
+}
+
+// Suspend refuses the API keys of a tenant and stops its sessions from
+// sending, keeping everything it stored.
+func (uc *TenantUseCase) Suspend(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	return uc.setStatus(ctx, tenantID, (*tenant.Tenant).Suspend)
+}
+
+func (uc *TenantUseCase) Activate(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	return uc.setStatus(ctx, tenantID, (*tenant.Tenant).Activate)
+}
+
+func (uc *TenantUseCase) setStatus(ctx context.Context, tenantID string, change func(*tenant.Tenant)) (*dto.TenantResponse, error) {
+	t, err := uc.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.IsDefault() {
+		return nil, shared.ErrDefaultTenant
+	}
+
+	change(t)
+
+	if err := uc.tenantRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return dto.NewTenantResponse(t), nil
+}
+
+// Delete removes a tenant once its sessions are gone. Its API keys and
+// usage go with it.
+func (uc *TenantUseCase) Delete(ctx context.Context, tenantID string) error {
+	t, err := uc.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if t.IsDefault() {
+		return shared.ErrDefaultTenant
+	}
+
+	sessions, err := uc.tenantRepo.CountSessions(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	if sessions > 0 {
+		return shared.ErrTenantHasSessions
+	}
+
+	return uc.tenantRepo.Delete(ctx, t.ID)
+}
+
+func (uc *TenantUseCase) CheckSession(ctx context.Context, sessionID string) error {
+	tenantID := shared.TenantFromContext(ctx)
+	if tenantID == "" {
+		return nil
+	}
+
+	owner, err := uc.tenantRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, shared.ErrTenantNotFound) {
+			return shared.ErrSessionNotFound
+		}
+
+		return fmt.Errorf("failed to get session tenant: %w", err)
+	}
+
+	if owner.ID != tenantID {
+		return shared.ErrSessionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package tenant
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/tenant"
+	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
+)
+
+type UseCases struct {
+	tenant *TenantUseCase
+	quota  *QuotaUseCase
+}
+
+func NewUseCases(tenantRepo tenant.Repository, logger output.Logger) input.TenantUseCases {
+	return &UseCases{
+		tenant: NewTenantUseCase(tenantRepo, logger),
+		quota:  NewQuotaUseCase(tenantRepo, logger),
+	}
+}
+
+func (t *UseCases) Create(ctx context.Context, req *dto.CreateTenantRequest) (*dto.TenantResponse, error) {
+	return t.tenant.Create(ctx, req)
+}
+
+func (t *UseCases) List(ctx context.Context) (*dto.TenantListResponse, error) {
+	return t.tenant.List(ctx)
+}
+
+func (t *UseCases) Get(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	return t.tenant.Get(ctx, tenantID)
+}
+
+func (t *UseCases) Update(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*dto.TenantResponse, error) {
+	return t.tenant.Update(ctx, tenantID, req)
+}
+
+func (t *UseCases) Suspend(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	return t.tenant.Suspend(ctx, tenantID)
+}
+
+func (t *UseCases) Activate(ctx context.Context, tenantID string) (*dto.TenantResponse, error) {
+	return t.tenant.Activate(ctx, tenantID)
+}
+
+func (t *UseCases) Delete(ctx context.Context, tenantID string) error {
+	return t.tenant.Delete(ctx, tenantID)
+}
+
+func (t *UseCases) CheckSession(ctx context.Context, sessionID string) error {
+	return t.tenant.CheckSession(ctx, sessionID)
+}
+
+func (t *UseCases) Consume(ctx context.Context, sessionID string) error {
+	return t.quota.Consume(ctx, sessionID)
+}
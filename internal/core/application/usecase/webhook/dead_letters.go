@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/webhook"
+)
+
+type DeadLetterUseCase struct {
+	deliveryRepo webhook.DeliveryRepository
+}
+
+func NewDeadLetterUseCase(deliveryRepo webhook.DeliveryRepository) *DeadLetterUseCase {
+	return &DeadLetterUseCase{
+		deliveryRepo: deliveryRepo,
+	}
+}
+func (uc *DeadLetterUseCase) List(ctx context.Context, request *dto.ListDeadLettersRequest) (*dto.PaginationResponse, error) {
+	if request == nil {
+		request = &dto.ListDeadLettersRequest{}
+	}
+
+	request.ApplyDefaults()
+
+	deadLetters, total, err := uc.deliveryRepo.ListDeadLetters(ctx, request.ToFilter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	items := make([]*dto.DeadLetterResponse, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		items = append(items, dto.NewDeadLetterResponse(deadLetter, false))
+	}
+
+	return &dto.PaginationResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+		HasMore: request.Offset+len(items) < total,
+	}, nil
+}
+func (uc *DeadLetterUseCase) Get(ctx context.Context, id string) (*dto.DeadLetterResponse, error) {
+	deadLetter, err := uc.deliveryRepo.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewDeadLetterResponse(deadLetter, true), nil
+}
+func (uc *DeadLetterUseCase) Replay(ctx context.Context, request *dto.ReplayDeadLettersRequest) (*dto.ReplayDeadLettersResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		replayed int
+		err      error
+	)
+
+	if len(request.IDs) > 0 {
+		replayed, err = uc.deliveryRepo.ReplayDeadLetters(ctx, request.IDs)
+	} else {
+		replayed, err = uc.deliveryRepo.ReplayDeadLettersInRange(ctx, request.ToFilter())
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay dead letters: %w", err)
+	}
+
+	return &dto.ReplayDeadLettersResponse{Replayed: replayed}, nil
+}
@@ -16,10 +16,21 @@ func NewDeleteUseCase(webhookRepo webhook.Repository) *DeleteUseCase {
 		webhookRepo: webhookRepo,
 	}
 }
-func (uc *DeleteUseCase) Execute(ctx context.Context, sessionID string) error {
-	if err := uc.webhookRepo.DeleteBySessionID(ctx, sessionID); err != nil {
+func (uc *DeleteUseCase) Execute(ctx context.Context, sessionID, webhookID string) error {
+	if _, err := getSessionWebhook(ctx, uc.webhookRepo, sessionID, webhookID); err != nil {
 		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
 
+	if err := uc.webhookRepo.Delete(ctx, webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+func (uc *DeleteUseCase) DeleteAll(ctx context.Context, sessionID string) error {
+	if err := uc.webhookRepo.DeleteBySessionID(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete webhooks: %w", err)
+	}
+
 	return nil
 }
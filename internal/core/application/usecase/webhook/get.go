@@ -17,19 +17,28 @@ func NewGetUseCase(webhookRepo webhook.Repository) *GetUseCase {
 		webhookRepo: webhookRepo,
 	}
 }
-func (uc *GetUseCase) Execute(ctx context.Context, sessionID string) (*dto.WebhookResponse, error) {
-	wh, err := uc.webhookRepo.GetBySessionID(ctx, sessionID)
+func (uc *GetUseCase) Execute(ctx context.Context, sessionID, webhookID string) (*dto.WebhookResponse, error) {
+	wh, err := getSessionWebhook(ctx, uc.webhookRepo, sessionID, webhookID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get webhook: %w", err)
 	}
 
-	return &dto.WebhookResponse{
-		ID:        wh.ID,
-		SessionID: wh.SessionID,
-		URL:       wh.URL,
-		Events:    wh.Events,
-		Enabled:   wh.Enabled,
-		CreatedAt: wh.CreatedAt,
-		UpdatedAt: wh.UpdatedAt,
+	return dto.NewWebhookResponse(wh), nil
+}
+func (uc *GetUseCase) List(ctx context.Context, sessionID string) (*dto.ListWebhooksResponse, error) {
+	webhooks, err := uc.webhookRepo.ListBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	responses := make([]*dto.WebhookResponse, 0, len(webhooks))
+	for _, wh := range webhooks {
+		responses = append(responses, dto.NewWebhookResponse(wh))
+	}
+
+	return &dto.ListWebhooksResponse{
+		SessionID: sessionID,
+		Webhooks:  responses,
+		Total:     len(responses),
 	}, nil
 }
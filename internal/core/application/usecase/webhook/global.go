@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
+)
+
+// GlobalUseCase manages the instance-wide webhook that receives the events of
+// every session.
+type GlobalUseCase struct {
+	webhookRepo    webhook.Repository
+	webhookService *webhook.Service
+	urlPolicy      output.OutboundPolicy
+}
+
+func NewGlobalUseCase(
+	webhookRepo webhook.Repository,
+	webhookService *webhook.Service,
+	urlPolicy output.OutboundPolicy,
+) *GlobalUseCase {
+	return &GlobalUseCase{
+		webhookRepo:    webhookRepo,
+		webhookService: webhookService,
+		urlPolicy:      urlPolicy,
+	}
+}
+func (uc *GlobalUseCase) Get(ctx context.Context) (*dto.WebhookResponse, error) {
+	wh, err := uc.webhookRepo.GetGlobal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global webhook: %w", err)
+	}
+
+	return dto.NewWebhookResponse(wh), nil
+}
+
+// Set creates the global webhook or replaces its configuration. An existing
+// secret is kept when the request does not carry one.
+func (uc *GlobalUseCase) Set(ctx context.Context, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := validateWebhookRequest(ctx, uc.webhookService, uc.urlPolicy, request); err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.webhookRepo.GetGlobal(ctx)
+	if err != nil && !errors.Is(err, shared.ErrWebhookNotFound) {
+		return nil, fmt.Errorf("failed to check global webhook: %w", err)
+	}
+
+	if existing != nil {
+		applyWebhookRequest(existing, request)
+
+		if err := uc.webhookRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to update global webhook: %w", err)
+		}
+
+		return dto.NewWebhookResponse(existing), nil
+	}
+
+	wh, err := newWebhook("", request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.webhookRepo.Create(ctx, wh); err != nil {
+		return nil, fmt.Errorf("failed to create global webhook: %w", err)
+	}
+
+	return dto.NewWebhookResponse(wh), nil
+}
+func (uc *GlobalUseCase) Delete(ctx context.Context) error {
+	wh, err := uc.webhookRepo.GetGlobal(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete global webhook: %w", err)
+	}
+
+	if err := uc.webhookRepo.Delete(ctx, wh.ID); err != nil {
+		return fmt.Errorf("failed to delete global webhook: %w", err)
+	}
+
+	return nil
+}
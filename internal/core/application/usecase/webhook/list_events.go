@@ -33,3 +33,12 @@ func (uc *ListEventsUseCase) Execute(ctx context.Context) (*dto.ListEventsRespon
 		AllEvents:  allEvents,
 	}, nil
 }
+func (uc *ListEventsUseCase) Schemas(ctx context.Context) (*dto.EventSchemasResponse, error) {
+	return &dto.EventSchemasResponse{
+		SchemaVersion: webhook.SchemaVersion,
+		Schemas:       uc.webhookService.GetEventSchemas(),
+	}, nil
+}
+func (uc *ListEventsUseCase) Schema(ctx context.Context, eventType string) (map[string]interface{}, error) {
+	return uc.webhookService.GetEventSchema(eventType)
+}
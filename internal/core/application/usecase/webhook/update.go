@@ -6,64 +6,46 @@ import (
 
 	"zpwoot/internal/core/application/dto"
 	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
 )
 
 type UpdateUseCase struct {
 	webhookRepo    webhook.Repository
 	webhookService *webhook.Service
+	urlPolicy      output.OutboundPolicy
 }
 
 func NewUpdateUseCase(
 	webhookRepo webhook.Repository,
 	webhookService *webhook.Service,
+	urlPolicy output.OutboundPolicy,
 ) *UpdateUseCase {
 	return &UpdateUseCase{
 		webhookRepo:    webhookRepo,
 		webhookService: webhookService,
+		urlPolicy:      urlPolicy,
 	}
 }
 func (uc *UpdateUseCase) Execute(
 	ctx context.Context,
 	sessionID string,
+	webhookID string,
 	request *dto.CreateWebhookRequest,
 ) (*dto.WebhookResponse, error) {
-	if err := uc.webhookService.ValidateURL(request.URL); err != nil {
-		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	if err := validateWebhookRequest(ctx, uc.webhookService, uc.urlPolicy, request); err != nil {
+		return nil, err
 	}
 
-	if err := uc.webhookService.ValidateEvents(request.Events); err != nil {
-		return nil, fmt.Errorf("invalid events: %w", err)
-	}
-
-	if request.Secret != nil && *request.Secret != "" {
-		if err := uc.webhookService.ValidateSecret(*request.Secret); err != nil {
-			return nil, fmt.Errorf("invalid secret: %w", err)
-		}
-	}
-
-	existingWebhook, err := uc.webhookRepo.GetBySessionID(ctx, sessionID)
+	existingWebhook, err := getSessionWebhook(ctx, uc.webhookRepo, sessionID, webhookID)
 	if err != nil {
-		return nil, fmt.Errorf("webhook not found for session %s: %w", sessionID, err)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
 	}
 
-	existingWebhook.UpdateURL(request.URL)
-	existingWebhook.UpdateEvents(request.Events)
-
-	if request.Secret != nil && *request.Secret != "" {
-		existingWebhook.SetSecret(*request.Secret)
-	}
+	applyWebhookRequest(existingWebhook, request)
 
 	if err := uc.webhookRepo.Update(ctx, existingWebhook); err != nil {
 		return nil, fmt.Errorf("failed to update webhook: %w", err)
 	}
 
-	return &dto.WebhookResponse{
-		ID:        existingWebhook.ID,
-		SessionID: existingWebhook.SessionID,
-		URL:       existingWebhook.URL,
-		Events:    existingWebhook.Events,
-		Enabled:   existingWebhook.Enabled,
-		CreatedAt: existingWebhook.CreatedAt,
-		UpdatedAt: existingWebhook.UpdatedAt,
-	}, nil
+	return dto.NewWebhookResponse(existingWebhook), nil
 }
@@ -6,20 +6,26 @@ import (
 
 	"zpwoot/internal/core/application/dto"
 	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
 )
 
+// UpsertUseCase updates the session webhook registered for the request URL,
+// or registers a new one when the URL is not known yet.
 type UpsertUseCase struct {
 	webhookRepo    webhook.Repository
 	webhookService *webhook.Service
+	urlPolicy      output.OutboundPolicy
 }
 
 func NewUpsertUseCase(
 	webhookRepo webhook.Repository,
 	webhookService *webhook.Service,
+	urlPolicy output.OutboundPolicy,
 ) *UpsertUseCase {
 	return &UpsertUseCase{
 		webhookRepo:    webhookRepo,
 		webhookService: webhookService,
+		urlPolicy:      urlPolicy,
 	}
 }
 func (uc *UpsertUseCase) Execute(
@@ -27,80 +33,37 @@ func (uc *UpsertUseCase) Execute(
 	sessionID string,
 	request *dto.CreateWebhookRequest,
 ) (*dto.WebhookResponse, error) {
-	if err := uc.webhookService.ValidateURL(request.URL); err != nil {
-		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	if err := validateWebhookRequest(ctx, uc.webhookService, uc.urlPolicy, request); err != nil {
+		return nil, err
 	}
 
-	if err := uc.webhookService.ValidateEvents(request.Events); err != nil {
-		return nil, fmt.Errorf("invalid events: %w", err)
+	webhooks, err := uc.webhookRepo.ListBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing webhooks: %w", err)
 	}
 
-	if request.Secret != nil && *request.Secret != "" {
-		if err := uc.webhookService.ValidateSecret(*request.Secret); err != nil {
-			return nil, fmt.Errorf("invalid secret: %w", err)
+	for _, existing := range webhooks {
+		if existing.URL != request.URL {
+			continue
 		}
-	}
 
-	existingWebhook, err := uc.webhookRepo.GetBySessionID(ctx, sessionID)
-	if err != nil && err.Error() != "webhook not found" {
-		return nil, fmt.Errorf("failed to check existing webhook: %w", err)
-	}
+		applyWebhookRequest(existing, request)
 
-	var wh *webhook.Webhook
+		if err := uc.webhookRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to update webhook: %w", err)
+		}
 
-	if existingWebhook != nil {
-		wh, err = uc.updateExistingWebhook(ctx, existingWebhook, request)
-	} else {
-		wh, err = uc.createNewWebhook(ctx, sessionID, request)
+		return dto.NewWebhookResponse(existing), nil
 	}
 
+	wh, err := newWebhook(sessionID, request)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.WebhookResponse{
-		ID:        wh.ID,
-		SessionID: wh.SessionID,
-		URL:       wh.URL,
-		Events:    wh.Events,
-		Enabled:   wh.Enabled,
-		CreatedAt: wh.CreatedAt,
-		UpdatedAt: wh.UpdatedAt,
-	}, nil
-}
-
-func (uc *UpsertUseCase) updateExistingWebhook(ctx context.Context, existingWebhook *webhook.Webhook, request *dto.CreateWebhookRequest) (*webhook.Webhook, error) {
-	existingWebhook.UpdateURL(request.URL)
-	existingWebhook.UpdateEvents(request.Events)
-
-	if request.Secret != nil && *request.Secret != "" {
-		existingWebhook.SetSecret(*request.Secret)
-	}
-
-	if err := uc.webhookRepo.Update(ctx, existingWebhook); err != nil {
-		return nil, fmt.Errorf("failed to update webhook: %w", err)
-	}
-
-	return existingWebhook, nil
-}
-
-func (uc *UpsertUseCase) createNewWebhook(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*webhook.Webhook, error) {
-	wh := webhook.NewWebhook(sessionID, request.URL, request.Events)
-
-	secret := request.Secret
-	if secret == nil || *secret == "" {
-		generated, err := generateSecretKey()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate secret: %w", err)
-		}
-		secret = &generated
-	}
-
-	wh.SetSecret(*secret)
-
 	if err := uc.webhookRepo.Create(ctx, wh); err != nil {
 		return nil, fmt.Errorf("failed to create webhook: %w", err)
 	}
 
-	return wh, nil
+	return dto.NewWebhookResponse(wh), nil
 }
@@ -6,45 +6,82 @@ import (
 	"zpwoot/internal/core/application/dto"
 	"zpwoot/internal/core/domain/webhook"
 	"zpwoot/internal/core/ports/input"
+	"zpwoot/internal/core/ports/output"
 )
 
 type WebhookUseCases struct {
-	create     *CreateUseCase
-	update     *UpdateUseCase
-	upsert     *UpsertUseCase
-	get        *GetUseCase
-	delete     *DeleteUseCase
-	listEvents *ListEventsUseCase
+	create      *CreateUseCase
+	update      *UpdateUseCase
+	upsert      *UpsertUseCase
+	get         *GetUseCase
+	delete      *DeleteUseCase
+	global      *GlobalUseCase
+	listEvents  *ListEventsUseCase
+	deadLetters *DeadLetterUseCase
 }
 
 func NewWebhookUseCases(
 	webhookRepo webhook.Repository,
 	webhookService *webhook.Service,
+	deliveryRepo webhook.DeliveryRepository,
+	urlPolicy output.OutboundPolicy,
 ) input.WebhookUseCases {
 	return &WebhookUseCases{
-		create:     NewCreateUseCase(webhookRepo, webhookService),
-		update:     NewUpdateUseCase(webhookRepo, webhookService),
-		upsert:     NewUpsertUseCase(webhookRepo, webhookService),
-		get:        NewGetUseCase(webhookRepo),
-		delete:     NewDeleteUseCase(webhookRepo),
-		listEvents: NewListEventsUseCase(webhookService),
+		create:      NewCreateUseCase(webhookRepo, webhookService, urlPolicy),
+		update:      NewUpdateUseCase(webhookRepo, webhookService, urlPolicy),
+		upsert:      NewUpsertUseCase(webhookRepo, webhookService, urlPolicy),
+		get:         NewGetUseCase(webhookRepo),
+		delete:      NewDeleteUseCase(webhookRepo),
+		global:      NewGlobalUseCase(webhookRepo, webhookService, urlPolicy),
+		listEvents:  NewListEventsUseCase(webhookService),
+		deadLetters: NewDeadLetterUseCase(deliveryRepo),
 	}
 }
 func (w *WebhookUseCases) Create(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
 	return w.create.Execute(ctx, sessionID, request)
 }
-func (w *WebhookUseCases) Update(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
-	return w.update.Execute(ctx, sessionID, request)
-}
 func (w *WebhookUseCases) Upsert(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
 	return w.upsert.Execute(ctx, sessionID, request)
 }
-func (w *WebhookUseCases) Get(ctx context.Context, sessionID string) (*dto.WebhookResponse, error) {
-	return w.get.Execute(ctx, sessionID)
+func (w *WebhookUseCases) List(ctx context.Context, sessionID string) (*dto.ListWebhooksResponse, error) {
+	return w.get.List(ctx, sessionID)
+}
+func (w *WebhookUseCases) Get(ctx context.Context, sessionID, webhookID string) (*dto.WebhookResponse, error) {
+	return w.get.Execute(ctx, sessionID, webhookID)
+}
+func (w *WebhookUseCases) Update(ctx context.Context, sessionID, webhookID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	return w.update.Execute(ctx, sessionID, webhookID, request)
+}
+func (w *WebhookUseCases) Delete(ctx context.Context, sessionID, webhookID string) error {
+	return w.delete.Execute(ctx, sessionID, webhookID)
+}
+func (w *WebhookUseCases) DeleteAll(ctx context.Context, sessionID string) error {
+	return w.delete.DeleteAll(ctx, sessionID)
+}
+func (w *WebhookUseCases) GetGlobal(ctx context.Context) (*dto.WebhookResponse, error) {
+	return w.global.Get(ctx)
 }
-func (w *WebhookUseCases) Delete(ctx context.Context, sessionID string) error {
-	return w.delete.Execute(ctx, sessionID)
+func (w *WebhookUseCases) SetGlobal(ctx context.Context, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	return w.global.Set(ctx, request)
+}
+func (w *WebhookUseCases) DeleteGlobal(ctx context.Context) error {
+	return w.global.Delete(ctx)
 }
 func (w *WebhookUseCases) ListEvents(ctx context.Context) (*dto.ListEventsResponse, error) {
 	return w.listEvents.Execute(ctx)
 }
+func (w *WebhookUseCases) GetEventSchemas(ctx context.Context) (*dto.EventSchemasResponse, error) {
+	return w.listEvents.Schemas(ctx)
+}
+func (w *WebhookUseCases) GetEventSchema(ctx context.Context, eventType string) (map[string]interface{}, error) {
+	return w.listEvents.Schema(ctx, eventType)
+}
+func (w *WebhookUseCases) ListDeadLetters(ctx context.Context, request *dto.ListDeadLettersRequest) (*dto.PaginationResponse, error) {
+	return w.deadLetters.List(ctx, request)
+}
+func (w *WebhookUseCases) GetDeadLetter(ctx context.Context, id string) (*dto.DeadLetterResponse, error) {
+	return w.deadLetters.Get(ctx, id)
+}
+func (w *WebhookUseCases) ReplayDeadLetters(ctx context.Context, request *dto.ReplayDeadLettersRequest) (*dto.ReplayDeadLettersResponse, error) {
+	return w.deadLetters.Replay(ctx, request)
+}
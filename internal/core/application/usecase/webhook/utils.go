@@ -1,8 +1,15 @@
 package webhook
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/shared"
+	"zpwoot/internal/core/domain/webhook"
+	"zpwoot/internal/core/ports/output"
 )
 
 func generateSecretKey() (string, error) {
@@ -13,3 +20,91 @@ func generateSecretKey() (string, error) {
 
 	return hex.EncodeToString(bytes), nil
 }
+func validateWebhookRequest(ctx context.Context, webhookService *webhook.Service, urlPolicy output.OutboundPolicy, request *dto.CreateWebhookRequest) error {
+	if err := webhookService.ValidateURL(request.URL); err != nil {
+		return dto.NewValidationError("url", err.Error())
+	}
+
+	if err := urlPolicy.CheckURL(ctx, request.URL); err != nil {
+		return dto.NewValidationError("url", err.Error())
+	}
+
+	if err := webhookService.ValidateEvents(request.Events); err != nil {
+		return dto.NewValidationError("events", err.Error())
+	}
+
+	if err := webhookService.ValidateFormat(request.Format); err != nil {
+		return dto.NewValidationError("format", err.Error())
+	}
+
+	if request.Secret != nil && *request.Secret != "" {
+		if err := webhookService.ValidateSecret(*request.Secret); err != nil {
+			return dto.NewValidationError("secret", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// newWebhook builds a webhook from a request, generating a signing
+// secret when none is given.
+func newWebhook(sessionID string, request *dto.CreateWebhookRequest) (*webhook.Webhook, error) {
+	wh := webhook.NewWebhook(sessionID, request.URL, request.Events)
+
+	secret := request.Secret
+	if secret == nil || *secret == "" {
+		generated, err := generateSecretKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+
+		secret = &generated
+	}
+
+	wh.SetSecret(*secret)
+
+	if request.Enabled != nil && !*request.Enabled {
+		wh.Disable()
+	}
+
+	if request.Format != "" {
+		wh.SetFormat(request.Format)
+	}
+
+	return wh, nil
+}
+func applyWebhookRequest(wh *webhook.Webhook, request *dto.CreateWebhookRequest) {
+	wh.UpdateURL(request.URL)
+	wh.UpdateEvents(request.Events)
+
+	if request.Secret != nil && *request.Secret != "" {
+		wh.SetSecret(*request.Secret)
+	}
+
+	if request.Enabled != nil {
+		if *request.Enabled {
+			wh.Enable()
+		} else {
+			wh.Disable()
+		}
+	}
+
+	if request.Format != "" {
+		wh.SetFormat(request.Format)
+	}
+}
+
+// getSessionWebhook loads a webhook by ID and reports it as missing when it
+// belongs to another session.
+func getSessionWebhook(ctx context.Context, webhookRepo webhook.Repository, sessionID, webhookID string) (*webhook.Webhook, error) {
+	wh, err := webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wh.SessionID != sessionID {
+		return nil, shared.ErrWebhookNotFound
+	}
+
+	return wh, nil
+}
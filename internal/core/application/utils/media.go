@@ -15,9 +15,28 @@ import (
 )
 
 type MediaProcessor struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	maxDownload int64
+	localRoot   string
+	limits      MediaLimits
 }
 
+// MediaProcessorConfig controls where a MediaProcessor may read media from.
+type MediaProcessorConfig struct {
+	// HTTPClient downloads media URLs. It should enforce the outbound
+	// request policy, since the URLs come from API callers.
+	HTTPClient *http.Client
+	// MaxDownloadSize bounds media downloaded from URLs, in bytes; 0 means
+	// no limit.
+	MaxDownloadSize int64
+	// LocalRoot is the directory local file paths are read from, as an
+	// absolute path with symlinks resolved. Empty disables local files.
+	LocalRoot string
+	Limits    MediaLimits
+}
+
+// NewMediaProcessor returns a MediaProcessor that downloads URLs with a
+// plain client and does not read local files.
 func NewMediaProcessor() *MediaProcessor {
 	return &MediaProcessor{
 		httpClient: &http.Client{
@@ -26,6 +45,20 @@ func NewMediaProcessor() *MediaProcessor {
 	}
 }
 
+func NewMediaProcessorWithConfig(config MediaProcessorConfig) *MediaProcessor {
+	mp := NewMediaProcessor()
+
+	if config.HTTPClient != nil {
+		mp.httpClient = config.HTTPClient
+	}
+
+	mp.maxDownload = config.MaxDownloadSize
+	mp.localRoot = config.LocalRoot
+	mp.limits = config.Limits
+
+	return mp
+}
+
 func (mp *MediaProcessor) ProcessMedia(file, mimeType, fileName string) (*output.MediaData, error) {
 	var data []byte
 
@@ -79,13 +112,38 @@ func (mp *MediaProcessor) isURL(input string) bool {
 }
 func (mp *MediaProcessor) isFilePath(input string) bool {
 	if strings.Contains(input, "/") || strings.Contains(input, "\\") {
-		if _, err := os.Stat(input); err == nil {
+		if _, err := mp.localPath(input); err == nil {
 			return true
 		}
 	}
 
 	return false
 }
+
+// localPath resolves a local file path below the local root, following
+// symlinks, and refuses paths that end up outside of it.
+func (mp *MediaProcessor) localPath(input string) (string, error) {
+	if mp.localRoot == "" {
+		return "", fmt.Errorf("local files are disabled")
+	}
+
+	path := input
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(mp.localRoot, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("file not found or inaccessible: %w", err)
+	}
+
+	rel, err := filepath.Rel(mp.localRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file is outside of the media root")
+	}
+
+	return resolved, nil
+}
 func (mp *MediaProcessor) isBase64(input string) bool {
 	if strings.Contains(input, ",") {
 		parts := strings.Split(input, ",")
@@ -109,11 +167,24 @@ func (mp *MediaProcessor) downloadFromURL(url string) ([]byte, string, string, e
 		return nil, "", "", fmt.Errorf("HTTP error: %d", resp.StatusCode)
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	if mp.maxDownload > 0 && resp.ContentLength > mp.maxDownload {
+		return nil, "", "", mp.downloadTooLarge()
+	}
+
+	body := io.Reader(resp.Body)
+	if mp.maxDownload > 0 {
+		body = io.LimitReader(resp.Body, mp.maxDownload+1)
+	}
+
+	data, err := io.ReadAll(body)
 	if err != nil {
 		return nil, "", "", err
 	}
 
+	if mp.maxDownload > 0 && int64(len(data)) > mp.maxDownload {
+		return nil, "", "", mp.downloadTooLarge()
+	}
+
 	mimeType := resp.Header.Get("Content-Type")
 	if mimeType != "" {
 		if idx := strings.Index(mimeType, ";"); idx != -1 {
@@ -130,10 +201,13 @@ func (mp *MediaProcessor) downloadFromURL(url string) ([]byte, string, string, e
 
 	return data, mimeType, fileName, nil
 }
+func (mp *MediaProcessor) downloadTooLarge() error {
+	return fmt.Errorf("%w: downloads are limited to %d MB", ErrMediaTooLarge, mp.maxDownload>>20)
+}
 func (mp *MediaProcessor) readFromFile(filePath string) ([]byte, string, string, error) {
-	cleanPath := filepath.Clean(filePath)
-	if strings.Contains(cleanPath, "..") {
-		return nil, "", "", fmt.Errorf("invalid file path: directory traversal not allowed")
+	cleanPath, err := mp.localPath(filePath)
+	if err != nil {
+		return nil, "", "", err
 	}
 
 	fileInfo, err := os.Stat(cleanPath)
@@ -0,0 +1,245 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zpwoot/internal/core/ports/output"
+)
+
+// MediaKind is the kind of message a media file is sent as.
+type MediaKind string
+
+const (
+	MediaKindImage    MediaKind = "image"
+	MediaKindAudio    MediaKind = "audio"
+	MediaKindVideo    MediaKind = "video"
+	MediaKindDocument MediaKind = "document"
+	MediaKindSticker  MediaKind = "sticker"
+)
+
+// MediaLimits is the largest file accepted for each kind of media, in
+// bytes. Kinds without a limit, or with 0, accept files of any size.
+type MediaLimits map[MediaKind]int64
+
+var (
+	ErrMediaTooLarge    = errors.New("media exceeds the maximum size")
+	ErrMediaTypeInvalid = errors.New("media content does not match the media type")
+)
+
+// sniffLength is how much of a file is read to detect its type, the same
+// as http.DetectContentType.
+const sniffLength = 512
+
+// ProcessUpload streams an uploaded file of the given kind to a temporary
+// file, so large files are never held in memory. The MIME type is sniffed
+// from the content; declaredMime, given by the client, is only used for
+// documents whose content is not recognized. The returned media must be
+// released with Release.
+func (mp *MediaProcessor) ProcessUpload(kind MediaKind, r io.Reader, declaredMime, fileName string) (*output.MediaData, error) {
+	file, err := os.CreateTemp("", "zpwoot-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	upload := &tempFile{File: file}
+
+	media, err := mp.spoolUpload(kind, upload, r, declaredMime, fileName)
+	if err != nil {
+		_ = upload.Close()
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (mp *MediaProcessor) spoolUpload(kind MediaKind, file *tempFile, r io.Reader, declaredMime, fileName string) (*output.MediaData, error) {
+	limit := mp.limits[kind]
+
+	src := r
+	if limit > 0 {
+		src = io.LimitReader(r, limit+1)
+	}
+
+	size, err := io.Copy(file, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	if limit > 0 && size > limit {
+		return nil, mp.tooLarge(kind)
+	}
+
+	if size == 0 {
+		return nil, errors.New("uploaded file is empty")
+	}
+
+	head := make([]byte, sniffLength)
+
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	mimeType, err := resolveMimeType(kind, head[:n], declaredMime, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind upload: %w", err)
+	}
+
+	return &output.MediaData{
+		MimeType: mimeType,
+		File:     file,
+		FileName: fileName,
+	}, nil
+}
+
+// Verify checks media held in memory against the limit of its kind and
+// replaces its MIME type with the one sniffed from the content.
+func (mp *MediaProcessor) Verify(kind MediaKind, media *output.MediaData) error {
+	if limit := mp.limits[kind]; limit > 0 && int64(len(media.Data)) > limit {
+		return mp.tooLarge(kind)
+	}
+
+	head := media.Data
+	if len(head) > sniffLength {
+		head = head[:sniffLength]
+	}
+
+	mimeType, err := resolveMimeType(kind, head, media.MimeType, media.FileName)
+	if err != nil {
+		return err
+	}
+
+	media.MimeType = mimeType
+
+	return nil
+}
+
+// Release removes the temporary file of uploaded media. It does nothing
+// for media held in memory or nil media.
+func (mp *MediaProcessor) Release(media *output.MediaData) {
+	if media != nil && media.File != nil {
+		_ = media.File.Close()
+	}
+}
+
+func (mp *MediaProcessor) tooLarge(kind MediaKind) error {
+	return fmt.Errorf("%w: %s files are limited to %d MB", ErrMediaTooLarge, kind, mp.limits[kind]>>20)
+}
+
+// resolveMimeType returns the MIME type media of kind is sent with. Images,
+// stickers, videos and audio must be recognized as such from their
+// content. Documents may be anything, so their content is trusted when it
+// is recognized, and their extension or declared type otherwise.
+func resolveMimeType(kind MediaKind, head []byte, declaredMime, fileName string) (string, error) {
+	sniffed := SniffMimeType(head)
+
+	var family string
+
+	switch kind {
+	case MediaKindImage, MediaKindSticker:
+		family = "image/"
+	case MediaKindVideo:
+		family = "video/"
+	case MediaKindAudio:
+		family = "audio/"
+	default:
+		if !isGenericMimeType(sniffed) {
+			return sniffed, nil
+		}
+
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			return byExt, nil
+		}
+
+		if declaredMime != "" {
+			return declaredMime, nil
+		}
+
+		return sniffed, nil
+	}
+
+	if !strings.HasPrefix(sniffed, family) {
+		return "", fmt.Errorf("%w: expected %s content, got %s", ErrMediaTypeInvalid, kind, sniffed)
+	}
+
+	return sniffed, nil
+}
+
+// isGenericMimeType reports whether a sniffed type says too little about a
+// file to name it: unknown binaries, plain text and ZIP archives, which is
+// what Office and OpenDocument files look like.
+func isGenericMimeType(mimeType string) bool {
+	switch strings.TrimSpace(strings.Split(mimeType, ";")[0]) {
+	case "application/octet-stream", "text/plain", "application/zip":
+		return true
+	default:
+		return false
+	}
+}
+
+// SniffMimeType detects the MIME type of a file from its first bytes. It
+// extends http.DetectContentType with the audio and video containers
+// WhatsApp uses, which that function reports as generic types.
+func SniffMimeType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("OggS")):
+		if bytes.Contains(head, []byte("OpusHead")) {
+			return "audio/ogg; codecs=opus"
+		}
+
+		return "audio/ogg"
+	case bytes.HasPrefix(head, []byte("#!AMR")):
+		return "audio/amr"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		return sniffISOMedia(string(head[8:12]))
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xF6 == 0xF0:
+		return "audio/aac"
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0:
+		return "audio/mpeg"
+	}
+
+	return http.DetectContentType(head)
+}
+
+// sniffISOMedia names an ISO base media file (MP4 and relatives) from its
+// major brand.
+func sniffISOMedia(brand string) string {
+	switch {
+	case strings.HasPrefix(brand, "M4A"), strings.HasPrefix(brand, "M4B"):
+		return "audio/mp4"
+	case strings.HasPrefix(brand, "3gp"):
+		return "video/3gpp"
+	case brand == "qt  ":
+		return "video/quicktime"
+	case brand == "avif", brand == "avis":
+		return "image/avif"
+	case brand == "heic", brand == "heix", brand == "mif1":
+		return "image/heic"
+	default:
+		return "video/mp4"
+	}
+}
+
+// tempFile is a temporary file removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+
+	return err
+}
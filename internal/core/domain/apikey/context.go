@@ -0,0 +1,16 @@
+package apikey
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the key a request was made with.
+func NewContext(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key a request was made with, or nil.
+func FromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(contextKey{}).(*APIKey)
+	return key
+}
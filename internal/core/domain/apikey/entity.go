@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Permission string
+
+const (
+	// PermissionSend sends messages and acts on chats, groups, communities
+	// and newsletters.
+	PermissionSend Permission = "send"
+	// PermissionRead reads chats, messages, media, contacts and groups.
+	PermissionRead Permission = "read"
+	// PermissionSessions creates, connects, pairs and deletes sessions.
+	PermissionSessions Permission = "sessions"
+	// PermissionWebhooks configures webhooks and the Chatwoot integration.
+	PermissionWebhooks Permission = "webhooks"
+	// PermissionAdmin grants every permission on every session, including
+	// managing API keys.
+	PermissionAdmin Permission = "admin"
+)
+
+func ValidPermissions() []Permission {
+	return []Permission{PermissionSend, PermissionRead, PermissionSessions, PermissionWebhooks, PermissionAdmin}
+}
+
+const (
+	// tokenPrefix marks keys issued by zpwoot, so they are recognized in
+	// logs and by secret scanners.
+	tokenPrefix = "zpk_"
+	// displayLength is how much of a key is kept in the clear to tell keys
+	// apart.
+	displayLength = len(tokenPrefix) + 8
+)
+
+// APIKey grants access to the sessions in SessionIDs, or to all sessions
+// when it is empty, with Permissions. Keys of a tenant only ever reach the
+// sessions of TenantID; keys without one belong to the server operator.
+// Only the hash of the key is kept; the key itself is shown once, when it
+// is created or rotated.
+type APIKey struct {
+	ID          string
+	TenantID    string
+	Name        string
+	Prefix      string
+	Hash        string
+	SessionIDs  []string
+	Permissions []Permission
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewAPIKey creates a key and returns it with its secret, which is not
+// stored anywhere.
+func NewAPIKey(name string, sessionIDs []string, permissions []Permission, expiresAt *time.Time) (*APIKey, string, error) {
+	now := time.Now()
+
+	k := &APIKey{
+		ID:          uuid.New().String(),
+		Name:        name,
+		SessionIDs:  sessionIDs,
+		Permissions: permissions,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	token, err := k.Rotate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return k, token, nil
+}
+
+// Superuser is the key ZP_API_KEY stands for: admin on every session.
+func Superuser() *APIKey {
+	return &APIKey{
+		ID:          "global",
+		Name:        "ZP_API_KEY",
+		Permissions: []Permission{PermissionAdmin},
+	}
+}
+
+// Rotate replaces the secret of the key and returns the new one. The old
+// secret stops working once the key is saved.
+func (k *APIKey) Rotate() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	token := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	k.Prefix = token[:displayLength]
+	k.Hash = Hash(token)
+	k.UpdatedAt = time.Now()
+
+	return token, nil
+}
+
+func (k *APIKey) Revoke() {
+	now := time.Now()
+
+	k.RevokedAt = &now
+	k.UpdatedAt = now
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// IsActive reports whether the key may be used at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+func (k *APIKey) IsAdmin() bool {
+	return slices.Contains(k.Permissions, PermissionAdmin)
+}
+
+// HasPermission reports whether the key holds permission, which admin keys
+// always do.
+func (k *APIKey) HasPermission(permission Permission) bool {
+	return k.IsAdmin() || slices.Contains(k.Permissions, permission)
+}
+
+// CoversAllSessions reports whether the key is not limited to some
+// sessions.
+func (k *APIKey) CoversAllSessions() bool {
+	return len(k.SessionIDs) == 0
+}
+
+func (k *APIKey) CoversSession(sessionID string) bool {
+	return k.CoversAllSessions() || slices.Contains(k.SessionIDs, sessionID)
+}
+
+// Allows reports whether the key may use permission on the session.
+func (k *APIKey) Allows(sessionID string, permission Permission) bool {
+	return k.HasPermission(permission) && k.CoversSession(sessionID)
+}
+
+// Hash is how a key is looked up. Keys are random, so a plain SHA-256 is
+// enough to keep a database leak from revealing them.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
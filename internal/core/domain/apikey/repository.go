@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id string) (*APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+	// List returns every key, revoked ones included, newest first.
+	List(ctx context.Context) ([]*APIKey, error)
+	// Save stores the secret and revocation of a key.
+	Save(ctx context.Context, key *APIKey) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
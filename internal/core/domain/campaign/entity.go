@@ -0,0 +1,149 @@
+package campaign
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusPaused    Status = "paused"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+)
+
+type RecipientStatus string
+
+const (
+	RecipientQueued    RecipientStatus = "queued"
+	RecipientSent      RecipientStatus = "sent"
+	RecipientDelivered RecipientStatus = "delivered"
+	RecipientRead      RecipientStatus = "read"
+	RecipientFailed    RecipientStatus = "failed"
+)
+
+func ValidRecipientStatuses() []RecipientStatus {
+	return []RecipientStatus{RecipientQueued, RecipientSent, RecipientDelivered, RecipientRead, RecipientFailed}
+}
+
+// Campaign sends Text to every queued recipient, one message per Interval.
+// The runner picks the campaign up whenever it is running and NextSendAt is
+// reached.
+type Campaign struct {
+	ID          string
+	SessionID   string
+	Name        string
+	Text        string
+	Status      Status
+	Interval    time.Duration
+	NextSendAt  time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewCampaign(sessionID, name, text string, interval time.Duration) *Campaign {
+	now := time.Now()
+
+	return &Campaign{
+		ID:         uuid.New().String(),
+		SessionID:  sessionID,
+		Name:       name,
+		Text:       text,
+		Status:     StatusRunning,
+		Interval:   interval,
+		NextSendAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// IsFinished reports whether the campaign can no longer send.
+func (c *Campaign) IsFinished() bool {
+	return c.Status == StatusCompleted || c.Status == StatusCancelled
+}
+
+func (c *Campaign) Pause() {
+	c.Status = StatusPaused
+	c.UpdatedAt = time.Now()
+}
+
+func (c *Campaign) Resume() {
+	c.Status = StatusRunning
+	c.NextSendAt = time.Now()
+	c.UpdatedAt = time.Now()
+}
+
+func (c *Campaign) Cancel() {
+	c.Status = StatusCancelled
+	c.UpdatedAt = time.Now()
+}
+
+func (c *Campaign) Complete() {
+	now := time.Now()
+
+	c.Status = StatusCompleted
+	c.CompletedAt = &now
+	c.UpdatedAt = now
+}
+
+// Recipient is one phone number of a campaign. Its status moves forward from
+// queued to sent, delivered and read, or ends as failed.
+type Recipient struct {
+	ID          int64
+	CampaignID  string
+	Phone       string
+	JID         string
+	Variables   map[string]string
+	Status      RecipientStatus
+	Attempts    int
+	MessageID   *string
+	Error       *string
+	SentAt      *time.Time
+	DeliveredAt *time.Time
+	ReadAt      *time.Time
+	UpdatedAt   time.Time
+}
+
+func NewRecipient(phone string, variables map[string]string) *Recipient {
+	if variables == nil {
+		variables = map[string]string{}
+	}
+
+	return &Recipient{
+		Phone:     phone,
+		Variables: variables,
+		Status:    RecipientQueued,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (r *Recipient) MarkSent(messageID string, sentAt time.Time) {
+	r.Status = RecipientSent
+	r.MessageID = &messageID
+	r.SentAt = &sentAt
+	r.Error = nil
+	r.UpdatedAt = time.Now()
+}
+
+func (r *Recipient) Fail(reason string) {
+	r.Status = RecipientFailed
+	r.Error = &reason
+	r.UpdatedAt = time.Now()
+}
+
+// Report counts the recipients of a campaign by status.
+type Report struct {
+	Total     int
+	Queued    int
+	Sent      int
+	Delivered int
+	Read      int
+	Failed    int
+
+	// Errors counts the failed recipients by error message.
+	Errors map[string]int
+}
@@ -0,0 +1,49 @@
+package campaign
+
+import (
+	"context"
+	"time"
+)
+
+type ListFilter struct {
+	SessionID string
+	Status    Status
+	Limit     int
+	Offset    int
+}
+
+type RecipientFilter struct {
+	CampaignID string
+	Status     RecipientStatus
+	Limit      int
+	Offset     int
+}
+
+type Repository interface {
+	// Create stores a campaign together with its recipients.
+	Create(ctx context.Context, campaign *Campaign, recipients []*Recipient) error
+	GetByID(ctx context.Context, sessionID, id string) (*Campaign, error)
+	List(ctx context.Context, filter *ListFilter) ([]*Campaign, int, error)
+	// UpdateStatus stores the status of a campaign that has not finished. It
+	// returns shared.ErrCampaignFinished otherwise.
+	UpdateStatus(ctx context.Context, campaign *Campaign) error
+	ListRecipients(ctx context.Context, filter *RecipientFilter) ([]*Recipient, int, error)
+	Report(ctx context.Context, campaignID string) (*Report, error)
+
+	// ClaimDue leases up to limit running campaigns whose next send is due.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Campaign, error)
+	// NextRecipient returns the first queued recipient of a campaign, or nil
+	// when none is left.
+	NextRecipient(ctx context.Context, campaignID string) (*Recipient, error)
+	SaveRecipient(ctx context.Context, recipient *Recipient) error
+	// Release stores the next send time of a claimed campaign and releases
+	// its lease.
+	Release(ctx context.Context, campaign *Campaign) error
+	// Complete marks a campaign as completed unless it was paused or
+	// cancelled meanwhile, and releases its lease.
+	Complete(ctx context.Context, campaign *Campaign) error
+	// ApplyReceipt moves the recipients that were sent the given messages
+	// forward to status. Recipients already at or past status are left
+	// alone. It returns how many recipients changed.
+	ApplyReceipt(ctx context.Context, sessionID string, messageIDs []string, status RecipientStatus, at time.Time) (int, error)
+}
@@ -0,0 +1,47 @@
+package campaign
+
+import (
+	"regexp"
+	"strings"
+)
+
+// placeholderPattern matches {{name}} placeholders, allowing spaces inside
+// the braces.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Render fills the placeholders of text with the recipient's variables. The
+// phone variable defaults to the recipient's phone; placeholders without a
+// value are left empty.
+func Render(text string, recipient *Recipient) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+
+		if value, ok := recipient.Variables[name]; ok {
+			return value
+		}
+
+		if name == "phone" {
+			return recipient.Phone
+		}
+
+		return ""
+	})
+}
+
+// Placeholders returns the variable names used in text, in order of first
+// use.
+func Placeholders(text string) []string {
+	var names []string
+
+	seen := make(map[string]bool)
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimSpace(match[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,17 @@
+package chat
+
+import (
+	"time"
+)
+
+// Chat is the locally tracked state of a WhatsApp conversation. Names and
+// archive/pin/mute flags live in the whatsmeow store and are not duplicated.
+type Chat struct {
+	ID          string
+	SessionID   string
+	JID         string
+	UnreadCount int
+	LastReadAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,13 @@
+package chat
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	IncrementUnread(ctx context.Context, sessionID, chatJID string) error
+	MarkRead(ctx context.Context, sessionID, chatJID string, readAt time.Time) error
+	SetUnread(ctx context.Context, sessionID, chatJID string, count int) error
+	List(ctx context.Context, sessionID string) ([]*Chat, error)
+}
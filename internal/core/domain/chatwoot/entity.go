@@ -0,0 +1,155 @@
+package chatwoot
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultSignDelimiter = "\n\n"
+
+// Config connects a session to a Chatwoot inbox. Inbound WhatsApp messages
+// are posted to the inbox as conversations of the sending contact.
+type Config struct {
+	ID             string
+	SessionID      string
+	URL            string
+	Token          string
+	AccountID      string
+	InboxID        string
+	Enabled        bool
+	InboxName      string
+	AutoCreate     bool
+	SignMsg        bool
+	SignDelimiter  string
+	ReopenConv     bool
+	ConvPending    bool
+	ImportContacts bool
+	ImportMessages bool
+	ImportDays     int
+	MergeBrazil    bool
+	Organization   string
+	Logo           string
+	Number         string
+	IgnoreJIDs     []string
+	WebhookSecret  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewConfig(sessionID, url, token, accountID string) *Config {
+	now := time.Now()
+
+	return &Config{
+		ID:            uuid.New().String(),
+		SessionID:     sessionID,
+		URL:           strings.TrimRight(url, "/"),
+		Token:         token,
+		AccountID:     accountID,
+		Enabled:       true,
+		SignDelimiter: DefaultSignDelimiter,
+		ReopenConv:    true,
+		ImportDays:    60,
+		MergeBrazil:   true,
+		WebhookSecret: rand.Text(),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// RotateWebhookSecret replaces the secret Chatwoot must send to the webhook
+// of the session. The old secret stops working once the config is saved.
+func (c *Config) RotateWebhookSecret() {
+	c.WebhookSecret = rand.Text()
+}
+
+// CheckWebhookSecret reports whether secret is the webhook secret of the
+// config, in constant time.
+func (c *Config) CheckWebhookSecret(secret string) bool {
+	return c.WebhookSecret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(c.WebhookSecret)) == 1
+}
+
+// IsIgnored reports whether messages of a chat stay out of Chatwoot. An
+// entry matches the full JID or, when it starts with "@", every JID of that
+// server, e.g. "@g.us" for all groups.
+func (c *Config) IsIgnored(jid string) bool {
+	for _, ignored := range c.IgnoreJIDs {
+		if ignored == jid || (strings.HasPrefix(ignored, "@") && strings.HasSuffix(jid, ignored)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConversationStatus is the status given to conversations opened by
+// zpwoot.
+func (c *Config) ConversationStatus() string {
+	if c.ConvPending {
+		return ConversationStatusPending
+	}
+
+	return ConversationStatusOpen
+}
+
+// Sign prefixes an agent reply with the agent's name when signMsg is set.
+func (c *Config) Sign(agentName, content string) string {
+	if !c.SignMsg || agentName == "" {
+		return content
+	}
+
+	signature := "*" + agentName + ":*"
+	if content == "" {
+		return signature
+	}
+
+	return signature + c.SignDelimiter + content
+}
+
+const (
+	ConversationStatusOpen     = "open"
+	ConversationStatusPending  = "pending"
+	ConversationStatusResolved = "resolved"
+)
+
+type Contact struct {
+	ID          int
+	Name        string
+	PhoneNumber string
+	Identifier  string
+}
+
+type Conversation struct {
+	ID      int
+	InboxID int
+	Status  string
+}
+
+type Inbox struct {
+	ID   int
+	Name string
+}
+
+const (
+	MessageTypeIncoming = "incoming"
+	MessageTypeOutgoing = "outgoing"
+)
+
+// Message is a message posted to a Chatwoot conversation. SourceID carries
+// the WhatsApp message ID so replies and echoes can be matched.
+type Message struct {
+	Content     string
+	MessageType string
+	Private     bool
+	SourceID    string
+}
+
+// Attachment is a file downloaded from a Chatwoot message.
+type Attachment struct {
+	Data     []byte
+	MimeType string
+	FileName string
+}
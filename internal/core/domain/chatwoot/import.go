@@ -0,0 +1,93 @@
+package chatwoot
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+// ImportPhase is the step an import resumes from.
+type ImportPhase string
+
+const (
+	ImportPhaseContacts ImportPhase = "contacts"
+	ImportPhaseMessages ImportPhase = "messages"
+	ImportPhaseDone     ImportPhase = "done"
+)
+
+// ImportJob tracks the import of a session's WhatsApp contacts and message
+// history into Chatwoot. Messages are imported in timestamp order and the
+// cursor records the last one handled, so a stopped import resumes where it
+// left off.
+type ImportJob struct {
+	ID               string
+	SessionID        string
+	Status           ImportStatus
+	Phase            ImportPhase
+	ContactsTotal    int
+	ContactsImported int
+	MessagesTotal    int
+	MessagesImported int
+	MessagesFailed   int
+	CursorTimestamp  *time.Time
+	CursorID         string
+	LastError        string
+	StartedAt        time.Time
+	FinishedAt       *time.Time
+	UpdatedAt        time.Time
+}
+
+func NewImportJob(sessionID string) *ImportJob {
+	now := time.Now()
+
+	return &ImportJob{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		Status:    ImportStatusRunning,
+		Phase:     ImportPhaseContacts,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Resume restarts a failed or interrupted import from its phase and cursor.
+func (j *ImportJob) Resume() {
+	j.Status = ImportStatusRunning
+	j.LastError = ""
+	j.FinishedAt = nil
+	j.UpdatedAt = time.Now()
+}
+
+func (j *ImportJob) Complete() {
+	now := time.Now()
+
+	j.Status = ImportStatusCompleted
+	j.Phase = ImportPhaseDone
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+}
+
+func (j *ImportJob) Fail(err error) {
+	now := time.Now()
+
+	j.Status = ImportStatusFailed
+	j.LastError = err.Error()
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+}
+
+// Advance records the message with the given timestamp and ID as the last
+// one handled.
+func (j *ImportJob) Advance(timestamp time.Time, messageID string) {
+	j.CursorTimestamp = &timestamp
+	j.CursorID = messageID
+	j.UpdatedAt = time.Now()
+}
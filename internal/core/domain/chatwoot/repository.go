@@ -0,0 +1,14 @@
+package chatwoot
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, config *Config) error
+	GetBySessionID(ctx context.Context, sessionID string) (*Config, error)
+	Update(ctx context.Context, config *Config) error
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+	// SaveImport stores the import job of a session, replacing any previous
+	// one.
+	SaveImport(ctx context.Context, job *ImportJob) error
+	GetImport(ctx context.Context, sessionID string) (*ImportJob, error)
+}
@@ -0,0 +1,96 @@
+package media
+
+import "time"
+
+type Status string
+
+const (
+	// StatusPending media is known but was not downloaded yet.
+	StatusPending Status = "pending"
+	// StatusStored media is in the blob store.
+	StatusStored Status = "stored"
+	// StatusRetrying media expired on the WhatsApp servers and a re-upload
+	// was requested from the phone.
+	StatusRetrying Status = "retrying"
+	// StatusFailed media could not be downloaded.
+	StatusFailed Status = "failed"
+	// StatusExpired media was stored and removed by retention. It is
+	// downloaded again when requested.
+	StatusExpired Status = "expired"
+)
+
+// Media is the file of an image, video, audio, document or sticker message.
+// Descriptor holds what is needed to download and decrypt the file from
+// WhatsApp; it is opaque to everything but the WhatsApp adapter.
+type Media struct {
+	SessionID    string
+	MessageID    string
+	Chat         string
+	Sender       string
+	FromMe       bool
+	IsGroup      bool
+	Type         string
+	MimeType     string
+	FileName     string
+	Size         int64
+	SHA256       string
+	Descriptor   []byte
+	StorageKey   string
+	Status       Status
+	Error        *string
+	DownloadedAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func NewMedia(sessionID, messageID, chat, sender, mediaType string, fromMe, isGroup bool, descriptor []byte) *Media {
+	now := time.Now()
+
+	return &Media{
+		SessionID:  sessionID,
+		MessageID:  messageID,
+		Chat:       chat,
+		Sender:     sender,
+		FromMe:     fromMe,
+		IsGroup:    isGroup,
+		Type:       mediaType,
+		Descriptor: descriptor,
+		Status:     StatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Key is where the file of the media is kept in the blob store.
+func (m *Media) Key() string {
+	return m.SessionID + "/" + m.MessageID
+}
+
+func (m *Media) IsStored() bool {
+	return m.Status == StatusStored
+}
+
+func (m *Media) MarkStored(key string, size int64) {
+	now := time.Now()
+
+	m.Status = StatusStored
+	m.StorageKey = key
+	m.Size = size
+	m.Error = nil
+	m.DownloadedAt = &now
+}
+
+func (m *Media) MarkRetrying() {
+	m.Status = StatusRetrying
+	m.Error = nil
+}
+
+func (m *Media) Fail(reason string) {
+	m.Status = StatusFailed
+	m.Error = &reason
+}
+
+func (m *Media) Expire() {
+	m.Status = StatusExpired
+	m.StorageKey = ""
+}
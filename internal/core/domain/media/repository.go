@@ -0,0 +1,18 @@
+package media
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Create stores media and reports whether it was inserted. Media already
+	// stored for the same message is left untouched.
+	Create(ctx context.Context, m *Media) (bool, error)
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*Media, error)
+	// Save updates the descriptor, status and storage of media.
+	Save(ctx context.Context, m *Media) error
+	// ListStoredBefore returns stored media downloaded before the given time,
+	// oldest first.
+	ListStoredBefore(ctx context.Context, before time.Time, limit int) ([]*Media, error)
+}
@@ -0,0 +1,56 @@
+package message
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SyncStatus string
+
+const (
+	SyncStatusPending SyncStatus = "pending"
+	SyncStatusSynced  SyncStatus = "synced"
+	SyncStatusFailed  SyncStatus = "failed"
+)
+
+type Message struct {
+	ID               string
+	SessionID        string
+	MessageID        string
+	Sender           string
+	Chat             string
+	Timestamp        time.Time
+	FromMe           bool
+	Type             string
+	Content          string
+	CwMessageID      *int
+	CwConversationID *int
+	SyncStatus       SyncStatus
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	SyncedAt         *time.Time
+}
+
+func NewMessage(sessionID, messageID, chat, sender, messageType, content string, fromMe bool, timestamp time.Time) *Message {
+	now := time.Now()
+
+	if timestamp.IsZero() {
+		timestamp = now
+	}
+
+	return &Message{
+		ID:         uuid.New().String(),
+		SessionID:  sessionID,
+		MessageID:  messageID,
+		Sender:     sender,
+		Chat:       chat,
+		Timestamp:  timestamp,
+		FromMe:     fromMe,
+		Type:       messageType,
+		Content:    content,
+		SyncStatus: SyncStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
@@ -0,0 +1,93 @@
+package message
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultQueryLimit = 50
+	MaxQueryLimit     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Query selects messages of a single chat, newest first. Before continues a
+// previous page from the position of its last message.
+type Query struct {
+	SessionID string
+	Chat      string
+	Type      string
+	FromMe    *bool
+	Since     *time.Time
+	Until     *time.Time
+	Search    string
+	Before    *Cursor
+	Limit     int
+}
+
+func (q *Query) Normalize() {
+	if q.Limit <= 0 {
+		q.Limit = DefaultQueryLimit
+	}
+
+	if q.Limit > MaxQueryLimit {
+		q.Limit = MaxQueryLimit
+	}
+
+	q.Search = strings.TrimSpace(q.Search)
+}
+
+// UnsyncedQuery selects the messages of a session within [Since, Until] that
+// were not synced to Chatwoot, oldest first. After continues from the
+// position of the last message handled.
+type UnsyncedQuery struct {
+	SessionID string
+	Since     time.Time
+	Until     time.Time
+	After     *Cursor
+	Limit     int
+}
+
+// Cursor is a keyset position in the (timestamp, id) ordering of a chat.
+type Cursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+func CursorOf(msg *Message) *Cursor {
+	return &Cursor{
+		Timestamp: msg.Timestamp,
+		ID:        msg.ID,
+	}
+}
+
+func (c *Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	ts, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{
+		Timestamp: time.Unix(0, ts),
+		ID:        id,
+	}, nil
+}
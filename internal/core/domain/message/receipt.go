@@ -0,0 +1,85 @@
+package message
+
+import "time"
+
+// Status is how far a sent message got. Sent means the server acknowledged
+// it; the others come from the receipts of its recipients.
+type Status string
+
+const (
+	StatusSent      Status = "sent"
+	StatusDelivered Status = "delivered"
+	StatusRead      Status = "read"
+	StatusPlayed    Status = "played"
+)
+
+// rank orders the statuses so a late receipt never moves a message back.
+func (s Status) rank() int {
+	switch s {
+	case StatusDelivered:
+		return 1
+	case StatusRead:
+		return 2
+	case StatusPlayed:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// After reports whether s is further along than other.
+func (s Status) After(other Status) bool {
+	return s.rank() > other.rank()
+}
+
+// Receipt is the status of a sent message for one recipient: the other user
+// of a private chat, or each participant of a group. A read receipt also
+// counts as delivery, so DeliveredAt is set whenever ReadAt is.
+type Receipt struct {
+	SessionID   string
+	MessageID   string
+	Recipient   string
+	Status      Status
+	DeliveredAt *time.Time
+	ReadAt      *time.Time
+	PlayedAt    *time.Time
+	UpdatedAt   time.Time
+}
+
+func NewReceipt(sessionID, messageID, recipient string, status Status, timestamp time.Time) *Receipt {
+	receipt := &Receipt{
+		SessionID: sessionID,
+		MessageID: messageID,
+		Recipient: recipient,
+		Status:    status,
+		UpdatedAt: timestamp,
+	}
+
+	if status.After(StatusSent) {
+		receipt.DeliveredAt = &timestamp
+	}
+
+	if status.After(StatusDelivered) {
+		receipt.ReadAt = &timestamp
+	}
+
+	if status == StatusPlayed {
+		receipt.PlayedAt = &timestamp
+	}
+
+	return receipt
+}
+
+// OverallStatus is the furthest status any recipient reached, or sent when
+// no receipt arrived yet.
+func OverallStatus(receipts []*Receipt) Status {
+	status := StatusSent
+
+	for _, receipt := range receipts {
+		if receipt.Status.After(status) {
+			status = receipt.Status
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,78 @@
+package message
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusAfter(t *testing.T) {
+	order := []Status{StatusSent, StatusDelivered, StatusRead, StatusPlayed}
+
+	for i, status := range order {
+		for j, other := range order {
+			if got, want := status.After(other), i > j; got != want {
+				t.Errorf("%s.After(%s) = %v, want %v", status, other, got, want)
+			}
+		}
+	}
+}
+
+func TestNewReceipt(t *testing.T) {
+	at := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status                              Status
+		wantDelivered, wantRead, wantPlayed bool
+	}{
+		{StatusDelivered, true, false, false},
+		{StatusRead, true, true, false},
+		{StatusPlayed, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			receipt := NewReceipt("session-1", "MSG1", "5511999999999@s.whatsapp.net", tt.status, at)
+
+			check := func(name string, value *time.Time, want bool) {
+				t.Helper()
+
+				if want && (value == nil || !value.Equal(at)) {
+					t.Errorf("%s = %v, want %s", name, value, at)
+				}
+
+				if !want && value != nil {
+					t.Errorf("%s = %s, want unset", name, value)
+				}
+			}
+
+			check("deliveredAt", receipt.DeliveredAt, tt.wantDelivered)
+			check("readAt", receipt.ReadAt, tt.wantRead)
+			check("playedAt", receipt.PlayedAt, tt.wantPlayed)
+		})
+	}
+}
+
+func TestOverallStatus(t *testing.T) {
+	receipt := func(status Status) *Receipt {
+		return NewReceipt("session-1", "MSG1", "5511999999999@s.whatsapp.net", status, time.Now())
+	}
+
+	tests := []struct {
+		name     string
+		receipts []*Receipt
+		want     Status
+	}{
+		{"no receipts", nil, StatusSent},
+		{"delivered", []*Receipt{receipt(StatusDelivered)}, StatusDelivered},
+		{"furthest participant wins", []*Receipt{receipt(StatusDelivered), receipt(StatusRead), receipt(StatusDelivered)}, StatusRead},
+		{"played", []*Receipt{receipt(StatusPlayed), receipt(StatusRead)}, StatusPlayed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OverallStatus(tt.receipts); got != tt.want {
+				t.Errorf("OverallStatus = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
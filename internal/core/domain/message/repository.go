@@ -0,0 +1,32 @@
+package message
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Create stores a message and reports whether it was inserted. A message
+	// already stored for the same session and WhatsApp ID is left untouched.
+	Create(ctx context.Context, message *Message) (bool, error)
+	CreateBatch(ctx context.Context, messages []*Message) (int, error)
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*Message, error)
+	GetOldestInChat(ctx context.Context, sessionID, chatJID string) (*Message, error)
+	// GetLatestInConversation returns the most recent message synced to a
+	// Chatwoot conversation.
+	GetLatestInConversation(ctx context.Context, sessionID string, cwConversationID int) (*Message, error)
+	List(ctx context.Context, query *Query) ([]*Message, error)
+	ListLastPerChat(ctx context.Context, sessionID string) ([]*Message, error)
+	// ListUnsynced returns messages not yet synced to Chatwoot, oldest first.
+	ListUnsynced(ctx context.Context, query *UnsyncedQuery) ([]*Message, error)
+	CountUnsynced(ctx context.Context, query *UnsyncedQuery) (int, error)
+	// MarkSynced records the Chatwoot message and conversation a message was
+	// posted as.
+	MarkSynced(ctx context.Context, sessionID, messageID string, cwMessageID, cwConversationID int) error
+	MarkSyncFailed(ctx context.Context, sessionID, messageID string) error
+	// ApplyReceipt records a receipt from recipient for messages the session
+	// sent and returns the IDs of the messages it moved forward. Unknown
+	// messages and receipts behind the recorded status are ignored.
+	ApplyReceipt(ctx context.Context, sessionID string, messageIDs []string, recipient string, status Status, timestamp time.Time) ([]string, error)
+	ListReceipts(ctx context.Context, sessionID, messageID string) ([]*Receipt, error)
+}
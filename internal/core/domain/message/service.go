@@ -0,0 +1,93 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zpwoot/internal/core/domain/shared"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Record stores a message unless it is already known for the session. It
+// reports whether a new row was written.
+func (s *Service) Record(ctx context.Context, msg *Message) (bool, error) {
+	if msg.SessionID == "" {
+		return false, errors.New("session ID cannot be empty")
+	}
+
+	if msg.MessageID == "" {
+		return false, errors.New("message ID cannot be empty")
+	}
+
+	if msg.Chat == "" {
+		return false, errors.New("chat JID cannot be empty")
+	}
+
+	created, err := s.repo.Create(ctx, msg)
+	if err != nil {
+		return false, fmt.Errorf("failed to record message: %w", err)
+	}
+
+	return created, nil
+}
+
+// History returns one page of a chat's messages and whether older messages
+// remain beyond it.
+func (s *Service) History(ctx context.Context, query *Query) ([]*Message, bool, error) {
+	if query.SessionID == "" {
+		return nil, false, errors.New("session ID cannot be empty")
+	}
+
+	if query.Chat == "" {
+		return nil, false, errors.New("chat JID cannot be empty")
+	}
+
+	query.Normalize()
+
+	limit := query.Limit
+	query.Limit = limit + 1
+
+	messages, err := s.repo.List(ctx, query)
+	query.Limit = limit
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	return messages, hasMore, nil
+}
+
+// Status returns a message the session sent with the receipts of its
+// recipients.
+func (s *Service) Status(ctx context.Context, sessionID, messageID string) (*Message, []*Receipt, error) {
+	msg, err := s.repo.GetByMessageID(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !msg.FromMe {
+		return nil, nil, shared.ErrMessageNotFound
+	}
+
+	receipts, err := s.repo.ListReceipts(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	return msg, receipts, nil
+}
@@ -0,0 +1,134 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSent      Status = "sent"
+	StatusFailed    Status = "failed"
+	StatusSkipped   Status = "skipped"
+	StatusCancelled Status = "cancelled"
+)
+
+// DisconnectedPolicy is what the scheduler does with a due message whose
+// session is not connected.
+type DisconnectedPolicy string
+
+const (
+	DisconnectedRetry DisconnectedPolicy = "retry"
+	DisconnectedSkip  DisconnectedPolicy = "skip"
+)
+
+// Message types a scheduled message can have. Each one stores the body of
+// the matching send endpoint as its payload.
+const (
+	TypeText     = "text"
+	TypeImage    = "image"
+	TypeAudio    = "audio"
+	TypeVideo    = "video"
+	TypeDocument = "document"
+	TypeSticker  = "sticker"
+	TypeLocation = "location"
+	TypeContact  = "contact"
+	TypePoll     = "poll"
+)
+
+func ValidTypes() []string {
+	return []string{TypeText, TypeImage, TypeAudio, TypeVideo, TypeDocument, TypeSticker, TypeLocation, TypeContact, TypePoll}
+}
+
+// ScheduledMessage is a message to be sent at SendAt. The scheduler picks it
+// up once NextAttemptAt is reached; failed attempts move NextAttemptAt
+// forward until the message is sent or given up on.
+type ScheduledMessage struct {
+	ID             string
+	SessionID      string
+	Phone          string
+	Type           string
+	Payload        []byte
+	SendAt         time.Time
+	Timezone       string
+	OnDisconnected DisconnectedPolicy
+	Status         Status
+	Attempts       int
+	NextAttemptAt  time.Time
+	MessageID      *string
+	LastError      *string
+	SentAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewScheduledMessage(sessionID, phone, messageType string, payload []byte, sendAt time.Time, timezone string, onDisconnected DisconnectedPolicy) *ScheduledMessage {
+	now := time.Now()
+
+	return &ScheduledMessage{
+		ID:             uuid.New().String(),
+		SessionID:      sessionID,
+		Phone:          phone,
+		Type:           messageType,
+		Payload:        payload,
+		SendAt:         sendAt,
+		Timezone:       timezone,
+		OnDisconnected: onDisconnected,
+		Status:         StatusPending,
+		NextAttemptAt:  sendAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func (m *ScheduledMessage) IsPending() bool {
+	return m.Status == StatusPending
+}
+
+// Reschedule moves a pending message to a new send time and gives it a fresh
+// set of attempts.
+func (m *ScheduledMessage) Reschedule(sendAt time.Time, timezone string) {
+	m.SendAt = sendAt
+	m.Timezone = timezone
+	m.NextAttemptAt = sendAt
+	m.Attempts = 0
+	m.LastError = nil
+	m.UpdatedAt = time.Now()
+}
+
+func (m *ScheduledMessage) Cancel() {
+	m.Status = StatusCancelled
+	m.UpdatedAt = time.Now()
+}
+
+func (m *ScheduledMessage) MarkSent(messageID string, sentAt time.Time) {
+	m.Status = StatusSent
+	m.MessageID = &messageID
+	m.SentAt = &sentAt
+	m.LastError = nil
+	m.UpdatedAt = time.Now()
+}
+
+// Retry records why the message was not sent and when to try again.
+func (m *ScheduledMessage) Retry(reason string, nextAttemptAt time.Time) {
+	m.LastError = &reason
+	m.NextAttemptAt = nextAttemptAt
+	m.UpdatedAt = time.Now()
+}
+
+func (m *ScheduledMessage) Fail(reason string) {
+	m.finish(StatusFailed, reason)
+}
+
+func (m *ScheduledMessage) Skip(reason string) {
+	m.finish(StatusSkipped, reason)
+}
+
+func (m *ScheduledMessage) finish(status Status, reason string) {
+	m.Status = status
+	m.LastError = &reason
+	m.UpdatedAt = time.Now()
+}
@@ -0,0 +1,26 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+type ListFilter struct {
+	SessionID string
+	Status    Status
+	Limit     int
+	Offset    int
+}
+
+type Repository interface {
+	Create(ctx context.Context, msg *ScheduledMessage) error
+	GetByID(ctx context.Context, sessionID, id string) (*ScheduledMessage, error)
+	List(ctx context.Context, filter *ListFilter) ([]*ScheduledMessage, int, error)
+	// UpdatePending stores changes to a message that is still pending and not
+	// being sent. It returns shared.ErrScheduledMessageNotPending otherwise.
+	UpdatePending(ctx context.Context, msg *ScheduledMessage) error
+	// ClaimDue leases up to limit pending messages whose next attempt is due.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*ScheduledMessage, error)
+	// Save stores the outcome of an attempt and releases the lease.
+	Save(ctx context.Context, msg *ScheduledMessage) error
+}
@@ -8,6 +8,7 @@ import (
 
 type Session struct {
 	ID              string     `json:"id" db:"id"`
+	TenantID        string     `json:"tenant_id" db:"tenantId"`
 	Name            string     `json:"name" db:"name"`
 	DeviceJID       string     `json:"device_jid,omitempty" db:"deviceJid"`
 	IsConnected     bool       `json:"is_connected" db:"isConnected"`
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ProxyType string
+
+const (
+	ProxyTypeHTTP   ProxyType = "http"
+	ProxyTypeHTTPS  ProxyType = "https"
+	ProxyTypeSOCKS5 ProxyType = "socks5"
+)
+
+func (t ProxyType) IsValid() bool {
+	switch t {
+	case ProxyTypeHTTP, ProxyTypeHTTPS, ProxyTypeSOCKS5:
+		return true
+	default:
+		return false
+	}
+}
+
+type Proxy struct {
+	Type     ProxyType `json:"type"`
+	Host     string    `json:"host"`
+	Port     string    `json:"port"`
+	Username string    `json:"user,omitempty"`
+	Password string    `json:"pass,omitempty"`
+}
+
+func (p *Proxy) Validate() error {
+	if !p.Type.IsValid() {
+		return fmt.Errorf("invalid proxy type %q (must be http, https or socks5)", p.Type)
+	}
+
+	if p.Host == "" {
+		return errors.New("proxy host is required")
+	}
+
+	port, err := strconv.Atoi(p.Port)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid proxy port %q", p.Port)
+	}
+
+	if p.Password != "" && p.Username == "" {
+		return errors.New("proxy username is required when password is set")
+	}
+
+	return nil
+}
+
+// URL returns the proxy address in the scheme://[user:pass@]host:port form
+// understood by whatsmeow's SetProxyAddress.
+func (p *Proxy) URL() string {
+	u := &url.URL{
+		Scheme: string(p.Type),
+		Host:   net.JoinHostPort(p.Host, p.Port),
+	}
+
+	if p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+
+	return u.String()
+}
+
+func (s *Session) GetProxy() (*Proxy, error) {
+	if s.ProxyConfig == nil || *s.ProxyConfig == "" {
+		return nil, nil
+	}
+
+	var proxy Proxy
+	if err := json.Unmarshal([]byte(*s.ProxyConfig), &proxy); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy config: %w", err)
+	}
+
+	return &proxy, nil
+}
+
+func (s *Session) SetProxy(proxy *Proxy) error {
+	if proxy == nil {
+		s.ClearProxy()
+		return nil
+	}
+
+	if err := proxy.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(proxy)
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy config: %w", err)
+	}
+
+	config := string(data)
+	s.ProxyConfig = &config
+	s.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *Session) ClearProxy() {
+	s.ProxyConfig = nil
+	s.UpdatedAt = time.Now()
+}
@@ -5,6 +5,8 @@ import (
 )
 
 type Repository interface {
+	// Create returns shared.ErrSessionQuotaExceeded when the tenant of the
+	// session already has all the sessions its quota allows.
 	Create(ctx context.Context, session *Session) error
 
 	GetByID(ctx context.Context, id string) (*Session, error)
@@ -19,12 +19,14 @@ func NewService(repo Repository) *Service {
 	}
 }
 
-func (s *Service) Create(ctx context.Context, name string) (*Session, error) {
+// Create creates a session of the given tenant. Names are unique within a
+// tenant.
+func (s *Service) Create(ctx context.Context, tenantID, name string) (*Session, error) {
 	if name == "" {
 		return nil, errors.New("session name cannot be empty")
 	}
 
-	existingSession, err := s.repo.GetByName(ctx, name)
+	existingSession, err := s.repo.GetByName(shared.WithTenant(ctx, tenantID), name)
 	if err != nil && !errors.Is(err, shared.ErrSessionNotFound) {
 		return nil, fmt.Errorf("failed to check existing session: %w", err)
 	}
@@ -34,6 +36,7 @@ func (s *Service) Create(ctx context.Context, name string) (*Session, error) {
 	}
 
 	session := NewSession(name)
+	session.TenantID = tenantID
 
 	if err := s.repo.Create(ctx, session); err != nil {
 		if isUniqueConstraintError(err) {
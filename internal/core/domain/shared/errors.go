@@ -14,6 +14,36 @@ var (
 	ErrEmptyMessageContent = errors.New("message content cannot be empty")
 	ErrInvalidRecipient    = errors.New("invalid recipient")
 
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrWebhookAlreadyExists = errors.New("webhook already exists for session")
+	ErrDeadLetterNotFound   = errors.New("dead letter not found")
+	ErrUnknownEventType     = errors.New("unknown event type")
+
+	ErrChatwootConfigNotFound = errors.New("chatwoot configuration not found")
+	ErrChatwootImportNotFound = errors.New("chatwoot import not found")
+	ErrChatwootWebhookSecret  = errors.New("invalid chatwoot webhook secret")
+
+	ErrScheduledMessageNotFound   = errors.New("scheduled message not found")
+	ErrScheduledMessageNotPending = errors.New("scheduled message is no longer pending")
+
+	ErrCampaignNotFound = errors.New("campaign not found")
+	ErrCampaignFinished = errors.New("campaign has already finished")
+
+	ErrMediaNotFound = errors.New("media not found")
+	ErrMediaTooLarge = errors.New("media exceeds the maximum download size")
+	ErrMediaRetrying = errors.New("media expired on WhatsApp and was requested again from the phone")
+	ErrMediaFailed   = errors.New("media could not be downloaded")
+
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key is revoked")
+
+	ErrTenantNotFound       = errors.New("tenant not found")
+	ErrTenantAlreadyExists  = errors.New("tenant already exists")
+	ErrTenantSuspended      = errors.New("tenant is suspended")
+	ErrTenantHasSessions    = errors.New("tenant still has sessions")
+	ErrDefaultTenant        = errors.New("the default tenant cannot be deleted or suspended")
+	ErrSessionQuotaExceeded = errors.New("tenant reached its maximum number of sessions")
+
 	ErrContactNotFound = errors.New("contact not found")
 	ErrInvalidJID      = errors.New("invalid JID format")
 
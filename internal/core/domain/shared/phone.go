@@ -0,0 +1,43 @@
+package shared
+
+import "strings"
+
+// NormalizePhoneNumber strips formatting characters and the leading "+" from
+// a phone number.
+func NormalizePhoneNumber(phone string) string {
+	phone = strings.ReplaceAll(phone, " ", "")
+	phone = strings.ReplaceAll(phone, "-", "")
+	phone = strings.ReplaceAll(phone, "(", "")
+	phone = strings.ReplaceAll(phone, ")", "")
+	phone = strings.TrimPrefix(phone, "+")
+
+	return phone
+}
+
+// PhoneVariants returns a normalized phone number followed by the other forms
+// it may be registered under. Brazilian mobile numbers gained a ninth digit,
+// but WhatsApp accounts created before the change can still be registered
+// without it, so both forms are candidates. Landlines, which start with 2-5,
+// never had the ninth digit.
+func PhoneVariants(phone string) []string {
+	variants := []string{phone}
+
+	if !strings.HasPrefix(phone, "55") {
+		return variants
+	}
+
+	switch len(phone) {
+	case 13:
+		// 55 DD 9XXXXXXXX
+		if phone[4] == '9' {
+			variants = append(variants, phone[:4]+phone[5:])
+		}
+	case 12:
+		// 55 DD XXXXXXXX
+		if phone[4] >= '6' && phone[4] <= '9' {
+			variants = append(variants, phone[:4]+"9"+phone[4:])
+		}
+	}
+
+	return variants
+}
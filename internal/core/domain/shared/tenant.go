@@ -0,0 +1,18 @@
+package shared
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx limited to the tenant with the given ID.
+// Repositories only read and change the data of that tenant's sessions.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ctx is limited to, or "" when it is
+// not limited, as for background work and the server operator.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
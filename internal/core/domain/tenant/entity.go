@@ -0,0 +1,83 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultID is the tenant sessions belong to unless another one is chosen.
+// It holds every session created before tenants existed.
+const DefaultID = "00000000-0000-0000-0000-000000000000"
+
+type Status string
+
+const (
+	StatusActive Status = "active"
+	// StatusSuspended tenants keep their data, but their API keys are
+	// refused and their sessions cannot send.
+	StatusSuspended Status = "suspended"
+)
+
+// Tenant is a customer sharing the server. It owns sessions, and through
+// them their webhooks, messages and media. Quotas of 0 are unlimited.
+type Tenant struct {
+	ID                string
+	Name              string
+	Status            Status
+	MaxSessions       int
+	MaxMessagesPerDay int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func NewTenant(name string, maxSessions, maxMessagesPerDay int) *Tenant {
+	now := time.Now()
+
+	return &Tenant{
+		ID:                uuid.New().String(),
+		Name:              name,
+		Status:            StatusActive,
+		MaxSessions:       maxSessions,
+		MaxMessagesPerDay: maxMessagesPerDay,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+func (t *Tenant) IsDefault() bool {
+	return t.ID == DefaultID
+}
+
+func (t *Tenant) IsActive() bool {
+	return t.Status == StatusActive
+}
+
+func (t *Tenant) Suspend() {
+	t.Status = StatusSuspended
+	t.UpdatedAt = time.Now()
+}
+
+func (t *Tenant) Activate() {
+	t.Status = StatusActive
+	t.UpdatedAt = time.Now()
+}
+
+// CanAddSession reports whether a tenant with count sessions may create
+// another one.
+func (t *Tenant) CanAddSession(count int) bool {
+	return t.MaxSessions == 0 || count < t.MaxSessions
+}
+
+// Usage is what a tenant used on one UTC day.
+type Usage struct {
+	TenantID string
+	Day      time.Time
+	Messages int
+}
+
+// Day is the UTC day a time falls on, which quotas are counted by.
+func Day(at time.Time) time.Time {
+	year, month, day := at.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
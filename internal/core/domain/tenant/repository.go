@@ -0,0 +1,26 @@
+package tenant
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, tenant *Tenant) error
+	GetByID(ctx context.Context, id string) (*Tenant, error)
+	// GetBySessionID returns the tenant owning a session.
+	GetBySessionID(ctx context.Context, sessionID string) (*Tenant, error)
+	List(ctx context.Context) ([]*Tenant, error)
+	// Update stores the name, status and quotas of a tenant.
+	Update(ctx context.Context, tenant *Tenant) error
+	// Delete removes a tenant without sessions. It returns
+	// shared.ErrTenantHasSessions otherwise.
+	Delete(ctx context.Context, id string) error
+	CountSessions(ctx context.Context, tenantID string) (int, error)
+
+	// ConsumeMessage counts one more message sent on day unless the tenant
+	// already sent limit messages that day, limit 0 meaning no limit. It
+	// reports whether the message was counted.
+	ConsumeMessage(ctx context.Context, tenantID string, day time.Time, limit int) (bool, error)
+	GetUsage(ctx context.Context, tenantID string, day time.Time) (*Usage, error)
+}
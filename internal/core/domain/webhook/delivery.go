@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery is a webhook event waiting in the outbox for its endpoint.
+// Deliveries of the same endpoint are sent one at a time, oldest first.
+type Delivery struct {
+	ID             string
+	SessionID      string
+	Endpoint       string
+	Secret         *string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      *string
+	LastStatusCode *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewDelivery(sessionID, endpoint string, secret *string, eventID, eventType string, payload []byte) *Delivery {
+	now := time.Now()
+
+	return &Delivery{
+		ID:            uuid.New().String(),
+		SessionID:     sessionID,
+		Endpoint:      endpoint,
+		Secret:        secret,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// DeadLetter is a delivery that exhausted its attempts or was rejected by
+// the endpoint. It keeps the original payload so it can be replayed.
+type DeadLetter struct {
+	ID             string
+	SessionID      string
+	Endpoint       string
+	Secret         *string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Attempts       int
+	LastError      *string
+	LastStatusCode *int
+	CreatedAt      time.Time
+	FailedAt       time.Time
+}
+
+type DeadLetterFilter struct {
+	SessionID string
+	EventType string
+	Since     *time.Time
+	Until     *time.Time
+	Limit     int
+	Offset    int
+}
+
+type DeliveryRepository interface {
+	Enqueue(ctx context.Context, delivery *Delivery) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	Complete(ctx context.Context, id string) error
+	Reschedule(ctx context.Context, id string, nextAttemptAt time.Time, lastError string, statusCode *int) error
+	MoveToDeadLetter(ctx context.Context, id string, lastError string, statusCode *int) error
+
+	ListDeadLetters(ctx context.Context, filter *DeadLetterFilter) ([]*DeadLetter, int, error)
+	GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	ReplayDeadLetters(ctx context.Context, ids []string) (int, error)
+	ReplayDeadLettersInRange(ctx context.Context, filter *DeadLetterFilter) (int, error)
+}
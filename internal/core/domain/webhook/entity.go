@@ -6,6 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// Payload formats of a webhook. Normalized payloads follow the versioned
+// zpwoot schema; raw payloads keep the whatsmeow structures, and event names
+// such as QR and ReadReceipt, sent before it.
+const (
+	FormatNormalized = "normalized"
+	FormatRaw        = "raw"
+)
+
+// Webhook is an HTTP endpoint receiving session events. A webhook without a
+// session is the global webhook and receives the events of every session.
 type Webhook struct {
 	ID        string
 	SessionID string
@@ -13,6 +23,7 @@ type Webhook struct {
 	Secret    *string
 	Events    []string
 	Enabled   bool
+	Format    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -26,10 +37,17 @@ func NewWebhook(sessionID, url string, events []string) *Webhook {
 		URL:       url,
 		Events:    events,
 		Enabled:   true,
+		Format:    FormatNormalized,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
+func NewGlobalWebhook(url string, events []string) *Webhook {
+	return NewWebhook("", url, events)
+}
+func (w *Webhook) IsGlobal() bool {
+	return w.SessionID == ""
+}
 func (w *Webhook) HasEvent(eventType string) bool {
 	if !w.Enabled {
 		return false
@@ -70,6 +88,13 @@ func (w *Webhook) UpdateEvents(events []string) {
 	w.Events = events
 	w.UpdatedAt = time.Now()
 }
+func (w *Webhook) SetFormat(format string) {
+	w.Format = format
+	w.UpdatedAt = time.Now()
+}
+func (w *Webhook) IsRaw() bool {
+	return w.Format == FormatRaw
+}
 func (w *Webhook) Update(url string, events []string, secret *string) {
 	w.URL = url
 	w.Events = events
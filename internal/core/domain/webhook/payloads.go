@@ -0,0 +1,477 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SchemaVersion is the version of the normalized webhook payloads below. It is
+// sent as "schemaVersion" with every normalized event and bumped on breaking
+// changes.
+const SchemaVersion = 1
+
+// Webhook payloads sent as the "data" of each event type. They only expose
+// plain values so subscribers do not depend on whatsmeow structures.
+
+type MessageInfo struct {
+	ID        string    `json:"id"`
+	Chat      string    `json:"chat"`
+	Sender    string    `json:"sender"`
+	PushName  string    `json:"pushName"`
+	Timestamp time.Time `json:"timestamp"`
+	FromMe    bool      `json:"fromMe"`
+	Type      string    `json:"type"`
+	IsGroup   bool      `json:"isGroup"`
+}
+
+// MessagePayload is a received or synced message. Exactly one body matching
+// Type is set; buttons, lists and templates are reduced to their text.
+type MessagePayload struct {
+	MessageInfo *MessageInfo    `json:"messageInfo"`
+	Text        *TextBody       `json:"text,omitempty"`
+	Media       *MediaBody      `json:"media,omitempty"`
+	Location    *LocationBody   `json:"location,omitempty"`
+	Contacts    []*ContactBody  `json:"contacts,omitempty"`
+	Poll        *PollBody       `json:"poll,omitempty"`
+	Reaction    *ReactionBody   `json:"reaction,omitempty"`
+	Context     *MessageContext `json:"context,omitempty"`
+	IsEdit      bool            `json:"isEdit,omitempty"`
+	IsViewOnce  bool            `json:"isViewOnce,omitempty"`
+	IsEphemeral bool            `json:"isEphemeral,omitempty"`
+}
+
+type TextBody struct {
+	Body string `json:"body"`
+}
+
+// MediaBody describes an image, video, audio, document or sticker. Media
+// content itself is not part of the payload; it is fetched from MediaURL.
+type MediaBody struct {
+	MimeType   string `json:"mimeType"`
+	Caption    string `json:"caption,omitempty"`
+	FileName   string `json:"fileName,omitempty"`
+	FileSize   uint64 `json:"fileSize,omitempty"`
+	SHA256     string `json:"sha256,omitempty" description:"Base64 SHA-256 of the decrypted file"`
+	Duration   uint32 `json:"duration,omitempty" description:"Length of audio and video in seconds"`
+	Width      uint32 `json:"width,omitempty"`
+	Height     uint32 `json:"height,omitempty"`
+	IsVoice    bool   `json:"isVoice,omitempty" description:"Audio recorded as a voice note"`
+	IsAnimated bool   `json:"isAnimated,omitempty" description:"Animated sticker or GIF video"`
+	MediaURL   string `json:"mediaUrl,omitempty" description:"Where the API serves the file of the message"`
+}
+
+type LocationBody struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	Caption   string  `json:"caption,omitempty"`
+	IsLive    bool    `json:"isLive"`
+	Accuracy  uint32  `json:"accuracy,omitempty" description:"Accuracy in meters of a live location"`
+}
+
+type ContactBody struct {
+	DisplayName string `json:"displayName"`
+	VCard       string `json:"vcard"`
+}
+
+type PollBody struct {
+	Name            string   `json:"name"`
+	Options         []string `json:"options"`
+	SelectableCount uint32   `json:"selectableCount" description:"Number of options a voter may pick, 0 for any"`
+}
+
+type ReactionBody struct {
+	TargetMessageID string `json:"targetMessageId"`
+	TargetFromMe    bool   `json:"targetFromMe"`
+	TargetSender    string `json:"targetSender,omitempty"`
+	Emoji           string `json:"emoji"`
+	Removed         bool   `json:"removed"`
+}
+
+// MessageContext holds the reply, mention and forwarding details of a
+// message.
+type MessageContext struct {
+	QuotedMessageID string   `json:"quotedMessageId,omitempty"`
+	QuotedSender    string   `json:"quotedSender,omitempty"`
+	QuotedType      string   `json:"quotedType,omitempty"`
+	QuotedText      string   `json:"quotedText,omitempty"`
+	Mentions        []string `json:"mentions,omitempty" description:"JIDs mentioned in the message"`
+	Forwarded       bool     `json:"forwarded,omitempty"`
+	ForwardingScore uint32   `json:"forwardingScore,omitempty"`
+	Expiration      uint32   `json:"expiration,omitempty" description:"Disappearing message timer in seconds"`
+}
+
+// HistorySyncBatch is one page of a history sync. Large syncs are split into
+// pages of at most 100 messages.
+type HistorySyncBatch struct {
+	SyncType      string                     `json:"syncType"`
+	ChunkOrder    uint32                     `json:"chunkOrder"`
+	Progress      uint32                     `json:"progress"`
+	Page          int                        `json:"page"`
+	TotalPages    int                        `json:"totalPages"`
+	TotalMessages int                        `json:"totalMessages"`
+	Conversations []*HistorySyncConversation `json:"conversations"`
+}
+
+type HistorySyncConversation struct {
+	JID         string            `json:"jid"`
+	Name        string            `json:"name,omitempty"`
+	UnreadCount uint32            `json:"unreadCount"`
+	Archived    bool              `json:"archived"`
+	Pinned      bool              `json:"pinned"`
+	Messages    []*MessagePayload `json:"messages"`
+}
+
+// MessageRevokedPayload is sent when a message is deleted for everyone.
+type MessageRevokedPayload struct {
+	MessageInfo      *MessageInfo `json:"messageInfo"`
+	RevokedMessageID string       `json:"revokedMessageId"`
+	RevokedFromMe    bool         `json:"revokedFromMe"`
+	RevokedSender    string       `json:"revokedSender,omitempty"`
+}
+
+// MessageReactionPayload is sent when a reaction is added to or removed from
+// a message. Reaction is empty when the reaction was removed.
+type MessageReactionPayload struct {
+	MessageInfo     *MessageInfo `json:"messageInfo"`
+	TargetMessageID string       `json:"targetMessageId"`
+	TargetFromMe    bool         `json:"targetFromMe"`
+	TargetSender    string       `json:"targetSender,omitempty"`
+	Reaction        string       `json:"reaction"`
+	Removed         bool         `json:"removed"`
+}
+
+// NewsletterMessageMetaPayload is sent alongside a newsletter message with its
+// edit and original timestamps.
+type NewsletterMessageMetaPayload struct {
+	MessageInfo *MessageInfo `json:"messageInfo"`
+	EditedAt    *time.Time   `json:"editedAt,omitempty"`
+	OriginalAt  *time.Time   `json:"originalAt,omitempty"`
+}
+
+type ReceiptPayload struct {
+	Chat          string    `json:"chat"`
+	Sender        string    `json:"sender"`
+	MessageSender string    `json:"messageSender,omitempty"`
+	MessageIDs    []string  `json:"messageIds"`
+	Type          string    `json:"type"`
+	FromMe        bool      `json:"fromMe"`
+	IsGroup       bool      `json:"isGroup"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type ConnectedPayload struct {
+	DeviceJID   string    `json:"deviceJid,omitempty"`
+	ConnectedAt time.Time `json:"connectedAt"`
+}
+
+type DisconnectedPayload struct {
+	DisconnectedAt time.Time `json:"disconnectedAt"`
+}
+
+// LoggedOutPayload is sent when the device was unlinked. OnConnect is true
+// when the logout was reported while connecting instead of on a live stream.
+type LoggedOutPayload struct {
+	OnConnect  bool   `json:"onConnect"`
+	ReasonCode int    `json:"reasonCode"`
+	Reason     string `json:"reason"`
+}
+
+type QRCodePayload struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type PairSuccessPayload struct {
+	DeviceJID    string `json:"deviceJid"`
+	LID          string `json:"lid,omitempty"`
+	BusinessName string `json:"businessName,omitempty"`
+	Platform     string `json:"platform"`
+}
+
+type KeepAliveTimeoutPayload struct {
+	ErrorCount  int       `json:"errorCount"`
+	LastSuccess time.Time `json:"lastSuccess"`
+}
+
+type KeepAliveRestoredPayload struct {
+	RestoredAt time.Time `json:"restoredAt"`
+}
+
+// GroupInfoPayload describes a change of group metadata or membership. Only
+// the fields that changed are set.
+type GroupInfoPayload struct {
+	JID            string    `json:"jid"`
+	Sender         string    `json:"sender,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	Name           *string   `json:"name,omitempty"`
+	Topic          *string   `json:"topic,omitempty"`
+	Locked         *bool     `json:"locked,omitempty"`
+	Announce       *bool     `json:"announce,omitempty"`
+	EphemeralTimer *uint32   `json:"ephemeralTimer,omitempty"`
+	Deleted        bool      `json:"deleted,omitempty"`
+	NewInviteLink  *string   `json:"newInviteLink,omitempty"`
+	JoinReason     string    `json:"joinReason,omitempty"`
+	Join           []string  `json:"join,omitempty"`
+	Leave          []string  `json:"leave,omitempty"`
+	Promote        []string  `json:"promote,omitempty"`
+	Demote         []string  `json:"demote,omitempty"`
+}
+
+type JoinedGroupPayload struct {
+	JID          string    `json:"jid"`
+	Name         string    `json:"name"`
+	Topic        string    `json:"topic,omitempty"`
+	Owner        string    `json:"owner,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Type         string    `json:"type,omitempty"`
+	Sender       string    `json:"sender,omitempty"`
+	Participants int       `json:"participants"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type PicturePayload struct {
+	JID       string    `json:"jid"`
+	Author    string    `json:"author,omitempty"`
+	PictureID string    `json:"pictureId,omitempty"`
+	Removed   bool      `json:"removed"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IdentityChangePayload is sent when a contact's encryption identity changed,
+// usually because they reinstalled WhatsApp or switched phones.
+type IdentityChangePayload struct {
+	JID       string    `json:"jid"`
+	Implicit  bool      `json:"implicit"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PrivacySettingsPayload carries the current privacy settings and the names
+// of the settings that changed.
+type PrivacySettingsPayload struct {
+	Settings map[string]string `json:"settings"`
+	Changed  []string          `json:"changed"`
+}
+
+type BlocklistChangePayload struct {
+	JID    string `json:"jid"`
+	Action string `json:"action"`
+}
+
+// BlocklistPayload is sent when the blocklist changed. An empty Changes list
+// means the whole blocklist must be refetched.
+type BlocklistPayload struct {
+	Action  string                   `json:"action,omitempty"`
+	Changes []BlocklistChangePayload `json:"changes"`
+}
+
+type PresencePayload struct {
+	From      string     `json:"from"`
+	Available bool       `json:"available"`
+	LastSeen  *time.Time `json:"lastSeen,omitempty"`
+}
+
+type ChatPresencePayload struct {
+	Chat    string `json:"chat"`
+	Sender  string `json:"sender"`
+	IsGroup bool   `json:"isGroup"`
+	State   string `json:"state"`
+	Media   string `json:"media,omitempty"`
+}
+
+type OfflineSyncPreviewPayload struct {
+	Total          int `json:"total"`
+	AppDataChanges int `json:"appDataChanges"`
+	Messages       int `json:"messages"`
+	Notifications  int `json:"notifications"`
+	Receipts       int `json:"receipts"`
+}
+
+type OfflineSyncCompletedPayload struct {
+	Count int `json:"count"`
+}
+
+// AppStatePayload is a raw app state mutation. Index holds the action name
+// followed by its targets, e.g. ["mute", "<chat jid>"].
+type AppStatePayload struct {
+	Action    string          `json:"action"`
+	Index     []string        `json:"index"`
+	Timestamp *time.Time      `json:"timestamp,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty" description:"Sync action value in protobuf JSON form"`
+}
+
+// CallPayload is shared by all call events. Media, CallType and Reason are
+// only present on the events that carry them.
+type CallPayload struct {
+	CallID         string    `json:"callId"`
+	From           string    `json:"from"`
+	Creator        string    `json:"creator,omitempty"`
+	GroupJID       string    `json:"groupJid,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	RemotePlatform string    `json:"remotePlatform,omitempty"`
+	RemoteVersion  string    `json:"remoteVersion,omitempty"`
+	Media          string    `json:"media,omitempty"`
+	CallType       string    `json:"callType,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+}
+
+// UnknownCallEventPayload exposes the tag and attributes of a call node that
+// zpwoot does not understand yet.
+type UnknownCallEventPayload struct {
+	Tag   string                 `json:"tag"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+type NewsletterJoinPayload struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InviteCode  string `json:"inviteCode,omitempty"`
+	Subscribers int    `json:"subscribers"`
+	State       string `json:"state,omitempty"`
+	Role        string `json:"role,omitempty"`
+	Mute        string `json:"mute,omitempty"`
+}
+
+type NewsletterLeavePayload struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+type NewsletterMuteChangePayload struct {
+	ID   string `json:"id"`
+	Mute string `json:"mute"`
+}
+
+type NewsletterLiveMessage struct {
+	ServerID  int            `json:"serverId"`
+	MessageID string         `json:"messageId"`
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Views     int            `json:"views"`
+	Reactions map[string]int `json:"reactions,omitempty"`
+}
+
+// NewsletterLiveUpdatePayload carries view and reaction counters of
+// newsletter messages.
+type NewsletterLiveUpdatePayload struct {
+	JID      string                   `json:"jid"`
+	Time     time.Time                `json:"time"`
+	Messages []*NewsletterLiveMessage `json:"messages"`
+}
+
+// MediaRetryPayload is the phone's answer to a media re-upload request.
+// ErrorCode is set when the phone could not re-upload the media.
+type MediaRetryPayload struct {
+	MessageID string    `json:"messageId"`
+	Chat      string    `json:"chat"`
+	Sender    string    `json:"sender,omitempty"`
+	FromMe    bool      `json:"fromMe"`
+	Timestamp time.Time `json:"timestamp"`
+	ErrorCode *int      `json:"errorCode,omitempty"`
+}
+
+// MediaStoredPayload announces that the file of a received media message
+// was downloaded into the media store. MediaURL is presigned, and ExpiresAt
+// set, when the store links to files directly.
+type MediaStoredPayload struct {
+	MessageID string     `json:"messageId"`
+	Chat      string     `json:"chat"`
+	Sender    string     `json:"sender,omitempty"`
+	Type      string     `json:"type"`
+	MimeType  string     `json:"mimeType"`
+	Size      int64      `json:"size"`
+	MediaURL  string     `json:"mediaUrl"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// QueuedMessagePayload reports the outcome of a message sent through the
+// send queue with async=true. MessageSent carries SentAt, MessageSendFailed
+// carries Error.
+type QueuedMessagePayload struct {
+	MessageID string     `json:"messageId"`
+	Chat      string     `json:"chat"`
+	Type      string     `json:"type"`
+	QueuedAt  time.Time  `json:"queuedAt"`
+	SentAt    *time.Time `json:"sentAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// MessageStatusPayload reports a sent message moving forward: sent once the
+// server acknowledged it, then delivered, read and played as receipts
+// arrive. Recipient is the user the receipt came from, a participant for
+// group messages, and is empty for sent.
+type MessageStatusPayload struct {
+	MessageID string    `json:"messageId"`
+	Chat      string    `json:"chat"`
+	Recipient string    `json:"recipient,omitempty"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ScheduledMessagePayload reports the outcome of a scheduled message.
+// ScheduledMessageSent carries MessageID and SentAt; ScheduledMessageFailed
+// carries Error and a status of failed or skipped.
+type ScheduledMessagePayload struct {
+	ScheduleID string     `json:"scheduleId"`
+	MessageID  string     `json:"messageId,omitempty"`
+	Phone      string     `json:"phone"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	SendAt     time.Time  `json:"sendAt"`
+	SentAt     *time.Time `json:"sentAt,omitempty"`
+	Attempts   int        `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// EventPayloads maps every event type to the payload sent as its "data". It
+// is the source for the published JSON Schemas.
+func EventPayloads() map[string]interface{} {
+	return map[string]interface{}{
+		"Message":                MessagePayload{},
+		"MessageRevoked":         MessageRevokedPayload{},
+		"MessageReaction":        MessageReactionPayload{},
+		"Receipt":                ReceiptPayload{},
+		"Connected":              ConnectedPayload{},
+		"Disconnected":           DisconnectedPayload{},
+		"QRCode":                 QRCodePayload{},
+		"PairSuccess":            PairSuccessPayload{},
+		"LoggedOut":              LoggedOutPayload{},
+		"KeepAliveTimeout":       KeepAliveTimeoutPayload{},
+		"KeepAliveRestored":      KeepAliveRestoredPayload{},
+		"GroupInfo":              GroupInfoPayload{},
+		"JoinedGroup":            JoinedGroupPayload{},
+		"Picture":                PicturePayload{},
+		"IdentityChange":         IdentityChangePayload{},
+		"PrivacySettings":        PrivacySettingsPayload{},
+		"Blocklist":              BlocklistPayload{},
+		"Presence":               PresencePayload{},
+		"ChatPresence":           ChatPresencePayload{},
+		"HistorySync":            HistorySyncBatch{},
+		"OfflineSyncPreview":     OfflineSyncPreviewPayload{},
+		"OfflineSyncCompleted":   OfflineSyncCompletedPayload{},
+		"AppState":               AppStatePayload{},
+		"CallOffer":              CallPayload{},
+		"CallAccept":             CallPayload{},
+		"CallPreAccept":          CallPayload{},
+		"CallTransport":          CallPayload{},
+		"CallOfferNotice":        CallPayload{},
+		"CallRelayLatency":       CallPayload{},
+		"CallTerminate":          CallPayload{},
+		"UnknownCallEvent":       UnknownCallEventPayload{},
+		"NewsletterJoin":         NewsletterJoinPayload{},
+		"NewsletterLeave":        NewsletterLeavePayload{},
+		"NewsletterMuteChange":   NewsletterMuteChangePayload{},
+		"NewsletterLiveUpdate":   NewsletterLiveUpdatePayload{},
+		"NewsletterMessageMeta":  NewsletterMessageMetaPayload{},
+		"MediaRetry":             MediaRetryPayload{},
+		"MediaStored":            MediaStoredPayload{},
+		"MessageSent":            QueuedMessagePayload{},
+		"MessageSendFailed":      QueuedMessagePayload{},
+		"MessageStatus":          MessageStatusPayload{},
+		"ScheduledMessageSent":   ScheduledMessagePayload{},
+		"ScheduledMessageFailed": ScheduledMessagePayload{},
+	}
+}
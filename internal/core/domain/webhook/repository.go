@@ -5,7 +5,9 @@ import "context"
 type Repository interface {
 	Create(ctx context.Context, webhook *Webhook) error
 	GetByID(ctx context.Context, id string) (*Webhook, error)
-	GetBySessionID(ctx context.Context, sessionID string) (*Webhook, error)
+	ListBySessionID(ctx context.Context, sessionID string) ([]*Webhook, error)
+	ListForSession(ctx context.Context, sessionID string) ([]*Webhook, error)
+	GetGlobal(ctx context.Context) (*Webhook, error)
 	Update(ctx context.Context, webhook *Webhook) error
 	Delete(ctx context.Context, id string) error
 	DeleteBySessionID(ctx context.Context, sessionID string) error
@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"zpwoot/internal/core/domain/shared"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// GetEventSchema returns the JSON Schema of a normalized event of the given
+// type, envelope included. Schemas are derived from the payload structs so
+// they cannot drift from what is actually sent.
+func (s *Service) GetEventSchema(eventType string) (map[string]interface{}, error) {
+	payload, ok := EventPayloads()[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", shared.ErrUnknownEventType, eventType)
+	}
+
+	return eventSchema(eventType, payload), nil
+}
+func (s *Service) GetEventSchemas() map[string]map[string]interface{} {
+	payloads := EventPayloads()
+
+	schemas := make(map[string]map[string]interface{}, len(payloads))
+	for eventType, payload := range payloads {
+		schemas[eventType] = eventSchema(eventType, payload)
+	}
+
+	return schemas
+}
+func eventSchema(eventType string, payload interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema": jsonSchemaDraft,
+		"title":   eventType,
+		"type":    "object",
+		"required": []string{
+			"id", "type", "sessionId", "timestamp", "schemaVersion", "data",
+		},
+		"properties": map[string]interface{}{
+			"id":            map[string]interface{}{"type": "string", "format": "uuid", "description": "Event ID, shared by every delivery of the event"},
+			"type":          map[string]interface{}{"const": eventType},
+			"sessionId":     map[string]interface{}{"type": "string"},
+			"timestamp":     map[string]interface{}{"type": "string", "format": "date-time"},
+			"schemaVersion": map[string]interface{}{"const": SchemaVersion},
+			"data":          typeSchema(reflect.TypeOf(payload)),
+		},
+	}
+}
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes the JSON encoding of a struct. Fields tagged
+// omitempty are optional, and pointer or slice fields without it may be null.
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{}, t.NumField())
+	required := make([]string, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := strings.Contains(options, "omitempty")
+
+		schema := typeSchema(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+
+		if !omitEmpty && isNullable(field.Type) {
+			schema = map[string]interface{}{
+				"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+			}
+		}
+
+		properties[name] = schema
+
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+func isNullable(t reflect.Type) bool {
+	if t == rawMessageType {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
@@ -3,7 +3,6 @@ package webhook
 import (
 	"fmt"
 	"net/url"
-	"strings"
 )
 
 type Service struct{}
@@ -29,10 +28,6 @@ func (s *Service) ValidateURL(webhookURL string) error {
 		return fmt.Errorf("webhook URL must have a valid host")
 	}
 
-	if strings.Contains(parsedURL.Host, "localhost") || strings.Contains(parsedURL.Host, "127.0.0.1") {
-		return fmt.Errorf("localhost URLs are not allowed for webhooks")
-	}
-
 	return nil
 }
 func (s *Service) ValidateEvents(events []string) error {
@@ -55,11 +50,24 @@ func (s *Service) ValidateEvents(events []string) error {
 
 	return nil
 }
+func (s *Service) ValidateFormat(format string) error {
+	switch format {
+	case "", FormatNormalized, FormatRaw:
+		return nil
+	default:
+		return fmt.Errorf("invalid payload format: %s (expected %s or %s)", format, FormatNormalized, FormatRaw)
+	}
+}
 func (s *Service) GetValidEventTypes() []string {
 	return []string{
 		"Message",
 		"MessageRevoked",
 		"MessageReaction",
+		"MessageSent",
+		"MessageSendFailed",
+		"MessageStatus",
+		"ScheduledMessageSent",
+		"ScheduledMessageFailed",
 		"Connected",
 		"Disconnected",
 		"QRCode",
@@ -67,6 +75,7 @@ func (s *Service) GetValidEventTypes() []string {
 		"LoggedOut",
 		"HistorySync",
 		"Receipt",
+		"Presence",
 		"ChatPresence",
 		"GroupInfo",
 		"JoinedGroup",
@@ -80,6 +89,7 @@ func (s *Service) GetValidEventTypes() []string {
 		"KeepAliveRestored",
 		"Blocklist",
 		"MediaRetry",
+		"MediaStored",
 		"CallOffer",
 		"CallAccept",
 		"CallPreAccept",
@@ -102,6 +112,11 @@ func (s *Service) GetEventCategories() map[string][]string {
 			"MessageRevoked",
 			"MessageReaction",
 			"Receipt",
+			"MessageSent",
+			"MessageSendFailed",
+			"MessageStatus",
+			"ScheduledMessageSent",
+			"ScheduledMessageFailed",
 		},
 		"Connection": {
 			"Connected",
@@ -121,6 +136,7 @@ func (s *Service) GetEventCategories() map[string][]string {
 			"IdentityChange",
 			"PrivacySettings",
 			"Blocklist",
+			"Presence",
 			"ChatPresence",
 		},
 		"Sync": {
@@ -148,6 +164,7 @@ func (s *Service) GetEventCategories() map[string][]string {
 		},
 		"Media": {
 			"MediaRetry",
+			"MediaStored",
 		},
 	}
 }
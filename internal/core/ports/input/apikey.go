@@ -0,0 +1,20 @@
+package input
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/apikey"
+)
+
+type APIKeyUseCases interface {
+	Create(ctx context.Context, req *dto.CreateAPIKeyRequest) (*dto.APIKeySecretResponse, error)
+	List(ctx context.Context) (*dto.APIKeyListResponse, error)
+	Get(ctx context.Context, keyID string) (*dto.APIKeyResponse, error)
+	Rotate(ctx context.Context, keyID string) (*dto.APIKeySecretResponse, error)
+	Revoke(ctx context.Context, keyID string) (*dto.APIKeyResponse, error)
+	// Authenticate returns the active key matching token. It returns
+	// shared.ErrUnauthorized when there is none, and
+	// shared.ErrTenantSuspended when the key's tenant is suspended.
+	Authenticate(ctx context.Context, token string) (*apikey.APIKey, error)
+}
@@ -0,0 +1,20 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/core/application/dto"
+)
+
+type CampaignUseCases interface {
+	Create(ctx context.Context, sessionID string, req *dto.CreateCampaignRequest) (*dto.CampaignResponse, error)
+	List(ctx context.Context, sessionID string, req *dto.ListCampaignsRequest) (*dto.PaginationResponse, error)
+	Get(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error)
+	Report(ctx context.Context, sessionID, campaignID string) (*dto.CampaignReportResponse, error)
+	ListRecipients(ctx context.Context, sessionID, campaignID string, req *dto.ListCampaignRecipientsRequest) (*dto.PaginationResponse, error)
+	Pause(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error)
+	Resume(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error)
+	Cancel(ctx context.Context, sessionID, campaignID string) (*dto.CampaignResponse, error)
+	SyncReceipt(ctx context.Context, sessionID string, messageIDs []string, status string, timestamp time.Time) error
+}
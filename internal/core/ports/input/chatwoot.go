@@ -0,0 +1,21 @@
+package input
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+	"zpwoot/internal/core/domain/message"
+)
+
+type ChatwootUseCases interface {
+	GetConfig(ctx context.Context, sessionID string) (*dto.ChatwootConfigResponse, error)
+	SetConfig(ctx context.Context, sessionID string, req *dto.ChatwootConfigRequest) (*dto.ChatwootConfigResponse, error)
+	DeleteConfig(ctx context.Context, sessionID string) error
+	SyncMessage(ctx context.Context, msg *message.Message, pushName string) error
+	// HandleWebhook answers shared.ErrChatwootWebhookSecret unless secret is
+	// the webhook secret of the session's config.
+	HandleWebhook(ctx context.Context, sessionID, secret string, req *dto.ChatwootWebhookRequest) (*dto.ChatwootWebhookResponse, error)
+	StartImport(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error)
+	GetImport(ctx context.Context, sessionID string) (*dto.ChatwootImportResponse, error)
+	StartImportAfterPairing(ctx context.Context, sessionID string) error
+}
@@ -17,6 +17,9 @@ type UserCheckResult struct {
 	IsInWhatsApp bool
 	JID          string
 	VerifiedName string
+	// Candidates lists the forms of the number that were looked up, e.g.
+	// both forms of a Brazilian mobile number.
+	Candidates []string
 }
 type UserDetail struct {
 	JID          string
@@ -0,0 +1,11 @@
+package input
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+)
+
+type MediaUseCases interface {
+	Get(ctx context.Context, sessionID, messageID string) (*dto.MediaFile, error)
+}
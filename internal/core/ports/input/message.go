@@ -26,12 +26,22 @@ type ContactsGetter interface {
 }
 
 type ChatsGetter interface {
-	GetChats(ctx context.Context, sessionID string) (interface{}, error)
+	GetChats(ctx context.Context, sessionID string, req *dto.ListChatsRequest) (*dto.PaginationResponse, error)
+}
+
+type MessageHistoryGetter interface {
+	GetMessageHistory(ctx context.Context, sessionID string, req *dto.MessageHistoryRequest) (*dto.MessageHistoryResponse, error)
+}
+
+type MessageStatusGetter interface {
+	GetMessageStatus(ctx context.Context, sessionID, messageID string) (*dto.MessageStatusResponse, error)
 }
 
 type MessageUseCases interface {
 	MessageSender
 	MessageReceiver
+	MessageHistoryGetter
+	MessageStatusGetter
 	ChatInfoGetter
 	ContactsGetter
 	ChatsGetter
@@ -51,7 +61,7 @@ type MessageService interface {
 	DeleteMessage(ctx context.Context, sessionID, phone, messageID string) error
 	EditMessage(ctx context.Context, sessionID, phone, messageID, text string) error
 	MarkRead(ctx context.Context, sessionID, phone string, messageIDs []string) error
-	RequestHistorySync(ctx context.Context, sessionID string, count int) error
+	RequestHistorySync(ctx context.Context, sessionID, chatJID string, count int) error
 	GetChatInfo(ctx context.Context, sessionID, chatJID string) (*ChatInfo, error)
 	GetContacts(ctx context.Context, sessionID string) ([]*ContactInfo, error)
 	GetChats(ctx context.Context, sessionID string) ([]*ChatInfo, error)
@@ -90,4 +100,7 @@ type ChatInfo struct {
 	Topic            string `json:"topic,omitempty"`
 	IsGroup          bool   `json:"isGroup"`
 	ParticipantCount int    `json:"participantCount,omitempty"`
+	UnreadCount      int    `json:"unreadCount"`
+	Archived         bool   `json:"archived"`
+	Pinned           bool   `json:"pinned"`
 }
@@ -0,0 +1,15 @@
+package input
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+)
+
+type ScheduleUseCases interface {
+	Schedule(ctx context.Context, sessionID string, req *dto.ScheduleMessageRequest) (*dto.ScheduledMessageResponse, error)
+	List(ctx context.Context, sessionID string, req *dto.ListScheduledMessagesRequest) (*dto.PaginationResponse, error)
+	Get(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error)
+	Reschedule(ctx context.Context, sessionID, scheduleID string, req *dto.RescheduleMessageRequest) (*dto.ScheduledMessageResponse, error)
+	Cancel(ctx context.Context, sessionID, scheduleID string) (*dto.ScheduledMessageResponse, error)
+}
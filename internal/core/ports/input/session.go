@@ -54,6 +54,9 @@ type SessionUseCases interface {
 	GetQRCode(ctx context.Context, sessionID string) (*dto.QRCodeResponse, error)
 	RefreshQRCode(ctx context.Context, sessionID string) (*dto.QRCodeResponse, error)
 	PairPhone(ctx context.Context, sessionID string, phone string) (*dto.PairPhoneResponse, error)
+
+	SetProxy(ctx context.Context, sessionID string, req *dto.ProxySettings) (*dto.ProxyResponse, error)
+	RemoveProxy(ctx context.Context, sessionID string) (*dto.ProxyResponse, error)
 }
 
 type SessionManager interface {
@@ -0,0 +1,25 @@
+package input
+
+import (
+	"context"
+
+	"zpwoot/internal/core/application/dto"
+)
+
+type TenantUseCases interface {
+	Create(ctx context.Context, req *dto.CreateTenantRequest) (*dto.TenantResponse, error)
+	List(ctx context.Context) (*dto.TenantListResponse, error)
+	// Get returns a tenant together with its current usage.
+	Get(ctx context.Context, tenantID string) (*dto.TenantResponse, error)
+	Update(ctx context.Context, tenantID string, req *dto.UpdateTenantRequest) (*dto.TenantResponse, error)
+	Suspend(ctx context.Context, tenantID string) (*dto.TenantResponse, error)
+	Activate(ctx context.Context, tenantID string) (*dto.TenantResponse, error)
+	// Delete removes a tenant without sessions, along with its API keys.
+	Delete(ctx context.Context, tenantID string) error
+
+	// CheckSession returns shared.ErrSessionNotFound unless the session
+	// belongs to the tenant ctx is limited to, if any.
+	CheckSession(ctx context.Context, sessionID string) error
+	// Consume implements output.SendQuota.
+	Consume(ctx context.Context, sessionID string) error
+}
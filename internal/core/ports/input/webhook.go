@@ -8,9 +8,22 @@ import (
 
 type WebhookUseCases interface {
 	Create(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
-	Update(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
 	Upsert(ctx context.Context, sessionID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
-	Get(ctx context.Context, sessionID string) (*dto.WebhookResponse, error)
-	Delete(ctx context.Context, sessionID string) error
+	List(ctx context.Context, sessionID string) (*dto.ListWebhooksResponse, error)
+	Get(ctx context.Context, sessionID, webhookID string) (*dto.WebhookResponse, error)
+	Update(ctx context.Context, sessionID, webhookID string, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(ctx context.Context, sessionID, webhookID string) error
+	DeleteAll(ctx context.Context, sessionID string) error
+
+	GetGlobal(ctx context.Context) (*dto.WebhookResponse, error)
+	SetGlobal(ctx context.Context, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	DeleteGlobal(ctx context.Context) error
+
 	ListEvents(ctx context.Context) (*dto.ListEventsResponse, error)
+	GetEventSchemas(ctx context.Context) (*dto.EventSchemasResponse, error)
+	GetEventSchema(ctx context.Context, eventType string) (map[string]interface{}, error)
+
+	ListDeadLetters(ctx context.Context, request *dto.ListDeadLettersRequest) (*dto.PaginationResponse, error)
+	GetDeadLetter(ctx context.Context, id string) (*dto.DeadLetterResponse, error)
+	ReplayDeadLetters(ctx context.Context, request *dto.ReplayDeadLettersRequest) (*dto.ReplayDeadLettersResponse, error)
 }
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps binary objects, such as downloaded media, under a key made
+// of path segments separated by slashes.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+	// Get opens a blob; it returns ErrBlobNotFound for unknown keys.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes a blob. Deleting an unknown key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobPresigner is implemented by blob stores that can hand out temporary
+// URLs, so clients download blobs from the store instead of through the
+// API.
+type BlobPresigner interface {
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// BlobExpirer is implemented by blob stores that can remove old blobs on
+// their own, such as object stores with lifecycle rules.
+type BlobExpirer interface {
+	ExpireAfter(ctx context.Context, days int) error
+}
+
+// MediaFetcher downloads the file of a media message from WhatsApp into the
+// blob store.
+type MediaFetcher interface {
+	FetchMedia(ctx context.Context, sessionID, messageID string) error
+}
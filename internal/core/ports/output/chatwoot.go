@@ -0,0 +1,20 @@
+package output
+
+import (
+	"context"
+
+	"zpwoot/internal/core/domain/chatwoot"
+)
+
+// ChatwootClient talks to the Chatwoot account API of a configuration.
+type ChatwootClient interface {
+	ListInboxes(ctx context.Context, config *chatwoot.Config) ([]*chatwoot.Inbox, error)
+	CreateInbox(ctx context.Context, config *chatwoot.Config, name string) (*chatwoot.Inbox, error)
+	SearchContacts(ctx context.Context, config *chatwoot.Config, query string) ([]*chatwoot.Contact, error)
+	CreateContact(ctx context.Context, config *chatwoot.Config, inboxID int, contact *chatwoot.Contact) (*chatwoot.Contact, error)
+	ListContactConversations(ctx context.Context, config *chatwoot.Config, contactID int) ([]*chatwoot.Conversation, error)
+	CreateConversation(ctx context.Context, config *chatwoot.Config, inboxID, contactID int, status string) (*chatwoot.Conversation, error)
+	SetConversationStatus(ctx context.Context, config *chatwoot.Config, conversationID int, status string) error
+	CreateMessage(ctx context.Context, config *chatwoot.Config, conversationID int, message *chatwoot.Message) (int, error)
+	DownloadAttachment(ctx context.Context, config *chatwoot.Config, dataURL string) (*chatwoot.Attachment, error)
+}
@@ -0,0 +1,9 @@
+package output
+
+import "context"
+
+// EventPublisher delivers events raised by the application, rather than by
+// WhatsApp, to the webhooks of a session.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, sessionID, eventType string, payload interface{}) error
+}
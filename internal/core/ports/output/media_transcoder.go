@@ -0,0 +1,31 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// MediaTranscoder prepares media before it is uploaded to WhatsApp, which
+// only plays OGG/Opus voice notes, only accepts WebP stickers and shows
+// images and videos without a preview unless given a thumbnail. Converted
+// media comes back in a new temporary File the caller closes.
+type MediaTranscoder interface {
+	// VoiceNote converts audio to OGG/Opus and measures its duration and
+	// waveform.
+	VoiceNote(ctx context.Context, media *MediaData) (*MediaData, error)
+	// Sticker converts an image to a 512x512 WebP.
+	Sticker(ctx context.Context, media *MediaData) (*MediaData, error)
+	// Preview measures an image or video and renders its JPEG thumbnail.
+	Preview(ctx context.Context, media *MediaData) (*MediaDetails, error)
+}
+
+// MediaDetails describes media to the recipient's app. Zero fields are
+// left out of the message.
+type MediaDetails struct {
+	Width     uint32
+	Height    uint32
+	Duration  time.Duration
+	Thumbnail []byte
+	// Waveform holds 64 samples from 0 to 100, drawn on voice notes.
+	Waveform []byte
+}
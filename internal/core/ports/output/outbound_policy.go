@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrOutboundDenied = errors.New("outbound request denied")
+
+// OutboundPolicy decides which URLs the server may fetch from or post to on
+// behalf of API callers, such as media URLs and webhook targets.
+type OutboundPolicy interface {
+	// CheckURL returns an error wrapping ErrOutboundDenied when the URL
+	// uses another scheme than http or https or its host resolves to a
+	// denied address.
+	CheckURL(ctx context.Context, rawURL string) error
+}
@@ -0,0 +1,12 @@
+package output
+
+import "context"
+
+// SendQuota limits how many messages the sessions of a tenant may send.
+type SendQuota interface {
+	// Consume counts one message about to be sent by a session. It returns
+	// ErrMessageQuotaExceeded when the session's tenant has sent all the
+	// messages it may send today, and ErrTenantSuspended when the tenant
+	// is suspended.
+	Consume(ctx context.Context, sessionID string) error
+}
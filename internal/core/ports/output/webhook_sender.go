@@ -6,11 +6,12 @@ import (
 )
 
 type WebhookEvent struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	Timestamp time.Time              `json:"timestamp"`
-	Data      map[string]interface{} `json:"data"`
+	ID            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	SessionID     string                 `json:"sessionId"`
+	Timestamp     time.Time              `json:"timestamp"`
+	SchemaVersion int                    `json:"schemaVersion,omitempty"`
+	Data          map[string]interface{} `json:"data"`
 }
 
 type WebhookSender interface {
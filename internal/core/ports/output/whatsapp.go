@@ -2,6 +2,7 @@ package output
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -19,11 +20,14 @@ type WhatsAppClient interface {
 	ConnectAndGetQRCode(ctx context.Context, sessionID string) (*QRCodeInfo, error)
 	GetQRCode(ctx context.Context, sessionID string) (*QRCodeInfo, error)
 	PairPhone(ctx context.Context, sessionID string, phone string) (string, error)
+	ReloadProxy(ctx context.Context, sessionID string) error
 
 	SendTextMessage(ctx context.Context, sessionID, to, text string) (*MessageResult, error)
 	SendMediaMessage(ctx context.Context, sessionID, to string, media *MediaData) (*MessageResult, error)
 	SendLocationMessage(ctx context.Context, sessionID, to string, location *Location) (*MessageResult, error)
 	SendContactMessage(ctx context.Context, sessionID, to string, contact *ContactInfo) (*MessageResult, error)
+
+	GetChats(ctx context.Context, sessionID string) ([]*ChatInfo, error)
 }
 
 type SessionStatus struct {
@@ -61,6 +65,16 @@ type MediaData struct {
 	FileName string `json:"fileName,omitempty"`
 	Caption  string `json:"caption,omitempty"`
 	ViewOnce bool   `json:"viewOnce,omitempty"`
+
+	// File holds streamed media, kept in a temporary file instead of Data.
+	// Whoever created the media closes it.
+	File io.ReadSeekCloser `json:"-"`
+
+	// Sticker sends an image as a sticker rather than a photo.
+	Sticker bool `json:"sticker,omitempty"`
+
+	// Details are filled in by the MediaTranscoder, when there is one.
+	Details *MediaDetails `json:"-"`
 }
 
 type Location struct {
@@ -75,6 +89,28 @@ type ContactInfo struct {
 	PhoneNumber string `json:"phoneNumber"`
 }
 
+type ChatInfo struct {
+	JID              string           `json:"jid"`
+	Name             string           `json:"name,omitempty"`
+	Topic            string           `json:"topic,omitempty"`
+	IsGroup          bool             `json:"isGroup"`
+	ParticipantCount int              `json:"participantCount,omitempty"`
+	UnreadCount      int              `json:"unreadCount"`
+	Archived         bool             `json:"archived"`
+	Pinned           bool             `json:"pinned"`
+	MutedUntil       *time.Time       `json:"mutedUntil,omitempty"`
+	LastMessage      *ChatLastMessage `json:"lastMessage,omitempty"`
+}
+
+type ChatLastMessage struct {
+	ID        string    `json:"id"`
+	Sender    string    `json:"sender"`
+	FromMe    bool      `json:"fromMe"`
+	Type      string    `json:"type"`
+	Content   string    `json:"content,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type WhatsAppError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -85,11 +121,36 @@ func (e *WhatsAppError) Error() string {
 }
 
 var (
-	ErrSessionNotFound     = &WhatsAppError{Code: "SESSION_NOT_FOUND", Message: "Session not found"}
-	ErrSessionNotConnected = &WhatsAppError{Code: "SESSION_NOT_CONNECTED", Message: "Session not connected"}
-	ErrAlreadyConnected    = &WhatsAppError{Code: "ALREADY_CONNECTED", Message: "Session already connected"}
-	ErrInvalidJID          = &WhatsAppError{Code: "INVALID_JID", Message: "Invalid JID format"}
-	ErrQRCodeExpired       = &WhatsAppError{Code: "QR_CODE_EXPIRED", Message: "QR code expired"}
-	ErrConnectionFailed    = &WhatsAppError{Code: "CONNECTION_FAILED", Message: "Failed to connect to WhatsApp"}
-	ErrSendMessageFailed   = &WhatsAppError{Code: "SEND_MESSAGE_FAILED", Message: "Failed to send message"}
+	ErrSessionNotFound      = &WhatsAppError{Code: "SESSION_NOT_FOUND", Message: "Session not found"}
+	ErrSessionNotConnected  = &WhatsAppError{Code: "SESSION_NOT_CONNECTED", Message: "Session not connected"}
+	ErrAlreadyConnected     = &WhatsAppError{Code: "ALREADY_CONNECTED", Message: "Session already connected"}
+	ErrInvalidJID           = &WhatsAppError{Code: "INVALID_JID", Message: "Invalid JID format"}
+	ErrQRCodeExpired        = &WhatsAppError{Code: "QR_CODE_EXPIRED", Message: "QR code expired"}
+	ErrConnectionFailed     = &WhatsAppError{Code: "CONNECTION_FAILED", Message: "Failed to connect to WhatsApp"}
+	ErrSendMessageFailed    = &WhatsAppError{Code: "SEND_MESSAGE_FAILED", Message: "Failed to send message"}
+	ErrSendQueueFull        = &WhatsAppError{Code: "SEND_QUEUE_FULL", Message: "Too many messages waiting to be sent for this session"}
+	ErrMessageQuotaExceeded = &WhatsAppError{Code: "QUOTA_EXCEEDED", Message: "The tenant of this session reached its daily message quota"}
+	ErrTenantSuspended      = &WhatsAppError{Code: "TENANT_SUSPENDED", Message: "The tenant of this session is suspended"}
 )
+
+const (
+	MessageStatusSent      = "sent"
+	MessageStatusQueued    = "queued"
+	MessageStatusDelivered = "delivered"
+	MessageStatusRead      = "read"
+)
+
+type queuedSendKey struct{}
+
+// WithQueuedSend marks the sends made with ctx as queued: they return as soon
+// as the message is queued, with status "queued", and report the outcome
+// through the MessageSent and MessageSendFailed webhooks.
+func WithQueuedSend(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queuedSendKey{}, true)
+}
+
+// IsQueuedSend reports whether ctx was marked by WithQueuedSend.
+func IsQueuedSend(ctx context.Context) bool {
+	queued, _ := ctx.Value(queuedSendKey{}).(bool)
+	return queued
+}